@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/ifinu/ifinu-api-go/config"
+)
+
+// executarSubcomando trata os subcomandos do binário (ex: ifinu-api migrate status)
+func executarSubcomando(nome string, args []string) error {
+	switch nome {
+	case "migrate":
+		return executarMigrate(args)
+	default:
+		return fmt.Errorf("subcomando desconhecido: %s (disponível: migrate)", nome)
+	}
+}
+
+const usoMigrate = `uso: ifinu-api migrate <comando>
+  up                aplica as migrations pendentes
+  down [n]          reverte as últimas n migrations (padrão 1)
+  status            lista as migrations e se estão aplicadas
+  baseline <versão> marca como aplicadas, sem executar, as migrations até a versão`
+
+func executarMigrate(args []string) error {
+	if len(args) == 0 {
+		return errors.New(usoMigrate)
+	}
+
+	switch args[0] {
+	case "up":
+		aplicadas, err := config.AplicarMigracoes()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✅ %d migration(s) aplicada(s)\n", aplicadas)
+
+	case "down":
+		quantidade := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("quantidade inválida: %s", args[1])
+			}
+			quantidade = n
+		}
+		revertidas, err := config.ReverterMigracoes(quantidade)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✅ %d migration(s) revertida(s)\n", revertidas)
+
+	case "status":
+		status, err := config.StatusMigracoes()
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSÃO\tNOME\tSITUAÇÃO\tAPLICADA EM\tDOWN")
+		for _, s := range status {
+			situacao, data := "pendente", "-"
+			if s.Aplicada {
+				situacao = "aplicada"
+				data = s.DataAplicacao.Format("02/01/2006 15:04")
+			}
+			if s.Alterada {
+				situacao = "alterada após aplicar"
+			}
+			down := "não"
+			if s.TemDown() {
+				down = "sim"
+			}
+			fmt.Fprintf(w, "%03d\t%s\t%s\t%s\t%s\n", s.Versao, s.Nome, situacao, data, down)
+		}
+		return w.Flush()
+
+	case "baseline":
+		if len(args) < 2 {
+			return errors.New(usoMigrate)
+		}
+		versao, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("versão inválida: %s", args[1])
+		}
+		marcadas, err := config.MarcarMigracoesAte(versao)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✅ %d migration(s) marcada(s) como aplicada(s) até a versão %03d\n", marcadas, versao)
+
+	default:
+		return errors.New(usoMigrate)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// encerrando fica verdadeiro ao receber SIGTERM/SIGINT; a partir daí /health/ready responde 503
+var encerrando atomic.Bool
+
+// servirAteSinal atende HTTP até SIGTERM/SIGINT e então encerra de forma ordenada:
+//  1. /health/ready passa a falhar e aguarda SHUTDOWN_DRAIN_SECONDS para o balanceador tirar a réplica
+//  2. para de aceitar conexões e aguarda as requisições em andamento (http.Server.Shutdown)
+//  3. executa as funções de parada (cron e workers) em paralelo
+//
+// Os passos 2 e 3 compartilham o prazo de SHUTDOWN_TIMEOUT_SECONDS.
+func servirAteSinal(srv *http.Server, paradas ...func(context.Context) error) error {
+	ctxSinal, pararSinal := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer pararSinal()
+
+	erroServidor := make(chan error, 1)
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			erroServidor <- err
+		}
+		close(erroServidor)
+	}()
+
+	select {
+	case err := <-erroServidor:
+		return err
+	case <-ctxSinal.Done():
+	}
+	// Um segundo sinal encerra imediatamente
+	pararSinal()
+
+	encerrando.Store(true)
+	espera := time.Duration(viper.GetInt("SHUTDOWN_DRAIN_SECONDS")) * time.Second
+	log.Printf("🛑 Sinal de encerramento recebido, readiness desativada (aguardando %s)", espera)
+	time.Sleep(espera)
+
+	ctx, cancelar := context.WithTimeout(context.Background(), time.Duration(viper.GetInt("SHUTDOWN_TIMEOUT_SECONDS"))*time.Second)
+	defer cancelar()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Printf("⚠️  Requisições ainda em andamento no encerramento: %v", err)
+	} else {
+		log.Println("✅ Servidor HTTP encerrado")
+	}
+
+	var wg sync.WaitGroup
+	for _, parar := range paradas {
+		wg.Add(1)
+		go func(parar func(context.Context) error) {
+			defer wg.Done()
+			if err := parar(ctx); err != nil {
+				log.Printf("⚠️  %v", err)
+			}
+		}(parar)
+	}
+	wg.Wait()
+
+	log.Println("👋 Encerramento concluído")
+	return nil
+}
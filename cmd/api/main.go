@@ -1,13 +1,19 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/ifinu/ifinu-api-go/config"
 	"github.com/ifinu/ifinu-api-go/controlador"
+	"github.com/ifinu/ifinu-api-go/dominio/enums"
 	"github.com/ifinu/ifinu-api-go/integracao"
+	"github.com/ifinu/ifinu-api-go/metricas"
 	"github.com/ifinu/ifinu-api-go/middleware"
 	"github.com/ifinu/ifinu-api-go/repositorio"
 	"github.com/ifinu/ifinu-api-go/servico"
@@ -29,11 +35,44 @@ func main() {
 		log.Fatalf("❌ Erro ao carregar configurações: %v", err)
 	}
 
+	// Logs estruturados em JSON (LOG_LEVEL, LOG_FORMAT, LOG_MASK_PII)
+	logger := config.ConfigurarLogs()
+
+	// Tracing OpenTelemetry (OTEL_TRACES_EXPORTER; o padrão none não coleta nada)
+	encerrarRastreamento, err := config.ConfigurarRastreamento(context.Background())
+	if err != nil {
+		log.Fatalf("❌ Erro ao configurar rastreamento: %v", err)
+	}
+
+	// Chaves de criptografia dos segredos (Stripe e webhooks)
+	if err := config.CarregarChaveiro(); err != nil {
+		log.Fatalf("❌ Erro nas chaves de criptografia: %v", err)
+	}
+
 	// Conectar ao banco de dados
 	if err := config.ConectarBancoDados(); err != nil {
 		log.Fatalf("❌ Erro ao conectar ao banco: %v", err)
 	}
 
+	// Subcomandos (ex: ifinu-api migrate status) rodam e encerram sem subir a API
+	if len(os.Args) > 1 {
+		if err := executarSubcomando(os.Args[1], os.Args[2:]); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		return
+	}
+
+	// Migrations embutidas no binário: com MIGRATE_ON_START aplica as pendentes (com lock
+	// entre réplicas); sem o schema em dia a API não sobe
+	if viper.GetBool("MIGRATE_ON_START") {
+		if _, err := config.AplicarMigracoes(); err != nil {
+			log.Fatalf("❌ Erro ao aplicar migrations: %v", err)
+		}
+	}
+	if err := config.VerificarMigracoes(); err != nil {
+		log.Fatalf("❌ %v. Execute: ifinu-api migrate up", err)
+	}
+
 	// Inicializar repositórios
 	usuarioRepo := repositorio.NovoUsuarioRepositorio(config.DB)
 	clienteRepo := repositorio.NovoClienteRepositorio(config.DB)
@@ -41,35 +80,81 @@ func main() {
 	whatsappRepo := repositorio.NovoWhatsAppRepositorio(config.DB)
 	assinaturaRepo := repositorio.NovoAssinaturaRepositorio(config.DB)
 	stripeConfigRepo := repositorio.NovoStripeConfigRepositorio(config.DB)
+	organizacaoRepo := repositorio.NovoOrganizacaoRepositorio(config.DB)
+	chaveAPIRepo := repositorio.NovoChaveAPIRepositorio(config.DB)
+	webhookRepo := repositorio.NovoWebhookRepositorio(config.DB)
+	auditoriaRepo := repositorio.NovoAuditoriaRepositorio(config.DB)
+	mensagemWhatsAppRepo := repositorio.NovoMensagemWhatsAppRepositorio(config.DB)
+	respostaAutomaticaRepo := repositorio.NovoRespostaAutomaticaRepositorio(config.DB)
+	execucaoJobRepo := repositorio.NovoExecucaoJobRepositorio(config.DB)
 
 	// Inicializar integrações
 	evolutionAPI := integracao.NovoEvolutionAPICliente()
 
 	// Inicializar integrações adicionais
 	resendAPI := integracao.NovoResendCliente()
+	integracao.ConfigurarStripe()
+
+	// Consulta de CEP (CEP_PROVIDER: viacep ou local)
+	provedorCEP, err := integracao.NovoProvedorCEP()
+	if err != nil {
+		log.Fatalf("❌ Erro ao configurar provedor de CEP: %v", err)
+	}
+
+	// Endereço do Redis (fila de mensagens e webhooks)
+	redisAddr := viper.GetString("REDIS_ADDR")
+	if redisAddr == "" {
+		redisAddr = "localhost:6379" // Fallback para desenvolvimento
+	}
+
+	// Inicializar webhooks de saída (usado pelos demais services para disparar eventos)
+	webhookServico := servico.NovoWebhookServico(webhookRepo, redisAddr, logger)
+	webhookServico.IniciarWorkers(3)
+
+	// Trilha de auditoria (gravada na mesma transação das alterações)
+	auditoriaServico := servico.NovoAuditoriaServico(auditoriaRepo, usuarioRepo)
+
+	// Ritmo de envio por número WhatsApp (aquecimento, pausa humana e limite diário da conta)
+	limitadorWhatsApp := servico.NovoLimitadorWhatsApp(redisAddr, logger)
 
 	// Inicializar services
 	autenticacaoServico := servico.NovoAutenticacaoServico(usuarioRepo)
-	clienteServico := servico.NovoClienteServico(clienteRepo)
-	cobrancaServico := servico.NovoCobrancaServico(cobrancaRepo, clienteRepo)
-	whatsappServico := servico.NovoWhatsAppServico(whatsappRepo, usuarioRepo, evolutionAPI)
-	assinaturaServico := servico.NovoAssinaturaServico(assinaturaRepo, usuarioRepo)
+	whatsappServico := servico.NovoWhatsAppServico(whatsappRepo, usuarioRepo, evolutionAPI, webhookServico, limitadorWhatsApp, logger)
+	cepServico := servico.NovoCEPServico(provedorCEP, logger)
+	clienteServico := servico.NovoClienteServico(clienteRepo, whatsappRepo, whatsappServico, cepServico, webhookServico, auditoriaServico)
+	cobrancaServico := servico.NovoCobrancaServico(cobrancaRepo, clienteRepo, webhookServico, auditoriaServico)
+	assinaturaServico := servico.NovoAssinaturaServico(assinaturaRepo, usuarioRepo, auditoriaServico)
 	relatorioServico := servico.NovoRelatorioServico(clienteRepo, cobrancaRepo)
 	stripeServico := servico.NovoStripeServico(usuarioRepo, assinaturaRepo)
-	stripeConfigServico := servico.NovoStripeConfigServico(stripeConfigRepo)
+	stripeConfigServico := servico.NovoStripeConfigServico(stripeConfigRepo, auditoriaServico)
 	stripeConnectServico := servico.NovoStripeConnectServico(usuarioRepo)
+	organizacaoServico := servico.NovoOrganizacaoServico(organizacaoRepo, usuarioRepo, resendAPI, auditoriaServico)
+	chaveAPIServico := servico.NovoChaveAPIServico(chaveAPIRepo)
+	respostaAutomaticaServico := servico.NovoRespostaAutomaticaServico(respostaAutomaticaRepo, mensagemWhatsAppRepo, clienteRepo, cobrancaRepo, whatsappServico, clienteServico, auditoriaServico, logger)
+	conversaServico := servico.NovoConversaServico(mensagemWhatsAppRepo, whatsappRepo, clienteRepo, cobrancaRepo, whatsappServico, webhookServico, respostaAutomaticaServico, logger)
 
-	// Inicializar e iniciar agendador
-	redisAddr := viper.GetString("REDIS_ADDR")
-	if redisAddr == "" {
-		redisAddr = "localhost:6379" // Fallback para desenvolvimento
-	}
-	agendadorServico := servico.NovoAgendadorServico(cobrancaRepo, whatsappRepo, usuarioRepo, assinaturaRepo, evolutionAPI, resendAPI, whatsappServico, redisAddr)
+	// Segredos de chaves antigas não são recriptografados na subida: durante o rollout as
+	// réplicas antigas ainda precisam lê-los (ver `ifinu chave recriptografar` no README)
+
+	// Inicializar e iniciar agendador (cada job roda em uma única réplica por horário)
+	execucaoJobServico := servico.NovoExecucaoJobServico(execucaoJobRepo, redisAddr, logger)
+	agendadorServico := servico.NovoAgendadorServico(cobrancaRepo, whatsappRepo, usuarioRepo, assinaturaRepo, respostaAutomaticaRepo, evolutionAPI, resendAPI, whatsappServico, webhookServico, auditoriaServico, execucaoJobServico, redisAddr, logger)
 	agendadorServico.Iniciar()
 
+	// Métricas Prometheus: pool do banco e saúde das filas (lidas a cada scrape)
+	if sqlDB, err := config.DB.DB(); err == nil {
+		metricas.RegistrarBancoDados(sqlDB, "ifinu")
+	}
+	metricas.Registrar(servico.NovoColetorFilas(agendadorServico.FilaMensagem(), webhookServico))
+
+	// Probes do Kubernetes (HEALTH_CHECK_EXTERNAL inclui Evolution API e Resend na readiness)
+	saudeServico := servico.NovoSaudeServico(config.DB, redisAddr, agendadorServico, webhookServico, evolutionAPI, resendAPI,
+		viper.GetBool("HEALTH_CHECK_EXTERNAL"), time.Duration(viper.GetInt("HEALTH_CHECK_TIMEOUT_MS"))*time.Millisecond)
+
 	// Inicializar controllers
 	autenticacaoController := controlador.NovoAutenticacaoControlador(autenticacaoServico)
 	clienteController := controlador.NovoClienteControlador(clienteServico)
+	cepController := controlador.NovoCEPControlador(cepServico)
 	cobrancaController := controlador.NovoCobrancaControlador(cobrancaServico)
 	whatsappController := controlador.NovoWhatsAppControlador(whatsappServico)
 	assinaturaController := controlador.NovoAssinaturaControlador(assinaturaServico)
@@ -77,25 +162,45 @@ func main() {
 	stripeController := controlador.NovoStripeControlador(stripeServico)
 	stripeConfigController := controlador.NovoStripeConfigControlador(stripeConfigServico)
 	stripeConnectController := controlador.NovoStripeConnectControlador(stripeConnectServico)
+	organizacaoController := controlador.NovoOrganizacaoControlador(organizacaoServico)
+	chaveAPIController := controlador.NovoChaveAPIControlador(chaveAPIServico)
+	webhookController := controlador.NovoWebhookControlador(webhookServico)
+	auditoriaController := controlador.NovoAuditoriaControlador(auditoriaServico)
+	conversaController := controlador.NovoConversaControlador(conversaServico)
+	respostaAutomaticaController := controlador.NovoRespostaAutomaticaControlador(respostaAutomaticaServico)
+	saudeController := controlador.NovoSaudeControlador(saudeServico, encerrando.Load)
 
 	// Configurar Gin
 	if viper.GetString("APP_ENV") == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	r := gin.Default()
+	r := gin.New()
+	// O IP do cliente (rate limit e auditoria) só vem de X-Forwarded-For se a conexão chegar
+	// de um proxy listado em TRUSTED_PROXIES; caso contrário o header seria forjável
+	if err := r.SetTrustedProxies(config.ProxiesConfiaveis()); err != nil {
+		log.Fatalf("❌ TRUSTED_PROXIES inválido: %v", err)
+	}
+	r.Use(gin.Recovery(), middleware.Rastreamento(viper.GetString("OTEL_SERVICE_NAME")), middleware.RequestID(), middleware.LogRequisicoes(logger), middleware.MetricasHTTP())
+
+	// Rate limit da API (Redis com fallback em memória)
+	middleware.ConfigurarLimiteTaxa(redisAddr, logger)
+	limiteAuth := middleware.LimitarTaxa(middleware.LimiteAutenticacao)
+	limiteEnvio := middleware.LimitarTaxa(middleware.LimiteEnvioWhatsApp)
+	limitePorMetodo := middleware.LimitarTaxaPorMetodo(middleware.LimiteLeitura, middleware.LimiteEscrita)
 
 	// Middleware de CORS
 	r.Use(corsMiddleware())
 
 	// Rotas públicas
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status":  "ok",
-			"message": "IFINU API GO está rodando",
-			"version": "1.0.0",
-		})
-	})
+	// /health/live é a liveness (workers e cron); /health/ready é a readiness (banco, Redis)
+	// e falha durante o encerramento. /health e /ready continuam como atalhos.
+	r.GET("/health/live", saudeController.Vivacidade)
+	r.GET("/health/ready", saudeController.Prontidao)
+	r.GET("/health", saudeController.Vivacidade)
+	r.GET("/ready", saudeController.Prontidao)
+	// Métricas Prometheus (com METRICS_TOKEN, exige Authorization: Bearer <token>)
+	r.GET("/metrics", gin.WrapH(metricas.Handler(viper.GetString("METRICS_TOKEN"))))
 
 	r.GET("/", func(c *gin.Context) {
 		c.JSON(200, gin.H{
@@ -108,13 +213,14 @@ func main() {
 	// Rotas de autenticação SEM /api (compatibilidade com frontend)
 	authLegacy := r.Group("/auth")
 	{
-		authLegacy.POST("/login", autenticacaoController.Login)
-		authLegacy.POST("/cadastro", autenticacaoController.Cadastro)
-		authLegacy.POST("/refresh", autenticacaoController.RefreshToken)
-		authLegacy.POST("/2fa/verificar", autenticacaoController.Verificar2FA)
+		authLegacy.POST("/login", limiteAuth, autenticacaoController.Login)
+		authLegacy.POST("/cadastro", limiteAuth, autenticacaoController.Cadastro)
+		authLegacy.POST("/refresh", limiteAuth, autenticacaoController.RefreshToken)
+		authLegacy.POST("/2fa/verificar", limiteAuth, autenticacaoController.Verificar2FA)
 
 		authLegacyProtegido := authLegacy.Group("")
 		authLegacyProtegido.Use(middleware.AutenticacaoMiddleware())
+		authLegacyProtegido.Use(limitePorMetodo)
 		{
 			authLegacyProtegido.GET("/me", autenticacaoController.Me)
 			authLegacyProtegido.GET("/status-trial", autenticacaoController.StatusTrial)
@@ -126,21 +232,30 @@ func main() {
 	// Rotas de WhatsApp SEM /api (compatibilidade com frontend)
 	whatsappLegacy := r.Group("/whatsapp")
 	whatsappLegacy.Use(middleware.AutenticacaoMiddleware())
+	whatsappLegacy.Use(middleware.OrganizacaoMiddleware())
+	whatsappLegacy.Use(limitePorMetodo)
 	whatsappLegacy.Use(middleware.AssinaturaMiddleware())
+	whatsappLegacy.Use(middleware.AutorizarPorMetodo(enums.PapelLeitura, enums.PapelFinanceiro, enums.PapelAdmin))
 	{
-		whatsappLegacy.POST("/conectar", whatsappController.Conectar)
+		whatsappLegacy.POST("/conectar", middleware.ExigirPapel(enums.PapelAdmin), whatsappController.Conectar)
 		whatsappLegacy.GET("/status", whatsappController.ObterStatus)
 		whatsappLegacy.GET("/qrcode", whatsappController.ObterQRCode)
-		whatsappLegacy.POST("/desconectar", whatsappController.Desconectar)
-		whatsappLegacy.POST("/enviar", whatsappController.EnviarMensagem)
+		whatsappLegacy.POST("/desconectar", middleware.ExigirPapel(enums.PapelAdmin), whatsappController.Desconectar)
+		whatsappLegacy.POST("/enviar", limiteEnvio, whatsappController.EnviarMensagem)
 		whatsappLegacy.POST("/testar", whatsappController.TestarConexao)
-		whatsappLegacy.POST("/limpar-orfaos", whatsappController.LimparOrfaos)
+		whatsappLegacy.POST("/limpar-orfaos", middleware.ExigirPapel(enums.PapelAdmin), whatsappController.LimparOrfaos)
 		whatsappLegacy.GET("/estatisticas", whatsappController.ObterEstatisticas)
+		whatsappLegacy.GET("/conexoes", whatsappController.ListarConexoes)
+		whatsappLegacy.PUT("/conexoes/:id", middleware.ExigirPapel(enums.PapelAdmin), whatsappController.AtualizarConexao)
+		whatsappLegacy.DELETE("/conexoes/:id", middleware.ExigirPapel(enums.PapelAdmin), whatsappController.RemoverConexao)
 	}
 
 	// Rotas de Stripe SEM /api (compatibilidade com frontend)
 	stripeLegacy := r.Group("/stripe-trial")
 	stripeLegacy.Use(middleware.AutenticacaoMiddleware())
+	stripeLegacy.Use(middleware.OrganizacaoMiddleware())
+	stripeLegacy.Use(limitePorMetodo)
+	stripeLegacy.Use(middleware.ExigirPapel(enums.PapelOwner))
 	{
 		stripeLegacy.POST("/create-checkout", stripeController.CreateCheckout)
 	}
@@ -152,17 +267,25 @@ func main() {
 		api.POST("/stripe/webhook", stripeController.WebhookStripe)
 		api.POST("/stripe-connect/webhook", stripeConnectController.WebhookAccountUpdated)
 
+		// Webhook da Evolution API (público - autenticado por token)
+		api.POST("/whatsapp/webhook", conversaController.WebhookEvolution)
+
+		// Descadastro de lembretes pelo link dos emails (público - autenticado por token assinado)
+		api.GET("/notificacoes/descadastrar", limiteAuth, clienteController.ConfirmarDescadastro)
+		api.POST("/notificacoes/descadastrar", limiteAuth, clienteController.Descadastrar)
+
 		// Rotas de autenticação (públicas)
 		auth := api.Group("/auth")
 		{
-			auth.POST("/login", autenticacaoController.Login)
-			auth.POST("/cadastro", autenticacaoController.Cadastro)
-			auth.POST("/refresh", autenticacaoController.RefreshToken)
-			auth.POST("/2fa/verificar", autenticacaoController.Verificar2FA)
+			auth.POST("/login", limiteAuth, autenticacaoController.Login)
+			auth.POST("/cadastro", limiteAuth, autenticacaoController.Cadastro)
+			auth.POST("/refresh", limiteAuth, autenticacaoController.RefreshToken)
+			auth.POST("/2fa/verificar", limiteAuth, autenticacaoController.Verificar2FA)
 
 			// Rotas protegidas de autenticação
 			authProtegido := auth.Group("")
 			authProtegido.Use(middleware.AutenticacaoMiddleware())
+			authProtegido.Use(limitePorMetodo)
 			{
 				authProtegido.GET("/me", autenticacaoController.Me)
 				authProtegido.POST("/2fa/gerar", autenticacaoController.Gerar2FA)
@@ -173,6 +296,8 @@ func main() {
 		// Rotas protegidas apenas com autenticação (sem verificar assinatura)
 		autenticado := api.Group("")
 		autenticado.Use(middleware.AutenticacaoMiddleware())
+		autenticado.Use(middleware.OrganizacaoMiddleware())
+		autenticado.Use(limitePorMetodo)
 		{
 			// Rota de perfil (sem exigir assinatura ativa)
 			autenticado.GET("/perfil", autenticacaoController.Me)
@@ -185,6 +310,7 @@ func main() {
 
 			// Rotas de configuração Stripe (sem exigir assinatura ativa)
 			stripeConfig := autenticado.Group("/stripe")
+			stripeConfig.Use(middleware.ExigirPapel(enums.PapelAdmin))
 			{
 				stripeConfig.GET("/config", stripeConfigController.BuscarConfiguracao)
 				stripeConfig.POST("/config", stripeConfigController.SalvarConfiguracao)
@@ -194,6 +320,7 @@ func main() {
 
 			// Rotas de Stripe Connect (sem exigir assinatura ativa)
 			stripeConnect := autenticado.Group("/stripe-connect")
+			stripeConnect.Use(middleware.ExigirPapel(enums.PapelAdmin))
 			{
 				stripeConnect.POST("/criar-conta", stripeConnectController.CriarContaConnect)
 				stripeConnect.GET("/status", stripeConnectController.ObterStatus)
@@ -203,25 +330,80 @@ func main() {
 				stripeConnect.GET("/dashboard-link", stripeConnectController.GerarDashboardLink)
 				stripeConnect.DELETE("/desconectar", stripeConnectController.Desconectar)
 			}
+
+			// Rotas de organização (membros e papéis)
+			organizacao := autenticado.Group("/organizacao")
+			{
+				organizacao.GET("", organizacaoController.Obter)
+				organizacao.POST("/convites/aceitar", organizacaoController.AceitarConvite)
+
+				membros := organizacao.Group("/membros")
+				membros.Use(middleware.ExigirPapel(enums.PapelAdmin))
+				{
+					membros.POST("", organizacaoController.ConvidarMembro)
+					membros.PATCH("/:id", organizacaoController.AlterarPapel)
+					membros.DELETE("/:id", organizacaoController.RemoverMembro)
+				}
+			}
+
+			// Rotas de chaves de API (integrações servidor a servidor)
+			chavesAPI := autenticado.Group("/chaves-api")
+			chavesAPI.Use(middleware.ExigirPapel(enums.PapelAdmin))
+			{
+				chavesAPI.GET("", chaveAPIController.Listar)
+				chavesAPI.POST("", chaveAPIController.Criar)
+				chavesAPI.DELETE("/:id", chaveAPIController.Revogar)
+			}
+
+			// Rotas de webhooks de saída (eventos de cobranças, clientes e WhatsApp)
+			webhooks := autenticado.Group("/webhooks")
+			webhooks.Use(middleware.ExigirPapel(enums.PapelAdmin))
+			{
+				webhooks.GET("", webhookController.ListarEndpoints)
+				webhooks.POST("", webhookController.CriarEndpoint)
+				webhooks.PUT("/:id", webhookController.AtualizarEndpoint)
+				webhooks.DELETE("/:id", webhookController.RemoverEndpoint)
+				webhooks.GET("/:id/entregas", webhookController.ListarEntregas)
+				webhooks.POST("/entregas/:entregaId/reenviar", webhookController.Reentregar)
+			}
+
+			// Rotas de auditoria (histórico de alterações da conta)
+			auditoria := autenticado.Group("/auditoria")
+			auditoria.Use(middleware.ExigirPapel(enums.PapelAdmin))
+			{
+				auditoria.GET("", auditoriaController.Buscar)
+			}
 		}
 
 		// Rotas protegidas (requerem autenticação e assinatura ativa)
+		// Aceitam JWT ou chave de API; com chave, cada grupo exige o escopo do recurso
 		protegido := api.Group("")
-		protegido.Use(middleware.AutenticacaoMiddleware())
+		protegido.Use(middleware.AutenticacaoOuChaveAPIMiddleware())
+		protegido.Use(middleware.OrganizacaoMiddleware())
+		protegido.Use(limitePorMetodo)
 		protegido.Use(middleware.AssinaturaMiddleware())
 		{
 			// Rotas de clientes
 			clientes := protegido.Group("/clientes")
+			clientes.Use(middleware.ExigirEscopo("clientes"))
+			clientes.Use(middleware.AutorizarPorMetodo(enums.PapelLeitura, enums.PapelFinanceiro, enums.PapelAdmin))
 			{
 				clientes.GET("", clienteController.Listar)
 				clientes.POST("", clienteController.Criar)
 				clientes.GET("/:id", clienteController.BuscarPorID)
 				clientes.PUT("/:id", clienteController.Atualizar)
+				clientes.PUT("/:id/preferencias-notificacao", clienteController.AtualizarPreferencias)
+				clientes.POST("/:id/verificar-whatsapp", clienteController.VerificarWhatsApp)
 				clientes.DELETE("/:id", clienteController.Deletar)
 			}
 
+			// Consulta de endereço por CEP (preenchimento do cadastro de clientes)
+			protegido.GET("/cep/:cep", middleware.ExigirEscopo("clientes"), cepController.BuscarCEP)
+
 			// Rotas de cobranças
 			cobrancas := protegido.Group("/cobrancas")
+			cobrancas.Use(middleware.ExigirEscopo("cobrancas"))
+			cobrancas.Use(middleware.AutorizarPorMetodo(enums.PapelLeitura, enums.PapelFinanceiro, enums.PapelAdmin))
 			{
 				cobrancas.GET("", cobrancaController.Listar)
 				cobrancas.POST("", cobrancaController.Criar)
@@ -234,19 +416,40 @@ func main() {
 
 			// Rotas de WhatsApp
 			whatsapp := protegido.Group("/whatsapp")
+			whatsapp.Use(middleware.ExigirEscopo("whatsapp"))
+			whatsapp.Use(middleware.AutorizarPorMetodo(enums.PapelLeitura, enums.PapelFinanceiro, enums.PapelAdmin))
 			{
-				whatsapp.POST("/conectar", whatsappController.Conectar)
+				whatsapp.POST("/conectar", middleware.ExigirPapel(enums.PapelAdmin), whatsappController.Conectar)
 				whatsapp.GET("/status", whatsappController.ObterStatus)
 				whatsapp.GET("/qrcode", whatsappController.ObterQRCode)
-				whatsapp.POST("/desconectar", whatsappController.Desconectar)
-				whatsapp.POST("/enviar", whatsappController.EnviarMensagem)
+				whatsapp.POST("/desconectar", middleware.ExigirPapel(enums.PapelAdmin), whatsappController.Desconectar)
+				whatsapp.POST("/enviar", limiteEnvio, whatsappController.EnviarMensagem)
 				whatsapp.POST("/testar", whatsappController.TestarConexao)
-				whatsapp.POST("/limpar-orfaos", whatsappController.LimparOrfaos)
+				whatsapp.POST("/limpar-orfaos", middleware.ExigirPapel(enums.PapelAdmin), whatsappController.LimparOrfaos)
 				whatsapp.GET("/estatisticas", whatsappController.ObterEstatisticas)
+				whatsapp.GET("/conexoes", whatsappController.ListarConexoes)
+				whatsapp.PUT("/conexoes/:id", middleware.ExigirPapel(enums.PapelAdmin), whatsappController.AtualizarConexao)
+				whatsapp.DELETE("/conexoes/:id", middleware.ExigirPapel(enums.PapelAdmin), whatsappController.RemoverConexao)
+
+				// Caixa de entrada (respostas dos clientes)
+				whatsapp.GET("/conversas", conversaController.Listar)
+				whatsapp.GET("/conversas/:telefone/mensagens", conversaController.ListarMensagens)
+				whatsapp.POST("/conversas/:telefone/lida", conversaController.MarcarComoLida)
+				whatsapp.POST("/conversas/:telefone/responder", conversaController.Responder)
+
+				// Auto-responder por palavra-chave
+				whatsapp.GET("/respostas-automaticas", respostaAutomaticaController.Listar)
+				whatsapp.POST("/respostas-automaticas", middleware.ExigirPapel(enums.PapelAdmin), respostaAutomaticaController.Criar)
+				whatsapp.GET("/respostas-automaticas/config", respostaAutomaticaController.ObterConfig)
+				whatsapp.PUT("/respostas-automaticas/config", middleware.ExigirPapel(enums.PapelAdmin), respostaAutomaticaController.SalvarConfig)
+				whatsapp.GET("/respostas-automaticas/disparos", respostaAutomaticaController.ListarDisparos)
+				whatsapp.PUT("/respostas-automaticas/:id", middleware.ExigirPapel(enums.PapelAdmin), respostaAutomaticaController.Atualizar)
+				whatsapp.DELETE("/respostas-automaticas/:id", middleware.ExigirPapel(enums.PapelAdmin), respostaAutomaticaController.Remover)
 			}
 
 			// Rotas de assinaturas
 			assinaturas := protegido.Group("/assinaturas")
+			assinaturas.Use(middleware.AutorizarPorMetodo(enums.PapelLeitura, enums.PapelOwner, enums.PapelOwner))
 			{
 				assinaturas.GET("/status", assinaturaController.Status)
 				assinaturas.GET("/planos", stripeController.ListarPlanos)
@@ -258,6 +461,8 @@ func main() {
 
 			// Rotas de relatórios
 			relatorios := protegido.Group("/relatorios")
+			relatorios.Use(middleware.ExigirEscopo("relatorios"))
+			relatorios.Use(middleware.ExigirPapel(enums.PapelLeitura))
 			{
 				relatorios.GET("/dashboard", relatorioController.Dashboard)
 				relatorios.GET("/pagamentos", relatorioController.HistoricoPagamentos)
@@ -265,6 +470,7 @@ func main() {
 
 			// Rotas de Stripe Connect
 			stripeConnect := protegido.Group("/stripe-connect")
+			stripeConnect.Use(middleware.ExigirPapel(enums.PapelFinanceiro))
 			{
 				stripeConnect.POST("/create-checkout-session", stripeController.CreateCheckoutSession)
 			}
@@ -279,11 +485,25 @@ func main() {
 
 	log.Printf("🚀 Servidor iniciando na porta %s...", porta)
 	log.Printf("📚 Documentação disponível em: http://localhost:%s/", porta)
-	log.Printf("💚 Health check: http://localhost:%s/health", porta)
+	log.Printf("💚 Health check: http://localhost:%s/health/live e /health/ready", porta)
+
+	srv := &http.Server{
+		Addr:    ":" + porta,
+		Handler: r,
+	}
 
-	if err := r.Run(":" + porta); err != nil {
+	// Em SIGTERM (rollout do Kubernetes) conclui as requisições, para o cron e os workers
+	// e aguarda os envios de WhatsApp disparados pelas requisições
+	if err := servirAteSinal(srv, agendadorServico.Parar, webhookServico.Parar, whatsappServico.Parar); err != nil {
 		log.Fatalf("❌ Erro ao iniciar servidor: %v", err)
 	}
+
+	// Por último, para incluir os spans dos workers que acabaram de parar
+	ctxRastreamento, cancelar := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelar()
+	if err := encerrarRastreamento(ctxRastreamento); err != nil {
+		log.Printf("⚠️  Erro ao enviar os últimos traces: %v", err)
+	}
 }
 
 // corsMiddleware adiciona headers CORS
@@ -291,8 +511,8 @@ func corsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, PATCH, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-		c.Writer.Header().Set("Access-Control-Expose-Headers", "Content-Length")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, X-Request-ID")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "Content-Length, X-Request-ID, RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset, RateLimit-Policy, Retry-After")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 
 		if c.Request.Method == "OPTIONS" {
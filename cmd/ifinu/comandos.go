@@ -0,0 +1,360 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/config"
+	"github.com/ifinu/ifinu-api-go/dominio/entidades"
+	"github.com/ifinu/ifinu-api-go/dto"
+	"github.com/ifinu/ifinu-api-go/integracao"
+	"github.com/ifinu/ifinu-api-go/repositorio"
+	"github.com/ifinu/ifinu-api-go/servico"
+	"github.com/ifinu/ifinu-api-go/util"
+	"github.com/spf13/viper"
+)
+
+// executarComando despacha o comando para o service correspondente
+func executarComando(nome string, args []string) error {
+	switch nome {
+	case "vitalicio":
+		return executarVitalicio(args)
+	case "trial":
+		return executarTrial(args)
+	case "cobrancas":
+		return executarCobrancas(args)
+	case "filas":
+		return executarFilas(args)
+	case "stripe":
+		return executarStripe(args)
+	case "chave":
+		return executarChave(args)
+	case "jobs":
+		return executarJobs(args)
+	default:
+		return fmt.Errorf("comando desconhecido: %s\n\n%s", nome, uso)
+	}
+}
+
+func executarVitalicio(args []string) error {
+	if len(args) != 2 || (args[0] != "conceder" && args[0] != "revogar") {
+		return errors.New("uso: ifinu vitalicio conceder|revogar <email|id>")
+	}
+
+	usuario, err := buscarUsuario(args[1])
+	if err != nil {
+		return err
+	}
+
+	usuario, err = novoAssinaturaServico().DefinirVitalicio(usuario.ID, dto.AtorSistema, args[0] == "conceder")
+	if err != nil {
+		return err
+	}
+
+	if usuario.Vitalicio {
+		fmt.Printf("✅ Acesso vitalício concedido a %s\n", usuario.Email)
+	} else {
+		fmt.Printf("✅ Acesso vitalício revogado de %s\n", usuario.Email)
+	}
+	return nil
+}
+
+func executarTrial(args []string) error {
+	if len(args) != 3 || args[0] != "estender" {
+		return errors.New("uso: ifinu trial estender <email|id> <dias>")
+	}
+
+	dias, err := strconv.Atoi(args[2])
+	if err != nil {
+		return fmt.Errorf("quantidade de dias inválida: %s", args[2])
+	}
+
+	usuario, err := buscarUsuario(args[1])
+	if err != nil {
+		return err
+	}
+
+	usuario, err = novoAssinaturaServico().EstenderTrial(usuario.ID, dto.AtorSistema, dias)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Trial de %s estendido até %s\n", usuario.Email, usuario.DataFimTrial().Format("02/01/2006 15:04"))
+	return nil
+}
+
+func executarCobrancas(args []string) error {
+	if len(args) < 1 || len(args) > 2 || args[0] != "vencidas" {
+		return errors.New("uso: ifinu cobrancas vencidas [AAAA-MM-DD]")
+	}
+
+	data := time.Now()
+	if len(args) == 2 {
+		var err error
+		data, err = time.Parse("2006-01-02", args[1])
+		if err != nil {
+			return fmt.Errorf("data inválida: %s (use AAAA-MM-DD)", args[1])
+		}
+	}
+
+	atualizadas, err := novoAgendadorServico().AtualizarCobrancasVencidasEm(context.Background(), data)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ %d cobrança(s) marcada(s) como vencida(s) com vencimento antes de %s\n", atualizadas, data.Format("02/01/2006"))
+	return nil
+}
+
+func executarFilas(args []string) error {
+	const usoFilas = "uso: ifinu filas status [n] | ifinu filas esvaziar whatsapp|webhooks --confirmar"
+	if len(args) == 0 {
+		return errors.New(usoFilas)
+	}
+
+	redisAddr := enderecoRedis()
+	webhookServico := servico.NovoWebhookServico(repositorio.NovoWebhookRepositorio(config.DB), redisAddr, slog.Default())
+
+	switch args[0] {
+	case "status":
+		limite := int64(10)
+		if len(args) > 1 {
+			n, err := strconv.ParseInt(args[1], 10, 64)
+			if err != nil || n < 0 {
+				return fmt.Errorf("quantidade inválida: %s", args[1])
+			}
+			limite = n
+		}
+
+		filas, err := novoFilaMensagemServico(redisAddr, webhookServico).InspecionarFilas(limite)
+		if err != nil {
+			return err
+		}
+		filasWebhook, err := webhookServico.InspecionarFilas(limite)
+		if err != nil {
+			return err
+		}
+
+		for _, fila := range append(filas, filasWebhook...) {
+			fmt.Printf("📊 %s: %d item(ns)\n", fila.Nome, fila.Tamanho)
+			for _, item := range fila.Itens {
+				fmt.Printf("   %s\n", item)
+			}
+		}
+		return nil
+
+	case "esvaziar":
+		if len(args) != 3 || args[2] != "--confirmar" {
+			return errors.New(usoFilas)
+		}
+
+		var removidos int64
+		var err error
+		switch args[1] {
+		case "whatsapp":
+			removidos, err = novoFilaMensagemServico(redisAddr, webhookServico).EsvaziarFila()
+		case "webhooks":
+			removidos, err = webhookServico.EsvaziarFilas()
+		default:
+			return fmt.Errorf("fila desconhecida: %s (whatsapp ou webhooks)", args[1])
+		}
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("✅ %d item(ns) removido(s) da fila %s\n", removidos, args[1])
+		if args[1] == "webhooks" {
+			fmt.Printf("   As entregas continuam pendentes e voltam à fila em %s; desative o endpoint para descartá-las\n",
+				servico.AtrasoReenfileirarWebhook)
+		}
+		return nil
+
+	default:
+		return errors.New(usoFilas)
+	}
+}
+
+func executarStripe(args []string) error {
+	if len(args) != 2 || args[0] != "reconciliar" {
+		return errors.New("uso: ifinu stripe reconciliar <email|id>")
+	}
+
+	usuario, err := buscarUsuario(args[1])
+	if err != nil {
+		return err
+	}
+
+	stripeServico := servico.NovoStripeServico(repositorio.NovoUsuarioRepositorio(config.DB), repositorio.NovoAssinaturaRepositorio(config.DB))
+	assinatura, err := stripeServico.ReconciliarAssinatura(context.Background(), usuario.ID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Assinatura de %s reconciliada: %s (subscription %s)\n", usuario.Email, assinatura.Status, assinatura.StripeSubscriptionID)
+	if assinatura.DataProximaCobranca != nil {
+		fmt.Printf("   Próxima cobrança: %s\n", assinatura.DataProximaCobranca.Format("02/01/2006"))
+	}
+	return nil
+}
+
+func executarChave(args []string) error {
+	if len(args) != 1 || (args[0] != "status" && args[0] != "recriptografar") {
+		return errors.New("uso: ifinu chave status|recriptografar")
+	}
+
+	criptografiaServico := servico.NovoCriptografiaServico(repositorio.NovoStripeConfigRepositorio(config.DB), repositorio.NovoWebhookRepositorio(config.DB), slog.Default())
+
+	if args[0] == "recriptografar" {
+		resultado, err := criptografiaServico.RecriptografarSegredos(context.Background())
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✅ %d configuração(ões) Stripe e %d endpoint(s) de webhook recriptografados com a chave %s\n",
+			resultado.StripeConfigs, resultado.EndpointsWebhook, util.ObterChaveiro().IDAtual())
+		if resultado.Falhas > 0 {
+			return fmt.Errorf("%d segredo(s) não abrem com as chaves configuradas", resultado.Falhas)
+		}
+		return nil
+	}
+
+	contagem, err := criptografiaServico.ContarSegredosPorChave()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("🔐 Chave atual: %s\n", util.ObterChaveiro().IDAtual())
+	ids := make([]string, 0, len(contagem))
+	for id := range contagem {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		nome := id
+		if nome == "" {
+			nome = "(sem ID, anterior ao chaveiro)"
+		}
+		fmt.Printf("   %s: %d segredo(s)\n", nome, contagem[id])
+	}
+	return nil
+}
+
+func executarJobs(args []string) error {
+	if len(args) > 2 {
+		return errors.New("uso: ifinu jobs [job] [n]")
+	}
+
+	job, limite := "", 20
+	for _, arg := range args {
+		if n, err := strconv.Atoi(arg); err == nil {
+			if n <= 0 {
+				return fmt.Errorf("quantidade inválida: %s", arg)
+			}
+			limite = n
+		} else {
+			job = arg
+		}
+	}
+
+	execucaoJobServico := servico.NovoExecucaoJobServico(repositorio.NovoExecucaoJobRepositorio(config.DB), enderecoRedis(), slog.Default())
+	execucoes, err := execucaoJobServico.ListarExecucoes(job, limite)
+	if err != nil {
+		return err
+	}
+	if len(execucoes) == 0 {
+		fmt.Println("📭 Nenhuma execução registrada")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "JOB\tAGENDADO\tSTATUS\tDURAÇÃO\tINSTÂNCIA\tCONTAGENS")
+	for _, execucao := range execucoes {
+		contagens := execucao.Contagens
+		if execucao.Erro != "" {
+			contagens += " erro: " + execucao.Erro
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", execucao.Job, execucao.AgendadoPara.Format("02/01 15:04"),
+			execucao.Status, execucao.Duracao().Round(time.Millisecond), execucao.Instancia, contagens)
+	}
+	return w.Flush()
+}
+
+// buscarUsuario aceita o ID (UUID) ou o email da conta
+func buscarUsuario(identificador string) (*entidades.Usuario, error) {
+	usuarioRepo := repositorio.NovoUsuarioRepositorio(config.DB)
+
+	var usuario *entidades.Usuario
+	var err error
+	if id, errID := uuid.Parse(identificador); errID == nil {
+		usuario, err = usuarioRepo.BuscarPorID(id)
+	} else {
+		usuario, err = usuarioRepo.BuscarPorEmail(strings.TrimSpace(identificador))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("usuário não encontrado: %s", identificador)
+	}
+	return usuario, nil
+}
+
+func enderecoRedis() string {
+	redisAddr := viper.GetString("REDIS_ADDR")
+	if redisAddr == "" {
+		redisAddr = "localhost:6379" // Fallback para desenvolvimento
+	}
+	return redisAddr
+}
+
+func novoAssinaturaServico() *servico.AssinaturaServico {
+	usuarioRepo := repositorio.NovoUsuarioRepositorio(config.DB)
+	auditoriaServico := servico.NovoAuditoriaServico(repositorio.NovoAuditoriaRepositorio(config.DB), usuarioRepo)
+	return servico.NovoAssinaturaServico(repositorio.NovoAssinaturaRepositorio(config.DB), usuarioRepo, auditoriaServico)
+}
+
+func novoFilaMensagemServico(redisAddr string, webhookServico *servico.WebhookServico) *servico.FilaMensagemServico {
+	whatsappServico := novoWhatsAppServico(redisAddr, webhookServico)
+	return servico.NovoFilaMensagemServico(redisAddr, whatsappServico, integracao.NovoResendCliente(), repositorio.NovoCobrancaRepositorio(config.DB), slog.Default())
+}
+
+// novoAgendadorServico monta o agendador como a API, sem iniciar o cron nem os workers
+func novoAgendadorServico() *servico.AgendadorServico {
+	redisAddr := enderecoRedis()
+	usuarioRepo := repositorio.NovoUsuarioRepositorio(config.DB)
+	webhookServico := servico.NovoWebhookServico(repositorio.NovoWebhookRepositorio(config.DB), redisAddr, slog.Default())
+	auditoriaServico := servico.NovoAuditoriaServico(repositorio.NovoAuditoriaRepositorio(config.DB), usuarioRepo)
+	whatsappServico := novoWhatsAppServico(redisAddr, webhookServico)
+
+	return servico.NovoAgendadorServico(
+		repositorio.NovoCobrancaRepositorio(config.DB),
+		repositorio.NovoWhatsAppRepositorio(config.DB),
+		usuarioRepo,
+		repositorio.NovoAssinaturaRepositorio(config.DB),
+		repositorio.NovoRespostaAutomaticaRepositorio(config.DB),
+		integracao.NovoEvolutionAPICliente(),
+		integracao.NovoResendCliente(),
+		whatsappServico,
+		webhookServico,
+		auditoriaServico,
+		servico.NovoExecucaoJobServico(repositorio.NovoExecucaoJobRepositorio(config.DB), redisAddr, slog.Default()),
+		redisAddr,
+		slog.Default(),
+	)
+}
+
+func novoWhatsAppServico(redisAddr string, webhookServico *servico.WebhookServico) *servico.WhatsAppServico {
+	return servico.NovoWhatsAppServico(
+		repositorio.NovoWhatsAppRepositorio(config.DB),
+		repositorio.NovoUsuarioRepositorio(config.DB),
+		integracao.NovoEvolutionAPICliente(),
+		webhookServico,
+		servico.NovoLimitadorWhatsApp(redisAddr, slog.Default()),
+		slog.Default(),
+	)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/ifinu/ifinu-api-go/config"
+)
+
+const uso = `uso: ifinu <comando> [argumentos]
+
+  vitalicio conceder|revogar <email|id>  concede ou revoga o acesso vitalício da conta
+  trial estender <email|id> <dias>       reativa o trial da conta e o estende em dias
+  cobrancas vencidas [AAAA-MM-DD]        marca como vencidas as cobranças pendentes antes
+                                         da data (padrão: hoje), como o job das 23h
+  filas status [n]                       tamanho e primeiros n itens das filas Redis (padrão 10)
+  filas esvaziar whatsapp|webhooks --confirmar
+                                         descarta os itens pendentes da fila
+  stripe reconciliar <email|id>          corrige a assinatura a partir da subscription no Stripe
+  chave status                           quantos segredos cada chave de criptografia protege
+  chave recriptografar                   regrava com a chave atual os segredos de chaves antigas (após o rollout)
+  jobs [job] [n]                         últimas n execuções dos jobs agendados (padrão 20)`
+
+// ifinu é o CLI de operação: executa tarefas administrativas usando os mesmos
+// services da API, com a mesma configuração (.env e variáveis de ambiente)
+func main() {
+	if len(os.Args) < 2 || os.Args[1] == "-h" || os.Args[1] == "--help" || os.Args[1] == "help" {
+		fmt.Println(uso)
+		return
+	}
+
+	if err := config.CarregarConfiguracoes(); err != nil {
+		log.Fatalf("❌ Erro ao carregar configurações: %v", err)
+	}
+
+	if err := config.CarregarChaveiro(); err != nil {
+		log.Fatalf("❌ Erro nas chaves de criptografia: %v", err)
+	}
+
+	if err := config.ConectarBancoDados(); err != nil {
+		log.Fatalf("❌ Erro ao conectar ao banco: %v", err)
+	}
+
+	// Os comandos usam as entidades atuais: o schema precisa estar em dia
+	if err := config.VerificarMigracoes(); err != nil {
+		log.Fatalf("❌ %v. Execute: ifinu-api migrate up", err)
+	}
+
+	if err := executarComando(os.Args[1], os.Args[2:]); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+}
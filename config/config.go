@@ -2,6 +2,7 @@ package config
 
 import (
 	"log"
+	"strings"
 
 	"github.com/spf13/viper"
 )
@@ -21,6 +22,15 @@ func CarregarConfiguracoes() error {
 	viper.SetDefault("JWT_REFRESH_EXPIRATION_DAYS", 7)
 	viper.SetDefault("UPLOAD_DIR", "./uploads")
 	viper.SetDefault("MAX_UPLOAD_SIZE_MB", 10)
+	viper.SetDefault("SHUTDOWN_DRAIN_SECONDS", 5)
+	viper.SetDefault("SHUTDOWN_TIMEOUT_SECONDS", 20)
+	viper.SetDefault("HEALTH_CHECK_TIMEOUT_MS", 800)
+	viper.SetDefault("HEALTH_CHECK_EXTERNAL", false)
+	viper.SetDefault("LOG_LEVEL", "info")
+	viper.SetDefault("LOG_FORMAT", "json")
+	viper.SetDefault("OTEL_TRACES_EXPORTER", "none")
+	viper.SetDefault("OTEL_SERVICE_NAME", "ifinu-api")
+	viper.SetDefault("OTEL_TRACES_SAMPLER_ARG", 1.0)
 
 	if err := viper.ReadInConfig(); err != nil {
 		log.Printf("⚠️  Arquivo .env não encontrado, usando valores padrão: %v", err)
@@ -30,3 +40,15 @@ func CarregarConfiguracoes() error {
 	log.Println("✅ Configurações carregadas com sucesso")
 	return nil
 }
+
+// ProxiesConfiaveis lê TRUSTED_PROXIES (IPs ou CIDRs separados por vírgula). Só esses
+// proxies podem informar o IP do cliente em X-Forwarded-For; vazio, vale o IP da conexão.
+func ProxiesConfiaveis() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(viper.GetString("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
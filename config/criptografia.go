@@ -0,0 +1,34 @@
+package config
+
+import (
+	"errors"
+	"log"
+
+	"github.com/ifinu/ifinu-api-go/util"
+	"github.com/spf13/viper"
+)
+
+// CarregarChaveiro monta o chaveiro de criptografia (ENCRYPTION_KEYS, ENCRYPTION_KEY_ID e a
+// ENCRYPTION_KEY legada) e o torna o padrão de util.EncryptString. Em produção falha se a
+// chave atual for a padrão do código ou a do .env.example.
+func CarregarChaveiro() error {
+	chaveiro, err := util.MontarChaveiro(
+		viper.GetString("ENCRYPTION_KEYS"),
+		viper.GetString("ENCRYPTION_KEY_ID"),
+		viper.GetString("ENCRYPTION_KEY"),
+	)
+	if err != nil {
+		return err
+	}
+
+	if chaveiro.ChaveAtualInsegura() {
+		if viper.GetString("APP_ENV") == "production" {
+			return errors.New("chave de criptografia padrão em uso em produção: configure ENCRYPTION_KEYS ou ENCRYPTION_KEY")
+		}
+		log.Println("⚠️  Usando chave de criptografia padrão (apenas para desenvolvimento)")
+	}
+
+	util.DefinirChaveiro(chaveiro)
+	log.Printf("🔐 Chaveiro de criptografia carregado (chave atual: %s)", chaveiro.IDAtual())
+	return nil
+}
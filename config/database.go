@@ -5,6 +5,7 @@ import (
 	"log"
 
 	"github.com/ifinu/ifinu-api-go/dominio/entidades"
+	"github.com/ifinu/ifinu-api-go/rastreamento"
 	"github.com/spf13/viper"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -36,6 +37,11 @@ func ConectarBancoDados() error {
 
 	log.Println("✅ Conectado ao banco de dados PostgreSQL")
 
+	// Spans das consultas feitas com contexto rastreado
+	if err := rastreamento.RegistrarGORM(DB); err != nil {
+		return fmt.Errorf("erro ao registrar rastreamento do GORM: %w", err)
+	}
+
 	// Auto-migrate desabilitado - banco já existe do sistema Java
 	// Se necessário, executar migrations manualmente
 	log.Println("⚠️  Auto-migrate desabilitado - usando schema existente")
@@ -53,6 +59,16 @@ func MigrarTabelas() error {
 		&entidades.Cobranca{},
 		&entidades.AssinaturaUsuario{},
 		&entidades.WhatsAppConexao{},
+		&entidades.Organizacao{},
+		&entidades.MembroOrganizacao{},
+		&entidades.ChaveAPI{},
+		&entidades.EndpointWebhook{},
+		&entidades.EntregaWebhook{},
+		&entidades.RegistroAuditoria{},
+		&entidades.MensagemWhatsApp{},
+		&entidades.RespostaAutomatica{},
+		&entidades.ConfigRespostaAutomatica{},
+		&entidades.DisparoRespostaAutomatica{},
 	)
 
 	if err != nil {
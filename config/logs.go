@@ -0,0 +1,27 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/ifinu/ifinu-api-go/logs"
+	"github.com/spf13/viper"
+)
+
+// ConfigurarLogs cria o logger estruturado (LOG_LEVEL, LOG_FORMAT e LOG_MASK_PII) e o torna
+// o padrão do slog. Os log.Printf que restam passam a sair pelo mesmo handler, em JSON.
+// Emails e telefones são mascarados em produção, salvo LOG_MASK_PII=false.
+func ConfigurarLogs() *slog.Logger {
+	mascarar := viper.GetString("APP_ENV") == "production"
+	if viper.IsSet("LOG_MASK_PII") {
+		mascarar = viper.GetBool("LOG_MASK_PII")
+	}
+
+	logger := logs.Novo(os.Stdout, logs.Opcoes{
+		Nivel:                 logs.ParseNivel(viper.GetString("LOG_LEVEL")),
+		Formato:               viper.GetString("LOG_FORMAT"),
+		MascararDadosPessoais: mascarar,
+	})
+	slog.SetDefault(logger)
+	return logger
+}
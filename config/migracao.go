@@ -0,0 +1,245 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ifinu/ifinu-api-go/migrations"
+	"github.com/ifinu/ifinu-api-go/util"
+	"gorm.io/gorm"
+)
+
+// RegistroMigracao é uma linha de schema_migrations: uma migration aplicada
+type RegistroMigracao struct {
+	Versao        int64     `gorm:"primaryKey;autoIncrement:false"`
+	Nome          string    `gorm:"type:varchar(255);not null"`
+	Checksum      string    `gorm:"type:varchar(64);not null"`
+	DataAplicacao time.Time `gorm:"type:timestamp;not null"`
+}
+
+func (RegistroMigracao) TableName() string {
+	return "schema_migrations"
+}
+
+// StatusMigracao é uma migration embutida no binário e sua situação no banco
+type StatusMigracao struct {
+	util.Migracao
+	Aplicada      bool
+	DataAplicacao *time.Time
+	// Conteúdo do arquivo mudou depois de aplicada
+	Alterada bool
+}
+
+// ErrSchemaDesatualizado indica migrations embutidas ainda não aplicadas no banco
+var ErrSchemaDesatualizado = errors.New("schema do banco desatualizado")
+
+// Serializa as migrations entre réplicas (pg_advisory_lock)
+const lockMigracoes = "SELECT pg_advisory_lock(hashtext('ifinu:schema_migrations'))"
+const unlockMigracoes = "SELECT pg_advisory_unlock(hashtext('ifinu:schema_migrations'))"
+
+// StatusMigracoes lista as migrations embutidas com a situação de cada uma no banco
+func StatusMigracoes() ([]StatusMigracao, error) {
+	if err := criarTabelaMigracoes(DB); err != nil {
+		return nil, err
+	}
+	return statusMigracoes(DB)
+}
+
+// VerificarMigracoes retorna ErrSchemaDesatualizado se alguma migration não foi aplicada
+func VerificarMigracoes() error {
+	status, err := StatusMigracoes()
+	if err != nil {
+		return err
+	}
+
+	var pendentes []StatusMigracao
+	for _, s := range status {
+		if !s.Aplicada {
+			pendentes = append(pendentes, s)
+		}
+		if s.Alterada {
+			log.Printf("⚠️  Migration %03d_%s foi alterada depois de aplicada", s.Versao, s.Nome)
+		}
+	}
+
+	if len(pendentes) > 0 {
+		return fmt.Errorf("%w: %d migration(s) pendente(s), a primeira é %03d_%s",
+			ErrSchemaDesatualizado, len(pendentes), pendentes[0].Versao, pendentes[0].Nome)
+	}
+	return nil
+}
+
+// AplicarMigracoes aplica as migrations pendentes em ordem, cada uma em sua transação.
+// Retorna quantas foram aplicadas.
+func AplicarMigracoes() (int, error) {
+	aplicadas := 0
+	err := comLockMigracoes(func(conn *gorm.DB) error {
+		status, err := statusMigracoes(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, s := range status {
+			if s.Aplicada {
+				continue
+			}
+
+			log.Printf("🔄 Aplicando migration %03d_%s...", s.Versao, s.Nome)
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := executarSQL(tx, s.Up); err != nil {
+					return err
+				}
+				return tx.Create(&RegistroMigracao{
+					Versao:        s.Versao,
+					Nome:          s.Nome,
+					Checksum:      s.Checksum(),
+					DataAplicacao: time.Now(),
+				}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("erro na migration %03d_%s: %w", s.Versao, s.Nome, err)
+			}
+			aplicadas++
+		}
+		return nil
+	})
+	return aplicadas, err
+}
+
+// ReverterMigracoes desfaz as últimas migrations aplicadas usando os arquivos .down.sql.
+// Se alguma delas não tiver down (as anteriores à 012), nenhuma é revertida. Retorna quantas
+// foram revertidas.
+func ReverterMigracoes(quantidade int) (int, error) {
+	revertidas := 0
+	err := comLockMigracoes(func(conn *gorm.DB) error {
+		migracoes, err := util.LerMigracoes(migrations.Arquivos)
+		if err != nil {
+			return err
+		}
+		porVersao := make(map[int64]util.Migracao, len(migracoes))
+		for _, m := range migracoes {
+			porVersao[m.Versao] = m
+		}
+
+		var registros []RegistroMigracao
+		if err := conn.Order("versao DESC").Limit(quantidade).Find(&registros).Error; err != nil {
+			return err
+		}
+
+		// Conferir antes de começar, para não parar no meio do caminho
+		for _, registro := range registros {
+			if migracao, ok := porVersao[registro.Versao]; !ok || !migracao.TemDown() {
+				return fmt.Errorf("migration %03d_%s não tem down: o rollback só alcança migrations com arquivo .down.sql, nada foi revertido",
+					registro.Versao, registro.Nome)
+			}
+		}
+
+		for _, registro := range registros {
+			migracao := porVersao[registro.Versao]
+
+			log.Printf("⏪ Revertendo migration %03d_%s...", registro.Versao, registro.Nome)
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := executarSQL(tx, migracao.Down); err != nil {
+					return err
+				}
+				return tx.Delete(&RegistroMigracao{}, "versao = ?", registro.Versao).Error
+			})
+			if err != nil {
+				return fmt.Errorf("erro ao reverter %03d_%s: %w", registro.Versao, registro.Nome, err)
+			}
+			revertidas++
+		}
+		return nil
+	})
+	return revertidas, err
+}
+
+// MarcarMigracoesAte registra como aplicadas, sem executar, as migrations até a versão
+// informada. Usado uma vez em bancos onde elas já foram rodadas à mão (run_migration.sh).
+func MarcarMigracoesAte(versao int64) (int, error) {
+	marcadas := 0
+	err := comLockMigracoes(func(conn *gorm.DB) error {
+		status, err := statusMigracoes(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, s := range status {
+			if s.Aplicada || s.Versao > versao {
+				continue
+			}
+			err := conn.Create(&RegistroMigracao{
+				Versao:        s.Versao,
+				Nome:          s.Nome,
+				Checksum:      s.Checksum(),
+				DataAplicacao: time.Now(),
+			}).Error
+			if err != nil {
+				return err
+			}
+			marcadas++
+		}
+		return nil
+	})
+	return marcadas, err
+}
+
+// comLockMigracoes executa fn em uma conexão dedicada segurando o advisory lock das migrations
+func comLockMigracoes(fn func(conn *gorm.DB) error) error {
+	return DB.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec(lockMigracoes).Error; err != nil {
+			return fmt.Errorf("erro ao obter lock das migrations: %w", err)
+		}
+		defer conn.Exec(unlockMigracoes)
+
+		if err := criarTabelaMigracoes(conn); err != nil {
+			return err
+		}
+		return fn(conn)
+	})
+}
+
+func criarTabelaMigracoes(db *gorm.DB) error {
+	return db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		versao BIGINT PRIMARY KEY,
+		nome VARCHAR(255) NOT NULL,
+		checksum VARCHAR(64) NOT NULL,
+		data_aplicacao TIMESTAMP NOT NULL DEFAULT NOW()
+	)`).Error
+}
+
+func statusMigracoes(db *gorm.DB) ([]StatusMigracao, error) {
+	migracoes, err := util.LerMigracoes(migrations.Arquivos)
+	if err != nil {
+		return nil, err
+	}
+
+	var registros []RegistroMigracao
+	if err := db.Find(&registros).Error; err != nil {
+		return nil, err
+	}
+	aplicadas := make(map[int64]RegistroMigracao, len(registros))
+	for _, registro := range registros {
+		aplicadas[registro.Versao] = registro
+	}
+
+	status := make([]StatusMigracao, len(migracoes))
+	for i, migracao := range migracoes {
+		status[i].Migracao = migracao
+		if registro, ok := aplicadas[migracao.Versao]; ok {
+			data := registro.DataAplicacao
+			status[i].Aplicada = true
+			status[i].DataAplicacao = &data
+			status[i].Alterada = registro.Checksum != migracao.Checksum()
+		}
+	}
+	return status, nil
+}
+
+// executarSQL roda o arquivo inteiro direto na conexão (sem o tratamento de ? e @ do GORM)
+func executarSQL(tx *gorm.DB, sql string) error {
+	_, err := tx.Statement.ConnPool.ExecContext(tx.Statement.Context, sql)
+	return err
+}
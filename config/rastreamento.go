@@ -0,0 +1,20 @@
+package config
+
+import (
+	"context"
+
+	"github.com/ifinu/ifinu-api-go/rastreamento"
+	"github.com/spf13/viper"
+)
+
+// ConfigurarRastreamento configura o tracing OpenTelemetry (OTEL_TRACES_EXPORTER,
+// OTEL_SERVICE_NAME e OTEL_TRACES_SAMPLER_ARG). O padrão "none" não coleta nada.
+// A função retornada descarrega os spans pendentes no encerramento.
+func ConfigurarRastreamento(ctx context.Context) (func(context.Context) error, error) {
+	return rastreamento.Configurar(ctx, rastreamento.Opcoes{
+		Exportador:  viper.GetString("OTEL_TRACES_EXPORTER"),
+		NomeServico: viper.GetString("OTEL_SERVICE_NAME"),
+		Ambiente:    viper.GetString("APP_ENV"),
+		Amostragem:  viper.GetFloat64("OTEL_TRACES_SAMPLER_ARG"),
+	})
+}
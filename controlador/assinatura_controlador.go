@@ -22,13 +22,13 @@ func NovoAssinaturaControlador(assinaturaServico *servico.AssinaturaServico) *As
 // Status retorna o status da assinatura do usuário
 // GET /api/assinaturas/status
 func (ctrl *AssinaturaControlador) Status(c *gin.Context) {
-	email, exists := middleware.ObterEmailUsuario(c)
+	usuarioID, exists := middleware.ObterUsuarioID(c)
 	if !exists {
 		util.RespostaErro(c, http.StatusUnauthorized, "Usuário não autenticado", nil)
 		return
 	}
 
-	status, err := ctrl.assinaturaServico.ObterStatus(email)
+	status, err := ctrl.assinaturaServico.ObterStatus(usuarioID)
 	if err != nil {
 		util.RespostaErro(c, http.StatusInternalServerError, "Erro ao obter status da assinatura", err)
 		return
@@ -40,13 +40,13 @@ func (ctrl *AssinaturaControlador) Status(c *gin.Context) {
 // Cancelar cancela a assinatura do usuário
 // POST /api/assinaturas/cancelar
 func (ctrl *AssinaturaControlador) Cancelar(c *gin.Context) {
-	email, exists := middleware.ObterEmailUsuario(c)
+	usuarioID, exists := middleware.ObterUsuarioID(c)
 	if !exists {
 		util.RespostaErro(c, http.StatusUnauthorized, "Usuário não autenticado", nil)
 		return
 	}
 
-	err := ctrl.assinaturaServico.CancelarAssinatura(email)
+	err := ctrl.assinaturaServico.CancelarAssinatura(usuarioID)
 	if err != nil {
 		util.RespostaErro(c, http.StatusInternalServerError, "Erro ao cancelar assinatura", err)
 		return
@@ -0,0 +1,45 @@
+package controlador
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ifinu/ifinu-api-go/dto"
+	"github.com/ifinu/ifinu-api-go/middleware"
+	"github.com/ifinu/ifinu-api-go/servico"
+	"github.com/ifinu/ifinu-api-go/util"
+)
+
+type AuditoriaControlador struct {
+	auditoriaServico *servico.AuditoriaServico
+}
+
+func NovoAuditoriaControlador(auditoriaServico *servico.AuditoriaServico) *AuditoriaControlador {
+	return &AuditoriaControlador{
+		auditoriaServico: auditoriaServico,
+	}
+}
+
+// Buscar consulta a trilha de auditoria por entidade, ator e período
+// GET /api/auditoria?entidade=cobranca&entidadeId=...&dataInicio=...&dataFim=...
+func (ctrl *AuditoriaControlador) Buscar(c *gin.Context) {
+	usuarioID, exists := middleware.ObterUsuarioID(c)
+	if !exists {
+		util.RespostaErro(c, http.StatusUnauthorized, "Usuário não autenticado", nil)
+		return
+	}
+
+	var req dto.BuscarAuditoriaRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, "Parâmetros inválidos", err)
+		return
+	}
+
+	resultado, err := ctrl.auditoriaServico.Buscar(usuarioID, req)
+	if err != nil {
+		util.RespostaErro(c, http.StatusInternalServerError, "Erro ao consultar auditoria", err)
+		return
+	}
+
+	util.RespostaSucesso(c, "Registros de auditoria listados com sucesso", resultado)
+}
@@ -0,0 +1,40 @@
+package controlador
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ifinu/ifinu-api-go/integracao"
+	"github.com/ifinu/ifinu-api-go/servico"
+	"github.com/ifinu/ifinu-api-go/util"
+)
+
+type CEPControlador struct {
+	cepServico *servico.CEPServico
+}
+
+func NovoCEPControlador(cepServico *servico.CEPServico) *CEPControlador {
+	return &CEPControlador{
+		cepServico: cepServico,
+	}
+}
+
+// BuscarCEP retorna logradouro, bairro, cidade e estado de um CEP
+// GET /api/cep/:cep
+func (ctrl *CEPControlador) BuscarCEP(c *gin.Context) {
+	resultado, err := ctrl.cepServico.BuscarCEP(c.Param("cep"))
+	if err != nil {
+		switch {
+		case errors.Is(err, util.ErrCEPInvalido):
+			util.RespostaErro(c, http.StatusBadRequest, err.Error(), nil)
+		case errors.Is(err, integracao.ErrCEPNaoEncontrado):
+			util.RespostaErro(c, http.StatusNotFound, err.Error(), nil)
+		default:
+			util.RespostaErro(c, http.StatusBadGateway, "Erro ao consultar CEP", nil)
+		}
+		return
+	}
+
+	util.RespostaSucesso(c, "CEP encontrado", resultado)
+}
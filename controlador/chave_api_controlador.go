@@ -0,0 +1,89 @@
+package controlador
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/dto"
+	"github.com/ifinu/ifinu-api-go/middleware"
+	"github.com/ifinu/ifinu-api-go/servico"
+	"github.com/ifinu/ifinu-api-go/util"
+)
+
+type ChaveAPIControlador struct {
+	chaveAPIServico *servico.ChaveAPIServico
+}
+
+func NovoChaveAPIControlador(chaveAPIServico *servico.ChaveAPIServico) *ChaveAPIControlador {
+	return &ChaveAPIControlador{
+		chaveAPIServico: chaveAPIServico,
+	}
+}
+
+// Listar lista as chaves de API da conta
+// GET /api/chaves-api
+func (ctrl *ChaveAPIControlador) Listar(c *gin.Context) {
+	usuarioID, exists := middleware.ObterUsuarioID(c)
+	if !exists {
+		util.RespostaErro(c, http.StatusUnauthorized, "Usuário não autenticado", nil)
+		return
+	}
+
+	chaves, err := ctrl.chaveAPIServico.Listar(usuarioID)
+	if err != nil {
+		util.RespostaErro(c, http.StatusInternalServerError, "Erro ao listar chaves de API", err)
+		return
+	}
+
+	util.RespostaSucesso(c, "Chaves de API listadas com sucesso", chaves)
+}
+
+// Criar gera uma nova chave de API
+// POST /api/chaves-api
+func (ctrl *ChaveAPIControlador) Criar(c *gin.Context) {
+	usuarioID, exists := middleware.ObterUsuarioID(c)
+	if !exists {
+		util.RespostaErro(c, http.StatusUnauthorized, "Usuário não autenticado", nil)
+		return
+	}
+	atorID, _ := middleware.ObterAtorID(c)
+	papel, _ := middleware.ObterPapel(c)
+
+	var req dto.CriarChaveAPIRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, "Dados inválidos", err)
+		return
+	}
+
+	chave, err := ctrl.chaveAPIServico.Criar(usuarioID, atorID, papel, req)
+	if err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	util.RespostaCriado(c, "Chave de API criada. Guarde-a agora, ela não será exibida novamente", chave)
+}
+
+// Revogar revoga uma chave de API
+// DELETE /api/chaves-api/:id
+func (ctrl *ChaveAPIControlador) Revogar(c *gin.Context) {
+	usuarioID, exists := middleware.ObterUsuarioID(c)
+	if !exists {
+		util.RespostaErro(c, http.StatusUnauthorized, "Usuário não autenticado", nil)
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, "ID inválido", nil)
+		return
+	}
+
+	if err := ctrl.chaveAPIServico.Revogar(usuarioID, id); err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	util.RespostaSucesso(c, "Chave de API revogada com sucesso", nil)
+}
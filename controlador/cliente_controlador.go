@@ -1,6 +1,7 @@
 package controlador
 
 import (
+	"html"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -36,7 +37,7 @@ func (ctrl *ClienteControlador) Criar(c *gin.Context) {
 		return
 	}
 
-	resultado, err := ctrl.clienteServico.Criar(usuarioID, req)
+	resultado, err := ctrl.clienteServico.Criar(usuarioID, middleware.ObterAtor(c), req)
 	if err != nil {
 		util.RespostaErro(c, http.StatusBadRequest, err.Error(), nil)
 		return
@@ -122,7 +123,7 @@ func (ctrl *ClienteControlador) Atualizar(c *gin.Context) {
 		return
 	}
 
-	resultado, err := ctrl.clienteServico.Atualizar(usuarioID, id, req)
+	resultado, err := ctrl.clienteServico.Atualizar(usuarioID, middleware.ObterAtor(c), id, req)
 	if err != nil {
 		util.RespostaErro(c, http.StatusBadRequest, err.Error(), nil)
 		return
@@ -146,7 +147,7 @@ func (ctrl *ClienteControlador) Deletar(c *gin.Context) {
 		return
 	}
 
-	err = ctrl.clienteServico.Deletar(usuarioID, id)
+	err = ctrl.clienteServico.Deletar(usuarioID, middleware.ObterAtor(c), id)
 	if err != nil {
 		util.RespostaErro(c, http.StatusBadRequest, err.Error(), nil)
 		return
@@ -154,3 +155,111 @@ func (ctrl *ClienteControlador) Deletar(c *gin.Context) {
 
 	util.RespostaSucesso(c, "Cliente deletado com sucesso", nil)
 }
+
+// AtualizarPreferencias atualiza canais, horário de silêncio e opt-out de um cliente
+// PUT /api/clientes/:id/preferencias-notificacao
+func (ctrl *ClienteControlador) AtualizarPreferencias(c *gin.Context) {
+	usuarioID, exists := middleware.ObterUsuarioID(c)
+	if !exists {
+		util.RespostaErro(c, http.StatusUnauthorized, "Usuário não autenticado", nil)
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, "ID inválido", nil)
+		return
+	}
+
+	var req dto.PreferenciasNotificacaoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, "Dados inválidos", err)
+		return
+	}
+
+	resultado, err := ctrl.clienteServico.AtualizarPreferencias(usuarioID, middleware.ObterAtor(c), id, req)
+	if err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	util.RespostaSucesso(c, "Preferências de notificação atualizadas com sucesso", resultado)
+}
+
+// VerificarWhatsApp confere se o telefone do cliente tem conta no WhatsApp (resultado em cache;
+// ?forcar=true consulta de novo)
+// POST /api/clientes/:id/verificar-whatsapp
+func (ctrl *ClienteControlador) VerificarWhatsApp(c *gin.Context) {
+	usuarioID, exists := middleware.ObterUsuarioID(c)
+	if !exists {
+		util.RespostaErro(c, http.StatusUnauthorized, "Usuário não autenticado", nil)
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, "ID inválido", nil)
+		return
+	}
+
+	resultado, err := ctrl.clienteServico.VerificarWhatsApp(c.Request.Context(), usuarioID, id, c.Query("forcar") == "true")
+	if err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	util.RespostaSucesso(c, "Número verificado", resultado)
+}
+
+// ConfirmarDescadastro abre o link de descadastro dos emails. Só exibe a confirmação: os
+// verificadores de links dos provedores de email seguem todo GET, e o descadastro só
+// acontece no POST do formulário (ou no one-click do provedor).
+// GET /api/notificacoes/descadastrar?token=
+func (ctrl *ClienteControlador) ConfirmarDescadastro(c *gin.Context) {
+	token := c.Query("token")
+	if _, err := util.ValidarTokenDescadastro(token); err != nil {
+		paginaDescadastro(c, http.StatusBadRequest, "Não foi possível processar o descadastro: "+err.Error(), "")
+		return
+	}
+
+	paginaDescadastro(c, http.StatusOK, "Deseja deixar de receber os lembretes de cobrança?",
+		`<form method="post" action="/api/notificacoes/descadastrar">`+
+			`<input type="hidden" name="token" value="`+html.EscapeString(token)+`">`+
+			`<button type="submit" style="padding:12px 24px;font-size:16px">Confirmar descadastro</button></form>`)
+}
+
+// Descadastrar registra o opt-out (público, autenticado pelo token). Atende o formulário da
+// página de confirmação e o one-click (RFC 8058), em que o provedor de email envia
+// List-Unsubscribe=One-Click e só precisa do status.
+// POST /api/notificacoes/descadastrar?token=
+func (ctrl *ClienteControlador) Descadastrar(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		token = c.PostForm("token")
+	}
+	err := ctrl.clienteServico.DescadastrarPorToken(token)
+
+	if c.PostForm("List-Unsubscribe") == "One-Click" {
+		if err != nil {
+			util.RespostaErro(c, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
+		util.RespostaSucesso(c, "Descadastro realizado com sucesso", nil)
+		return
+	}
+
+	if err != nil {
+		paginaDescadastro(c, http.StatusBadRequest, "Não foi possível processar o descadastro: "+err.Error(), "")
+		return
+	}
+	paginaDescadastro(c, http.StatusOK, "Pronto! Você não receberá mais lembretes de cobrança.", "")
+}
+
+// paginaDescadastro responde a página simples de descadastro; conteudoHTML já deve estar escapado
+func paginaDescadastro(c *gin.Context, status int, mensagem, conteudoHTML string) {
+	c.Data(status, "text/html; charset=utf-8", []byte(
+		`<!DOCTYPE html><html lang="pt-BR"><head><meta charset="utf-8"><title>Descadastro</title></head>`+
+			`<body style="font-family:Arial,sans-serif;text-align:center;padding:48px;color:#333">`+
+			`<p>`+html.EscapeString(mensagem)+`</p>`+conteudoHTML+`</body></html>`,
+	))
+}
@@ -36,7 +36,7 @@ func (ctrl *CobrancaControlador) Criar(c *gin.Context) {
 		return
 	}
 
-	resultado, err := ctrl.cobrancaServico.Criar(usuarioID, req)
+	resultado, err := ctrl.cobrancaServico.Criar(usuarioID, middleware.ObterAtor(c), req)
 	if err != nil {
 		util.RespostaErro(c, http.StatusBadRequest, err.Error(), nil)
 		return
@@ -122,7 +122,7 @@ func (ctrl *CobrancaControlador) Atualizar(c *gin.Context) {
 		return
 	}
 
-	resultado, err := ctrl.cobrancaServico.Atualizar(usuarioID, id, req)
+	resultado, err := ctrl.cobrancaServico.Atualizar(usuarioID, middleware.ObterAtor(c), id, req)
 	if err != nil {
 		util.RespostaErro(c, http.StatusBadRequest, err.Error(), nil)
 		return
@@ -152,7 +152,7 @@ func (ctrl *CobrancaControlador) AtualizarStatus(c *gin.Context) {
 		return
 	}
 
-	resultado, err := ctrl.cobrancaServico.AtualizarStatus(usuarioID, id, req.Status)
+	resultado, err := ctrl.cobrancaServico.AtualizarStatus(usuarioID, middleware.ObterAtor(c), id, req.Status)
 	if err != nil {
 		util.RespostaErro(c, http.StatusBadRequest, err.Error(), nil)
 		return
@@ -176,7 +176,7 @@ func (ctrl *CobrancaControlador) Deletar(c *gin.Context) {
 		return
 	}
 
-	err = ctrl.cobrancaServico.Deletar(usuarioID, id)
+	err = ctrl.cobrancaServico.Deletar(usuarioID, middleware.ObterAtor(c), id)
 	if err != nil {
 		util.RespostaErro(c, http.StatusBadRequest, err.Error(), nil)
 		return
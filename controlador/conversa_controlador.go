@@ -0,0 +1,157 @@
+package controlador
+
+import (
+	"crypto/subtle"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ifinu/ifinu-api-go/dto"
+	"github.com/ifinu/ifinu-api-go/integracao"
+	"github.com/ifinu/ifinu-api-go/middleware"
+	"github.com/ifinu/ifinu-api-go/servico"
+	"github.com/ifinu/ifinu-api-go/util"
+	"github.com/spf13/viper"
+)
+
+type ConversaControlador struct {
+	conversaServico *servico.ConversaServico
+}
+
+func NovoConversaControlador(conversaServico *servico.ConversaServico) *ConversaControlador {
+	return &ConversaControlador{
+		conversaServico: conversaServico,
+	}
+}
+
+// WebhookEvolution recebe os eventos da Evolution API (mensagens recebidas)
+// POST /api/whatsapp/webhook
+func (ctrl *ConversaControlador) WebhookEvolution(c *gin.Context) {
+	var evento integracao.EventoWebhookEvolution
+	if err := c.ShouldBindJSON(&evento); err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, "Payload inválido", err)
+		return
+	}
+
+	if !webhookEvolutionAutorizado(c, &evento) {
+		util.RespostaErro(c, http.StatusUnauthorized, "Token do webhook inválido", nil)
+		return
+	}
+
+	if err := ctrl.conversaServico.ProcessarEventoEvolution(c.Request.Context(), &evento); err != nil {
+		log.Printf("❌ Erro ao processar evento da Evolution API (%s): %v", evento.Event, err)
+		util.RespostaErro(c, http.StatusInternalServerError, "Erro ao processar evento", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"received": true})
+}
+
+// webhookEvolutionAutorizado aceita o token configurado (query ?token=, header apikey
+// ou campo apikey do payload). Sem EVOLUTION_WEBHOOK_TOKEN, exige a chave global da Evolution.
+func webhookEvolutionAutorizado(c *gin.Context, evento *integracao.EventoWebhookEvolution) bool {
+	esperado := viper.GetString("EVOLUTION_WEBHOOK_TOKEN")
+	if esperado == "" {
+		esperado = viper.GetString("EVOLUTION_API_KEY")
+	}
+	if esperado == "" {
+		return false
+	}
+
+	for _, recebido := range []string{c.Query("token"), c.GetHeader("apikey"), evento.APIKey} {
+		if recebido != "" && subtle.ConstantTimeCompare([]byte(recebido), []byte(esperado)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// Listar lista as conversas da caixa de entrada com contagem de não lidas
+// GET /api/whatsapp/conversas
+func (ctrl *ConversaControlador) Listar(c *gin.Context) {
+	usuarioID, exists := middleware.ObterUsuarioID(c)
+	if !exists {
+		util.RespostaErro(c, http.StatusUnauthorized, "Usuário não autenticado", nil)
+		return
+	}
+
+	var req dto.BuscarConversasRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, "Parâmetros inválidos", err)
+		return
+	}
+
+	conversas, err := ctrl.conversaServico.ListarConversas(usuarioID, req)
+	if err != nil {
+		util.RespostaErro(c, http.StatusInternalServerError, "Erro ao listar conversas", err)
+		return
+	}
+
+	util.RespostaSucesso(c, "Conversas listadas com sucesso", conversas)
+}
+
+// ListarMensagens retorna o histórico de uma conversa
+// GET /api/whatsapp/conversas/:telefone/mensagens
+func (ctrl *ConversaControlador) ListarMensagens(c *gin.Context) {
+	usuarioID, exists := middleware.ObterUsuarioID(c)
+	if !exists {
+		util.RespostaErro(c, http.StatusUnauthorized, "Usuário não autenticado", nil)
+		return
+	}
+
+	var req dto.BuscarConversasRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, "Parâmetros inválidos", err)
+		return
+	}
+
+	mensagens, err := ctrl.conversaServico.ListarMensagens(usuarioID, c.Param("telefone"), req)
+	if err != nil {
+		util.RespostaErro(c, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+
+	util.RespostaSucesso(c, "Mensagens listadas com sucesso", mensagens)
+}
+
+// MarcarComoLida marca as mensagens recebidas da conversa como lidas
+// POST /api/whatsapp/conversas/:telefone/lida
+func (ctrl *ConversaControlador) MarcarComoLida(c *gin.Context) {
+	usuarioID, exists := middleware.ObterUsuarioID(c)
+	if !exists {
+		util.RespostaErro(c, http.StatusUnauthorized, "Usuário não autenticado", nil)
+		return
+	}
+
+	marcadas, err := ctrl.conversaServico.MarcarComoLida(usuarioID, c.Param("telefone"))
+	if err != nil {
+		util.RespostaErro(c, http.StatusInternalServerError, "Erro ao marcar conversa como lida", err)
+		return
+	}
+
+	util.RespostaSucesso(c, "Conversa marcada como lida", gin.H{"marcadas": marcadas})
+}
+
+// Responder envia uma resposta da equipe pela mesma instância da conversa
+// POST /api/whatsapp/conversas/:telefone/responder
+func (ctrl *ConversaControlador) Responder(c *gin.Context) {
+	usuarioID, exists := middleware.ObterUsuarioID(c)
+	if !exists {
+		util.RespostaErro(c, http.StatusUnauthorized, "Usuário não autenticado", nil)
+		return
+	}
+
+	var req dto.ResponderConversaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, "Dados inválidos", err)
+		return
+	}
+
+	mensagem, err := ctrl.conversaServico.Responder(c.Request.Context(), usuarioID, middleware.ObterAtor(c), c.Param("telefone"), req.Mensagem)
+	if err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	util.RespostaCriado(c, "Resposta enviada com sucesso", mensagem)
+}
@@ -0,0 +1,145 @@
+package controlador
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/dto"
+	"github.com/ifinu/ifinu-api-go/middleware"
+	"github.com/ifinu/ifinu-api-go/servico"
+	"github.com/ifinu/ifinu-api-go/util"
+)
+
+type OrganizacaoControlador struct {
+	organizacaoServico *servico.OrganizacaoServico
+}
+
+func NovoOrganizacaoControlador(organizacaoServico *servico.OrganizacaoServico) *OrganizacaoControlador {
+	return &OrganizacaoControlador{
+		organizacaoServico: organizacaoServico,
+	}
+}
+
+// Obter retorna a organização e seus membros
+// GET /api/organizacao
+func (ctrl *OrganizacaoControlador) Obter(c *gin.Context) {
+	usuarioID, exists := middleware.ObterUsuarioID(c)
+	if !exists {
+		util.RespostaErro(c, http.StatusUnauthorized, "Usuário não autenticado", nil)
+		return
+	}
+	papel, _ := middleware.ObterPapel(c)
+
+	resultado, err := ctrl.organizacaoServico.ObterOrganizacao(usuarioID, papel)
+	if err != nil {
+		util.RespostaErro(c, http.StatusInternalServerError, "Erro ao obter organização", err)
+		return
+	}
+
+	util.RespostaSucesso(c, "Organização obtida com sucesso", resultado)
+}
+
+// ConvidarMembro convida um novo membro por email
+// POST /api/organizacao/membros
+func (ctrl *OrganizacaoControlador) ConvidarMembro(c *gin.Context) {
+	usuarioID, exists := middleware.ObterUsuarioID(c)
+	if !exists {
+		util.RespostaErro(c, http.StatusUnauthorized, "Usuário não autenticado", nil)
+		return
+	}
+	papel, _ := middleware.ObterPapel(c)
+
+	var req dto.ConvidarMembroRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, "Dados inválidos", err)
+		return
+	}
+
+	resultado, err := ctrl.organizacaoServico.ConvidarMembro(c.Request.Context(), usuarioID, middleware.ObterAtor(c), papel, req)
+	if err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	util.RespostaCriado(c, "Convite enviado com sucesso", resultado)
+}
+
+// AlterarPapel altera o papel de um membro
+// PATCH /api/organizacao/membros/:id
+func (ctrl *OrganizacaoControlador) AlterarPapel(c *gin.Context) {
+	usuarioID, exists := middleware.ObterUsuarioID(c)
+	if !exists {
+		util.RespostaErro(c, http.StatusUnauthorized, "Usuário não autenticado", nil)
+		return
+	}
+	papel, _ := middleware.ObterPapel(c)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, "ID inválido", nil)
+		return
+	}
+
+	var req dto.AlterarPapelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, "Dados inválidos", err)
+		return
+	}
+
+	resultado, err := ctrl.organizacaoServico.AlterarPapel(usuarioID, middleware.ObterAtor(c), papel, id, req.Papel)
+	if err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	util.RespostaSucesso(c, "Papel atualizado com sucesso", resultado)
+}
+
+// RemoverMembro remove um membro ou cancela um convite
+// DELETE /api/organizacao/membros/:id
+func (ctrl *OrganizacaoControlador) RemoverMembro(c *gin.Context) {
+	usuarioID, exists := middleware.ObterUsuarioID(c)
+	if !exists {
+		util.RespostaErro(c, http.StatusUnauthorized, "Usuário não autenticado", nil)
+		return
+	}
+	papel, _ := middleware.ObterPapel(c)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, "ID inválido", nil)
+		return
+	}
+
+	if err := ctrl.organizacaoServico.RemoverMembro(usuarioID, middleware.ObterAtor(c), papel, id); err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	util.RespostaSucesso(c, "Membro removido com sucesso", nil)
+}
+
+// AceitarConvite vincula o usuário autenticado a um convite pendente
+// POST /api/organizacao/convites/aceitar
+func (ctrl *OrganizacaoControlador) AceitarConvite(c *gin.Context) {
+	email, exists := middleware.ObterEmailUsuario(c)
+	if !exists {
+		util.RespostaErro(c, http.StatusUnauthorized, "Usuário não autenticado", nil)
+		return
+	}
+
+	var req dto.AceitarConviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, "Dados inválidos", err)
+		return
+	}
+
+	resultado, err := ctrl.organizacaoServico.AceitarConvite(email, req.Token)
+	if err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	util.RespostaSucesso(c, "Convite aceito com sucesso", resultado)
+}
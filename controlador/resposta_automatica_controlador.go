@@ -0,0 +1,183 @@
+package controlador
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/dto"
+	"github.com/ifinu/ifinu-api-go/middleware"
+	"github.com/ifinu/ifinu-api-go/servico"
+	"github.com/ifinu/ifinu-api-go/util"
+)
+
+type RespostaAutomaticaControlador struct {
+	respostaAutomaticaServico *servico.RespostaAutomaticaServico
+}
+
+func NovoRespostaAutomaticaControlador(respostaAutomaticaServico *servico.RespostaAutomaticaServico) *RespostaAutomaticaControlador {
+	return &RespostaAutomaticaControlador{
+		respostaAutomaticaServico: respostaAutomaticaServico,
+	}
+}
+
+// ObterConfig retorna a configuração do auto-responder
+// GET /api/whatsapp/respostas-automaticas/config
+func (ctrl *RespostaAutomaticaControlador) ObterConfig(c *gin.Context) {
+	usuarioID, exists := middleware.ObterUsuarioID(c)
+	if !exists {
+		util.RespostaErro(c, http.StatusUnauthorized, "Usuário não autenticado", nil)
+		return
+	}
+
+	config, err := ctrl.respostaAutomaticaServico.ObterConfig(usuarioID)
+	if err != nil {
+		util.RespostaErro(c, http.StatusInternalServerError, "Erro ao buscar configuração", err)
+		return
+	}
+
+	util.RespostaSucesso(c, "Configuração encontrada", config)
+}
+
+// SalvarConfig ativa/desativa o auto-responder, define a resposta padrão e os dados do PIX
+// PUT /api/whatsapp/respostas-automaticas/config
+func (ctrl *RespostaAutomaticaControlador) SalvarConfig(c *gin.Context) {
+	usuarioID, exists := middleware.ObterUsuarioID(c)
+	if !exists {
+		util.RespostaErro(c, http.StatusUnauthorized, "Usuário não autenticado", nil)
+		return
+	}
+
+	var req dto.ConfigRespostaAutomaticaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, "Dados inválidos", err)
+		return
+	}
+
+	config, err := ctrl.respostaAutomaticaServico.SalvarConfig(usuarioID, middleware.ObterAtor(c), req)
+	if err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	util.RespostaSucesso(c, "Configuração salva com sucesso", config)
+}
+
+// Listar lista as regras do auto-responder
+// GET /api/whatsapp/respostas-automaticas
+func (ctrl *RespostaAutomaticaControlador) Listar(c *gin.Context) {
+	usuarioID, exists := middleware.ObterUsuarioID(c)
+	if !exists {
+		util.RespostaErro(c, http.StatusUnauthorized, "Usuário não autenticado", nil)
+		return
+	}
+
+	regras, err := ctrl.respostaAutomaticaServico.ListarRegras(usuarioID)
+	if err != nil {
+		util.RespostaErro(c, http.StatusInternalServerError, "Erro ao listar respostas automáticas", err)
+		return
+	}
+
+	util.RespostaSucesso(c, "Respostas automáticas listadas com sucesso", regras)
+}
+
+// Criar cadastra uma regra do auto-responder
+// POST /api/whatsapp/respostas-automaticas
+func (ctrl *RespostaAutomaticaControlador) Criar(c *gin.Context) {
+	usuarioID, exists := middleware.ObterUsuarioID(c)
+	if !exists {
+		util.RespostaErro(c, http.StatusUnauthorized, "Usuário não autenticado", nil)
+		return
+	}
+
+	var req dto.RespostaAutomaticaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, "Dados inválidos", err)
+		return
+	}
+
+	regra, err := ctrl.respostaAutomaticaServico.CriarRegra(usuarioID, middleware.ObterAtor(c), req)
+	if err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	util.RespostaCriado(c, "Resposta automática criada com sucesso", regra)
+}
+
+// Atualizar altera uma regra do auto-responder
+// PUT /api/whatsapp/respostas-automaticas/:id
+func (ctrl *RespostaAutomaticaControlador) Atualizar(c *gin.Context) {
+	usuarioID, exists := middleware.ObterUsuarioID(c)
+	if !exists {
+		util.RespostaErro(c, http.StatusUnauthorized, "Usuário não autenticado", nil)
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, "ID inválido", nil)
+		return
+	}
+
+	var req dto.RespostaAutomaticaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, "Dados inválidos", err)
+		return
+	}
+
+	regra, err := ctrl.respostaAutomaticaServico.AtualizarRegra(usuarioID, middleware.ObterAtor(c), id, req)
+	if err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	util.RespostaSucesso(c, "Resposta automática atualizada com sucesso", regra)
+}
+
+// Remover exclui uma regra do auto-responder
+// DELETE /api/whatsapp/respostas-automaticas/:id
+func (ctrl *RespostaAutomaticaControlador) Remover(c *gin.Context) {
+	usuarioID, exists := middleware.ObterUsuarioID(c)
+	if !exists {
+		util.RespostaErro(c, http.StatusUnauthorized, "Usuário não autenticado", nil)
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, "ID inválido", nil)
+		return
+	}
+
+	if err := ctrl.respostaAutomaticaServico.RemoverRegra(usuarioID, middleware.ObterAtor(c), id); err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	util.RespostaSucesso(c, "Resposta automática removida com sucesso", nil)
+}
+
+// ListarDisparos retorna o log de respostas automáticas enviadas
+// GET /api/whatsapp/respostas-automaticas/disparos
+func (ctrl *RespostaAutomaticaControlador) ListarDisparos(c *gin.Context) {
+	usuarioID, exists := middleware.ObterUsuarioID(c)
+	if !exists {
+		util.RespostaErro(c, http.StatusUnauthorized, "Usuário não autenticado", nil)
+		return
+	}
+
+	var req dto.BuscarDisparosRespostaAutomaticaRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, "Parâmetros inválidos", err)
+		return
+	}
+
+	disparos, err := ctrl.respostaAutomaticaServico.ListarDisparos(usuarioID, req)
+	if err != nil {
+		util.RespostaErro(c, http.StatusInternalServerError, "Erro ao listar respostas enviadas", err)
+		return
+	}
+
+	util.RespostaSucesso(c, "Respostas enviadas listadas com sucesso", disparos)
+}
@@ -0,0 +1,48 @@
+package controlador
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ifinu/ifinu-api-go/dto"
+	"github.com/ifinu/ifinu-api-go/servico"
+)
+
+type SaudeControlador struct {
+	saudeServico *servico.SaudeServico
+	encerrando   func() bool
+}
+
+// NovoSaudeControlador cria o controlador das probes; encerrando indica que a réplica
+// recebeu SIGTERM e deve sair do balanceamento
+func NovoSaudeControlador(saudeServico *servico.SaudeServico, encerrando func() bool) *SaudeControlador {
+	return &SaudeControlador{
+		saudeServico: saudeServico,
+		encerrando:   encerrando,
+	}
+}
+
+// Vivacidade é a liveness probe: 503 faz o Kubernetes reiniciar o pod
+// GET /health/live
+func (ctrl *SaudeControlador) Vivacidade(c *gin.Context) {
+	responderSaude(c, ctrl.saudeServico.Vivacidade(c.Request.Context()))
+}
+
+// Prontidao é a readiness probe: 503 tira a réplica do balanceamento
+// GET /health/ready (e /ready)
+func (ctrl *SaudeControlador) Prontidao(c *gin.Context) {
+	if ctrl.encerrando() {
+		c.JSON(http.StatusServiceUnavailable, dto.SaudeResponse{Status: servico.StatusSaudeEncerrando})
+		return
+	}
+	responderSaude(c, ctrl.saudeServico.Prontidao(c.Request.Context()))
+}
+
+// responderSaude responde 503 apenas quando uma verificação crítica falhou; degradado é 200
+func responderSaude(c *gin.Context, resposta dto.SaudeResponse) {
+	status := http.StatusOK
+	if resposta.Status == servico.StatusSaudeFalha {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, resposta)
+}
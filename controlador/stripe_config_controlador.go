@@ -56,7 +56,7 @@ func (ctrl *StripeConfigControlador) SalvarConfiguracao(c *gin.Context) {
 		return
 	}
 
-	err := ctrl.stripeConfigServico.SalvarConfiguracao(usuarioID, req)
+	err := ctrl.stripeConfigServico.SalvarConfiguracao(usuarioID, middleware.ObterAtor(c), req)
 	if err != nil {
 		util.RespostaErro(c, http.StatusBadRequest, err.Error(), nil)
 		return
@@ -72,7 +72,7 @@ func (ctrl *StripeConfigControlador) DeletarConfiguracao(c *gin.Context) {
 		return
 	}
 
-	err := ctrl.stripeConfigServico.DeletarConfiguracao(usuarioID)
+	err := ctrl.stripeConfigServico.DeletarConfiguracao(usuarioID, middleware.ObterAtor(c))
 	if err != nil {
 		util.RespostaErro(c, http.StatusBadRequest, err.Error(), nil)
 		return
@@ -2,8 +2,10 @@ package controlador
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/ifinu/ifinu-api-go/metricas"
 	"github.com/ifinu/ifinu-api-go/middleware"
 	"github.com/ifinu/ifinu-api-go/servico"
 	"github.com/ifinu/ifinu-api-go/util"
@@ -33,7 +35,7 @@ func (ctrl *StripeConnectControlador) CriarContaConnect(c *gin.Context) {
 	returnURL := baseURL + "/painel/configuracoes?stripe-connect=sucesso"
 	refreshURL := baseURL + "/painel/configuracoes?stripe-connect=refresh"
 
-	resultado, err := ctrl.stripeConnectServico.CriarContaConnect(usuarioID, returnURL, refreshURL)
+	resultado, err := ctrl.stripeConnectServico.CriarContaConnect(c.Request.Context(), usuarioID, returnURL, refreshURL)
 	if err != nil {
 		util.RespostaErro(c, http.StatusInternalServerError, "Erro ao criar conta Stripe Connect", err)
 		return
@@ -51,7 +53,7 @@ func (ctrl *StripeConnectControlador) ObterStatus(c *gin.Context) {
 		return
 	}
 
-	resultado, err := ctrl.stripeConnectServico.ObterStatusConnect(usuarioID)
+	resultado, err := ctrl.stripeConnectServico.ObterStatusConnect(c.Request.Context(), usuarioID)
 	if err != nil {
 		util.RespostaErro(c, http.StatusInternalServerError, "Erro ao obter status", err)
 		return
@@ -74,7 +76,7 @@ func (ctrl *StripeConnectControlador) RefreshOnboarding(c *gin.Context) {
 	returnURL := baseURL + "/painel/configuracoes?stripe-connect=sucesso"
 	refreshURL := baseURL + "/painel/configuracoes?stripe-connect=refresh"
 
-	resultado, err := ctrl.stripeConnectServico.GerarLinkOnboarding(usuarioID, returnURL, refreshURL)
+	resultado, err := ctrl.stripeConnectServico.GerarLinkOnboarding(c.Request.Context(), usuarioID, returnURL, refreshURL)
 	if err != nil {
 		util.RespostaErro(c, http.StatusInternalServerError, "Erro ao gerar link de onboarding", err)
 		return
@@ -92,7 +94,7 @@ func (ctrl *StripeConnectControlador) GerarDashboardLink(c *gin.Context) {
 		return
 	}
 
-	resultado, err := ctrl.stripeConnectServico.GerarDashboardLink(usuarioID)
+	resultado, err := ctrl.stripeConnectServico.GerarDashboardLink(c.Request.Context(), usuarioID)
 	if err != nil {
 		util.RespostaErro(c, http.StatusInternalServerError, "Erro ao gerar dashboard link", err)
 		return
@@ -135,13 +137,21 @@ func (ctrl *StripeConnectControlador) WebhookAccountUpdated(c *gin.Context) {
 		return
 	}
 
+	// O payload não é assinado: só eventos conhecidos viram label, para limitar as séries
+	inicio := time.Now()
+	evento, resultado := "outro", "falha"
+	defer func() { metricas.ObservarWebhookStripe(evento, resultado, inicio) }()
+
 	// Processar apenas eventos de account
 	if eventType != "account.updated" {
 		// Ignorar outros eventos
+		resultado = "ignorado"
 		util.RespostaSucesso(c, "Evento ignorado", nil)
 		return
 	}
 
+	evento = eventType
+
 	// Extrair dados
 	data, ok := payload["data"].(map[string]interface{})
 	if !ok {
@@ -165,6 +175,7 @@ func (ctrl *StripeConnectControlador) WebhookAccountUpdated(c *gin.Context) {
 		util.RespostaErro(c, http.StatusInternalServerError, "Erro ao processar webhook", err)
 		return
 	}
+	resultado = "sucesso"
 
 	util.RespostaSucesso(c, "Webhook processado com sucesso", nil)
 }
@@ -2,9 +2,11 @@ package controlador
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/ifinu/ifinu-api-go/dto"
+	"github.com/ifinu/ifinu-api-go/metricas"
 	"github.com/ifinu/ifinu-api-go/middleware"
 	"github.com/ifinu/ifinu-api-go/servico"
 	"github.com/ifinu/ifinu-api-go/util"
@@ -53,7 +55,7 @@ func (ctrl *StripeControlador) CreateCheckoutSession(c *gin.Context) {
 		return
 	}
 
-	resultado, err := ctrl.stripeServico.CriarCheckoutSession(usuarioID, &req)
+	resultado, err := ctrl.stripeServico.CriarCheckoutSession(c.Request.Context(), usuarioID, &req)
 	if err != nil {
 		// Log detalhado do erro
 		c.Error(err)
@@ -89,7 +91,7 @@ func (ctrl *StripeControlador) CriarCheckoutAssinatura(c *gin.Context) {
 		return
 	}
 
-	resultado, err := ctrl.stripeServico.CriarCheckoutAssinatura(usuarioID, req)
+	resultado, err := ctrl.stripeServico.CriarCheckoutAssinatura(c.Request.Context(), usuarioID, req)
 	if err != nil {
 		util.RespostaErro(c, http.StatusInternalServerError, "Erro ao criar checkout", err)
 		return
@@ -114,6 +116,11 @@ func (ctrl *StripeControlador) WebhookStripe(c *gin.Context) {
 		return
 	}
 
+	// O payload não é assinado: só eventos conhecidos viram label, para limitar as séries
+	inicio := time.Now()
+	evento, resultado := "outro", "falha"
+	defer func() { metricas.ObservarWebhookStripe(evento, resultado, inicio) }()
+
 	// Processar eventos do Stripe
 	data, ok := payload["data"].(map[string]interface{})
 	if !ok {
@@ -166,12 +173,19 @@ func (ctrl *StripeControlador) WebhookStripe(c *gin.Context) {
 		subscriptionID, _ := object["id"].(string)
 
 		err = ctrl.stripeServico.ProcessarSubscriptionCancelada(subscriptionID)
+
+	default:
+		resultado = "ignorado"
+		util.RespostaSucesso(c, "Webhook processado com sucesso", nil)
+		return
 	}
+	evento = eventType
 
 	if err != nil {
 		util.RespostaErro(c, http.StatusInternalServerError, "Erro ao processar webhook", err)
 		return
 	}
+	resultado = "sucesso"
 
 	util.RespostaSucesso(c, "Webhook processado com sucesso", nil)
 }
@@ -185,7 +199,7 @@ func (ctrl *StripeControlador) BuscarHistoricoFaturas(c *gin.Context) {
 		return
 	}
 
-	resultado, err := ctrl.stripeServico.BuscarHistoricoFaturas(usuarioID)
+	resultado, err := ctrl.stripeServico.BuscarHistoricoFaturas(c.Request.Context(), usuarioID)
 	if err != nil {
 		util.RespostaErro(c, http.StatusInternalServerError, "Erro ao buscar histórico", err)
 		return
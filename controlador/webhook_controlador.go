@@ -0,0 +1,170 @@
+package controlador
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/dto"
+	"github.com/ifinu/ifinu-api-go/middleware"
+	"github.com/ifinu/ifinu-api-go/servico"
+	"github.com/ifinu/ifinu-api-go/util"
+)
+
+type WebhookControlador struct {
+	webhookServico *servico.WebhookServico
+}
+
+func NovoWebhookControlador(webhookServico *servico.WebhookServico) *WebhookControlador {
+	return &WebhookControlador{
+		webhookServico: webhookServico,
+	}
+}
+
+// ListarEndpoints lista os endpoints de webhook da conta
+// GET /api/webhooks
+func (ctrl *WebhookControlador) ListarEndpoints(c *gin.Context) {
+	usuarioID, exists := middleware.ObterUsuarioID(c)
+	if !exists {
+		util.RespostaErro(c, http.StatusUnauthorized, "Usuário não autenticado", nil)
+		return
+	}
+
+	endpoints, err := ctrl.webhookServico.ListarEndpoints(usuarioID)
+	if err != nil {
+		util.RespostaErro(c, http.StatusInternalServerError, "Erro ao listar webhooks", err)
+		return
+	}
+
+	util.RespostaSucesso(c, "Webhooks listados com sucesso", endpoints)
+}
+
+// CriarEndpoint cadastra um endpoint de webhook
+// POST /api/webhooks
+func (ctrl *WebhookControlador) CriarEndpoint(c *gin.Context) {
+	usuarioID, exists := middleware.ObterUsuarioID(c)
+	if !exists {
+		util.RespostaErro(c, http.StatusUnauthorized, "Usuário não autenticado", nil)
+		return
+	}
+
+	var req dto.EndpointWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, "Dados inválidos", err)
+		return
+	}
+
+	endpoint, err := ctrl.webhookServico.CriarEndpoint(usuarioID, req)
+	if err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	util.RespostaCriado(c, "Webhook criado. Guarde o segredo agora, ele não será exibido novamente", endpoint)
+}
+
+// AtualizarEndpoint atualiza um endpoint de webhook
+// PUT /api/webhooks/:id
+func (ctrl *WebhookControlador) AtualizarEndpoint(c *gin.Context) {
+	usuarioID, exists := middleware.ObterUsuarioID(c)
+	if !exists {
+		util.RespostaErro(c, http.StatusUnauthorized, "Usuário não autenticado", nil)
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, "ID inválido", nil)
+		return
+	}
+
+	var req dto.EndpointWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, "Dados inválidos", err)
+		return
+	}
+
+	endpoint, err := ctrl.webhookServico.AtualizarEndpoint(usuarioID, id, req)
+	if err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	util.RespostaSucesso(c, "Webhook atualizado com sucesso", endpoint)
+}
+
+// RemoverEndpoint remove um endpoint de webhook
+// DELETE /api/webhooks/:id
+func (ctrl *WebhookControlador) RemoverEndpoint(c *gin.Context) {
+	usuarioID, exists := middleware.ObterUsuarioID(c)
+	if !exists {
+		util.RespostaErro(c, http.StatusUnauthorized, "Usuário não autenticado", nil)
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, "ID inválido", nil)
+		return
+	}
+
+	if err := ctrl.webhookServico.RemoverEndpoint(usuarioID, id); err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	util.RespostaSucesso(c, "Webhook removido com sucesso", nil)
+}
+
+// ListarEntregas retorna o log de entregas de um endpoint
+// GET /api/webhooks/:id/entregas
+func (ctrl *WebhookControlador) ListarEntregas(c *gin.Context) {
+	usuarioID, exists := middleware.ObterUsuarioID(c)
+	if !exists {
+		util.RespostaErro(c, http.StatusUnauthorized, "Usuário não autenticado", nil)
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, "ID inválido", nil)
+		return
+	}
+
+	var req dto.BuscarEntregasWebhookRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, "Parâmetros inválidos", err)
+		return
+	}
+
+	entregas, err := ctrl.webhookServico.ListarEntregas(usuarioID, id, req)
+	if err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	util.RespostaSucesso(c, "Entregas listadas com sucesso", entregas)
+}
+
+// Reentregar reenvia manualmente uma entrega
+// POST /api/webhooks/entregas/:entregaId/reenviar
+func (ctrl *WebhookControlador) Reentregar(c *gin.Context) {
+	usuarioID, exists := middleware.ObterUsuarioID(c)
+	if !exists {
+		util.RespostaErro(c, http.StatusUnauthorized, "Usuário não autenticado", nil)
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("entregaId"))
+	if err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, "ID inválido", nil)
+		return
+	}
+
+	if err := ctrl.webhookServico.Reentregar(usuarioID, id); err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	util.RespostaSucesso(c, "Entrega reenfileirada com sucesso", nil)
+}
@@ -2,6 +2,7 @@ package controlador
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/ifinu/ifinu-api-go/dto"
@@ -29,7 +30,16 @@ func (ctrl *WhatsAppControlador) Conectar(c *gin.Context) {
 		return
 	}
 
-	resultado, err := ctrl.whatsappServico.Conectar(usuarioID)
+	// Corpo opcional: sem nome, conecta o número "Principal"
+	var req dto.ConectarWhatsAppRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			util.RespostaErro(c, http.StatusBadRequest, "Dados inválidos", err)
+			return
+		}
+	}
+
+	resultado, err := ctrl.whatsappServico.Conectar(c.Request.Context(), usuarioID, req)
 	if err != nil {
 		util.RespostaErro(c, http.StatusBadRequest, err.Error(), nil)
 		return
@@ -47,7 +57,12 @@ func (ctrl *WhatsAppControlador) ObterStatus(c *gin.Context) {
 		return
 	}
 
-	resultado, err := ctrl.whatsappServico.ObterStatus(usuarioID)
+	conexaoID, ok := obterConexaoID(c)
+	if !ok {
+		return
+	}
+
+	resultado, err := ctrl.whatsappServico.ObterStatus(c.Request.Context(), usuarioID, conexaoID)
 	if err != nil {
 		util.RespostaErro(c, http.StatusInternalServerError, "Erro ao obter status", err)
 		return
@@ -65,7 +80,12 @@ func (ctrl *WhatsAppControlador) Desconectar(c *gin.Context) {
 		return
 	}
 
-	err := ctrl.whatsappServico.Desconectar(usuarioID)
+	conexaoID, ok := obterConexaoID(c)
+	if !ok {
+		return
+	}
+
+	err := ctrl.whatsappServico.Desconectar(c.Request.Context(), usuarioID, conexaoID)
 	if err != nil {
 		util.RespostaErro(c, http.StatusBadRequest, err.Error(), nil)
 		return
@@ -89,7 +109,7 @@ func (ctrl *WhatsAppControlador) EnviarMensagem(c *gin.Context) {
 		return
 	}
 
-	resultado, err := ctrl.whatsappServico.EnviarMensagem(usuarioID, req.Telefone, req.Mensagem)
+	resultado, err := ctrl.whatsappServico.EnviarMensagem(c.Request.Context(), usuarioID, req.ConexaoID, req.Telefone, req.Conteudo())
 	if err != nil {
 		util.RespostaErro(c, http.StatusBadRequest, err.Error(), nil)
 		return
@@ -113,7 +133,7 @@ func (ctrl *WhatsAppControlador) TestarConexao(c *gin.Context) {
 		return
 	}
 
-	resultado, err := ctrl.whatsappServico.EnviarMensagem(usuarioID, req.Telefone, req.Mensagem)
+	resultado, err := ctrl.whatsappServico.EnviarMensagem(c.Request.Context(), usuarioID, req.ConexaoID, req.Telefone, req.Conteudo())
 	if err != nil {
 		util.RespostaErro(c, http.StatusBadRequest, err.Error(), nil)
 		return
@@ -122,6 +142,77 @@ func (ctrl *WhatsAppControlador) TestarConexao(c *gin.Context) {
 	util.RespostaSucesso(c, resultado.Mensagem, resultado)
 }
 
+// ListarConexoes lista os números WhatsApp da conta
+// GET /api/whatsapp/conexoes
+func (ctrl *WhatsAppControlador) ListarConexoes(c *gin.Context) {
+	usuarioID, exists := middleware.ObterUsuarioID(c)
+	if !exists {
+		util.RespostaErro(c, http.StatusUnauthorized, "Usuário não autenticado", nil)
+		return
+	}
+
+	conexoes, err := ctrl.whatsappServico.ListarConexoes(usuarioID)
+	if err != nil {
+		util.RespostaErro(c, http.StatusInternalServerError, "Erro ao listar conexões", err)
+		return
+	}
+
+	util.RespostaSucesso(c, "Conexões listadas com sucesso", conexoes)
+}
+
+// AtualizarConexao altera nome, padrão e roteamento de um número WhatsApp
+// PUT /api/whatsapp/conexoes/:id
+func (ctrl *WhatsAppControlador) AtualizarConexao(c *gin.Context) {
+	usuarioID, exists := middleware.ObterUsuarioID(c)
+	if !exists {
+		util.RespostaErro(c, http.StatusUnauthorized, "Usuário não autenticado", nil)
+		return
+	}
+
+	conexaoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, "ID inválido", err)
+		return
+	}
+
+	var req dto.AtualizarConexaoWhatsAppRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, "Dados inválidos", err)
+		return
+	}
+
+	conexao, err := ctrl.whatsappServico.AtualizarConexao(usuarioID, conexaoID, req)
+	if err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	util.RespostaSucesso(c, "Conexão atualizada com sucesso", conexao)
+}
+
+// RemoverConexao desconecta e remove um número WhatsApp
+// DELETE /api/whatsapp/conexoes/:id
+func (ctrl *WhatsAppControlador) RemoverConexao(c *gin.Context) {
+	usuarioID, exists := middleware.ObterUsuarioID(c)
+	if !exists {
+		util.RespostaErro(c, http.StatusUnauthorized, "Usuário não autenticado", nil)
+		return
+	}
+
+	conexaoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, "ID inválido", err)
+		return
+	}
+
+	if err := ctrl.whatsappServico.Desconectar(c.Request.Context(), usuarioID, &conexaoID); err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	util.RespostaSucesso(c, "Conexão removida com sucesso", nil)
+}
+
 // ObterQRCode retorna o QR Code para conexão
 // GET /api/whatsapp/qrcode
 func (ctrl *WhatsAppControlador) ObterQRCode(c *gin.Context) {
@@ -131,7 +222,12 @@ func (ctrl *WhatsAppControlador) ObterQRCode(c *gin.Context) {
 		return
 	}
 
-	qrcode, err := ctrl.whatsappServico.ObterQRCode(usuarioID)
+	conexaoID, ok := obterConexaoID(c)
+	if !ok {
+		return
+	}
+
+	qrcode, err := ctrl.whatsappServico.ObterQRCode(c.Request.Context(), usuarioID, conexaoID)
 	if err != nil {
 		util.RespostaErro(c, http.StatusNotFound, err.Error(), nil)
 		return
@@ -149,7 +245,7 @@ func (ctrl *WhatsAppControlador) LimparOrfaos(c *gin.Context) {
 		return
 	}
 
-	resultado, err := ctrl.whatsappServico.LimparOrfaos(usuarioID)
+	resultado, err := ctrl.whatsappServico.LimparOrfaos(c.Request.Context(), usuarioID)
 	if err != nil {
 		util.RespostaErro(c, http.StatusInternalServerError, "Erro ao limpar órfãos", err)
 		return
@@ -167,7 +263,12 @@ func (ctrl *WhatsAppControlador) ObterEstatisticas(c *gin.Context) {
 		return
 	}
 
-	estatisticas, err := ctrl.whatsappServico.ObterEstatisticas(usuarioID)
+	conexaoID, ok := obterConexaoID(c)
+	if !ok {
+		return
+	}
+
+	estatisticas, err := ctrl.whatsappServico.ObterEstatisticas(c.Request.Context(), usuarioID, conexaoID)
 	if err != nil {
 		util.RespostaErro(c, http.StatusInternalServerError, "Erro ao obter estatísticas", err)
 		return
@@ -175,3 +276,18 @@ func (ctrl *WhatsAppControlador) ObterEstatisticas(c *gin.Context) {
 
 	util.RespostaSucesso(c, "Estatísticas obtidas com sucesso", estatisticas)
 }
+
+// obterConexaoID lê o parâmetro opcional ?conexaoId= (sem ele, usa a conexão padrão)
+func obterConexaoID(c *gin.Context) (*int64, bool) {
+	valor := c.Query("conexaoId")
+	if valor == "" {
+		return nil, true
+	}
+
+	conexaoID, err := strconv.ParseInt(valor, 10, 64)
+	if err != nil {
+		util.RespostaErro(c, http.StatusBadRequest, "conexaoId inválido", err)
+		return nil, false
+	}
+	return &conexaoID, true
+}
@@ -0,0 +1,72 @@
+package entidades
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/dominio/enums"
+)
+
+// PrefixoChaveAPI identifica chaves de API no header Authorization
+const PrefixoChaveAPI = "ifk_"
+
+// ChaveAPI é uma credencial de longa duração para integrações servidor a servidor.
+// Apenas o hash SHA-256 da chave é armazenado; o prefixo permite identificá-la na interface.
+type ChaveAPI struct {
+	ID            uuid.UUID         `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UsuarioID     uuid.UUID         `gorm:"type:uuid;not null;index" json:"usuarioId"`
+	CriadoPorID   uuid.UUID         `gorm:"type:uuid;not null" json:"criadoPorId"`
+	Nome          string            `gorm:"type:varchar(100);not null" json:"nome"`
+	Prefixo       string            `gorm:"type:varchar(20);not null" json:"prefixo"`
+	Hash          string            `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	Escopos       string            `gorm:"type:text;not null" json:"escopos"`
+	Papel         enums.PapelMembro `gorm:"type:varchar(20);not null" json:"papel"`
+	UltimoUso     *time.Time        `gorm:"type:timestamp" json:"ultimoUso"`
+	DataExpiracao *time.Time        `gorm:"type:timestamp" json:"dataExpiracao"`
+	DataRevogacao *time.Time        `gorm:"type:timestamp" json:"dataRevogacao"`
+	DataCriacao   time.Time         `gorm:"autoCreateTime" json:"dataCriacao"`
+
+	// Relacionamentos
+	Usuario Usuario `gorm:"foreignKey:UsuarioID" json:"-"`
+}
+
+// TableName sobrescreve o nome da tabela
+func (ChaveAPI) TableName() string {
+	return "chaves_api"
+}
+
+// IsExpirada verifica se a chave passou da data de expiração
+func (k *ChaveAPI) IsExpirada() bool {
+	return k.DataExpiracao != nil && time.Now().After(*k.DataExpiracao)
+}
+
+// IsRevogada verifica se a chave foi revogada
+func (k *ChaveAPI) IsRevogada() bool {
+	return k.DataRevogacao != nil
+}
+
+// IsValida verifica se a chave pode ser usada para autenticar
+func (k *ChaveAPI) IsValida() bool {
+	return !k.IsRevogada() && !k.IsExpirada()
+}
+
+// ListaEscopos retorna os escopos da chave
+func (k *ChaveAPI) ListaEscopos() []enums.EscopoAPI {
+	var escopos []enums.EscopoAPI
+	for _, e := range strings.Split(k.Escopos, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			escopos = append(escopos, enums.EscopoAPI(e))
+		}
+	}
+	return escopos
+}
+
+// DefinirEscopos armazena os escopos da chave
+func (k *ChaveAPI) DefinirEscopos(escopos []enums.EscopoAPI) {
+	partes := make([]string, len(escopos))
+	for i, e := range escopos {
+		partes[i] = string(e)
+	}
+	k.Escopos = strings.Join(partes, ",")
+}
@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/dominio/enums"
+	"github.com/ifinu/ifinu-api-go/util"
 )
 
 type Cliente struct {
@@ -12,17 +14,31 @@ type Cliente struct {
 	Nome            string    `gorm:"type:varchar(255);not null" json:"nome" validate:"required"`
 	Email           string    `gorm:"type:varchar(255)" json:"email" validate:"omitempty,email"`
 	Telefone        string    `gorm:"type:varchar(20)" json:"telefone"`
-	CPF             *string   `gorm:"type:varchar(14)" json:"cpf"`
-	CNPJ            *string   `gorm:"type:varchar(18)" json:"cnpj"`
+	CPF             *string   `gorm:"type:varchar(14)" json:"cpf"`  // sem máscara
+	CNPJ            *string   `gorm:"type:varchar(18)" json:"cnpj"` // sem máscara, pode ser alfanumérico
 	Endereco        string    `gorm:"type:varchar(255)" json:"endereco"`
 	Cidade          string    `gorm:"type:varchar(100)" json:"cidade"`
 	Estado          string    `gorm:"type:varchar(2)" json:"estado"`
-	CEP             string    `gorm:"type:varchar(10)" json:"cep"`
+	CEP             string    `gorm:"type:varchar(10)" json:"cep"` // 8 dígitos, sem máscara
 	Observacoes     string    `gorm:"type:text" json:"observacoes"`
 	Ativo           bool      `gorm:"type:boolean;not null;default:true" json:"ativo"`
 	DataCriacao     time.Time `gorm:"autoCreateTime" json:"dataCriacao"`
 	DataAtualizacao time.Time `gorm:"autoUpdateTime" json:"dataAtualizacao"`
 
+	// Número WhatsApp preferencial do cliente (nil = roteamento padrão da conta)
+	WhatsAppConexaoID *int64 `gorm:"column:whatsapp_conexao_id;index" json:"whatsappConexaoId"`
+
+	// Preferências de notificação (LGPD). Com DataOptOut preenchida nenhum lembrete é enviado.
+	CanalNotificacao enums.CanalNotificacao `gorm:"type:varchar(10);not null;default:'TODOS'" json:"canalNotificacao"`
+	SilencioInicio   *string                `gorm:"type:varchar(5)" json:"silencioInicio"`
+	SilencioFim      *string                `gorm:"type:varchar(5)" json:"silencioFim"`
+	DataOptOut       *time.Time             `gorm:"type:timestamp" json:"dataOptOut"`
+	OrigemOptOut     enums.OrigemOptOut     `gorm:"type:varchar(20)" json:"origemOptOut"`
+
+	// Resultado da última verificação do número na Evolution API (nil = não verificado)
+	WhatsAppValido          *bool      `gorm:"column:whatsapp_valido" json:"whatsappValido"`
+	DataVerificacaoWhatsApp *time.Time `gorm:"column:data_verificacao_whatsapp;type:timestamp" json:"dataVerificacaoWhatsapp"`
+
 	// Relacionamentos
 	Usuario   Usuario    `gorm:"foreignKey:UsuarioID" json:"-"`
 	Cobrancas []Cobranca `gorm:"foreignKey:ClienteID" json:"-"`
@@ -33,13 +49,73 @@ func (Cliente) TableName() string {
 	return "clientes"
 }
 
-// FormatarTelefone formata o telefone para envio WhatsApp
+// FormatarTelefone retorna o telefone em E.164 (ex: +5511999999999).
+// Cadastros antigos que não passam na validação são devolvidos como estão.
 func (c *Cliente) FormatarTelefone() string {
-	// Remove caracteres especiais e deixa só números
-	telefone := c.Telefone
+	telefone, err := util.NormalizarTelefone(c.Telefone)
+	if err != nil {
+		return c.Telefone
+	}
+	return telefone
+}
 
-	// Lógica de formatação pode ser adicionada aqui
-	// Por exemplo: +5511999999999
+// CPFFormatado retorna o CPF com máscara (000.000.000-00)
+func (c *Cliente) CPFFormatado() *string {
+	if c.CPF == nil {
+		return nil
+	}
+	cpf := util.FormatarCPF(*c.CPF)
+	return &cpf
+}
 
-	return telefone
+// CNPJFormatado retorna o CNPJ com máscara (00.000.000/0000-00)
+func (c *Cliente) CNPJFormatado() *string {
+	if c.CNPJ == nil {
+		return nil
+	}
+	cnpj := util.FormatarCNPJ(*c.CNPJ)
+	return &cnpj
+}
+
+// CEPFormatado retorna o CEP com máscara (00000-000)
+func (c *Cliente) CEPFormatado() string {
+	return util.FormatarCEP(c.CEP)
+}
+
+// WhatsAppVerificadoDesde indica se a verificação do número no WhatsApp foi feita depois de limite
+func (c *Cliente) WhatsAppVerificadoDesde(limite time.Time) bool {
+	return c.WhatsAppValido != nil && c.DataVerificacaoWhatsApp != nil && c.DataVerificacaoWhatsApp.After(limite)
+}
+
+// RecebeLembretes verifica se o cliente aceita lembretes automáticos por algum canal
+func (c *Cliente) RecebeLembretes() bool {
+	return c.DataOptOut == nil && c.CanalNotificacao != enums.CanalNotificacaoNenhum
+}
+
+// RecebePorWhatsApp verifica se os lembretes podem ser enviados por WhatsApp
+func (c *Cliente) RecebePorWhatsApp() bool {
+	return c.RecebeLembretes() && c.CanalNotificacao != enums.CanalNotificacaoEmail
+}
+
+// RecebePorEmail verifica se os lembretes podem ser enviados por email
+func (c *Cliente) RecebePorEmail() bool {
+	return c.RecebeLembretes() && c.CanalNotificacao != enums.CanalNotificacaoWhatsApp && c.Email != ""
+}
+
+// FimDoSilencio retorna quando termina o horário de silêncio do cliente, se estiver em andamento
+func (c *Cliente) FimDoSilencio(agora time.Time) (time.Time, bool) {
+	if c.SilencioInicio == nil || c.SilencioFim == nil {
+		return time.Time{}, false
+	}
+	return util.JanelaSilencio{Inicio: *c.SilencioInicio, Fim: *c.SilencioFim}.FimDoSilencio(agora)
+}
+
+// RegistrarOptOut marca que o cliente não quer mais receber lembretes
+func (c *Cliente) RegistrarOptOut(origem enums.OrigemOptOut) {
+	if c.DataOptOut != nil {
+		return
+	}
+	agora := time.Now()
+	c.DataOptOut = &agora
+	c.OrigemOptOut = origem
 }
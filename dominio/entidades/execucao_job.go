@@ -0,0 +1,43 @@
+package entidades
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type StatusExecucaoJob string
+
+const (
+	StatusExecucaoEmAndamento StatusExecucaoJob = "EM_ANDAMENTO"
+	StatusExecucaoSucesso     StatusExecucaoJob = "SUCESSO"
+	StatusExecucaoFalha       StatusExecucaoJob = "FALHA"
+)
+
+// ExecucaoJob registra uma execução de job agendado. Só existe uma por job e horário
+// (AgendadoPara), o que impede que duas réplicas rodem o mesmo disparo do cron.
+type ExecucaoJob struct {
+	ID           uuid.UUID         `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Job          string            `gorm:"type:varchar(100);not null" json:"job"`
+	AgendadoPara time.Time         `gorm:"not null" json:"agendadoPara"`
+	Instancia    string            `gorm:"type:varchar(255);not null" json:"instancia"`
+	TokenFencing int64             `gorm:"not null;default:0" json:"tokenFencing"`
+	Status       StatusExecucaoJob `gorm:"type:varchar(20);not null" json:"status"`
+	Contagens    string            `gorm:"type:jsonb;not null;default:'{}'" json:"contagens"`
+	Erro         string            `gorm:"type:text" json:"erro,omitempty"`
+	DataInicio   time.Time         `gorm:"not null" json:"dataInicio"`
+	DataFim      *time.Time        `json:"dataFim,omitempty"`
+}
+
+// TableName sobrescreve o nome da tabela
+func (ExecucaoJob) TableName() string {
+	return "execucoes_job"
+}
+
+// Duracao retorna quanto a execução levou (até agora, se ainda estiver em andamento)
+func (e *ExecucaoJob) Duracao() time.Duration {
+	if e.DataFim == nil {
+		return time.Since(e.DataInicio)
+	}
+	return e.DataFim.Sub(e.DataInicio)
+}
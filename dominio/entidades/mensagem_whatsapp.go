@@ -0,0 +1,50 @@
+package entidades
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DirecaoMensagem string
+
+const (
+	DirecaoMensagemEntrada DirecaoMensagem = "ENTRADA"
+	DirecaoMensagemSaida   DirecaoMensagem = "SAIDA"
+)
+
+// MensagemWhatsApp é uma mensagem da caixa de entrada do WhatsApp. As conversas
+// são agrupadas pelo telefone do contato; o vínculo com cliente e cobrança é
+// resolvido no recebimento.
+type MensagemWhatsApp struct {
+	ID           uuid.UUID       `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UsuarioID    uuid.UUID       `gorm:"type:uuid;not null;index:idx_whatsapp_mensagens_conversa" json:"usuarioId"`
+	ConexaoID    int64           `gorm:"not null;uniqueIndex:idx_whatsapp_mensagens_externo" json:"conexaoId"`
+	Telefone     string          `gorm:"type:varchar(20);not null;index:idx_whatsapp_mensagens_conversa" json:"telefone"`
+	ClienteID    *uuid.UUID      `gorm:"type:uuid;index" json:"clienteId"`
+	CobrancaID   *uuid.UUID      `gorm:"type:uuid" json:"cobrancaId"`
+	Direcao      DirecaoMensagem `gorm:"type:varchar(10);not null" json:"direcao"`
+	NomeContato  string          `gorm:"type:varchar(255)" json:"nomeContato"`
+	Texto        string          `gorm:"type:text" json:"texto"`
+	TipoMensagem string          `gorm:"type:varchar(50)" json:"tipoMensagem"`
+	IDExterno    string          `gorm:"column:id_externo;type:varchar(100);not null;uniqueIndex:idx_whatsapp_mensagens_externo" json:"idExterno"`
+	Lida         bool            `gorm:"not null;default:false" json:"lida"`
+	DataLeitura  *time.Time      `gorm:"type:timestamp" json:"dataLeitura"`
+	EnviadoPorID *uuid.UUID      `gorm:"type:uuid" json:"enviadoPorId"`
+	DataMensagem time.Time       `gorm:"type:timestamp;not null" json:"dataMensagem"`
+	DataCriacao  time.Time       `gorm:"autoCreateTime" json:"dataCriacao"`
+
+	// Relacionamentos
+	Usuario Usuario  `gorm:"foreignKey:UsuarioID" json:"-"`
+	Cliente *Cliente `gorm:"foreignKey:ClienteID" json:"-"`
+}
+
+// TableName sobrescreve o nome da tabela
+func (MensagemWhatsApp) TableName() string {
+	return "whatsapp_mensagens"
+}
+
+// IsEntrada verifica se a mensagem foi enviada pelo contato
+func (m *MensagemWhatsApp) IsEntrada() bool {
+	return m.Direcao == DirecaoMensagemEntrada
+}
@@ -0,0 +1,85 @@
+package entidades
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/dominio/enums"
+)
+
+type StatusMembro string
+
+const (
+	StatusMembroConvidado StatusMembro = "CONVIDADO"
+	StatusMembroAtivo     StatusMembro = "ATIVO"
+	StatusMembroRemovido  StatusMembro = "REMOVIDO"
+)
+
+// Organizacao agrupa os membros que compartilham a conta de um usuário titular.
+// Os dados (clientes, cobranças, WhatsApp...) continuam vinculados ao usuario_id
+// do titular, que funciona como identificador da conta.
+type Organizacao struct {
+	ID              uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TitularID       uuid.UUID `gorm:"type:uuid;uniqueIndex;not null" json:"titularId"`
+	Nome            string    `gorm:"type:varchar(255);not null" json:"nome"`
+	DataCriacao     time.Time `gorm:"autoCreateTime" json:"dataCriacao"`
+	DataAtualizacao time.Time `gorm:"autoUpdateTime" json:"dataAtualizacao"`
+
+	// Relacionamentos
+	Titular Usuario             `gorm:"foreignKey:TitularID" json:"-"`
+	Membros []MembroOrganizacao `gorm:"foreignKey:OrganizacaoID" json:"-"`
+}
+
+// TableName sobrescreve o nome da tabela
+func (Organizacao) TableName() string {
+	return "organizacoes"
+}
+
+type MembroOrganizacao struct {
+	ID              uuid.UUID         `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	OrganizacaoID   uuid.UUID         `gorm:"type:uuid;not null;index" json:"organizacaoId"`
+	UsuarioID       *uuid.UUID        `gorm:"type:uuid;index" json:"usuarioId"`
+	Email           string            `gorm:"type:varchar(255);not null" json:"email"`
+	Papel           enums.PapelMembro `gorm:"type:varchar(20);not null" json:"papel"`
+	Status          StatusMembro      `gorm:"type:varchar(20);not null;default:'CONVIDADO'" json:"status"`
+	TokenConvite    string            `gorm:"type:varchar(64);index" json:"-"`
+	ConvidadoPorID  *uuid.UUID        `gorm:"type:uuid" json:"convidadoPorId"`
+	DataConvite     *time.Time        `gorm:"type:timestamp" json:"dataConvite"`
+	DataAceite      *time.Time        `gorm:"type:timestamp" json:"dataAceite"`
+	DataCriacao     time.Time         `gorm:"autoCreateTime" json:"dataCriacao"`
+	DataAtualizacao time.Time         `gorm:"autoUpdateTime" json:"dataAtualizacao"`
+
+	// Relacionamentos
+	Organizacao Organizacao `gorm:"foreignKey:OrganizacaoID" json:"-"`
+	Usuario     *Usuario    `gorm:"foreignKey:UsuarioID" json:"-"`
+}
+
+// TableName sobrescreve o nome da tabela
+func (MembroOrganizacao) TableName() string {
+	return "membros_organizacao"
+}
+
+// IsAtivo verifica se o membro está ativo
+func (m *MembroOrganizacao) IsAtivo() bool {
+	return m.Status == StatusMembroAtivo
+}
+
+// IsTitular verifica se o membro é o dono da organização
+func (m *MembroOrganizacao) IsTitular() bool {
+	return m.Papel == enums.PapelOwner
+}
+
+// AceitarConvite vincula o usuário ao convite e ativa o membro
+func (m *MembroOrganizacao) AceitarConvite(usuarioID uuid.UUID) {
+	agora := time.Now()
+	m.UsuarioID = &usuarioID
+	m.Status = StatusMembroAtivo
+	m.DataAceite = &agora
+	m.TokenConvite = ""
+}
+
+// Remover marca o membro como removido
+func (m *MembroOrganizacao) Remover() {
+	m.Status = StatusMembroRemovido
+	m.TokenConvite = ""
+}
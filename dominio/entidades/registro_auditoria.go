@@ -0,0 +1,27 @@
+package entidades
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/dominio/enums"
+)
+
+// RegistroAuditoria é uma linha da trilha de auditoria (somente inserção).
+// Alteracoes guarda o diff em JSON no formato {"campo": {"antes": ..., "depois": ...}}.
+type RegistroAuditoria struct {
+	ID          uuid.UUID           `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UsuarioID   uuid.UUID           `gorm:"type:uuid;not null;index" json:"usuarioId"`
+	AtorID      *uuid.UUID          `gorm:"type:uuid" json:"atorId"`
+	Acao        enums.AcaoAuditoria `gorm:"type:varchar(20);not null" json:"acao"`
+	Entidade    string              `gorm:"type:varchar(50);not null" json:"entidade"`
+	EntidadeID  string              `gorm:"type:varchar(64);not null" json:"entidadeId"`
+	Alteracoes  string              `gorm:"type:jsonb;not null;default:'{}'" json:"alteracoes"`
+	IP          string              `gorm:"type:varchar(45)" json:"ip"`
+	DataCriacao time.Time           `gorm:"autoCreateTime" json:"dataCriacao"`
+}
+
+// TableName sobrescreve o nome da tabela
+func (RegistroAuditoria) TableName() string {
+	return "auditoria"
+}
@@ -0,0 +1,75 @@
+package entidades
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/dominio/enums"
+)
+
+// RespostaAutomatica é uma regra do auto-responder do WhatsApp. A palavra-chave
+// é guardada normalizada (maiúsculas, sem acentos) e comparada com as mensagens recebidas.
+type RespostaAutomatica struct {
+	ID              uuid.UUID                    `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UsuarioID       uuid.UUID                    `gorm:"type:uuid;not null;uniqueIndex:idx_respostas_automaticas_palavra" json:"usuarioId"`
+	PalavraChave    string                       `gorm:"type:varchar(50);not null;uniqueIndex:idx_respostas_automaticas_palavra" json:"palavraChave"`
+	Acao            enums.AcaoRespostaAutomatica `gorm:"type:varchar(20);not null" json:"acao"`
+	Mensagem        string                       `gorm:"type:text" json:"mensagem"`
+	Ativo           bool                         `gorm:"not null;default:true" json:"ativo"`
+	DataCriacao     time.Time                    `gorm:"autoCreateTime" json:"dataCriacao"`
+	DataAtualizacao time.Time                    `gorm:"autoUpdateTime" json:"dataAtualizacao"`
+
+	// Relacionamentos
+	Usuario Usuario `gorm:"foreignKey:UsuarioID" json:"-"`
+}
+
+// TableName sobrescreve o nome da tabela
+func (RespostaAutomatica) TableName() string {
+	return "respostas_automaticas"
+}
+
+// ConfigRespostaAutomatica guarda as opções gerais do auto-responder da conta:
+// liga/desliga, resposta padrão (quando nenhuma regra reconhece a mensagem)
+// e os dados do recebedor usados no PIX copia e cola.
+type ConfigRespostaAutomatica struct {
+	UsuarioID       uuid.UUID `gorm:"type:uuid;primaryKey" json:"usuarioId"`
+	Ativo           bool      `gorm:"not null;default:false" json:"ativo"`
+	MensagemPadrao  string    `gorm:"type:text" json:"mensagemPadrao"`
+	ChavePix        string    `gorm:"type:varchar(77)" json:"chavePix"`
+	RecebedorPix    string    `gorm:"type:varchar(25)" json:"recebedorPix"`
+	CidadePix       string    `gorm:"type:varchar(15)" json:"cidadePix"`
+	DataCriacao     time.Time `gorm:"autoCreateTime" json:"dataCriacao"`
+	DataAtualizacao time.Time `gorm:"autoUpdateTime" json:"dataAtualizacao"`
+}
+
+// TableName sobrescreve o nome da tabela
+func (ConfigRespostaAutomatica) TableName() string {
+	return "respostas_automaticas_config"
+}
+
+// DisparoRespostaAutomatica registra cada resposta automática enviada (ou tentada).
+// RespostaID nulo indica a resposta padrão.
+type DisparoRespostaAutomatica struct {
+	ID           uuid.UUID                    `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UsuarioID    uuid.UUID                    `gorm:"type:uuid;not null;index:idx_respostas_automaticas_disparos_usuario" json:"usuarioId"`
+	RespostaID   *uuid.UUID                   `gorm:"type:uuid;index" json:"respostaId"`
+	MensagemID   uuid.UUID                    `gorm:"type:uuid;not null" json:"mensagemId"`
+	PalavraChave string                       `gorm:"type:varchar(50)" json:"palavraChave"`
+	Acao         enums.AcaoRespostaAutomatica `gorm:"type:varchar(20)" json:"acao"`
+	Telefone     string                       `gorm:"type:varchar(20);not null" json:"telefone"`
+	ClienteID    *uuid.UUID                   `gorm:"type:uuid" json:"clienteId"`
+	CobrancaID   *uuid.UUID                   `gorm:"type:uuid" json:"cobrancaId"`
+	Sucesso      bool                         `gorm:"not null;default:false" json:"sucesso"`
+	Erro         string                       `gorm:"type:text" json:"erro"`
+	DataCriacao  time.Time                    `gorm:"autoCreateTime;index:idx_respostas_automaticas_disparos_usuario" json:"dataCriacao"`
+}
+
+// TableName sobrescreve o nome da tabela
+func (DisparoRespostaAutomatica) TableName() string {
+	return "respostas_automaticas_disparos"
+}
+
+// IsPadrao verifica se o disparo foi da resposta padrão
+func (d *DisparoRespostaAutomatica) IsPadrao() bool {
+	return d.RespostaID == nil
+}
@@ -7,6 +7,9 @@ import (
 	"gorm.io/gorm"
 )
 
+// DiasTrial é a duração do período de teste de novas contas
+const DiasTrial = 14
+
 type Usuario struct {
 	ID                     uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
 	NomeCompleto           string     `gorm:"type:varchar(255);not null" json:"nomeCompleto" validate:"required"`
@@ -14,7 +17,7 @@ type Usuario struct {
 	Telefone               string     `gorm:"type:varchar(20)" json:"telefone"`
 	NomeEmpresa            string     `gorm:"type:varchar(255)" json:"nomeEmpresa"`
 	TipoEmpresa            string     `gorm:"type:varchar(50)" json:"tipoEmpresa"`
-	CNPJ                   string     `gorm:"type:varchar(18)" json:"cnpj"`
+	CNPJ                   string     `gorm:"type:varchar(18)" json:"cnpj"` // sem máscara, pode ser alfanumérico
 	SenhaHash              string     `gorm:"type:varchar(255);not null" json:"-"`
 	Ativo                  bool       `gorm:"default:true" json:"ativo"`
 	EmailVerificado        bool       `gorm:"default:false" json:"emailVerificado"`
@@ -67,8 +70,7 @@ func (u *Usuario) IsTrialExpirado() bool {
 		return true
 	}
 
-	dataExpiracao := u.DataTrialInicio.AddDate(0, 0, 14) // 14 dias
-	return time.Now().After(dataExpiracao)
+	return time.Now().After(u.DataFimTrial())
 }
 
 // DiasRestantesTrial retorna quantos dias faltam no trial
@@ -77,8 +79,7 @@ func (u *Usuario) DiasRestantesTrial() int {
 		return 0
 	}
 
-	dataExpiracao := u.DataTrialInicio.AddDate(0, 0, 14)
-	diasRestantes := int(time.Until(dataExpiracao).Hours() / 24)
+	diasRestantes := int(time.Until(u.DataFimTrial()).Hours() / 24)
 
 	if diasRestantes < 0 {
 		return 0
@@ -87,6 +88,27 @@ func (u *Usuario) DiasRestantesTrial() int {
 	return diasRestantes
 }
 
+// DataFimTrial retorna quando o trial termina (DiasTrial dias após o início)
+func (u *Usuario) DataFimTrial() time.Time {
+	if u.DataTrialInicio == nil {
+		return time.Time{}
+	}
+	return u.DataTrialInicio.AddDate(0, 0, DiasTrial)
+}
+
+// EstenderTrial reativa o trial e adia o fim em dias, contando do fim atual ou,
+// se o trial já expirou, de agora. O início é deslocado para manter a duração padrão.
+func (u *Usuario) EstenderTrial(dias int) {
+	fim := u.DataFimTrial()
+	if !u.TrialAtivo || time.Now().After(fim) {
+		fim = time.Now()
+	}
+
+	inicio := fim.AddDate(0, 0, dias-DiasTrial)
+	u.DataTrialInicio = &inicio
+	u.TrialAtivo = true
+}
+
 // AtualizarUltimoAcesso atualiza data do último acesso
 func (u *Usuario) AtualizarUltimoAcesso() {
 	agora := time.Now()
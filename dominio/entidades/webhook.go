@@ -0,0 +1,95 @@
+package entidades
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/dominio/enums"
+)
+
+type StatusEntregaWebhook string
+
+const (
+	StatusEntregaPendente StatusEntregaWebhook = "PENDENTE"
+	StatusEntregaSucesso  StatusEntregaWebhook = "SUCESSO"
+	StatusEntregaFalha    StatusEntregaWebhook = "FALHA"
+)
+
+// EndpointWebhook é uma URL do cliente inscrita em eventos da conta.
+// O segredo é usado para assinar os payloads (HMAC-SHA256) e fica criptografado.
+type EndpointWebhook struct {
+	ID                uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UsuarioID         uuid.UUID `gorm:"type:uuid;not null;index" json:"usuarioId"`
+	URL               string    `gorm:"type:varchar(500);not null" json:"url"`
+	Descricao         string    `gorm:"type:varchar(255)" json:"descricao"`
+	Eventos           string    `gorm:"type:text;not null" json:"eventos"`
+	SegredoEncriptado string    `gorm:"type:text;not null" json:"-"`
+	Ativo             bool      `gorm:"not null;default:true" json:"ativo"`
+	DataCriacao       time.Time `gorm:"autoCreateTime" json:"dataCriacao"`
+	DataAtualizacao   time.Time `gorm:"autoUpdateTime" json:"dataAtualizacao"`
+
+	// Relacionamentos
+	Usuario Usuario `gorm:"foreignKey:UsuarioID" json:"-"`
+}
+
+// TableName sobrescreve o nome da tabela
+func (EndpointWebhook) TableName() string {
+	return "webhook_endpoints"
+}
+
+// ListaEventos retorna os eventos inscritos
+func (e *EndpointWebhook) ListaEventos() []enums.EventoWebhook {
+	var eventos []enums.EventoWebhook
+	for _, ev := range strings.Split(e.Eventos, ",") {
+		if ev = strings.TrimSpace(ev); ev != "" {
+			eventos = append(eventos, enums.EventoWebhook(ev))
+		}
+	}
+	return eventos
+}
+
+// DefinirEventos armazena os eventos inscritos
+func (e *EndpointWebhook) DefinirEventos(eventos []enums.EventoWebhook) {
+	partes := make([]string, len(eventos))
+	for i, ev := range eventos {
+		partes[i] = string(ev)
+	}
+	e.Eventos = strings.Join(partes, ",")
+}
+
+// InscritoEm verifica se o endpoint recebe o evento informado
+func (e *EndpointWebhook) InscritoEm(evento enums.EventoWebhook) bool {
+	for _, ev := range e.ListaEventos() {
+		if ev == evento {
+			return true
+		}
+	}
+	return false
+}
+
+// EntregaWebhook registra cada envio de evento para um endpoint (log de entregas).
+// O mesmo ID é reenviado em todas as tentativas para permitir idempotência no destino.
+type EntregaWebhook struct {
+	ID               uuid.UUID            `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	EndpointID       uuid.UUID            `gorm:"type:uuid;not null;index" json:"endpointId"`
+	UsuarioID        uuid.UUID            `gorm:"type:uuid;not null;index" json:"usuarioId"`
+	Evento           enums.EventoWebhook  `gorm:"type:varchar(50);not null" json:"evento"`
+	Payload          string               `gorm:"type:text;not null" json:"payload"`
+	Status           StatusEntregaWebhook `gorm:"type:varchar(20);not null;default:'PENDENTE'" json:"status"`
+	Tentativas       int                  `gorm:"not null;default:0" json:"tentativas"`
+	UltimoStatusHTTP int                  `gorm:"column:ultimo_status_http" json:"ultimoStatusHttp"`
+	UltimoErro       string               `gorm:"type:text" json:"ultimoErro"`
+	ProximaTentativa *time.Time           `gorm:"type:timestamp" json:"proximaTentativa"`
+	DataEntrega      *time.Time           `gorm:"type:timestamp" json:"dataEntrega"`
+	DataCriacao      time.Time            `gorm:"autoCreateTime" json:"dataCriacao"`
+	DataAtualizacao  time.Time            `gorm:"autoUpdateTime" json:"dataAtualizacao"`
+
+	// Relacionamentos
+	Endpoint EndpointWebhook `gorm:"foreignKey:EndpointID" json:"-"`
+}
+
+// TableName sobrescreve o nome da tabela
+func (EntregaWebhook) TableName() string {
+	return "webhook_entregas"
+}
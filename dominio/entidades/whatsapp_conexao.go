@@ -1,11 +1,16 @@
 package entidades
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/dominio/enums"
 )
 
+// NomeConexaoPadrao é usado quando a conexão é criada sem nome
+const NomeConexaoPadrao = "Principal"
+
 type StatusConexao string
 
 const (
@@ -15,14 +20,21 @@ const (
 	StatusConexaoErro          StatusConexao = "ERRO"
 )
 
+// WhatsAppConexao é um número WhatsApp da conta. Uma conta pode ter vários
+// números (ex: "Vendas" e "Cobrança"); o padrão é usado quando nenhuma regra
+// de roteamento se aplica.
 type WhatsAppConexao struct {
 	ID                  int64          `gorm:"primaryKey;autoIncrement" json:"id"`
 	UsuarioID           uuid.UUID      `gorm:"type:uuid;not null;index" json:"usuarioId" validate:"required"`
+	Nome                string         `gorm:"type:varchar(100);not null;default:'Principal'" json:"nome"`
+	Padrao              bool           `gorm:"not null;default:false" json:"padrao"`
+	TiposNotificacao    string         `gorm:"type:text" json:"tiposNotificacao"`
 	InstanceName        string         `gorm:"type:varchar(255);uniqueIndex;not null" json:"instanceName" validate:"required"`
 	Status              StatusConexao  `gorm:"type:varchar(20);default:'DESCONECTADO'" json:"status"`
 	QRCode              string         `gorm:"type:text" json:"qrCode"`
 	NumeroConectado     string         `gorm:"type:varchar(20)" json:"numeroConectado"`
 	DataConexao         *time.Time     `gorm:"type:timestamp" json:"dataConexao"`
+	DataPareamento      *time.Time     `gorm:"type:timestamp" json:"dataPareamento"` // Início do aquecimento do número
 	DataUltimaAtividade *time.Time     `gorm:"type:timestamp" json:"dataUltimaAtividade"`
 	MensagensEnviadas   int            `gorm:"type:integer;default:0" json:"mensagensEnviadas"`
 	MensagensSucesso    int            `gorm:"type:integer;default:0" json:"mensagensSucesso"`
@@ -30,6 +42,9 @@ type WhatsAppConexao struct {
 	DataCriacao         time.Time      `gorm:"autoCreateTime" json:"dataCriacao"`
 	DataAtualizacao     time.Time      `gorm:"autoUpdateTime" json:"dataAtualizacao"`
 
+	// Verificações seguidas do monitor sem a instância na Evolution API
+	VerificacoesSemInstancia int `gorm:"type:integer;not null;default:0" json:"-"`
+
 	// Relacionamento
 	Usuario Usuario `gorm:"foreignKey:UsuarioID" json:"-"`
 }
@@ -39,39 +54,77 @@ func (WhatsAppConexao) TableName() string {
 	return "whatsapp_conexoes"
 }
 
+// ListaTiposNotificacao retorna os tipos de notificação roteados para esta conexão
+func (w *WhatsAppConexao) ListaTiposNotificacao() []enums.TipoNotificacao {
+	var tipos []enums.TipoNotificacao
+	for _, t := range strings.Split(w.TiposNotificacao, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			tipos = append(tipos, enums.TipoNotificacao(t))
+		}
+	}
+	return tipos
+}
+
+// DefinirTiposNotificacao armazena os tipos de notificação roteados para esta conexão
+func (w *WhatsAppConexao) DefinirTiposNotificacao(tipos []enums.TipoNotificacao) {
+	partes := make([]string, len(tipos))
+	for i, t := range tipos {
+		partes[i] = string(t)
+	}
+	w.TiposNotificacao = strings.Join(partes, ",")
+}
+
+// AtendeTipoNotificacao verifica se a conexão é a rota do tipo de notificação
+func (w *WhatsAppConexao) AtendeTipoNotificacao(tipo enums.TipoNotificacao) bool {
+	for _, t := range w.ListaTiposNotificacao() {
+		if t == tipo {
+			return true
+		}
+	}
+	return false
+}
+
 // IsConectado verifica se está conectado
 func (w *WhatsAppConexao) IsConectado() bool {
 	return w.Status == StatusConexaoConectado
 }
 
-// Conectar marca como conectado
+// Conectar marca como conectado. A primeira conexão após um novo QR Code é o pareamento.
 func (w *WhatsAppConexao) Conectar(numero string) {
 	w.Status = StatusConexaoConectado
 	w.NumeroConectado = numero
 	agora := time.Now()
 	w.DataConexao = &agora
+	if w.DataPareamento == nil {
+		w.DataPareamento = &agora
+	}
 	w.DataUltimaAtividade = &agora
 	w.QRCode = "" // Limpa QR Code após conectar
 }
 
+// IniciarPareamento prepara a conexão para parear um número pelo QR Code da nova instância.
+// O número pode ser outro, então o aquecimento recomeça quando ele conectar.
+func (w *WhatsAppConexao) IniciarPareamento(instancia, qrCode string) {
+	w.InstanceName = instancia
+	w.QRCode = qrCode
+	w.Status = StatusConexaoConectando
+	w.DataPareamento = nil
+}
+
 // Desconectar marca como desconectado
 func (w *WhatsAppConexao) Desconectar() {
 	w.Status = StatusConexaoDesconectado
 	w.QRCode = ""
 }
 
-// IncrementarMensagemEnviada incrementa contador de mensagem
-func (w *WhatsAppConexao) IncrementarMensagemEnviada(sucesso bool) {
-	w.MensagensEnviadas++
-
-	if sucesso {
-		w.MensagensSucesso++
-	} else {
-		w.MensagensFalha++
-	}
+// MarcarQueda marca a conexão que caiu sem logout; o monitor tenta reconectá-la
+func (w *WhatsAppConexao) MarcarQueda() {
+	w.Status = StatusConexaoErro
+}
 
-	agora := time.Now()
-	w.DataUltimaAtividade = &agora
+// IsQueda verifica se a conexão caiu e aguarda reconexão
+func (w *WhatsAppConexao) IsQueda() bool {
+	return w.Status == StatusConexaoErro
 }
 
 // TaxaSucesso calcula taxa de sucesso de envios
@@ -0,0 +1,33 @@
+package enums
+
+type AcaoAuditoria string
+
+const (
+	AcaoAuditoriaCriar     AcaoAuditoria = "CRIAR"
+	AcaoAuditoriaAtualizar AcaoAuditoria = "ATUALIZAR"
+	AcaoAuditoriaExcluir   AcaoAuditoria = "EXCLUIR"
+)
+
+func (a AcaoAuditoria) String() string {
+	return string(a)
+}
+
+func (a AcaoAuditoria) Valido() bool {
+	switch a {
+	case AcaoAuditoriaCriar, AcaoAuditoriaAtualizar, AcaoAuditoriaExcluir:
+		return true
+	}
+	return false
+}
+
+// Entidades registradas na trilha de auditoria
+const (
+	EntidadeAuditoriaCliente      = "cliente"
+	EntidadeAuditoriaCobranca     = "cobranca"
+	EntidadeAuditoriaStripeConfig = "stripe_config"
+	EntidadeAuditoriaMembro       = "membro_organizacao"
+	EntidadeAuditoriaUsuario      = "usuario"
+
+	EntidadeAuditoriaRespostaAutomatica       = "resposta_automatica"
+	EntidadeAuditoriaConfigRespostaAutomatica = "config_resposta_automatica"
+)
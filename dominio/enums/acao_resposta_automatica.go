@@ -0,0 +1,24 @@
+package enums
+
+// AcaoRespostaAutomatica define o que o auto-responder do WhatsApp faz
+// quando uma palavra-chave é reconhecida na mensagem do cliente
+type AcaoRespostaAutomatica string
+
+const (
+	AcaoRespostaTexto        AcaoRespostaAutomatica = "TEXTO"        // Responde com o texto da regra
+	AcaoRespostaSegundaVia   AcaoRespostaAutomatica = "SEGUNDA_VIA"  // Reenvia os dados e o link da cobrança em aberto
+	AcaoRespostaPix          AcaoRespostaAutomatica = "PIX"          // Envia o PIX copia e cola da cobrança em aberto
+	AcaoRespostaDescadastrar AcaoRespostaAutomatica = "DESCADASTRAR" // Registra o opt-out de lembretes do cliente
+)
+
+func (a AcaoRespostaAutomatica) String() string {
+	return string(a)
+}
+
+func (a AcaoRespostaAutomatica) Valido() bool {
+	switch a {
+	case AcaoRespostaTexto, AcaoRespostaSegundaVia, AcaoRespostaPix, AcaoRespostaDescadastrar:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package enums
+
+// CanalNotificacao define por onde o cliente aceita receber lembretes de cobrança
+type CanalNotificacao string
+
+const (
+	CanalNotificacaoTodos    CanalNotificacao = "TODOS"
+	CanalNotificacaoWhatsApp CanalNotificacao = "WHATSAPP"
+	CanalNotificacaoEmail    CanalNotificacao = "EMAIL"
+	CanalNotificacaoNenhum   CanalNotificacao = "NENHUM"
+)
+
+func (c CanalNotificacao) String() string {
+	return string(c)
+}
+
+func (c CanalNotificacao) Valido() bool {
+	switch c {
+	case CanalNotificacaoTodos, CanalNotificacaoWhatsApp, CanalNotificacaoEmail, CanalNotificacaoNenhum:
+		return true
+	}
+	return false
+}
+
+// OrigemOptOut registra por onde o cliente pediu para não receber mais lembretes (LGPD)
+type OrigemOptOut string
+
+const (
+	OrigemOptOutWhatsApp OrigemOptOut = "WHATSAPP" // Resposta com palavra-chave (ex: SAIR)
+	OrigemOptOutEmail    OrigemOptOut = "EMAIL"    // Link de descadastro do email
+	OrigemOptOutPainel   OrigemOptOut = "PAINEL"   // Registrado pela equipe a pedido do cliente
+)
+
+func (o OrigemOptOut) String() string {
+	return string(o)
+}
@@ -0,0 +1,42 @@
+package enums
+
+// TipoAnexoWhatsApp define como um anexo é enviado pelo WhatsApp
+type TipoAnexoWhatsApp string
+
+const (
+	TipoAnexoImagem    TipoAnexoWhatsApp = "IMAGEM"    // Ex: QR Code do PIX (PNG)
+	TipoAnexoDocumento TipoAnexoWhatsApp = "DOCUMENTO" // Ex: fatura ou boleto em PDF
+)
+
+func (t TipoAnexoWhatsApp) String() string {
+	return string(t)
+}
+
+func (t TipoAnexoWhatsApp) Valido() bool {
+	switch t {
+	case TipoAnexoImagem, TipoAnexoDocumento:
+		return true
+	}
+	return false
+}
+
+// TipoBotaoWhatsApp define a ação de um botão interativo do WhatsApp
+type TipoBotaoWhatsApp string
+
+const (
+	TipoBotaoResposta TipoBotaoWhatsApp = "RESPOSTA" // Cliente responde com o texto do botão (ex: 2VIA)
+	TipoBotaoLink     TipoBotaoWhatsApp = "LINK"     // Abre uma URL (ex: link de pagamento)
+	TipoBotaoCopiar   TipoBotaoWhatsApp = "COPIAR"   // Copia um código (ex: PIX copia e cola)
+)
+
+func (t TipoBotaoWhatsApp) String() string {
+	return string(t)
+}
+
+func (t TipoBotaoWhatsApp) Valido() bool {
+	switch t {
+	case TipoBotaoResposta, TipoBotaoLink, TipoBotaoCopiar:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package enums
+
+import "strings"
+
+type EscopoAPI string
+
+const (
+	EscopoClientesLeitura   EscopoAPI = "clientes:read"
+	EscopoClientesEscrita   EscopoAPI = "clientes:write"
+	EscopoCobrancasLeitura  EscopoAPI = "cobrancas:read"
+	EscopoCobrancasEscrita  EscopoAPI = "cobrancas:write"
+	EscopoWhatsAppLeitura   EscopoAPI = "whatsapp:read"
+	EscopoWhatsAppEscrita   EscopoAPI = "whatsapp:write"
+	EscopoRelatoriosLeitura EscopoAPI = "relatorios:read"
+)
+
+func (e EscopoAPI) String() string {
+	return string(e)
+}
+
+func (e EscopoAPI) Valido() bool {
+	switch e {
+	case EscopoClientesLeitura, EscopoClientesEscrita,
+		EscopoCobrancasLeitura, EscopoCobrancasEscrita,
+		EscopoWhatsAppLeitura, EscopoWhatsAppEscrita,
+		EscopoRelatoriosLeitura:
+		return true
+	}
+	return false
+}
+
+// Recurso retorna a parte do escopo antes de ":" (ex: "cobrancas")
+func (e EscopoAPI) Recurso() string {
+	recurso, _, _ := strings.Cut(string(e), ":")
+	return recurso
+}
+
+// Permite verifica se o escopo concede acesso ao escopo solicitado.
+// Escopos de escrita incluem a leitura do mesmo recurso.
+func (e EscopoAPI) Permite(solicitado EscopoAPI) bool {
+	if e == solicitado {
+		return true
+	}
+	return strings.HasSuffix(string(e), ":write") &&
+		strings.HasSuffix(string(solicitado), ":read") &&
+		e.Recurso() == solicitado.Recurso()
+}
@@ -0,0 +1,25 @@
+package enums
+
+import "testing"
+
+func TestEscopoAPIPermite(t *testing.T) {
+	casos := []struct {
+		escopo, solicitado EscopoAPI
+		esperado           bool
+	}{
+		{EscopoClientesLeitura, EscopoClientesLeitura, true},
+		{EscopoClientesEscrita, EscopoClientesEscrita, true},
+		{EscopoClientesEscrita, EscopoClientesLeitura, true}, // escrita inclui leitura
+		{EscopoClientesLeitura, EscopoClientesEscrita, false},
+		{EscopoCobrancasEscrita, EscopoClientesLeitura, false}, // outro recurso
+		{EscopoCobrancasEscrita, EscopoClientesEscrita, false},
+		{EscopoRelatoriosLeitura, EscopoAPI("relatorios:write"), false},
+		{EscopoAPI("clientes:admin"), EscopoClientesLeitura, false},
+	}
+
+	for _, caso := range casos {
+		if got := caso.escopo.Permite(caso.solicitado); got != caso.esperado {
+			t.Errorf("%s.Permite(%s) = %v, esperado %v", caso.escopo, caso.solicitado, got, caso.esperado)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package enums
+
+type EventoWebhook string
+
+const (
+	EventoCobrancaCriada       EventoWebhook = "cobranca.criada"
+	EventoCobrancaPaga         EventoWebhook = "cobranca.paga"
+	EventoCobrancaVencida      EventoWebhook = "cobranca.vencida"
+	EventoClienteCriado        EventoWebhook = "cliente.criado"
+	EventoWhatsAppDesconectado EventoWebhook = "whatsapp.desconectado"
+	EventoWhatsAppMensagem     EventoWebhook = "whatsapp.mensagem_recebida"
+)
+
+func (e EventoWebhook) String() string {
+	return string(e)
+}
+
+func (e EventoWebhook) Valido() bool {
+	switch e {
+	case EventoCobrancaCriada, EventoCobrancaPaga, EventoCobrancaVencida,
+		EventoClienteCriado, EventoWhatsAppDesconectado, EventoWhatsAppMensagem:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package enums
+
+type PapelMembro string
+
+const (
+	PapelOwner      PapelMembro = "OWNER"
+	PapelAdmin      PapelMembro = "ADMIN"
+	PapelFinanceiro PapelMembro = "FINANCEIRO"
+	PapelLeitura    PapelMembro = "LEITURA"
+)
+
+func (p PapelMembro) String() string {
+	return string(p)
+}
+
+func (p PapelMembro) Valido() bool {
+	switch p {
+	case PapelOwner, PapelAdmin, PapelFinanceiro, PapelLeitura:
+		return true
+	}
+	return false
+}
+
+// Nivel retorna o nível hierárquico do papel (maior = mais permissões)
+func (p PapelMembro) Nivel() int {
+	switch p {
+	case PapelOwner:
+		return 4
+	case PapelAdmin:
+		return 3
+	case PapelFinanceiro:
+		return 2
+	case PapelLeitura:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Permite verifica se o papel tem pelo menos as permissões do papel mínimo
+func (p PapelMembro) Permite(minimo PapelMembro) bool {
+	return p.Nivel() >= minimo.Nivel()
+}
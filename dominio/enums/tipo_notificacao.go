@@ -0,0 +1,24 @@
+package enums
+
+// TipoNotificacao identifica o modelo de mensagem enviado ao cliente e é
+// usado para rotear o envio para um número WhatsApp específico
+type TipoNotificacao string
+
+const (
+	TipoNotificacaoLembrete   TipoNotificacao = "lembrete"
+	TipoNotificacaoVencimento TipoNotificacao = "vencimento"
+	TipoNotificacaoPagamento  TipoNotificacao = "pagamento"
+	TipoNotificacaoManual     TipoNotificacao = "manual"
+)
+
+func (t TipoNotificacao) String() string {
+	return string(t)
+}
+
+func (t TipoNotificacao) Valido() bool {
+	switch t {
+	case TipoNotificacaoLembrete, TipoNotificacaoVencimento, TipoNotificacaoPagamento, TipoNotificacaoManual:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/dominio/enums"
+)
+
+// Ator identifica quem executa uma alteração, para a trilha de auditoria.
+// ID igual a uuid.Nil indica uma ação automática do sistema (ex: agendador).
+type Ator struct {
+	ID uuid.UUID
+	IP string
+}
+
+// AtorSistema representa alterações feitas por jobs internos
+var AtorSistema = Ator{}
+
+// BuscarAuditoriaRequest representa os filtros de consulta da trilha de auditoria
+type BuscarAuditoriaRequest struct {
+	Entidade      string     `form:"entidade"`
+	EntidadeID    string     `form:"entidadeId"`
+	AtorID        *uuid.UUID `form:"atorId"`
+	DataInicio    *time.Time `form:"dataInicio"`
+	DataFim       *time.Time `form:"dataFim"`
+	Pagina        int        `form:"pagina" binding:"min=0"`
+	TamanhoPagina int        `form:"tamanhoPagina" binding:"min=0,max=100"`
+}
+
+// RegistroAuditoriaResponse representa um registro da trilha de auditoria
+type RegistroAuditoriaResponse struct {
+	ID          uuid.UUID           `json:"id"`
+	AtorID      *uuid.UUID          `json:"atorId"`
+	AtorEmail   string              `json:"atorEmail,omitempty"`
+	Acao        enums.AcaoAuditoria `json:"acao"`
+	Entidade    string              `json:"entidade"`
+	EntidadeID  string              `json:"entidadeId"`
+	Alteracoes  interface{}         `json:"alteracoes"`
+	IP          string              `json:"ip,omitempty"`
+	DataCriacao time.Time           `json:"dataCriacao"`
+}
+
+// AuditoriaListResponse representa a lista paginada de registros de auditoria
+type AuditoriaListResponse struct {
+	Registros     []RegistroAuditoriaResponse `json:"registros"`
+	Total         int64                       `json:"total"`
+	Pagina        int                         `json:"pagina"`
+	TamanhoPagina int                         `json:"tamanhoPagina"`
+	TotalPaginas  int                         `json:"totalPaginas"`
+}
@@ -13,6 +13,7 @@ type CadastroRequest struct {
 	NomeCompleto string `json:"nomeCompleto" binding:"required,min=3"`
 	Email        string `json:"email" binding:"required,email"`
 	Senha        string `json:"senha" binding:"required,min=6"`
+	CNPJ         string `json:"cnpj" binding:"omitempty,cnpj"`
 }
 
 // RefreshTokenRequest representa a requisição de refresh do token
@@ -33,6 +34,7 @@ type UsuarioResponse struct {
 	ID                string     `json:"id"`
 	NomeCompleto      string     `json:"nomeCompleto"`
 	Email             string     `json:"email"`
+	CNPJ              string     `json:"cnpj,omitempty"`
 	TrialAtivo        bool       `json:"trialAtivo"`
 	DataTrialInicio   *time.Time `json:"dataTrialInicio,omitempty"`
 	TrialExpirado     bool       `json:"trialExpirado"`
@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/dominio/enums"
+)
+
+// CriarChaveAPIRequest representa a requisição de criação de chave de API
+type CriarChaveAPIRequest struct {
+	Nome         string            `json:"nome" binding:"required,min=3,max=100"`
+	Escopos      []enums.EscopoAPI `json:"escopos" binding:"required,min=1"`
+	ExpiraEmDias *int              `json:"expiraEmDias" binding:"omitempty,min=1,max=730"`
+}
+
+// ChaveAPIResponse representa uma chave de API na resposta (sem o segredo)
+type ChaveAPIResponse struct {
+	ID            uuid.UUID         `json:"id"`
+	Nome          string            `json:"nome"`
+	Prefixo       string            `json:"prefixo"`
+	Escopos       []enums.EscopoAPI `json:"escopos"`
+	UltimoUso     *time.Time        `json:"ultimoUso,omitempty"`
+	DataExpiracao *time.Time        `json:"dataExpiracao,omitempty"`
+	DataCriacao   time.Time         `json:"dataCriacao"`
+	Expirada      bool              `json:"expirada"`
+}
+
+// ChaveAPICriadaResponse inclui a chave completa, exibida uma única vez na criação
+type ChaveAPICriadaResponse struct {
+	ChaveAPIResponse
+	Chave string `json:"chave"`
+}
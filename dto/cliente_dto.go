@@ -4,16 +4,24 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/dominio/enums"
 )
 
-// ClienteRequest representa a requisição de criação/atualização de cliente
+// ClienteRequest representa a requisição de criação/atualização de cliente.
+// CPF, CNPJ e CEP são aceitos com ou sem máscara e devolvidos com máscara.
+// Com CEP, cidade e estado são preenchidos pela consulta do CEP.
 type ClienteRequest struct {
 	Nome     string  `json:"nome" binding:"required,min=2"`
 	Email    string  `json:"email" binding:"required,email"`
 	Telefone string  `json:"telefone" binding:"required"`
 	Endereco string  `json:"endereco"`
-	CPF      *string `json:"cpf"`
-	CNPJ     *string `json:"cnpj"`
+	Cidade   string  `json:"cidade"`
+	Estado   string  `json:"estado" binding:"omitempty,uf"`
+	CEP      string  `json:"cep" binding:"omitempty,cep"`
+	CPF      *string `json:"cpf" binding:"omitempty,cpf"`
+	CNPJ     *string `json:"cnpj" binding:"omitempty,cnpj"`
+
+	WhatsAppConexaoID *int64 `json:"whatsappConexaoId"`
 }
 
 // ClienteResponse representa o cliente na resposta
@@ -23,9 +31,42 @@ type ClienteResponse struct {
 	Email       string    `json:"email"`
 	Telefone    string    `json:"telefone"`
 	Endereco    string    `json:"endereco,omitempty"`
+	Cidade      string    `json:"cidade,omitempty"`
+	Estado      string    `json:"estado,omitempty"`
+	CEP         string    `json:"cep,omitempty"`
 	CPF         *string   `json:"cpf,omitempty"`
 	CNPJ        *string   `json:"cnpj,omitempty"`
 	DataCriacao time.Time `json:"dataCriacao"`
+
+	WhatsAppConexaoID *int64 `json:"whatsappConexaoId,omitempty"`
+
+	CanalNotificacao enums.CanalNotificacao `json:"canalNotificacao"`
+	SilencioInicio   *string                `json:"silencioInicio,omitempty"`
+	SilencioFim      *string                `json:"silencioFim,omitempty"`
+	DataOptOut       *time.Time             `json:"dataOptOut,omitempty"`
+	OrigemOptOut     enums.OrigemOptOut     `json:"origemOptOut,omitempty"`
+
+	WhatsAppValido          *bool      `json:"whatsappValido,omitempty"`
+	DataVerificacaoWhatsApp *time.Time `json:"dataVerificacaoWhatsapp,omitempty"`
+}
+
+// PreferenciasNotificacaoRequest representa as preferências de notificação do cliente.
+// SilencioInicio e SilencioFim (HH:MM, horário de Brasília) devem ser informados juntos.
+type PreferenciasNotificacaoRequest struct {
+	CanalNotificacao enums.CanalNotificacao `json:"canalNotificacao" binding:"required"`
+	SilencioInicio   *string                `json:"silencioInicio"`
+	SilencioFim      *string                `json:"silencioFim"`
+	OptOut           bool                   `json:"optOut"`
+}
+
+// VerificacaoWhatsAppResponse representa o resultado da verificação do número no WhatsApp.
+// Cache indica que o resultado veio da última verificação gravada no cliente.
+type VerificacaoWhatsAppResponse struct {
+	ClienteID       uuid.UUID `json:"clienteId"`
+	Telefone        string    `json:"telefone"`
+	WhatsAppValido  bool      `json:"whatsappValido"`
+	DataVerificacao time.Time `json:"dataVerificacao"`
+	Cache           bool      `json:"cache"`
 }
 
 // ClienteListResponse representa a lista paginada de clientes
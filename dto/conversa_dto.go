@@ -0,0 +1,68 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MensagemWhatsAppResponse representa uma mensagem da conversa
+type MensagemWhatsAppResponse struct {
+	ID           uuid.UUID  `json:"id"`
+	ConexaoID    int64      `json:"conexaoId"`
+	Telefone     string     `json:"telefone"`
+	ClienteID    *uuid.UUID `json:"clienteId,omitempty"`
+	Direcao      string     `json:"direcao"`
+	Texto        string     `json:"texto"`
+	TipoMensagem string     `json:"tipoMensagem,omitempty"`
+	NomeContato  string     `json:"nomeContato,omitempty"`
+	CobrancaID   *uuid.UUID `json:"cobrancaId,omitempty"`
+	Lida         bool       `json:"lida"`
+	EnviadoPorID *uuid.UUID `json:"enviadoPorId,omitempty"`
+	DataMensagem time.Time  `json:"dataMensagem"`
+}
+
+// ConversaResponse representa uma conversa na caixa de entrada
+type ConversaResponse struct {
+	Telefone           string     `json:"telefone"`
+	ClienteID          *uuid.UUID `json:"clienteId,omitempty"`
+	NomeCliente        string     `json:"nomeCliente,omitempty"`
+	NomeContato        string     `json:"nomeContato,omitempty"`
+	UltimaMensagem     string     `json:"ultimaMensagem"`
+	UltimaDirecao      string     `json:"ultimaDirecao"`
+	DataUltimaMensagem time.Time  `json:"dataUltimaMensagem"`
+	NaoLidas           int64      `json:"naoLidas"`
+}
+
+// ConversaListResponse representa a lista paginada de conversas
+type ConversaListResponse struct {
+	Conversas     []ConversaResponse `json:"conversas"`
+	TotalNaoLidas int64              `json:"totalNaoLidas"`
+	Total         int64              `json:"total"`
+	Pagina        int                `json:"pagina"`
+	TamanhoPagina int                `json:"tamanhoPagina"`
+	TotalPaginas  int                `json:"totalPaginas"`
+}
+
+// MensagensConversaResponse representa o histórico de uma conversa com o contexto do cliente
+type MensagensConversaResponse struct {
+	Telefone          string                     `json:"telefone"`
+	Cliente           *ClienteResponse           `json:"cliente,omitempty"`
+	CobrancasEmAberto []CobrancaResponse         `json:"cobrancasEmAberto"`
+	Mensagens         []MensagemWhatsAppResponse `json:"mensagens"`
+	Total             int64                      `json:"total"`
+	Pagina            int                        `json:"pagina"`
+	TamanhoPagina     int                        `json:"tamanhoPagina"`
+	TotalPaginas      int                        `json:"totalPaginas"`
+}
+
+// BuscarConversasRequest representa a paginação da caixa de entrada
+type BuscarConversasRequest struct {
+	Pagina        int `form:"pagina" binding:"min=0"`
+	TamanhoPagina int `form:"tamanhoPagina" binding:"min=0,max=100"`
+}
+
+// ResponderConversaRequest representa a resposta da equipe a uma conversa
+type ResponderConversaRequest struct {
+	Mensagem string `json:"mensagem" binding:"required,max=4096"`
+}
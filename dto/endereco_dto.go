@@ -0,0 +1,12 @@
+package dto
+
+// EnderecoCEPResponse representa o endereço encontrado para um CEP
+type EnderecoCEPResponse struct {
+	CEP         string `json:"cep"`
+	Logradouro  string `json:"logradouro"`
+	Complemento string `json:"complemento,omitempty"`
+	Bairro      string `json:"bairro"`
+	Cidade      string `json:"cidade"`
+	Estado      string `json:"estado"`
+	IBGE        string `json:"ibge,omitempty"`
+}
@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/dominio/enums"
+)
+
+// ConvidarMembroRequest representa a requisição de convite de membro
+type ConvidarMembroRequest struct {
+	Email string            `json:"email" binding:"required,email"`
+	Papel enums.PapelMembro `json:"papel" binding:"required"`
+}
+
+// AlterarPapelRequest representa a requisição de alteração de papel de um membro
+type AlterarPapelRequest struct {
+	Papel enums.PapelMembro `json:"papel" binding:"required"`
+}
+
+// AceitarConviteRequest representa a requisição de aceite de convite
+type AceitarConviteRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// MembroResponse representa um membro da organização na resposta
+type MembroResponse struct {
+	ID          uuid.UUID         `json:"id"`
+	UsuarioID   *uuid.UUID        `json:"usuarioId,omitempty"`
+	Email       string            `json:"email"`
+	Papel       enums.PapelMembro `json:"papel"`
+	Status      string            `json:"status"`
+	DataConvite *time.Time        `json:"dataConvite,omitempty"`
+	DataAceite  *time.Time        `json:"dataAceite,omitempty"`
+}
+
+// OrganizacaoResponse representa a organização e seus membros
+type OrganizacaoResponse struct {
+	ID          uuid.UUID         `json:"id"`
+	Nome        string            `json:"nome"`
+	TitularID   uuid.UUID         `json:"titularId"`
+	MeuPapel    enums.PapelMembro `json:"meuPapel"`
+	Membros     []MembroResponse  `json:"membros"`
+	DataCriacao time.Time         `json:"dataCriacao"`
+}
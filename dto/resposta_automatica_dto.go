@@ -0,0 +1,76 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/dominio/enums"
+)
+
+// RespostaAutomaticaRequest representa a criação ou atualização de uma regra do auto-responder
+type RespostaAutomaticaRequest struct {
+	PalavraChave string                       `json:"palavraChave" binding:"required,max=50"`
+	Acao         enums.AcaoRespostaAutomatica `json:"acao" binding:"required"`
+	Mensagem     string                       `json:"mensagem" binding:"max=4096"`
+	Ativo        *bool                        `json:"ativo"`
+}
+
+// RespostaAutomaticaResponse representa uma regra do auto-responder na resposta
+type RespostaAutomaticaResponse struct {
+	ID           uuid.UUID                    `json:"id"`
+	PalavraChave string                       `json:"palavraChave"`
+	Acao         enums.AcaoRespostaAutomatica `json:"acao"`
+	Mensagem     string                       `json:"mensagem"`
+	Ativo        bool                         `json:"ativo"`
+	DataCriacao  time.Time                    `json:"dataCriacao"`
+}
+
+// ConfigRespostaAutomaticaRequest representa as opções gerais do auto-responder
+type ConfigRespostaAutomaticaRequest struct {
+	Ativo          bool   `json:"ativo"`
+	MensagemPadrao string `json:"mensagemPadrao" binding:"max=4096"`
+	ChavePix       string `json:"chavePix" binding:"max=77"`
+	RecebedorPix   string `json:"recebedorPix" binding:"max=25"`
+	CidadePix      string `json:"cidadePix" binding:"max=15"`
+}
+
+// ConfigRespostaAutomaticaResponse representa a configuração do auto-responder na resposta
+type ConfigRespostaAutomaticaResponse struct {
+	Ativo           bool       `json:"ativo"`
+	MensagemPadrao  string     `json:"mensagemPadrao"`
+	ChavePix        string     `json:"chavePix"`
+	RecebedorPix    string     `json:"recebedorPix"`
+	CidadePix       string     `json:"cidadePix"`
+	DataAtualizacao *time.Time `json:"dataAtualizacao,omitempty"`
+}
+
+// DisparoRespostaAutomaticaResponse representa uma resposta automática no log
+type DisparoRespostaAutomaticaResponse struct {
+	ID           uuid.UUID                    `json:"id"`
+	RespostaID   *uuid.UUID                   `json:"respostaId,omitempty"`
+	MensagemID   uuid.UUID                    `json:"mensagemId"`
+	PalavraChave string                       `json:"palavraChave,omitempty"`
+	Acao         enums.AcaoRespostaAutomatica `json:"acao,omitempty"`
+	Padrao       bool                         `json:"padrao"`
+	Telefone     string                       `json:"telefone"`
+	ClienteID    *uuid.UUID                   `json:"clienteId,omitempty"`
+	CobrancaID   *uuid.UUID                   `json:"cobrancaId,omitempty"`
+	Sucesso      bool                         `json:"sucesso"`
+	Erro         string                       `json:"erro,omitempty"`
+	DataCriacao  time.Time                    `json:"dataCriacao"`
+}
+
+// DisparoRespostaAutomaticaListResponse representa o log paginado de respostas automáticas
+type DisparoRespostaAutomaticaListResponse struct {
+	Disparos      []DisparoRespostaAutomaticaResponse `json:"disparos"`
+	Total         int64                               `json:"total"`
+	Pagina        int                                 `json:"pagina"`
+	TamanhoPagina int                                 `json:"tamanhoPagina"`
+	TotalPaginas  int                                 `json:"totalPaginas"`
+}
+
+// BuscarDisparosRespostaAutomaticaRequest representa a paginação do log de respostas automáticas
+type BuscarDisparosRespostaAutomaticaRequest struct {
+	Pagina        int `form:"pagina" binding:"min=0"`
+	TamanhoPagina int `form:"tamanhoPagina" binding:"min=0,max=100"`
+}
@@ -0,0 +1,16 @@
+package dto
+
+// SaudeResponse representa o resultado da liveness (/health/live) ou da readiness
+// (/health/ready)
+type SaudeResponse struct {
+	Status       string                              `json:"status"`
+	Verificacoes map[string]VerificacaoSaudeResponse `json:"verificacoes,omitempty"`
+}
+
+// VerificacaoSaudeResponse representa o resultado da verificação de uma dependência
+type VerificacaoSaudeResponse struct {
+	Status     string  `json:"status"`
+	Critica    bool    `json:"critica"`
+	LatenciaMs float64 `json:"latenciaMs"`
+	Erro       string  `json:"erro,omitempty"`
+}
@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/dominio/enums"
+)
+
+// EndpointWebhookRequest representa a criação ou atualização de um endpoint de webhook
+type EndpointWebhookRequest struct {
+	URL       string                `json:"url" binding:"required,url,max=500"`
+	Descricao string                `json:"descricao" binding:"max=255"`
+	Eventos   []enums.EventoWebhook `json:"eventos" binding:"required,min=1"`
+	Ativo     *bool                 `json:"ativo"`
+}
+
+// EndpointWebhookResponse representa um endpoint de webhook na resposta
+type EndpointWebhookResponse struct {
+	ID          uuid.UUID             `json:"id"`
+	URL         string                `json:"url"`
+	Descricao   string                `json:"descricao"`
+	Eventos     []enums.EventoWebhook `json:"eventos"`
+	Ativo       bool                  `json:"ativo"`
+	DataCriacao time.Time             `json:"dataCriacao"`
+}
+
+// EndpointWebhookCriadoResponse inclui o segredo de assinatura, exibido uma única vez
+type EndpointWebhookCriadoResponse struct {
+	EndpointWebhookResponse
+	Segredo string `json:"segredo"`
+}
+
+// EntregaWebhookResponse representa uma entrega no log de webhooks
+type EntregaWebhookResponse struct {
+	ID               uuid.UUID           `json:"id"`
+	Evento           enums.EventoWebhook `json:"evento"`
+	Status           string              `json:"status"`
+	Tentativas       int                 `json:"tentativas"`
+	UltimoStatusHTTP int                 `json:"ultimoStatusHttp,omitempty"`
+	UltimoErro       string              `json:"ultimoErro,omitempty"`
+	ProximaTentativa *time.Time          `json:"proximaTentativa,omitempty"`
+	DataEntrega      *time.Time          `json:"dataEntrega,omitempty"`
+	DataCriacao      time.Time           `json:"dataCriacao"`
+	Payload          string              `json:"payload"`
+}
+
+// EntregaWebhookListResponse representa a lista paginada de entregas
+type EntregaWebhookListResponse struct {
+	Entregas      []EntregaWebhookResponse `json:"entregas"`
+	Total         int64                    `json:"total"`
+	Pagina        int                      `json:"pagina"`
+	TamanhoPagina int                      `json:"tamanhoPagina"`
+	TotalPaginas  int                      `json:"totalPaginas"`
+}
+
+// BuscarEntregasWebhookRequest representa a paginação do log de entregas
+type BuscarEntregasWebhookRequest struct {
+	Pagina        int `form:"pagina" binding:"min=0"`
+	TamanhoPagina int `form:"tamanhoPagina" binding:"min=0,max=100"`
+}
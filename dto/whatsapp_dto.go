@@ -1,15 +1,50 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/ifinu/ifinu-api-go/dominio/enums"
+)
+
+// ConectarWhatsAppRequest representa a requisição de conexão de um número.
+// Sem nome, conecta (ou reconecta) o número "Principal".
+type ConectarWhatsAppRequest struct {
+	Nome             string                  `json:"nome" binding:"omitempty,max=100"`
+	TiposNotificacao []enums.TipoNotificacao `json:"tiposNotificacao"`
+}
 
 // ConectarWhatsAppResponse representa a resposta de conexão do WhatsApp
 type ConectarWhatsAppResponse struct {
+	ConexaoID     int64  `json:"conexaoId"`
 	QRCode        string `json:"qrcode"`
 	NomeInstancia string `json:"nomeInstancia"`
 }
 
+// AtualizarConexaoWhatsAppRequest representa a atualização das regras de uma conexão
+type AtualizarConexaoWhatsAppRequest struct {
+	Nome             *string                 `json:"nome" binding:"omitempty,min=1,max=100"`
+	Padrao           *bool                   `json:"padrao"`
+	TiposNotificacao []enums.TipoNotificacao `json:"tiposNotificacao"`
+}
+
+// ConexaoWhatsAppResponse representa um número WhatsApp da conta
+type ConexaoWhatsAppResponse struct {
+	ID                int64                   `json:"id"`
+	Nome              string                  `json:"nome"`
+	Padrao            bool                    `json:"padrao"`
+	TiposNotificacao  []enums.TipoNotificacao `json:"tiposNotificacao"`
+	Conectado         bool                    `json:"conectado"`
+	Status            string                  `json:"status"`
+	NomeInstancia     string                  `json:"nomeInstancia"`
+	NumeroConectado   string                  `json:"numeroConectado,omitempty"`
+	MensagensEnviadas int                     `json:"mensagensEnviadas"`
+	TaxaSucesso       float64                 `json:"taxaSucesso"`
+	DataConexao       *time.Time              `json:"dataConexao,omitempty"`
+}
+
 // StatusWhatsAppResponse representa o status da conexão WhatsApp
 type StatusWhatsAppResponse struct {
+	ConexaoID     int64     `json:"conexaoId,omitempty"`
 	Conectado     bool      `json:"conectado"`
 	NomeInstancia string    `json:"nomeInstancia,omitempty"`
 	QRCode        string    `json:"qrcode,omitempty"`
@@ -20,6 +55,77 @@ type StatusWhatsAppResponse struct {
 type EnviarMensagemRequest struct {
 	Telefone string `json:"telefone" binding:"required"`
 	Mensagem string `json:"mensagem" binding:"required"`
+	// Força o envio por um número específico (sem failover)
+	ConexaoID *int64 `json:"conexaoId"`
+
+	// Conteúdo opcional: preview do link, anexos, botões e lista interativa
+	LinkPreview bool            `json:"linkPreview"`
+	Anexos      []AnexoWhatsApp `json:"anexos" binding:"omitempty,max=5,dive"`
+	Botoes      []BotaoWhatsApp `json:"botoes" binding:"omitempty,max=3,dive"`
+	Rodape      string          `json:"rodape" binding:"omitempty,max=60"`
+	Lista       *ListaWhatsApp  `json:"lista"`
+}
+
+// Conteudo monta a mensagem rica a partir da requisição
+func (r *EnviarMensagemRequest) Conteudo() MensagemWhatsApp {
+	return MensagemWhatsApp{
+		Texto:       r.Mensagem,
+		LinkPreview: r.LinkPreview,
+		Anexos:      r.Anexos,
+		Botoes:      r.Botoes,
+		Rodape:      r.Rodape,
+		Lista:       r.Lista,
+	}
+}
+
+// MensagemWhatsApp é o conteúdo de um envio: texto e, quando houver, anexos,
+// botões ou lista interativa. Também é serializada na fila de mensagens.
+type MensagemWhatsApp struct {
+	Texto       string          `json:"texto"`
+	LinkPreview bool            `json:"linkPreview,omitempty"`
+	Anexos      []AnexoWhatsApp `json:"anexos,omitempty"`
+	Botoes      []BotaoWhatsApp `json:"botoes,omitempty"`
+	Rodape      string          `json:"rodape,omitempty"`
+	Lista       *ListaWhatsApp  `json:"lista,omitempty"`
+}
+
+// AnexoWhatsApp é uma imagem ou documento enviado por URL pública ou em base64
+type AnexoWhatsApp struct {
+	Tipo        enums.TipoAnexoWhatsApp `json:"tipo" binding:"required"`
+	URL         string                  `json:"url,omitempty" binding:"omitempty,url"`
+	Base64      string                  `json:"base64,omitempty"`
+	MimeType    string                  `json:"mimeType,omitempty"`
+	NomeArquivo string                  `json:"nomeArquivo,omitempty" binding:"omitempty,max=100"`
+	Legenda     string                  `json:"legenda,omitempty" binding:"omitempty,max=1000"`
+}
+
+// BotaoWhatsApp é um botão interativo (resposta rápida, link ou copiar código)
+type BotaoWhatsApp struct {
+	Tipo   enums.TipoBotaoWhatsApp `json:"tipo" binding:"required"`
+	Texto  string                  `json:"texto" binding:"required,max=20"`
+	ID     string                  `json:"id,omitempty"`
+	URL    string                  `json:"url,omitempty" binding:"omitempty,url"`
+	Codigo string                  `json:"codigo,omitempty"`
+}
+
+// ListaWhatsApp é uma lista de opções aberta por um botão
+type ListaWhatsApp struct {
+	Titulo     string               `json:"titulo" binding:"required,max=60"`
+	TextoBotao string               `json:"textoBotao" binding:"required,max=20"`
+	Secoes     []SecaoListaWhatsApp `json:"secoes" binding:"required,min=1,max=10,dive"`
+}
+
+// SecaoListaWhatsApp agrupa itens de uma lista interativa
+type SecaoListaWhatsApp struct {
+	Titulo string              `json:"titulo" binding:"required,max=24"`
+	Itens  []ItemListaWhatsApp `json:"itens" binding:"required,min=1,max=10,dive"`
+}
+
+// ItemListaWhatsApp é uma opção da lista; o ID volta na resposta do cliente
+type ItemListaWhatsApp struct {
+	ID        string `json:"id" binding:"required,max=200"`
+	Titulo    string `json:"titulo" binding:"required,max=24"`
+	Descricao string `json:"descricao,omitempty" binding:"omitempty,max=72"`
 }
 
 // EnviarMensagemResponse representa a resposta de envio de mensagem
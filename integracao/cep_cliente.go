@@ -0,0 +1,158 @@
+package integracao
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+var ErrCEPNaoEncontrado = errors.New("CEP não encontrado")
+
+// EnderecoCEP é o endereço de um CEP, independente do provedor
+type EnderecoCEP struct {
+	CEP         string `json:"cep"`
+	Logradouro  string `json:"logradouro"`
+	Complemento string `json:"complemento"`
+	Bairro      string `json:"bairro"`
+	Cidade      string `json:"cidade"`
+	UF          string `json:"uf"`
+	IBGE        string `json:"ibge"`
+}
+
+// ProvedorCEP consulta o endereço de um CEP (8 dígitos, sem máscara).
+// Retorna ErrCEPNaoEncontrado quando o CEP não existe.
+type ProvedorCEP interface {
+	BuscarCEP(cep string) (*EnderecoCEP, error)
+}
+
+// NovoProvedorCEP escolhe o provedor por CEP_PROVIDER: "viacep" (padrão) ou "local",
+// que lê os endereços do arquivo JSON em CEP_FIXTURE_PATH (desenvolvimento e testes)
+func NovoProvedorCEP() (ProvedorCEP, error) {
+	switch provedor := strings.ToLower(viper.GetString("CEP_PROVIDER")); provedor {
+	case "", "viacep":
+		return NovoViaCEPCliente(), nil
+	case "local":
+		return CarregarProvedorCEPLocal(viper.GetString("CEP_FIXTURE_PATH"))
+	default:
+		return nil, fmt.Errorf("provedor de CEP desconhecido: %s", provedor)
+	}
+}
+
+type ViaCEPCliente struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NovoViaCEPCliente() *ViaCEPCliente {
+	baseURL := viper.GetString("VIACEP_URL")
+	if baseURL == "" {
+		baseURL = "https://viacep.com.br"
+	}
+
+	return &ViaCEPCliente{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client: &http.Client{
+			Timeout: 5 * time.Second,
+		},
+	}
+}
+
+// respostaViaCEP é o retorno de /ws/{cep}/json/ ({"erro": true} para CEP inexistente)
+type respostaViaCEP struct {
+	CEP         string      `json:"cep"`
+	Logradouro  string      `json:"logradouro"`
+	Complemento string      `json:"complemento"`
+	Bairro      string      `json:"bairro"`
+	Localidade  string      `json:"localidade"`
+	UF          string      `json:"uf"`
+	IBGE        string      `json:"ibge"`
+	Erro        interface{} `json:"erro"` // bool ou "true", conforme a versão da API
+}
+
+// BuscarCEP consulta o ViaCEP
+func (c *ViaCEPCliente) BuscarCEP(cep string) (*EnderecoCEP, error) {
+	url := fmt.Sprintf("%s/ws/%s/json/", c.baseURL, cep)
+
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// O ViaCEP responde 400 para CEP mal formado
+	if resp.StatusCode == http.StatusBadRequest {
+		return nil, ErrCEPNaoEncontrado
+	}
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("erro ao consultar CEP: %s - %s", resp.Status, string(bodyBytes))
+	}
+
+	var result respostaViaCEP
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if result.Erro != nil && result.Erro != false {
+		return nil, ErrCEPNaoEncontrado
+	}
+
+	return &EnderecoCEP{
+		CEP:         cep,
+		Logradouro:  result.Logradouro,
+		Complemento: result.Complemento,
+		Bairro:      result.Bairro,
+		Cidade:      result.Localidade,
+		UF:          result.UF,
+		IBGE:        result.IBGE,
+	}, nil
+}
+
+// ProvedorCEPLocal responde a partir de endereços fixos, sem acesso à rede
+type ProvedorCEPLocal struct {
+	enderecos map[string]EnderecoCEP
+}
+
+// NovoProvedorCEPLocal cria o provedor com os endereços informados (chave: CEP sem máscara)
+func NovoProvedorCEPLocal(enderecos map[string]EnderecoCEP) *ProvedorCEPLocal {
+	return &ProvedorCEPLocal{enderecos: enderecos}
+}
+
+// CarregarProvedorCEPLocal lê os endereços de um arquivo JSON com uma lista de EnderecoCEP
+func CarregarProvedorCEPLocal(caminho string) (*ProvedorCEPLocal, error) {
+	if caminho == "" {
+		return nil, errors.New("CEP_FIXTURE_PATH não configurado")
+	}
+
+	conteudo, err := os.ReadFile(caminho)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler endereços de CEP: %w", err)
+	}
+
+	var lista []EnderecoCEP
+	if err := json.Unmarshal(conteudo, &lista); err != nil {
+		return nil, fmt.Errorf("erro ao ler endereços de CEP: %w", err)
+	}
+
+	enderecos := make(map[string]EnderecoCEP, len(lista))
+	for _, endereco := range lista {
+		endereco.CEP = strings.ReplaceAll(endereco.CEP, "-", "")
+		enderecos[endereco.CEP] = endereco
+	}
+	return NovoProvedorCEPLocal(enderecos), nil
+}
+
+// BuscarCEP retorna o endereço cadastrado para o CEP
+func (p *ProvedorCEPLocal) BuscarCEP(cep string) (*EnderecoCEP, error) {
+	endereco, ok := p.enderecos[cep]
+	if !ok {
+		return nil, ErrCEPNaoEncontrado
+	}
+	return &endereco, nil
+}
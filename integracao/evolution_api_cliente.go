@@ -2,38 +2,131 @@ package integracao
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/ifinu/ifinu-api-go/logs"
+	"github.com/ifinu/ifinu-api-go/rastreamento"
 	"github.com/spf13/viper"
 )
 
 type EvolutionAPICliente struct {
-	baseURL string
-	apiKey  string
-	client  *http.Client
+	baseURL      string
+	apiKey       string
+	webhookURL   string
+	webhookToken string
+	client       *http.Client
 }
 
 func NovoEvolutionAPICliente() *EvolutionAPICliente {
 	return &EvolutionAPICliente{
-		baseURL: viper.GetString("EVOLUTION_API_URL"),
-		apiKey:  viper.GetString("EVOLUTION_API_KEY"),
+		baseURL:      viper.GetString("EVOLUTION_API_URL"),
+		apiKey:       viper.GetString("EVOLUTION_API_KEY"),
+		webhookURL:   viper.GetString("EVOLUTION_WEBHOOK_URL"),
+		webhookToken: viper.GetString("EVOLUTION_WEBHOOK_TOKEN"),
 		client: &http.Client{
-			Timeout: 120 * time.Second, // Aumentado para 120s devido a lentidão da Evolution API
+			Timeout:   120 * time.Second, // Aumentado para 120s devido a lentidão da Evolution API
+			Transport: rastreamento.Transporte(logs.Transporte(nil)),
 		},
 	}
 }
 
 // CriarInstanciaRequest representa a requisição para criar uma instância
 type CriarInstanciaRequest struct {
-	InstanceName string `json:"instanceName"`
-	Integration  string `json:"integration"`
-	Token        string `json:"token,omitempty"`
-	Qrcode       bool   `json:"qrcode"`
+	InstanceName string            `json:"instanceName"`
+	Integration  string            `json:"integration"`
+	Token        string            `json:"token,omitempty"`
+	Qrcode       bool              `json:"qrcode"`
+	Webhook      *WebhookInstancia `json:"webhook,omitempty"`
+}
+
+// WebhookInstancia configura o envio de eventos da instância para a API
+type WebhookInstancia struct {
+	Enabled  bool              `json:"enabled"`
+	URL      string            `json:"url"`
+	ByEvents bool              `json:"byEvents"`
+	Base64   bool              `json:"base64"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	Events   []string          `json:"events"`
+}
+
+// EventoWebhookEvolution é o envelope dos eventos enviados pela Evolution API
+type EventoWebhookEvolution struct {
+	Event    string          `json:"event"`
+	Instance string          `json:"instance"`
+	Data     json.RawMessage `json:"data"`
+	APIKey   string          `json:"apikey"`
+}
+
+// IsMensagemRecebida verifica se é um evento MESSAGES_UPSERT ("messages.upsert")
+func (e *EventoWebhookEvolution) IsMensagemRecebida() bool {
+	evento := strings.ToLower(strings.ReplaceAll(e.Event, "_", "."))
+	return evento == "messages.upsert"
+}
+
+// MensagemEvolution representa o campo data de um evento MESSAGES_UPSERT
+type MensagemEvolution struct {
+	Key struct {
+		RemoteJid string `json:"remoteJid"`
+		FromMe    bool   `json:"fromMe"`
+		ID        string `json:"id"`
+	} `json:"key"`
+	PushName string `json:"pushName"`
+	Message  struct {
+		Conversation        string `json:"conversation"`
+		ExtendedTextMessage struct {
+			Text string `json:"text"`
+		} `json:"extendedTextMessage"`
+		ImageMessage struct {
+			Caption string `json:"caption"`
+		} `json:"imageMessage"`
+		VideoMessage struct {
+			Caption string `json:"caption"`
+		} `json:"videoMessage"`
+		DocumentMessage struct {
+			Caption  string `json:"caption"`
+			FileName string `json:"fileName"`
+		} `json:"documentMessage"`
+	} `json:"message"`
+	MessageType      string      `json:"messageType"`
+	MessageTimestamp json.Number `json:"messageTimestamp"`
+}
+
+// Texto retorna o conteúdo textual da mensagem (texto, legenda ou nome do arquivo)
+func (m *MensagemEvolution) Texto() string {
+	for _, texto := range []string{
+		m.Message.Conversation,
+		m.Message.ExtendedTextMessage.Text,
+		m.Message.ImageMessage.Caption,
+		m.Message.VideoMessage.Caption,
+		m.Message.DocumentMessage.Caption,
+		m.Message.DocumentMessage.FileName,
+	} {
+		if texto != "" {
+			return texto
+		}
+	}
+	return ""
+}
+
+// IsConversaIndividual descarta grupos, status e canais
+func (m *MensagemEvolution) IsConversaIndividual() bool {
+	return strings.HasSuffix(m.Key.RemoteJid, "@s.whatsapp.net")
+}
+
+// Data retorna a data da mensagem (ou agora, se o timestamp não vier)
+func (m *MensagemEvolution) Data() time.Time {
+	if segundos, err := m.MessageTimestamp.Int64(); err == nil && segundos > 0 {
+		return time.Unix(segundos, 0)
+	}
+	return time.Now()
 }
 
 // CriarInstanciaResponse representa a resposta ao criar uma instância
@@ -64,9 +157,63 @@ type StatusInstanciaResponse struct {
 type EnviarMensagemRequest struct {
 	Number      string            `json:"number"`
 	Text        string            `json:"text,omitempty"`
+	LinkPreview bool              `json:"linkPreview,omitempty"`
 	Options     map[string]string `json:"options,omitempty"`
 }
 
+// EnviarMidiaRequest representa o envio de imagem ou documento.
+// Media aceita URL pública ou conteúdo em base64 (sem o prefixo data:).
+type EnviarMidiaRequest struct {
+	Number    string `json:"number"`
+	Mediatype string `json:"mediatype"` // image | document
+	Mimetype  string `json:"mimetype,omitempty"`
+	Caption   string `json:"caption,omitempty"`
+	Media     string `json:"media"`
+	FileName  string `json:"fileName,omitempty"`
+}
+
+// BotaoEvolution representa um botão interativo.
+// Type: reply (usa ID), url (usa URL) ou copy (usa CopyCode).
+type BotaoEvolution struct {
+	Type        string `json:"type"`
+	DisplayText string `json:"displayText"`
+	ID          string `json:"id,omitempty"`
+	URL         string `json:"url,omitempty"`
+	CopyCode    string `json:"copyCode,omitempty"`
+}
+
+// EnviarBotoesRequest representa o envio de mensagem com botões
+type EnviarBotoesRequest struct {
+	Number      string           `json:"number"`
+	Title       string           `json:"title"`
+	Description string           `json:"description"`
+	Footer      string           `json:"footer,omitempty"`
+	Buttons     []BotaoEvolution `json:"buttons"`
+}
+
+// LinhaListaEvolution representa uma opção da lista; RowID volta na resposta do cliente
+type LinhaListaEvolution struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	RowID       string `json:"rowId"`
+}
+
+// SecaoListaEvolution agrupa opções de uma lista
+type SecaoListaEvolution struct {
+	Title string                `json:"title"`
+	Rows  []LinhaListaEvolution `json:"rows"`
+}
+
+// EnviarListaRequest representa o envio de mensagem com lista de opções
+type EnviarListaRequest struct {
+	Number      string                `json:"number"`
+	Title       string                `json:"title"`
+	Description string                `json:"description"`
+	ButtonText  string                `json:"buttonText"`
+	FooterText  string                `json:"footerText,omitempty"`
+	Sections    []SecaoListaEvolution `json:"sections"`
+}
+
 // EnviarMensagemResponse representa a resposta de envio de mensagem
 type EnviarMensagemResponse struct {
 	Key struct {
@@ -82,7 +229,7 @@ type EnviarMensagemResponse struct {
 }
 
 // CriarInstancia cria uma nova instância no Evolution API
-func (c *EvolutionAPICliente) CriarInstancia(nomeInstancia string) (*CriarInstanciaResponse, error) {
+func (c *EvolutionAPICliente) CriarInstancia(ctx context.Context, nomeInstancia string) (*CriarInstanciaResponse, error) {
 	url := fmt.Sprintf("%s/instance/create", c.baseURL)
 
 	payload := CriarInstanciaRequest{
@@ -91,12 +238,22 @@ func (c *EvolutionAPICliente) CriarInstancia(nomeInstancia string) (*CriarInstan
 		Qrcode:       true,
 	}
 
+	// Receber mensagens dos clientes (caixa de entrada)
+	if c.webhookURL != "" {
+		payload.Webhook = &WebhookInstancia{
+			Enabled: true,
+			URL:     c.webhookURL,
+			Headers: map[string]string{"apikey": c.webhookToken},
+			Events:  []string{"MESSAGES_UPSERT"},
+		}
+	}
+
 	body, err := json.Marshal(payload)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(body))
 	if err != nil {
 		return nil, err
 	}
@@ -125,10 +282,10 @@ func (c *EvolutionAPICliente) CriarInstancia(nomeInstancia string) (*CriarInstan
 }
 
 // ObterQRCode obtém o QR code de uma instância
-func (c *EvolutionAPICliente) ObterQRCode(nomeInstancia string) (string, error) {
+func (c *EvolutionAPICliente) ObterQRCode(ctx context.Context, nomeInstancia string) (string, error) {
 	url := fmt.Sprintf("%s/instance/connect/%s", c.baseURL, nomeInstancia)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return "", err
 	}
@@ -155,10 +312,10 @@ func (c *EvolutionAPICliente) ObterQRCode(nomeInstancia string) (string, error)
 }
 
 // ObterStatus obtém o status de uma instância
-func (c *EvolutionAPICliente) ObterStatus(nomeInstancia string) (*StatusInstanciaResponse, error) {
+func (c *EvolutionAPICliente) ObterStatus(ctx context.Context, nomeInstancia string) (*StatusInstanciaResponse, error) {
 	url := fmt.Sprintf("%s/instance/connectionState/%s", c.baseURL, nomeInstancia)
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -184,21 +341,74 @@ func (c *EvolutionAPICliente) ObterStatus(nomeInstancia string) (*StatusInstanci
 	return &result, nil
 }
 
-// EnviarMensagemTexto envia uma mensagem de texto
-func (c *EvolutionAPICliente) EnviarMensagemTexto(nomeInstancia, telefone, mensagem string) (*EnviarMensagemResponse, error) {
-	url := fmt.Sprintf("%s/message/sendText/%s", c.baseURL, nomeInstancia)
+// Verificar confirma que a Evolution API está respondendo. Qualquer status abaixo de 500
+// conta como disponível: a verificação é de alcance, não de autenticação.
+func (c *EvolutionAPICliente) Verificar(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL, nil)
+	if err != nil {
+		return err
+	}
 
-	payload := EnviarMensagemRequest{
+	req.Header.Set("apikey", c.apiKey)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("evolution api respondeu %s", resp.Status)
+	}
+	return nil
+}
+
+// EnviarMensagemTexto envia uma mensagem de texto
+func (c *EvolutionAPICliente) EnviarMensagemTexto(ctx context.Context, nomeInstancia, telefone, mensagem string) (*EnviarMensagemResponse, error) {
+	return c.enviarMensagem(ctx, "sendText", nomeInstancia, EnviarMensagemRequest{
 		Number: telefone,
 		Text:   mensagem,
-	}
+	})
+}
+
+// EnviarMensagemComPreview envia uma mensagem de texto com a prévia do primeiro link
+func (c *EvolutionAPICliente) EnviarMensagemComPreview(ctx context.Context, nomeInstancia, telefone, mensagem string) (*EnviarMensagemResponse, error) {
+	return c.enviarMensagem(ctx, "sendText", nomeInstancia, EnviarMensagemRequest{
+		Number:      telefone,
+		Text:        mensagem,
+		LinkPreview: true,
+	})
+}
+
+// EnviarMidia envia uma imagem ou documento (URL pública ou base64)
+func (c *EvolutionAPICliente) EnviarMidia(ctx context.Context, nomeInstancia, telefone string, midia EnviarMidiaRequest) (*EnviarMensagemResponse, error) {
+	midia.Number = telefone
+	return c.enviarMensagem(ctx, "sendMedia", nomeInstancia, midia)
+}
+
+// EnviarBotoes envia uma mensagem com botões interativos
+func (c *EvolutionAPICliente) EnviarBotoes(ctx context.Context, nomeInstancia, telefone string, botoes EnviarBotoesRequest) (*EnviarMensagemResponse, error) {
+	botoes.Number = telefone
+	return c.enviarMensagem(ctx, "sendButtons", nomeInstancia, botoes)
+}
+
+// EnviarLista envia uma mensagem com lista de opções
+func (c *EvolutionAPICliente) EnviarLista(ctx context.Context, nomeInstancia, telefone string, lista EnviarListaRequest) (*EnviarMensagemResponse, error) {
+	lista.Number = telefone
+	return c.enviarMensagem(ctx, "sendList", nomeInstancia, lista)
+}
+
+// enviarMensagem faz o POST em /message/{endpoint}/{instância} e decodifica a resposta
+func (c *EvolutionAPICliente) enviarMensagem(ctx context.Context, endpoint, nomeInstancia string, payload interface{}) (*EnviarMensagemResponse, error) {
+	url := fmt.Sprintf("%s/message/%s/%s", c.baseURL, endpoint, nomeInstancia)
 
 	body, err := json.Marshal(payload)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(body))
 	if err != nil {
 		return nil, err
 	}
@@ -213,8 +423,8 @@ func (c *EvolutionAPICliente) EnviarMensagemTexto(nomeInstancia, telefone, mensa
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("erro ao enviar mensagem: %s - %s", resp.Status, string(bodyBytes))
+		bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 1000))
+		return nil, &ErroRespostaEvolution{Status: resp.StatusCode, Corpo: string(bodyBytes)}
 	}
 
 	var result EnviarMensagemResponse
@@ -226,11 +436,179 @@ func (c *EvolutionAPICliente) EnviarMensagemTexto(nomeInstancia, telefone, mensa
 	return &result, nil
 }
 
+// ErroRespostaEvolution é a resposta de erro (status diferente de 200/201) a um envio
+type ErroRespostaEvolution struct {
+	Status int
+	Corpo  string
+}
+
+func (e *ErroRespostaEvolution) Error() string {
+	return fmt.Sprintf("erro ao enviar mensagem: %d %s - %s", e.Status, http.StatusText(e.Status), e.Corpo)
+}
+
+// Trechos das respostas 5xx da Evolution API em que a instância recusou o envio antes de
+// falar com o WhatsApp (desconectada ou inexistente)
+var falhasAntesDoEnvio = []string{"connection closed", "not connected", "does not exist", "not found"}
+
+// EnvioNaoRealizado indica se o erro de um envio garante que a mensagem não saiu: a
+// conexão com a Evolution API não foi aberta, a requisição foi recusada (4xx) ou a
+// instância está desconectada/inexistente. Timeouts, conexões interrompidas e demais 5xx
+// são incertos (a mensagem pode ter sido entregue) e retornam false.
+func EnvioNaoRealizado(err error) bool {
+	var resposta *ErroRespostaEvolution
+	if errors.As(err, &resposta) {
+		if resposta.Status < http.StatusInternalServerError {
+			return true
+		}
+		corpo := strings.ToLower(resposta.Corpo)
+		for _, trecho := range falhasAntesDoEnvio {
+			if strings.Contains(corpo, trecho) {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Falha ao conectar (recusada, DNS, rota): nada foi enviado
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
+}
+
+// NumeroWhatsApp é um item da resposta de /chat/whatsappNumbers
+type NumeroWhatsApp struct {
+	Exists bool   `json:"exists"`
+	JID    string `json:"jid"`
+	Number string `json:"number"`
+}
+
+// VerificarNumeros consulta quais números têm conta no WhatsApp
+func (c *EvolutionAPICliente) VerificarNumeros(ctx context.Context, nomeInstancia string, numeros []string) ([]NumeroWhatsApp, error) {
+	url := fmt.Sprintf("%s/chat/whatsappNumbers/%s", c.baseURL, nomeInstancia)
+
+	body, err := json.Marshal(map[string][]string{"numbers": numeros})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("apikey", c.apiKey)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("erro ao verificar números: %s - %s", resp.Status, string(bodyBytes))
+	}
+
+	var result []NumeroWhatsApp
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// InstanciaEvolution é um item de /instance/fetchInstances.
+// A v2 da Evolution API retorna os campos na raiz; a v1, dentro de "instance".
+type InstanciaEvolution struct {
+	Name             string `json:"name"`
+	ConnectionStatus string `json:"connectionStatus"`
+	Instance         *struct {
+		InstanceName string `json:"instanceName"`
+		Status       string `json:"status"`
+	} `json:"instance"`
+}
+
+// Nome retorna o nome da instância em qualquer versão da API
+func (i *InstanciaEvolution) Nome() string {
+	if i.Name == "" && i.Instance != nil {
+		return i.Instance.InstanceName
+	}
+	return i.Name
+}
+
+// Conectada verifica se a instância está com o WhatsApp aberto
+func (i *InstanciaEvolution) Conectada() bool {
+	if i.ConnectionStatus == "" && i.Instance != nil {
+		return i.Instance.Status == "open"
+	}
+	return i.ConnectionStatus == "open"
+}
+
+// ListarInstancias lista todas as instâncias da Evolution API
+func (c *EvolutionAPICliente) ListarInstancias(ctx context.Context) ([]InstanciaEvolution, error) {
+	url := fmt.Sprintf("%s/instance/fetchInstances", c.baseURL)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("apikey", c.apiKey)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("erro ao listar instâncias: %s - %s", resp.Status, string(bodyBytes))
+	}
+
+	var result []InstanciaEvolution
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Reconectar pede à Evolution API que reabra a sessão salva da instância
+func (c *EvolutionAPICliente) Reconectar(ctx context.Context, nomeInstancia string) error {
+	url := fmt.Sprintf("%s/instance/connect/%s", c.baseURL, nomeInstancia)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("apikey", c.apiKey)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("erro ao reconectar instância")
+	}
+
+	return nil
+}
+
 // DeletarInstancia remove uma instância
-func (c *EvolutionAPICliente) DeletarInstancia(nomeInstancia string) error {
+func (c *EvolutionAPICliente) DeletarInstancia(ctx context.Context, nomeInstancia string) error {
 	url := fmt.Sprintf("%s/instance/delete/%s", c.baseURL, nomeInstancia)
 
-	req, err := http.NewRequest("DELETE", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
 	if err != nil {
 		return err
 	}
@@ -251,10 +629,10 @@ func (c *EvolutionAPICliente) DeletarInstancia(nomeInstancia string) error {
 }
 
 // Desconectar desconecta uma instância
-func (c *EvolutionAPICliente) Desconectar(nomeInstancia string) error {
+func (c *EvolutionAPICliente) Desconectar(ctx context.Context, nomeInstancia string) error {
 	url := fmt.Sprintf("%s/instance/logout/%s", c.baseURL, nomeInstancia)
 
-	req, err := http.NewRequest("DELETE", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
 	if err != nil {
 		return err
 	}
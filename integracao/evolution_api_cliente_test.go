@@ -0,0 +1,63 @@
+package integracao
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestEnvioNaoRealizado(t *testing.T) {
+	responder := func(status int, corpo string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+			w.Write([]byte(corpo))
+		}
+	}
+
+	casos := []struct {
+		nome       string
+		handler    http.HandlerFunc
+		naoEnviado bool
+	}{
+		{"instância inexistente (404)", responder(http.StatusNotFound, `{"message":"The \"x\" instance does not exist"}`), true},
+		{"requisição recusada (400)", responder(http.StatusBadRequest, `{"message":"invalid number"}`), true},
+		{"instância desconectada (500)", responder(http.StatusInternalServerError, `{"message":"Connection Closed"}`), true},
+		{"erro interno genérico (500)", responder(http.StatusInternalServerError, `{"message":"internal error"}`), false},
+		{"gateway (502)", responder(http.StatusBadGateway, ""), false},
+		{"timeout", func(w http.ResponseWriter, r *http.Request) { time.Sleep(200 * time.Millisecond) }, false},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			servidor := httptest.NewServer(caso.handler)
+			defer servidor.Close()
+
+			cliente := &EvolutionAPICliente{baseURL: servidor.URL, client: &http.Client{Timeout: 50 * time.Millisecond}}
+			_, err := cliente.EnviarMensagemTexto(context.Background(), "instancia", "5511999999999", "oi")
+			if err == nil {
+				t.Fatal("esperado erro")
+			}
+			if got := EnvioNaoRealizado(err); got != caso.naoEnviado {
+				t.Fatalf("EnvioNaoRealizado(%v) = %v, esperado %v", err, got, caso.naoEnviado)
+			}
+		})
+	}
+
+	t.Run("conexão recusada", func(t *testing.T) {
+		ouvinte, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		endereco := ouvinte.Addr().String()
+		ouvinte.Close()
+
+		cliente := &EvolutionAPICliente{baseURL: "http://" + endereco, client: &http.Client{Timeout: time.Second}}
+		_, err = cliente.EnviarMensagemTexto(context.Background(), "instancia", "5511999999999", "oi")
+		if err == nil || !EnvioNaoRealizado(err) {
+			t.Fatalf("EnvioNaoRealizado(%v) = false, esperado true", err)
+		}
+	})
+}
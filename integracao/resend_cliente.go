@@ -2,13 +2,19 @@ package integracao
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"html"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/ifinu/ifinu-api-go/logs"
+	"github.com/ifinu/ifinu-api-go/metricas"
+	"github.com/ifinu/ifinu-api-go/rastreamento"
 	"github.com/spf13/viper"
 )
 
@@ -21,18 +27,20 @@ func NovoResendCliente() *ResendCliente {
 	return &ResendCliente{
 		apiKey: viper.GetString("RESEND_API_KEY"),
 		client: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: rastreamento.Transporte(logs.Transporte(nil)),
 		},
 	}
 }
 
 // EnviarEmailRequest representa a requisição de envio de email
 type EnviarEmailRequest struct {
-	From    string   `json:"from"`
-	To      []string `json:"to"`
-	Subject string   `json:"subject"`
-	Html    string   `json:"html,omitempty"`
-	Text    string   `json:"text,omitempty"`
+	From    string            `json:"from"`
+	To      []string          `json:"to"`
+	Subject string            `json:"subject"`
+	Html    string            `json:"html,omitempty"`
+	Text    string            `json:"text,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
 }
 
 // EnviarEmailResponse representa a resposta de envio de email
@@ -41,23 +49,64 @@ type EnviarEmailResponse struct {
 }
 
 // EnviarEmail envia um email usando a API do Resend
-func (c *ResendCliente) EnviarEmail(de, para, assunto, html, texto string) (string, error) {
-	url := "https://api.resend.com/emails"
-
-	payload := EnviarEmailRequest{
+func (c *ResendCliente) EnviarEmail(ctx context.Context, de, para, assunto, html, texto string) (string, error) {
+	return c.enviar(ctx, EnviarEmailRequest{
 		From:    de,
 		To:      []string{para},
 		Subject: assunto,
 		Html:    html,
 		Text:    texto,
+	})
+}
+
+// enviarEmailCliente envia um email de cobrança ao cliente final. Com link de descadastro,
+// inclui o rodapé e os cabeçalhos List-Unsubscribe (descadastro em um clique).
+func (c *ResendCliente) enviarEmailCliente(ctx context.Context, para, assunto, corpoHTML, texto, linkDescadastro string) error {
+	payload := EnviarEmailRequest{
+		From:    "noreply@ifinu.io",
+		To:      []string{para},
+		Subject: assunto,
+		Html:    strings.Replace(corpoHTML, "</body>", rodapeDescadastroHTML(linkDescadastro)+"</body>", 1),
+		Text:    texto,
 	}
 
+	if linkDescadastro != "" {
+		payload.Text += "\n\nNão quer mais receber estes lembretes? Descadastre-se: " + linkDescadastro
+		payload.Headers = map[string]string{
+			"List-Unsubscribe":      "<" + linkDescadastro + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		}
+	}
+
+	_, err := c.enviar(ctx, payload)
+	return err
+}
+
+// rodapeDescadastroHTML monta o rodapé com o link de descadastro
+func rodapeDescadastroHTML(linkDescadastro string) string {
+	if linkDescadastro == "" {
+		return ""
+	}
+	return fmt.Sprintf(`    <div style="max-width: 600px; margin: 0 auto; padding: 0 20px 20px; font-size: 12px; color: #6b7280;">
+        Não quer mais receber estes lembretes? <a href="%s" style="color: #6b7280;">Descadastre-se</a>.
+    </div>
+`, html.EscapeString(linkDescadastro))
+}
+
+// enviar envia o payload para a API do Resend e retorna o ID do email
+func (c *ResendCliente) enviar(ctx context.Context, payload EnviarEmailRequest) (id string, err error) {
+	defer func() {
+		metricas.EnviosEmail.WithLabelValues(metricas.Resultado(err)).Inc()
+	}()
+
+	url := "https://api.resend.com/emails"
+
 	body, err := json.Marshal(payload)
 	if err != nil {
 		return "", err
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(body))
 	if err != nil {
 		return "", err
 	}
@@ -85,8 +134,31 @@ func (c *ResendCliente) EnviarEmail(de, para, assunto, html, texto string) (stri
 	return result.ID, nil
 }
 
+// Verificar confirma que a API do Resend está respondendo. Qualquer status abaixo de 500
+// conta como disponível (chaves só de envio recebem 401 na listagem de domínios).
+func (c *ResendCliente) Verificar(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.resend.com/domains", nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("resend respondeu %s", resp.Status)
+	}
+	return nil
+}
+
 // EnviarEmailCobranca envia email de notificação de cobrança
-func (c *ResendCliente) EnviarEmailCobranca(para, nomeCliente, descricao string, valor float64, dataVencimento string) error {
+func (c *ResendCliente) EnviarEmailCobranca(ctx context.Context, para, nomeCliente, descricao string, valor float64, dataVencimento string, linkDescadastro string) error {
 	assunto := "Nova Cobrança - IFINU"
 
 	html := fmt.Sprintf(`
@@ -115,12 +187,11 @@ func (c *ResendCliente) EnviarEmailCobranca(para, nomeCliente, descricao string,
 	texto := fmt.Sprintf("Nova Cobrança - Descrição: %s - Valor: R$ %.2f - Vencimento: %s",
 		descricao, valor, dataVencimento)
 
-	_, err := c.EnviarEmail("noreply@ifinu.io", para, assunto, html, texto)
-	return err
+	return c.enviarEmailCliente(ctx, para, assunto, html, texto, linkDescadastro)
 }
 
 // EnviarEmailLembrete envia email de lembrete de vencimento
-func (c *ResendCliente) EnviarEmailLembrete(para, nomeCliente, descricao string, valor float64, dataVencimento string) error {
+func (c *ResendCliente) EnviarEmailLembrete(ctx context.Context, para, nomeCliente, descricao string, valor float64, dataVencimento string, linkDescadastro string) error {
 	assunto := "Lembrete: Cobrança vence em 3 dias - IFINU"
 
 	html := fmt.Sprintf(`
@@ -149,12 +220,11 @@ func (c *ResendCliente) EnviarEmailLembrete(para, nomeCliente, descricao string,
 	texto := fmt.Sprintf("Lembrete: Cobrança vence em 3 dias - Descrição: %s - Valor: R$ %.2f - Vencimento: %s",
 		descricao, valor, dataVencimento)
 
-	_, err := c.EnviarEmail("noreply@ifinu.io", para, assunto, html, texto)
-	return err
+	return c.enviarEmailCliente(ctx, para, assunto, html, texto, linkDescadastro)
 }
 
 // EnviarEmailVencimento envia email de vencimento hoje
-func (c *ResendCliente) EnviarEmailVencimento(para, nomeCliente, descricao string, valor float64) error {
+func (c *ResendCliente) EnviarEmailVencimento(ctx context.Context, para, nomeCliente, descricao string, valor float64, linkDescadastro string) error {
 	assunto := "Cobrança vence hoje - IFINU"
 
 	html := fmt.Sprintf(`
@@ -182,7 +252,71 @@ func (c *ResendCliente) EnviarEmailVencimento(para, nomeCliente, descricao strin
 
 	texto := fmt.Sprintf("Cobrança vence HOJE - Descrição: %s - Valor: R$ %.2f", descricao, valor)
 
-	_, err := c.EnviarEmail("noreply@ifinu.io", para, assunto, html, texto)
+	return c.enviarEmailCliente(ctx, para, assunto, html, texto, linkDescadastro)
+}
+
+// EnviarEmailConviteMembro envia convite para participar de uma organização
+func (c *ResendCliente) EnviarEmailConviteMembro(ctx context.Context, para, nomeOrganizacao, papel, linkConvite string) error {
+	assunto := fmt.Sprintf("Convite para a equipe %s - IFINU", nomeOrganizacao)
+
+	html := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>Convite de Equipe</title>
+</head>
+<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333;">
+    <div style="max-width: 600px; margin: 0 auto; padding: 20px;">
+        <h2 style="color: #2563eb;">Você foi convidado!</h2>
+        <p>Você foi convidado para participar da equipe <strong>%s</strong> no IFINU com o papel <strong>%s</strong>.</p>
+        <p style="margin: 30px 0;">
+            <a href="%s" style="background-color: #2563eb; color: #fff; padding: 12px 24px; border-radius: 6px; text-decoration: none;">Aceitar convite</a>
+        </p>
+        <p>Se você ainda não tem conta, cadastre-se com este mesmo email antes de aceitar.</p>
+        <p>Atenciosamente,<br>Equipe IFINU</p>
+    </div>
+</body>
+</html>
+	`, nomeOrganizacao, papel, linkConvite)
+
+	texto := fmt.Sprintf("Você foi convidado para a equipe %s no IFINU (papel: %s). Aceite em: %s",
+		nomeOrganizacao, papel, linkConvite)
+
+	_, err := c.EnviarEmail(ctx, "noreply@ifinu.io", para, assunto, html, texto)
+	return err
+}
+
+// EnviarEmailWhatsAppDesconectado avisa o usuário que um número WhatsApp da conta caiu
+func (c *ResendCliente) EnviarEmailWhatsAppDesconectado(ctx context.Context, para, nomeUsuario, nomeConexao, linkReconectar string) error {
+	assunto := fmt.Sprintf("WhatsApp \"%s\" desconectado - IFINU", nomeConexao)
+
+	html := fmt.Sprintf(`
+<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>WhatsApp desconectado</title>
+</head>
+<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333;">
+    <div style="max-width: 600px; margin: 0 auto; padding: 20px;">
+        <h2 style="color: #dc2626;">Seu WhatsApp foi desconectado</h2>
+        <p>Olá, %s!</p>
+        <p>O número <strong>%s</strong> perdeu a conexão com o WhatsApp. Estamos tentando reconectar automaticamente.</p>
+        <p>Enquanto nenhum número da conta estiver conectado, os lembretes por WhatsApp ficam pausados e serão enviados após a reconexão.</p>
+        <p style="margin: 30px 0;">
+            <a href="%s" style="background-color: #2563eb; color: #fff; padding: 12px 24px; border-radius: 6px; text-decoration: none;">Verificar conexão</a>
+        </p>
+        <p>Atenciosamente,<br>Equipe IFINU</p>
+    </div>
+</body>
+</html>
+	`, nomeUsuario, nomeConexao, linkReconectar)
+
+	texto := fmt.Sprintf("O número WhatsApp %s foi desconectado. Os lembretes por WhatsApp ficam pausados até a reconexão: %s",
+		nomeConexao, linkReconectar)
+
+	_, err := c.EnviarEmail(ctx, "noreply@ifinu.io", para, assunto, html, texto)
 	return err
 }
 
@@ -0,0 +1,22 @@
+package integracao
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ifinu/ifinu-api-go/logs"
+	"github.com/ifinu/ifinu-api-go/rastreamento"
+	"github.com/stripe/stripe-go/v81"
+)
+
+// ConfigurarStripe troca o cliente HTTP da API do Stripe por um que repassa o X-Request-ID
+// das chamadas feitas com params.Context
+func ConfigurarStripe() {
+	backend := stripe.GetBackendWithConfig(stripe.APIBackend, &stripe.BackendConfig{
+		HTTPClient: &http.Client{
+			Timeout:   80 * time.Second, // Mesmo timeout padrão do stripe-go
+			Transport: rastreamento.Transporte(logs.Transporte(nil)),
+		},
+	})
+	stripe.SetBackend(stripe.APIBackend, backend)
+}
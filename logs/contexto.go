@@ -0,0 +1,85 @@
+package logs
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+
+	"github.com/google/uuid"
+)
+
+// HeaderRequestID é o header que carrega o ID da requisição na entrada e nas chamadas de saída
+const HeaderRequestID = "X-Request-ID"
+
+// IDs recebidos de fora só são aceitos se forem curtos e sem caracteres de controle
+var requestIDValido = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
+type chaveContexto int
+
+const (
+	chaveRequestID chaveContexto = iota
+	chaveUsuarioID
+)
+
+// NovoRequestID gera um ID para uma requisição, execução de job ou mensagem sem origem
+func NovoRequestID() string {
+	return uuid.NewString()
+}
+
+// RequestIDValido indica se o ID recebido no header pode ser reaproveitado
+func RequestIDValido(id string) bool {
+	return requestIDValido.MatchString(id)
+}
+
+// ComRequestID guarda o ID da requisição no contexto
+func ComRequestID(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, chaveRequestID, id)
+}
+
+// RequestID retorna o ID da requisição do contexto, ou "" se não houver
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(chaveRequestID).(string)
+	return id
+}
+
+// ComUsuarioID guarda no contexto a conta (usuário titular) a que a operação pertence
+func ComUsuarioID(ctx context.Context, usuarioID uuid.UUID) context.Context {
+	if usuarioID == uuid.Nil {
+		return ctx
+	}
+	return context.WithValue(ctx, chaveUsuarioID, usuarioID)
+}
+
+// UsuarioID retorna a conta guardada no contexto
+func UsuarioID(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(chaveUsuarioID).(uuid.UUID)
+	return id, ok
+}
+
+// Transporte repassa o ID da requisição do contexto no header X-Request-ID das chamadas
+// HTTP de saída (Evolution, Resend, Stripe)
+func Transporte(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transporte{base: base}
+}
+
+type transporte struct {
+	base http.RoundTripper
+}
+
+func (t *transporte) RoundTrip(req *http.Request) (*http.Response, error) {
+	id := RequestID(req.Context())
+	if id == "" || req.Header.Get(HeaderRequestID) != "" {
+		return t.base.RoundTrip(req)
+	}
+
+	// RoundTripper não deve alterar a requisição recebida
+	copia := req.Clone(req.Context())
+	copia.Header.Set(HeaderRequestID, id)
+	return t.base.RoundTrip(copia)
+}
@@ -0,0 +1,117 @@
+// Package logs monta o logger estruturado (log/slog) da API. Cada registro ganha o
+// request_id, o usuario_id e o trace_id guardados no context.Context, e em produção
+// emails e telefones são mascarados.
+package logs
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/ifinu/ifinu-api-go/util"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Opcoes configura o logger criado por Novo
+type Opcoes struct {
+	Nivel slog.Level
+	// "json" (padrão) ou "texto", mais legível no terminal durante o desenvolvimento
+	Formato string
+	// Mascara emails e telefones nas mensagens e atributos
+	MascararDadosPessoais bool
+}
+
+// Atributos com dado pessoal conhecido; os demais textos passam por util.MascararTexto
+var mascarasPorCampo = map[string]func(string) string{
+	"telefone":     util.MascararTelefone,
+	"email":        util.MascararEmail,
+	"destinatario": util.MascararTexto,
+}
+
+// IDs que podem ter longas sequências de dígitos sem ser telefone
+var camposSemMascara = map[string]bool{
+	"request_id": true,
+	"trace_id":   true,
+	"span_id":    true,
+}
+
+// Novo cria o logger que escreve em saida
+func Novo(saida io.Writer, opcoes Opcoes) *slog.Logger {
+	handlerOpcoes := &slog.HandlerOptions{Level: opcoes.Nivel}
+	if opcoes.MascararDadosPessoais {
+		handlerOpcoes.ReplaceAttr = mascararAtributo
+	}
+
+	var handler slog.Handler
+	if strings.EqualFold(opcoes.Formato, "texto") {
+		handler = slog.NewTextHandler(saida, handlerOpcoes)
+	} else {
+		handler = slog.NewJSONHandler(saida, handlerOpcoes)
+	}
+	return slog.New(&handlerContexto{Handler: handler})
+}
+
+// ParseNivel converte LOG_LEVEL (debug, info, warn, error) no nível do slog; padrão info
+func ParseNivel(nivel string) slog.Level {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(nivel)); err != nil {
+		return slog.LevelInfo
+	}
+	return l
+}
+
+// Erro é o atributo padrão para erros nos registros
+func Erro(err error) slog.Attr {
+	return slog.Any("erro", err)
+}
+
+func mascararAtributo(_ []string, a slog.Attr) slog.Attr {
+	if a.Key == slog.TimeKey || a.Key == slog.LevelKey || camposSemMascara[a.Key] {
+		return a
+	}
+
+	valor := a.Value.Resolve()
+	if valor.Kind() != slog.KindString && valor.Kind() != slog.KindAny {
+		return a
+	}
+	if valor.Kind() == slog.KindAny {
+		if _, ok := valor.Any().(error); !ok {
+			return a
+		}
+	}
+
+	texto := valor.String()
+	if mascarar, ok := mascarasPorCampo[a.Key]; ok {
+		return slog.String(a.Key, mascarar(texto))
+	}
+	return slog.String(a.Key, util.MascararTexto(texto))
+}
+
+// handlerContexto acrescenta request_id, usuario_id e o trace do contexto a cada registro
+type handlerContexto struct {
+	slog.Handler
+}
+
+func (h *handlerContexto) Handle(ctx context.Context, r slog.Record) error {
+	if ctx != nil {
+		if id := RequestID(ctx); id != "" {
+			r.AddAttrs(slog.String("request_id", id))
+		}
+		if usuarioID, ok := UsuarioID(ctx); ok {
+			r.AddAttrs(slog.String("usuario_id", usuarioID.String()))
+		}
+		if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+			r.AddAttrs(slog.String("trace_id", span.TraceID().String()), slog.String("span_id", span.SpanID().String()))
+		}
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *handlerContexto) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &handlerContexto{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *handlerContexto) WithGroup(nome string) slog.Handler {
+	return &handlerContexto{Handler: h.Handler.WithGroup(nome)}
+}
@@ -0,0 +1,119 @@
+// Package metricas define as métricas Prometheus da API, expostas em /metrics
+package metricas
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Buckets para operações que levam de milissegundos a minutos (filas, envios e jobs)
+var bucketsLongos = prometheus.ExponentialBuckets(0.05, 3, 10) // 50ms .. ~16min
+
+var (
+	// HTTP (rota é o template do Gin, ex: /api/clientes/:id)
+	RequisicoesHTTP = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ifinu_http_requisicoes_total",
+		Help: "Requisições HTTP por método, rota e status.",
+	}, []string{"metodo", "rota", "status"})
+	DuracaoHTTP = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ifinu_http_duracao_segundos",
+		Help:    "Latência das requisições HTTP por método, rota e status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"metodo", "rota", "status"})
+
+	// Fila de notificações
+	EsperaFila = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "ifinu_fila_espera_segundos",
+		Help:    "Tempo entre a notificação ficar pronta para envio e um worker pegá-la.",
+		Buckets: bucketsLongos,
+	})
+	ProcessamentoFila = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ifinu_fila_processamento_segundos",
+		Help:    "Tempo de processamento de uma notificação pelo worker, por resultado (concluida, reenfileirada ou invalida).",
+		Buckets: bucketsLongos,
+	}, []string{"resultado"})
+
+	// Envios
+	EnviosWhatsApp = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ifinu_whatsapp_envios_total",
+		Help: "Mensagens WhatsApp enviadas por conexão (ID do número, estável entre pareamentos) e resultado (sucesso ou falha).",
+	}, []string{"conexao", "resultado"})
+	EnviosEmail = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ifinu_email_envios_total",
+		Help: "Emails enviados pelo Resend por resultado (sucesso ou falha).",
+	}, []string{"resultado"})
+
+	// Jobs agendados (status: sucesso, falha ou ignorada quando outra réplica executou)
+	ExecucoesJob = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ifinu_job_execucoes_total",
+		Help: "Disparos dos jobs agendados por job e status (sucesso, falha ou ignorada).",
+	}, []string{"job", "status"})
+	DuracaoJob = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ifinu_job_duracao_segundos",
+		Help:    "Duração das execuções dos jobs agendados por job e status.",
+		Buckets: bucketsLongos,
+	}, []string{"job", "status"})
+	UltimaExecucaoJob = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ifinu_job_ultima_execucao_timestamp_segundos",
+		Help: "Fim da última execução do job nesta réplica (Unix), por status.",
+	}, []string{"job", "status"})
+
+	// Webhooks do Stripe (resultado: sucesso, falha ou ignorado)
+	WebhooksStripe = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ifinu_stripe_webhooks_total",
+		Help: "Eventos de webhook do Stripe por tipo e resultado (sucesso, falha ou ignorado).",
+	}, []string{"evento", "resultado"})
+	DuracaoWebhookStripe = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ifinu_stripe_webhook_duracao_segundos",
+		Help:    "Tempo de processamento dos webhooks do Stripe por tipo de evento.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"evento"})
+)
+
+// Resultado converte o sucesso de uma operação no valor do label resultado
+func Resultado(err error) string {
+	if err != nil {
+		return "falha"
+	}
+	return "sucesso"
+}
+
+// ObservarWebhookStripe registra um evento de webhook do Stripe processado desde inicio
+func ObservarWebhookStripe(evento, resultado string, inicio time.Time) {
+	WebhooksStripe.WithLabelValues(evento, resultado).Inc()
+	DuracaoWebhookStripe.WithLabelValues(evento).Observe(time.Since(inicio).Seconds())
+}
+
+// Registrar registra um coletor no registro padrão (o mesmo exposto em /metrics)
+func Registrar(coletor prometheus.Collector) {
+	prometheus.MustRegister(coletor)
+}
+
+// RegistrarBancoDados expõe as estatísticas do pool de conexões (go_sql_*)
+func RegistrarBancoDados(db *sql.DB, nome string) {
+	Registrar(collectors.NewDBStatsCollector(db, nome))
+}
+
+// Handler serve as métricas no formato Prometheus. Com token, exige "Authorization: Bearer <token>".
+func Handler(token string) http.Handler {
+	handler := promhttp.Handler()
+	if token == "" {
+		return handler
+	}
+
+	esperado := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), esperado) != 1 {
+			http.Error(w, "não autorizado", http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
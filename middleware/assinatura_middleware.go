@@ -6,28 +6,33 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/ifinu/ifinu-api-go/config"
+	"github.com/ifinu/ifinu-api-go/dominio/entidades"
 	"github.com/ifinu/ifinu-api-go/repositorio"
 )
 
 // AssinaturaMiddleware verifica se o usuário tem assinatura ativa ou trial válido
 func AssinaturaMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Obter email do usuário do contexto (já validado pelo AuthMiddleware)
-		email, exists := c.Get("emailUsuario")
-		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"message": "Usuário não autenticado",
-			})
-			c.Abort()
-			return
-		}
-
-		emailStr := email.(string)
-
-		// Buscar usuário
+		// Buscar usuário titular da conta (definido pelo OrganizacaoMiddleware ou
+		// pela chave de API) ou, na ausência dele, o próprio usuário autenticado
 		usuarioRepo := repositorio.NovoUsuarioRepositorio(config.DB)
-		usuario, err := usuarioRepo.BuscarPorEmail(emailStr)
+		var usuario *entidades.Usuario
+		var err error
+		if contaID, ok := ObterUsuarioID(c); ok {
+			usuario, err = usuarioRepo.BuscarPorID(contaID)
+		} else {
+			// Obter email do usuário do contexto (já validado pelo AuthMiddleware)
+			email, exists := ObterEmailUsuario(c)
+			if !exists {
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"success": false,
+					"message": "Usuário não autenticado",
+				})
+				c.Abort()
+				return
+			}
+			usuario, err = usuarioRepo.BuscarPorEmail(email)
+		}
 		if err != nil {
 			c.JSON(http.StatusNotFound, gin.H{
 				"success": false,
@@ -39,14 +44,14 @@ func AssinaturaMiddleware() gin.HandlerFunc {
 
 		// Verificar se é vitalício (acesso ilimitado)
 		if usuario.Vitalicio {
-			c.Set("usuarioID", usuario.ID)
+			definirUsuarioID(c, usuario.ID)
 			c.Next()
 			return
 		}
 
 		// Verificar se tem trial ativo e não expirado
 		if usuario.TrialAtivo && !usuario.IsTrialExpirado() {
-			c.Set("usuarioID", usuario.ID)
+			definirUsuarioID(c, usuario.ID)
 			c.Next()
 			return
 		}
@@ -55,7 +60,7 @@ func AssinaturaMiddleware() gin.HandlerFunc {
 		assinaturaRepo := repositorio.NovoAssinaturaRepositorio(config.DB)
 		assinatura, err := assinaturaRepo.BuscarPorUsuario(usuario.ID)
 		if err == nil && assinatura.IsAtiva() {
-			c.Set("usuarioID", usuario.ID)
+			definirUsuarioID(c, usuario.ID)
 			c.Next()
 			return
 		}
@@ -0,0 +1,161 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ifinu/ifinu-api-go/config"
+	"github.com/ifinu/ifinu-api-go/dominio/entidades"
+	"github.com/ifinu/ifinu-api-go/dominio/enums"
+	"github.com/ifinu/ifinu-api-go/repositorio"
+	"github.com/ifinu/ifinu-api-go/util"
+	"gorm.io/gorm"
+)
+
+// intervaloRegistroUso evita uma escrita no banco a cada requisição da mesma chave
+const intervaloRegistroUso = time.Minute
+
+// AutenticacaoOuChaveAPIMiddleware aceita tanto o token JWT quanto uma chave de API.
+// Chaves são enviadas no header X-API-Key ou como "Bearer ifk_...". Com chave de API,
+// o contexto recebe usuarioID, atorID, papel e escopos diretamente da chave, e o
+// OrganizacaoMiddleware não é necessário. O papel é limitado ao papel atual de quem
+// criou a chave: se essa pessoa deixar a organização, a chave para de funcionar.
+func AutenticacaoOuChaveAPIMiddleware() gin.HandlerFunc {
+	autenticacaoJWT := AutenticacaoMiddleware()
+
+	return func(c *gin.Context) {
+		chaveInformada := extrairChaveAPI(c)
+		if chaveInformada == "" {
+			autenticacaoJWT(c)
+			return
+		}
+
+		chaveAPIRepo := repositorio.NovoChaveAPIRepositorio(config.DB)
+		chave, err := chaveAPIRepo.BuscarPorHash(util.HashSHA256(chaveInformada))
+		if err != nil || !chave.IsValida() {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"message": "Chave de API inválida, revogada ou expirada",
+			})
+			c.Abort()
+			return
+		}
+
+		organizacaoRepo := repositorio.NovoOrganizacaoRepositorio(config.DB)
+		membro, err := organizacaoRepo.BuscarMembroAtivoPorUsuario(chave.CriadoPorID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"message": "Erro ao validar chave de API",
+			})
+			c.Abort()
+			return
+		}
+		papel, valida := papelAtualChave(chave, membro)
+		if !valida {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"message": "Chave de API inválida: quem a criou não faz mais parte da organização",
+			})
+			c.Abort()
+			return
+		}
+
+		agora := time.Now()
+		if chave.UltimoUso == nil || agora.Sub(*chave.UltimoUso) > intervaloRegistroUso {
+			_ = chaveAPIRepo.RegistrarUso(chave.ID, agora)
+		}
+
+		c.Set("chaveAPIID", chave.ID)
+		definirUsuarioID(c, chave.UsuarioID)
+		c.Set("atorID", chave.CriadoPorID)
+		c.Set("papel", papel)
+		c.Set("escopos", chave.ListaEscopos())
+		c.Next()
+	}
+}
+
+// papelAtualChave resolve o papel com que a chave atua: o menor entre o papel gravado na
+// criação e o papel atual de quem a criou. membro é a associação ativa do criador (nil se
+// não houver); sem associação, o criador só atua na própria conta, como titular (mesma
+// regra do OrganizacaoMiddleware). Retorna false se o criador não pertence mais à conta.
+func papelAtualChave(chave *entidades.ChaveAPI, membro *entidades.MembroOrganizacao) (enums.PapelMembro, bool) {
+	papelCriador := enums.PapelOwner
+	contaCriador := chave.CriadoPorID
+	if membro != nil {
+		papelCriador = membro.Papel
+		contaCriador = membro.Organizacao.TitularID
+	}
+	if contaCriador != chave.UsuarioID {
+		return "", false
+	}
+
+	if papelCriador.Nivel() < chave.Papel.Nivel() {
+		return papelCriador, true
+	}
+	return chave.Papel, true
+}
+
+// ExigirEscopo verifica se a chave de API possui o escopo do recurso conforme o
+// método HTTP (recurso:read para GET/HEAD, recurso:write para os demais).
+// Requisições autenticadas por JWT não são afetadas.
+func ExigirEscopo(recurso string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !AutenticadoPorChaveAPI(c) {
+			c.Next()
+			return
+		}
+
+		solicitado := enums.EscopoAPI(recurso + ":write")
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			solicitado = enums.EscopoAPI(recurso + ":read")
+		}
+
+		for _, escopo := range ObterEscopos(c) {
+			if escopo.Permite(solicitado) {
+				c.Set("escopoVerificado", true)
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"message": "Chave de API sem o escopo " + solicitado.String(),
+			"code":    "ESCOPO_INSUFICIENTE",
+		})
+		c.Abort()
+	}
+}
+
+// AutenticadoPorChaveAPI indica se a requisição foi autenticada com chave de API
+func AutenticadoPorChaveAPI(c *gin.Context) bool {
+	_, exists := c.Get("chaveAPIID")
+	return exists
+}
+
+// ObterEscopos retorna os escopos da chave de API do contexto
+func ObterEscopos(c *gin.Context) []enums.EscopoAPI {
+	escopos, exists := c.Get("escopos")
+	if !exists {
+		return nil
+	}
+	lista, _ := escopos.([]enums.EscopoAPI)
+	return lista
+}
+
+// extrairChaveAPI retorna a chave de API informada na requisição, se houver
+func extrairChaveAPI(c *gin.Context) string {
+	if chave := strings.TrimSpace(c.GetHeader("X-API-Key")); chave != "" {
+		return chave
+	}
+
+	parts := strings.Split(c.GetHeader("Authorization"), " ")
+	if len(parts) == 2 && parts[0] == "Bearer" && strings.HasPrefix(parts[1], entidades.PrefixoChaveAPI) {
+		return parts[1]
+	}
+	return ""
+}
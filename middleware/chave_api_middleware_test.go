@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/dominio/entidades"
+	"github.com/ifinu/ifinu-api-go/dominio/enums"
+)
+
+func TestExigirEscopo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	casos := []struct {
+		nome     string
+		chave    bool
+		escopos  []enums.EscopoAPI
+		metodo   string
+		esperado int
+	}{
+		{"jwt não é afetado", false, nil, http.MethodPost, http.StatusOK},
+		{"leitura com escopo de leitura", true, []enums.EscopoAPI{enums.EscopoCobrancasLeitura}, http.MethodGet, http.StatusOK},
+		{"head conta como leitura", true, []enums.EscopoAPI{enums.EscopoCobrancasLeitura}, http.MethodHead, http.StatusOK},
+		{"leitura com escopo de escrita", true, []enums.EscopoAPI{enums.EscopoCobrancasEscrita}, http.MethodGet, http.StatusOK},
+		{"escrita com escopo de leitura", true, []enums.EscopoAPI{enums.EscopoCobrancasLeitura}, http.MethodPost, http.StatusForbidden},
+		{"delete exige escrita", true, []enums.EscopoAPI{enums.EscopoCobrancasLeitura}, http.MethodDelete, http.StatusForbidden},
+		{"escopo de outro recurso", true, []enums.EscopoAPI{enums.EscopoClientesEscrita}, http.MethodGet, http.StatusForbidden},
+		{"chave sem escopos", true, nil, http.MethodGet, http.StatusForbidden},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			var verificado bool
+			r := gin.New()
+			r.Use(func(c *gin.Context) {
+				if caso.chave {
+					c.Set("chaveAPIID", uuid.New())
+					c.Set("escopos", caso.escopos)
+				}
+			})
+			r.Handle(caso.metodo, "/cobrancas", ExigirEscopo("cobrancas"), func(c *gin.Context) {
+				verificado = c.GetBool("escopoVerificado")
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(caso.metodo, "/cobrancas", nil))
+
+			if w.Code != caso.esperado {
+				t.Fatalf("status %d, esperado %d", w.Code, caso.esperado)
+			}
+			if caso.esperado == http.StatusOK && verificado != caso.chave {
+				t.Errorf("escopoVerificado = %v, esperado %v", verificado, caso.chave)
+			}
+		})
+	}
+}
+
+func TestPapelAtualChave(t *testing.T) {
+	titular := uuid.New()
+	membroID := uuid.New()
+	outraConta := uuid.New()
+
+	associacao := func(conta uuid.UUID, papel enums.PapelMembro) *entidades.MembroOrganizacao {
+		return &entidades.MembroOrganizacao{Papel: papel, Organizacao: entidades.Organizacao{TitularID: conta}}
+	}
+
+	casos := []struct {
+		nome   string
+		chave  entidades.ChaveAPI
+		membro *entidades.MembroOrganizacao
+		papel  enums.PapelMembro
+		valida bool
+	}{
+		{"titular sem organização", entidades.ChaveAPI{UsuarioID: titular, CriadoPorID: titular, Papel: enums.PapelOwner}, nil, enums.PapelOwner, true},
+		{"titular com organização", entidades.ChaveAPI{UsuarioID: titular, CriadoPorID: titular, Papel: enums.PapelOwner}, associacao(titular, enums.PapelOwner), enums.PapelOwner, true},
+		{"membro com o mesmo papel", entidades.ChaveAPI{UsuarioID: titular, CriadoPorID: membroID, Papel: enums.PapelAdmin}, associacao(titular, enums.PapelAdmin), enums.PapelAdmin, true},
+		{"membro rebaixado", entidades.ChaveAPI{UsuarioID: titular, CriadoPorID: membroID, Papel: enums.PapelAdmin}, associacao(titular, enums.PapelLeitura), enums.PapelLeitura, true},
+		{"membro promovido não amplia a chave", entidades.ChaveAPI{UsuarioID: titular, CriadoPorID: membroID, Papel: enums.PapelFinanceiro}, associacao(titular, enums.PapelAdmin), enums.PapelFinanceiro, true},
+		{"membro removido", entidades.ChaveAPI{UsuarioID: titular, CriadoPorID: membroID, Papel: enums.PapelAdmin}, nil, "", false},
+		{"membro agora em outra organização", entidades.ChaveAPI{UsuarioID: titular, CriadoPorID: membroID, Papel: enums.PapelAdmin}, associacao(outraConta, enums.PapelAdmin), "", false},
+		{"titular que virou convidado de outra conta", entidades.ChaveAPI{UsuarioID: titular, CriadoPorID: titular, Papel: enums.PapelOwner}, associacao(outraConta, enums.PapelLeitura), "", false},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			papel, valida := papelAtualChave(&caso.chave, caso.membro)
+			if papel != caso.papel || valida != caso.valida {
+				t.Fatalf("papel=%q valida=%v, esperado papel=%q valida=%v", papel, valida, caso.papel, caso.valida)
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/logs"
+	"github.com/ifinu/ifinu-api-go/rastreamento"
+	"github.com/spf13/viper"
+)
+
+// LimiteTaxa define quantas requisições são aceitas por janela deslizante
+type LimiteTaxa struct {
+	Nome   string
+	Limite int
+	Janela time.Duration
+}
+
+// resultadoLimite é o estado da janela após contabilizar a requisição
+type resultadoLimite struct {
+	Permitido bool
+	Restante  int
+	Reset     time.Duration
+}
+
+// Limites padrão por grupo de rotas
+var (
+	LimiteAutenticacao  = LimiteTaxa{Nome: "auth", Limite: 10, Janela: time.Minute}
+	LimiteEnvioWhatsApp = LimiteTaxa{Nome: "whatsapp_enviar", Limite: 20, Janela: time.Minute}
+	LimiteLeitura       = LimiteTaxa{Nome: "leitura", Limite: 300, Janela: time.Minute}
+	LimiteEscrita       = LimiteTaxa{Nome: "escrita", Limite: 60, Janela: time.Minute}
+)
+
+var (
+	limitadorRedis   *armazenamentoLimiteRedis
+	limitadorMemoria = novoArmazenamentoLimiteMemoria()
+	loggerLimite     = slog.Default()
+)
+
+// ConfigurarLimiteTaxa conecta o limitador ao Redis. Sem Redis (ou se ele cair),
+// as janelas são mantidas em memória, por instância da API.
+// Deve ser chamado antes de registrar as rotas com LimitarTaxa.
+func ConfigurarLimiteTaxa(redisAddr string, logger *slog.Logger) {
+	loggerLimite = logger
+
+	client := redis.NewClient(&redis.Options{
+		Addr:         redisAddr,
+		DialTimeout:  2 * time.Second,
+		ReadTimeout:  500 * time.Millisecond,
+		WriteTimeout: 500 * time.Millisecond,
+	})
+	client.AddHook(rastreamento.HookRedis())
+
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		logger.Warn("⚠️  Redis não disponível para rate limit. Usando limite em memória", logs.Erro(err))
+		return
+	}
+
+	limitadorRedis = &armazenamentoLimiteRedis{client: client}
+	logger.Info("✅ Rate limit da API usando Redis")
+}
+
+// LimitarTaxa limita as requisições por usuário (ou IP, se não autenticado).
+// O limite pode ser sobrescrito por configuração: RATE_LIMIT_<NOME>=limite/janela (ex: 20/1m).
+func LimitarTaxa(limite LimiteTaxa) gin.HandlerFunc {
+	limite = aplicarConfiguracaoLimite(limite)
+
+	return func(c *gin.Context) {
+		chave := fmt.Sprintf("ifinu:ratelimit:%s:%s", limite.Nome, identificarCliente(c))
+
+		resultado, err := registrarRequisicao(c.Request.Context(), chave, limite)
+		if err != nil {
+			// Falha ao contabilizar não deve derrubar a API
+			c.Next()
+			return
+		}
+
+		reset := int(math.Ceil(resultado.Reset.Seconds()))
+		c.Header("RateLimit-Limit", strconv.Itoa(limite.Limite))
+		c.Header("RateLimit-Remaining", strconv.Itoa(resultado.Restante))
+		c.Header("RateLimit-Reset", strconv.Itoa(reset))
+		c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limite.Limite, int(limite.Janela.Seconds())))
+
+		if !resultado.Permitido {
+			c.Header("Retry-After", strconv.Itoa(reset))
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"success": false,
+				"message": "Muitas requisições. Tente novamente em alguns instantes.",
+				"code":    "LIMITE_REQUISICOES",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// LimitarTaxaPorMetodo aplica o limite de leitura para GET/HEAD e o de escrita para os demais
+func LimitarTaxaPorMetodo(leitura, escrita LimiteTaxa) gin.HandlerFunc {
+	limitarLeitura := LimitarTaxa(leitura)
+	limitarEscrita := LimitarTaxa(escrita)
+
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead:
+			limitarLeitura(c)
+		default:
+			limitarEscrita(c)
+		}
+	}
+}
+
+// registrarRequisicao usa o Redis e cai para memória se ele estiver indisponível
+func registrarRequisicao(ctx context.Context, chave string, limite LimiteTaxa) (resultadoLimite, error) {
+	if limitadorRedis != nil {
+		resultado, err := limitadorRedis.Registrar(chave, limite.Limite, limite.Janela)
+		if err == nil {
+			return resultado, nil
+		}
+		loggerLimite.WarnContext(ctx, "⚠️  Rate limit: erro no Redis, usando memória", logs.Erro(err))
+	}
+	return limitadorMemoria.Registrar(chave, limite.Limite, limite.Janela)
+}
+
+// identificarCliente usa o usuário autenticado (ator) e, na falta dele, o IP
+func identificarCliente(c *gin.Context) string {
+	if atorID, ok := ObterAtorID(c); ok && atorID != uuid.Nil {
+		return "usuario:" + atorID.String()
+	}
+	if usuarioID, ok := ObterUsuarioID(c); ok {
+		return "usuario:" + usuarioID.String()
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// aplicarConfiguracaoLimite lê RATE_LIMIT_<NOME> no formato "limite/janela"
+func aplicarConfiguracaoLimite(limite LimiteTaxa) LimiteTaxa {
+	valor := viper.GetString("RATE_LIMIT_" + strings.ToUpper(limite.Nome))
+	if valor == "" {
+		return limite
+	}
+
+	partes := strings.SplitN(valor, "/", 2)
+	quantidade, err := strconv.Atoi(strings.TrimSpace(partes[0]))
+	if err != nil || quantidade <= 0 {
+		loggerLimite.Warn("⚠️  RATE_LIMIT inválido", "limite", limite.Nome, "valor", valor)
+		return limite
+	}
+	limite.Limite = quantidade
+
+	if len(partes) == 2 {
+		janela, err := time.ParseDuration(strings.TrimSpace(partes[1]))
+		if err != nil || janela <= 0 {
+			loggerLimite.Warn("⚠️  RATE_LIMIT com janela inválida", "limite", limite.Nome, "valor", valor)
+			return limite
+		}
+		limite.Janela = janela
+	}
+
+	return limite
+}
+
+// scriptJanelaDeslizante remove entradas fora da janela, conta e registra a
+// requisição atomicamente. Retorna {permitido, total, ms até liberar}.
+var scriptJanelaDeslizante = redis.NewScript(`
+local chave = KEYS[1]
+local agora = tonumber(ARGV[1])
+local janela = tonumber(ARGV[2])
+local limite = tonumber(ARGV[3])
+local membro = ARGV[4]
+
+redis.call('ZREMRANGEBYSCORE', chave, 0, agora - janela)
+local total = redis.call('ZCARD', chave)
+local permitido = 0
+if total < limite then
+	redis.call('ZADD', chave, agora, membro)
+	total = total + 1
+	permitido = 1
+end
+redis.call('PEXPIRE', chave, janela)
+
+local reset = janela
+local maisAntigo = redis.call('ZRANGE', chave, 0, 0, 'WITHSCORES')
+if maisAntigo[2] then
+	reset = tonumber(maisAntigo[2]) + janela - agora
+end
+return {permitido, total, reset}
+`)
+
+type armazenamentoLimiteRedis struct {
+	client *redis.Client
+}
+
+func (a *armazenamentoLimiteRedis) Registrar(chave string, limite int, janela time.Duration) (resultadoLimite, error) {
+	agora := time.Now()
+	membro := fmt.Sprintf("%d-%s", agora.UnixNano(), uuid.NewString()[:8])
+
+	valores, err := scriptJanelaDeslizante.Run(context.Background(), a.client, []string{chave},
+		agora.UnixMilli(), janela.Milliseconds(), limite, membro).Int64Slice()
+	if err != nil {
+		return resultadoLimite{}, err
+	}
+	if len(valores) != 3 {
+		return resultadoLimite{}, errors.New("resposta inesperada do script de rate limit")
+	}
+
+	return resultadoLimite{
+		Permitido: valores[0] == 1,
+		Restante:  max(limite-int(valores[1]), 0),
+		Reset:     time.Duration(valores[2]) * time.Millisecond,
+	}, nil
+}
+
+type armazenamentoLimiteMemoria struct {
+	mu       sync.Mutex
+	janelas  map[string][]time.Time
+	limpezas int
+}
+
+func novoArmazenamentoLimiteMemoria() *armazenamentoLimiteMemoria {
+	return &armazenamentoLimiteMemoria{janelas: make(map[string][]time.Time)}
+}
+
+func (a *armazenamentoLimiteMemoria) Registrar(chave string, limite int, janela time.Duration) (resultadoLimite, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	agora := time.Now()
+	registros := descartarExpirados(a.janelas[chave], agora.Add(-janela))
+
+	permitido := len(registros) < limite
+	if permitido {
+		registros = append(registros, agora)
+	}
+	a.janelas[chave] = registros
+
+	// Limpeza periódica de chaves sem requisições recentes
+	a.limpezas++
+	if a.limpezas%1000 == 0 {
+		for k, v := range a.janelas {
+			if len(v) == 0 || agora.Sub(v[len(v)-1]) > janela {
+				delete(a.janelas, k)
+			}
+		}
+	}
+
+	reset := janela
+	if len(registros) > 0 {
+		reset = registros[0].Add(janela).Sub(agora)
+	}
+
+	return resultadoLimite{
+		Permitido: permitido,
+		Restante:  max(limite-len(registros), 0),
+		Reset:     reset,
+	}, nil
+}
+
+// descartarExpirados remove os registros anteriores ao início da janela
+func descartarExpirados(registros []time.Time, inicio time.Time) []time.Time {
+	i := 0
+	for i < len(registros) && !registros[i].After(inicio) {
+		i++
+	}
+	return registros[i:]
+}
@@ -0,0 +1,178 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+)
+
+// registrador é a interface comum aos armazenamentos Redis e em memória
+type registrador interface {
+	Registrar(chave string, limite int, janela time.Duration) (resultadoLimite, error)
+}
+
+func novoLimitadorRedisTeste(t *testing.T) *armazenamentoLimiteRedis {
+	t.Helper()
+	servidor := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: servidor.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return &armazenamentoLimiteRedis{client: client}
+}
+
+func TestJanelaDeslizante(t *testing.T) {
+	armazenamentos := map[string]func(t *testing.T) registrador{
+		"redis":   func(t *testing.T) registrador { return novoLimitadorRedisTeste(t) },
+		"memoria": func(*testing.T) registrador { return novoArmazenamentoLimiteMemoria() },
+	}
+
+	for nome, novo := range armazenamentos {
+		t.Run(nome, func(t *testing.T) {
+			limitador := novo(t)
+			janela := 300 * time.Millisecond
+
+			for i := 1; i <= 3; i++ {
+				resultado, err := limitador.Registrar("cliente-a", 3, janela)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !resultado.Permitido || resultado.Restante != 3-i {
+					t.Fatalf("requisição %d: permitido=%v restante=%d", i, resultado.Permitido, resultado.Restante)
+				}
+			}
+
+			// A quarta é recusada; o reset aponta para quando a mais antiga sai da janela
+			resultado, err := limitador.Registrar("cliente-a", 3, janela)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resultado.Permitido || resultado.Restante != 0 {
+				t.Fatalf("quarta requisição: permitido=%v restante=%d", resultado.Permitido, resultado.Restante)
+			}
+			if resultado.Reset <= 0 || resultado.Reset > janela {
+				t.Fatalf("reset=%v, esperado entre 0 e %v", resultado.Reset, janela)
+			}
+
+			// Outra chave tem a própria janela
+			if resultado, _ := limitador.Registrar("cliente-b", 3, janela); !resultado.Permitido {
+				t.Fatal("cliente-b recusado pelo limite de cliente-a")
+			}
+
+			// Requisições recusadas não ocupam a janela: passado o prazo, libera de novo
+			time.Sleep(janela + 50*time.Millisecond)
+			resultado, err = limitador.Registrar("cliente-a", 3, janela)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !resultado.Permitido || resultado.Restante != 2 {
+				t.Fatalf("após a janela: permitido=%v restante=%d", resultado.Permitido, resultado.Restante)
+			}
+		})
+	}
+}
+
+func TestRegistrarRequisicaoCaiParaMemoria(t *testing.T) {
+	servidor := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: servidor.Addr(), MaxRetries: -1})
+	defer client.Close()
+
+	anteriorRedis, anteriorMemoria := limitadorRedis, limitadorMemoria
+	limitadorRedis = &armazenamentoLimiteRedis{client: client}
+	limitadorMemoria = novoArmazenamentoLimiteMemoria()
+	defer func() { limitadorRedis, limitadorMemoria = anteriorRedis, anteriorMemoria }()
+
+	limite := LimiteTaxa{Nome: "teste", Limite: 1, Janela: time.Minute}
+	servidor.Close()
+
+	resultado, err := registrarRequisicao(context.Background(), "ifinu:ratelimit:teste:ip:1", limite)
+	if err != nil || !resultado.Permitido {
+		t.Fatalf("permitido=%v err=%v, esperado fallback em memória", resultado.Permitido, err)
+	}
+	if resultado, _ := registrarRequisicao(context.Background(), "ifinu:ratelimit:teste:ip:1", limite); resultado.Permitido {
+		t.Fatal("fallback em memória não aplicou o limite")
+	}
+}
+
+func TestLimitarTaxaCabecalhos(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	anteriorRedis, anteriorMemoria := limitadorRedis, limitadorMemoria
+	limitadorRedis = novoLimitadorRedisTeste(t)
+	limitadorMemoria = novoArmazenamentoLimiteMemoria()
+	defer func() { limitadorRedis, limitadorMemoria = anteriorRedis, anteriorMemoria }()
+
+	r := gin.New()
+	if err := r.SetTrustedProxies(nil); err != nil {
+		t.Fatal(err)
+	}
+	r.POST("/auth/login", LimitarTaxa(LimiteTaxa{Nome: "teste_cabecalhos", Limite: 2, Janela: time.Minute}), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	requisitar := func(forwardedFor string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/auth/login", nil)
+		req.RemoteAddr = "203.0.113.7:51000"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := requisitar("198.51.100.1")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d, esperado 200", w.Code)
+	}
+	if w.Header().Get("RateLimit-Limit") != "2" || w.Header().Get("RateLimit-Remaining") != "1" {
+		t.Fatalf("cabeçalhos %v", w.Header())
+	}
+	if w.Header().Get("RateLimit-Policy") != "2;w=60" || w.Header().Get("Retry-After") != "" {
+		t.Fatalf("cabeçalhos %v", w.Header())
+	}
+
+	// Sem proxy confiável, trocar o X-Forwarded-For não gera um novo limite
+	requisitar("198.51.100.2")
+	w = requisitar("198.51.100.3")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status %d, esperado 429", w.Code)
+	}
+	if w.Header().Get("RateLimit-Remaining") != "0" {
+		t.Fatalf("RateLimit-Remaining=%q, esperado 0", w.Header().Get("RateLimit-Remaining"))
+	}
+	retry, err := strconv.Atoi(w.Header().Get("Retry-After"))
+	if err != nil || retry < 1 || retry > 60 {
+		t.Fatalf("Retry-After=%q, esperado entre 1 e 60", w.Header().Get("Retry-After"))
+	}
+}
+
+func TestIdentificarClienteComProxyConfiavel(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	if err := r.SetTrustedProxies([]string{"10.0.0.0/8"}); err != nil {
+		t.Fatal(err)
+	}
+	var identificado string
+	r.GET("/", func(c *gin.Context) { identificado = identificarCliente(c) })
+
+	casos := []struct {
+		remoto, forwardedFor, esperado string
+	}{
+		{"10.1.2.3:40000", "198.51.100.9", "ip:198.51.100.9"},   // ingress confiável
+		{"203.0.113.7:40000", "198.51.100.9", "ip:203.0.113.7"}, // cliente direto forjando o header
+	}
+	for _, caso := range casos {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = caso.remoto
+		req.Header.Set("X-Forwarded-For", caso.forwardedFor)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+		if identificado != caso.esperado {
+			t.Errorf("remoto %s: %q, esperado %q", caso.remoto, identificado, caso.esperado)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ifinu/ifinu-api-go/metricas"
+)
+
+// MetricasHTTP registra a contagem e a latência das requisições. A rota é o template do
+// Gin (ex: /api/clientes/:id), para que IDs não multipliquem as séries.
+func MetricasHTTP() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		inicio := time.Now()
+		c.Next()
+
+		rota := c.FullPath()
+		if rota == "" {
+			rota = "nao_encontrada"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+
+		metricas.RequisicoesHTTP.WithLabelValues(c.Request.Method, rota, status).Inc()
+		metricas.DuracaoHTTP.WithLabelValues(c.Request.Method, rota, status).Observe(time.Since(inicio).Seconds())
+	}
+}
@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/config"
+	"github.com/ifinu/ifinu-api-go/dominio/enums"
+	"github.com/ifinu/ifinu-api-go/dto"
+	"github.com/ifinu/ifinu-api-go/repositorio"
+)
+
+// OrganizacaoMiddleware resolve a conta (organização) em que o usuário autenticado atua.
+// O usuarioID do contexto passa a ser o do titular da organização, mantendo o
+// isolamento de dados existente; o usuário autenticado fica disponível como atorID.
+func OrganizacaoMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Chaves de API já definem a conta e o papel
+		if AutenticadoPorChaveAPI(c) {
+			c.Next()
+			return
+		}
+
+		email, exists := c.Get("emailUsuario")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"message": "Usuário não autenticado",
+			})
+			c.Abort()
+			return
+		}
+
+		usuarioRepo := repositorio.NovoUsuarioRepositorio(config.DB)
+		usuario, err := usuarioRepo.BuscarPorEmail(email.(string))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"message": "Usuário não encontrado",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set("atorID", usuario.ID)
+
+		// Usuário sem organização atua na própria conta como titular
+		organizacaoRepo := repositorio.NovoOrganizacaoRepositorio(config.DB)
+		membro, err := organizacaoRepo.BuscarMembroAtivoPorUsuario(usuario.ID)
+		if err != nil {
+			definirUsuarioID(c, usuario.ID)
+			c.Set("papel", enums.PapelOwner)
+			c.Next()
+			return
+		}
+
+		definirUsuarioID(c, membro.Organizacao.TitularID)
+		c.Set("papel", membro.Papel)
+		c.Next()
+	}
+}
+
+// ExigirPapel bloqueia a requisição se o papel do membro for inferior ao mínimo.
+// Chaves de API só passam em rotas cujo escopo já foi verificado por ExigirEscopo.
+func ExigirPapel(minimo enums.PapelMembro) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if AutenticadoPorChaveAPI(c) && !c.GetBool("escopoVerificado") {
+			c.JSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"message": "Esta rota não está disponível para chaves de API",
+				"code":    "ESCOPO_INSUFICIENTE",
+			})
+			c.Abort()
+			return
+		}
+
+		papel, ok := ObterPapel(c)
+		if !ok || !papel.Permite(minimo) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"message": "Seu papel na organização não permite esta operação",
+				"code":    "PERMISSAO_NEGADA",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// AutorizarPorMetodo aplica o papel mínimo conforme o método HTTP:
+// leitura para GET/HEAD, exclusao para DELETE e escrita para os demais
+func AutorizarPorMetodo(leitura, escrita, exclusao enums.PapelMembro) gin.HandlerFunc {
+	exigirLeitura := ExigirPapel(leitura)
+	exigirEscrita := ExigirPapel(escrita)
+	exigirExclusao := ExigirPapel(exclusao)
+
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead:
+			exigirLeitura(c)
+		case http.MethodDelete:
+			exigirExclusao(c)
+		default:
+			exigirEscrita(c)
+		}
+	}
+}
+
+// ObterPapel retorna o papel do usuário autenticado na organização
+func ObterPapel(c *gin.Context) (enums.PapelMembro, bool) {
+	papel, exists := c.Get("papel")
+	if !exists {
+		return "", false
+	}
+	p, ok := papel.(enums.PapelMembro)
+	return p, ok
+}
+
+// ObterAtorID retorna o ID do usuário que está executando a ação
+func ObterAtorID(c *gin.Context) (uuid.UUID, bool) {
+	atorID, exists := c.Get("atorID")
+	if !exists {
+		return uuid.Nil, false
+	}
+	id, ok := atorID.(uuid.UUID)
+	return id, ok
+}
+
+// ObterAtor retorna quem executa a ação e o IP de origem, para a trilha de auditoria
+func ObterAtor(c *gin.Context) dto.Ator {
+	atorID, _ := ObterAtorID(c)
+	return dto.Ator{ID: atorID, IP: c.ClientIP()}
+}
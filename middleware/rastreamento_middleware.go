@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+// Rastreamento abre o span de cada requisição (nome: rota do Gin), continuando o trace do
+// traceparent recebido. Probes e scrape não geram traces.
+func Rastreamento(nomeServico string) gin.HandlerFunc {
+	return otelgin.Middleware(nomeServico, otelgin.WithFilter(func(req *http.Request) bool {
+		return !rotasSilenciosas[req.URL.Path]
+	}))
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/logs"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Rotas de probe e scrape, registradas só em nível debug para não encher os logs
+var rotasSilenciosas = map[string]bool{
+	"/health":       true,
+	"/health/live":  true,
+	"/health/ready": true,
+	"/ready":        true,
+	"/metrics":      true,
+}
+
+// RequestID reaproveita o X-Request-ID recebido (se válido) ou gera um novo, devolve no
+// response e o guarda no contexto da requisição para os logs, a fila e as chamadas de saída
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(logs.HeaderRequestID)
+		if !logs.RequestIDValido(id) {
+			id = logs.NovoRequestID()
+		}
+
+		c.Set("requestID", id)
+		c.Header(logs.HeaderRequestID, id)
+		trace.SpanFromContext(c.Request.Context()).SetAttributes(attribute.String("ifinu.request_id", id))
+		c.Request = c.Request.WithContext(logs.ComRequestID(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+// LogRequisicoes registra cada requisição concluída. Substitui o logger padrão do Gin, que
+// grava a query string (tokens de descadastro, por exemplo) e não tem request_id.
+func LogRequisicoes(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		inicio := time.Now()
+		c.Next()
+
+		rota := c.FullPath()
+		if rota == "" {
+			rota = "nao_encontrada"
+		}
+		status := c.Writer.Status()
+
+		nivel := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			nivel = slog.LevelError
+		case status >= http.StatusBadRequest:
+			nivel = slog.LevelWarn
+		case rotasSilenciosas[rota]:
+			nivel = slog.LevelDebug
+		}
+
+		atributos := []any{
+			slog.String("metodo", c.Request.Method),
+			slog.String("rota", rota),
+			slog.String("caminho", c.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Int64("duracao_ms", time.Since(inicio).Milliseconds()),
+			slog.String("ip", c.ClientIP()),
+		}
+		if len(c.Errors) > 0 {
+			atributos = append(atributos, slog.String("erro", c.Errors.String()))
+		}
+		logger.Log(c.Request.Context(), nivel, "requisição", atributos...)
+	}
+}
+
+// definirUsuarioID define a conta da requisição no gin.Context e no contexto da requisição,
+// para que os logs e a fila recebam o usuario_id sem que cada handler o repasse
+func definirUsuarioID(c *gin.Context, usuarioID uuid.UUID) {
+	c.Set("usuarioID", usuarioID)
+	trace.SpanFromContext(c.Request.Context()).SetAttributes(attribute.String("ifinu.usuario_id", usuarioID.String()))
+	c.Request = c.Request.WithContext(logs.ComUsuarioID(c.Request.Context(), usuarioID))
+}
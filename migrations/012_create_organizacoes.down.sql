@@ -0,0 +1,5 @@
+-- Reverte 012: remove organizações, membros e convites
+-- (os dados da conta continuam no usuario_id do titular)
+
+DROP TABLE IF EXISTS membros_organizacao;
+DROP TABLE IF EXISTS organizacoes;
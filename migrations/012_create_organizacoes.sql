@@ -0,0 +1,41 @@
+-- Migration: Criar tabelas de organização e membros
+-- Data: 2026-10-19
+-- Descrição: Permite que vários usuários (equipe financeira) acessem a mesma conta com papéis
+--            owner/admin/financeiro/leitura. Os dados continuam vinculados ao usuario_id do titular.
+
+CREATE TABLE IF NOT EXISTS organizacoes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    titular_id UUID NOT NULL UNIQUE REFERENCES usuarios(id) ON DELETE CASCADE,
+    nome VARCHAR(255) NOT NULL,
+    data_criacao TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    data_atualizacao TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS membros_organizacao (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organizacao_id UUID NOT NULL REFERENCES organizacoes(id) ON DELETE CASCADE,
+    usuario_id UUID REFERENCES usuarios(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    papel VARCHAR(20) NOT NULL CHECK (papel IN ('OWNER', 'ADMIN', 'FINANCEIRO', 'LEITURA')),
+    status VARCHAR(20) NOT NULL DEFAULT 'CONVIDADO' CHECK (status IN ('CONVIDADO', 'ATIVO', 'REMOVIDO')),
+    token_convite VARCHAR(64),
+    convidado_por_id UUID REFERENCES usuarios(id) ON DELETE SET NULL,
+    data_convite TIMESTAMP,
+    data_aceite TIMESTAMP,
+    data_criacao TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    data_atualizacao TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Índices
+CREATE INDEX IF NOT EXISTS idx_membros_organizacao_organizacao_id ON membros_organizacao(organizacao_id);
+CREATE INDEX IF NOT EXISTS idx_membros_organizacao_usuario_id ON membros_organizacao(usuario_id);
+CREATE INDEX IF NOT EXISTS idx_membros_organizacao_token ON membros_organizacao(token_convite) WHERE token_convite IS NOT NULL;
+
+-- Um email só pode ter um convite/associação ativa por organização
+CREATE UNIQUE INDEX IF NOT EXISTS uq_membros_organizacao_email
+ON membros_organizacao(organizacao_id, LOWER(email))
+WHERE status <> 'REMOVIDO';
+
+COMMENT ON TABLE organizacoes IS 'Organizações (equipes) que compartilham a conta de um usuário titular';
+COMMENT ON TABLE membros_organizacao IS 'Membros e convites de uma organização com seus papéis';
+COMMENT ON COLUMN membros_organizacao.papel IS 'OWNER, ADMIN, FINANCEIRO ou LEITURA';
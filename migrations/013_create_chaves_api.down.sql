@@ -0,0 +1,3 @@
+-- Reverte 013: remove as chaves de API (integrações que as usam deixam de autenticar)
+
+DROP TABLE IF EXISTS chaves_api;
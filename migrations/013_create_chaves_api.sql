@@ -0,0 +1,27 @@
+-- Migration: Criar tabela de chaves de API
+-- Data: 2026-10-19
+-- Descrição: Chaves de API com escopos para integrações servidor a servidor (ex: sincronização com ERP).
+--            Apenas o hash SHA-256 da chave é armazenado; o prefixo identifica a chave na interface.
+
+CREATE TABLE IF NOT EXISTS chaves_api (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    usuario_id UUID NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    criado_por_id UUID NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    nome VARCHAR(100) NOT NULL,
+    prefixo VARCHAR(20) NOT NULL,
+    hash VARCHAR(64) NOT NULL UNIQUE,
+    escopos TEXT NOT NULL,
+    papel VARCHAR(20) NOT NULL CHECK (papel IN ('OWNER', 'ADMIN', 'FINANCEIRO', 'LEITURA')),
+    ultimo_uso TIMESTAMP,
+    data_expiracao TIMESTAMP,
+    data_revogacao TIMESTAMP,
+    data_criacao TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Índices
+CREATE INDEX IF NOT EXISTS idx_chaves_api_usuario_id ON chaves_api(usuario_id);
+
+COMMENT ON TABLE chaves_api IS 'Chaves de API com escopos para integrações servidor a servidor';
+COMMENT ON COLUMN chaves_api.hash IS 'Hash SHA-256 da chave completa (a chave nunca é armazenada)';
+COMMENT ON COLUMN chaves_api.escopos IS 'Escopos separados por vírgula (ex: cobrancas:write,clientes:read)';
+COMMENT ON COLUMN chaves_api.papel IS 'Papel de quem criou a chave, limite máximo de permissões da chave';
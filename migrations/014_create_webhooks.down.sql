@@ -0,0 +1,4 @@
+-- Reverte 014: remove os endpoints de webhook e o log de entregas
+
+DROP TABLE IF EXISTS webhook_entregas;
+DROP TABLE IF EXISTS webhook_endpoints;
@@ -0,0 +1,41 @@
+-- Migration: Criar tabelas de webhooks de saída
+-- Data: 2026-10-19
+-- Descrição: Endpoints inscritos em eventos (cobranca.criada, cobranca.paga, cobranca.vencida,
+--            cliente.criado, whatsapp.desconectado) e log de entregas com tentativas e reenvio manual.
+
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    usuario_id UUID NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    url VARCHAR(500) NOT NULL,
+    descricao VARCHAR(255),
+    eventos TEXT NOT NULL,
+    segredo_encriptado TEXT NOT NULL,
+    ativo BOOLEAN NOT NULL DEFAULT true,
+    data_criacao TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    data_atualizacao TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS webhook_entregas (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    endpoint_id UUID NOT NULL REFERENCES webhook_endpoints(id) ON DELETE CASCADE,
+    usuario_id UUID NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    evento VARCHAR(50) NOT NULL,
+    payload TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDENTE' CHECK (status IN ('PENDENTE', 'SUCESSO', 'FALHA')),
+    tentativas INTEGER NOT NULL DEFAULT 0,
+    ultimo_status_http INTEGER,
+    ultimo_erro TEXT,
+    proxima_tentativa TIMESTAMP,
+    data_entrega TIMESTAMP,
+    data_criacao TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    data_atualizacao TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Índices
+CREATE INDEX IF NOT EXISTS idx_webhook_endpoints_usuario_id ON webhook_endpoints(usuario_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_entregas_endpoint_data ON webhook_entregas(endpoint_id, data_criacao DESC);
+CREATE INDEX IF NOT EXISTS idx_webhook_entregas_usuario_id ON webhook_entregas(usuario_id);
+
+COMMENT ON TABLE webhook_endpoints IS 'URLs inscritas em eventos da conta, com segredo HMAC criptografado';
+COMMENT ON TABLE webhook_entregas IS 'Log de entregas de webhooks (uma linha por evento e endpoint)';
+COMMENT ON COLUMN webhook_entregas.id IS 'Enviado no header X-Ifinu-Entrega para idempotência no destino';
@@ -0,0 +1,4 @@
+-- Reverte 015: remove a trilha de auditoria (os registros são perdidos)
+
+DROP TABLE IF EXISTS auditoria;
+DROP FUNCTION IF EXISTS auditoria_somente_insercao();
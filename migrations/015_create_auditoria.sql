@@ -0,0 +1,36 @@
+-- Migration: Criar trilha de auditoria
+-- Data: 2026-10-19
+-- Descrição: Registro somente-inserção das alterações em clientes, cobranças, configuração Stripe
+--            e membros da organização (ator, ação, entidade, diff antes/depois, IP e data).
+
+CREATE TABLE IF NOT EXISTS auditoria (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    usuario_id UUID NOT NULL, -- sem FK: registros sobrevivem à exclusão do usuário
+    ator_id UUID,
+    acao VARCHAR(20) NOT NULL CHECK (acao IN ('CRIAR', 'ATUALIZAR', 'EXCLUIR')),
+    entidade VARCHAR(50) NOT NULL,
+    entidade_id VARCHAR(64) NOT NULL,
+    alteracoes JSONB NOT NULL DEFAULT '{}',
+    ip VARCHAR(45),
+    data_criacao TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Índices para consulta por entidade e por período
+CREATE INDEX IF NOT EXISTS idx_auditoria_usuario_data ON auditoria(usuario_id, data_criacao DESC);
+CREATE INDEX IF NOT EXISTS idx_auditoria_entidade ON auditoria(usuario_id, entidade, entidade_id);
+
+-- Somente inserção: bloquear UPDATE e DELETE
+CREATE OR REPLACE FUNCTION auditoria_somente_insercao() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'auditoria é somente inserção';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_auditoria_somente_insercao ON auditoria;
+CREATE TRIGGER trg_auditoria_somente_insercao
+BEFORE UPDATE OR DELETE ON auditoria
+FOR EACH ROW EXECUTE FUNCTION auditoria_somente_insercao();
+
+COMMENT ON TABLE auditoria IS 'Trilha de auditoria somente-inserção das alterações da conta';
+COMMENT ON COLUMN auditoria.ator_id IS 'Usuário que executou a ação (NULL para jobs do sistema)';
+COMMENT ON COLUMN auditoria.alteracoes IS 'Diff no formato {"campo": {"antes": ..., "depois": ...}}';
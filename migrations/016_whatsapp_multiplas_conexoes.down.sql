@@ -0,0 +1,11 @@
+-- Reverte 016: volta a uma conexão sem nome por conta
+-- (nomes, roteamento por tipo e número preferencial dos clientes são perdidos)
+
+ALTER TABLE clientes DROP COLUMN IF EXISTS whatsapp_conexao_id;
+
+DROP INDEX IF EXISTS idx_whatsapp_conexoes_usuario_padrao;
+DROP INDEX IF EXISTS idx_whatsapp_conexoes_usuario_nome;
+
+ALTER TABLE whatsapp_conexoes DROP COLUMN IF EXISTS tipos_notificacao;
+ALTER TABLE whatsapp_conexoes DROP COLUMN IF EXISTS padrao;
+ALTER TABLE whatsapp_conexoes DROP COLUMN IF EXISTS nome;
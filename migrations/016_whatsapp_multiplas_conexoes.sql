@@ -0,0 +1,28 @@
+-- Migration: Múltiplos números WhatsApp por conta
+-- Data: 2026-10-19
+-- Descrição: Conexões nomeadas com conexão padrão e roteamento por tipo de notificação,
+--            e número WhatsApp preferencial por cliente.
+
+ALTER TABLE whatsapp_conexoes ADD COLUMN IF NOT EXISTS nome VARCHAR(100) NOT NULL DEFAULT 'Principal';
+ALTER TABLE whatsapp_conexoes ADD COLUMN IF NOT EXISTS padrao BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE whatsapp_conexoes ADD COLUMN IF NOT EXISTS tipos_notificacao TEXT;
+
+-- Contas existentes têm uma única conexão, que passa a ser a padrão
+UPDATE whatsapp_conexoes SET padrao = TRUE
+WHERE id IN (SELECT MIN(id) FROM whatsapp_conexoes GROUP BY usuario_id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_whatsapp_conexoes_usuario_nome
+    ON whatsapp_conexoes (usuario_id, LOWER(nome));
+
+-- No máximo uma conexão padrão por conta
+CREATE UNIQUE INDEX IF NOT EXISTS idx_whatsapp_conexoes_usuario_padrao
+    ON whatsapp_conexoes (usuario_id) WHERE padrao;
+
+ALTER TABLE clientes ADD COLUMN IF NOT EXISTS whatsapp_conexao_id BIGINT
+    REFERENCES whatsapp_conexoes(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_clientes_whatsapp_conexao_id ON clientes (whatsapp_conexao_id);
+
+COMMENT ON COLUMN whatsapp_conexoes.nome IS 'Nome do número na conta (ex: Vendas, Cobrança)';
+COMMENT ON COLUMN whatsapp_conexoes.padrao IS 'Conexão usada quando nenhuma regra de roteamento se aplica';
+COMMENT ON COLUMN whatsapp_conexoes.tipos_notificacao IS 'Tipos de notificação roteados para este número (lembrete,vencimento,pagamento,manual)';
+COMMENT ON COLUMN clientes.whatsapp_conexao_id IS 'Número WhatsApp preferencial do cliente';
@@ -0,0 +1,3 @@
+-- Reverte 017: remove a caixa de entrada do WhatsApp (o histórico de conversas é perdido)
+
+DROP TABLE IF EXISTS whatsapp_mensagens;
@@ -0,0 +1,32 @@
+-- Migration: Caixa de entrada do WhatsApp
+-- Data: 2026-10-19
+-- Descrição: Mensagens recebidas (MESSAGES_UPSERT da Evolution API) e respostas da equipe,
+--            agrupadas por telefone e vinculadas ao cliente e à cobrança em aberto.
+
+CREATE TABLE IF NOT EXISTS whatsapp_mensagens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    usuario_id UUID NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    conexao_id BIGINT NOT NULL, -- sem FK: o histórico sobrevive à remoção do número
+    telefone VARCHAR(20) NOT NULL,
+    cliente_id UUID REFERENCES clientes(id) ON DELETE SET NULL,
+    cobranca_id UUID REFERENCES cobrancas(id) ON DELETE SET NULL,
+    direcao VARCHAR(10) NOT NULL CHECK (direcao IN ('ENTRADA', 'SAIDA')),
+    nome_contato VARCHAR(255),
+    texto TEXT,
+    tipo_mensagem VARCHAR(50),
+    id_externo VARCHAR(100) NOT NULL,
+    lida BOOLEAN NOT NULL DEFAULT FALSE,
+    data_leitura TIMESTAMP,
+    enviado_por_id UUID,
+    data_mensagem TIMESTAMP NOT NULL,
+    data_criacao TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_whatsapp_mensagens_externo ON whatsapp_mensagens (conexao_id, id_externo);
+CREATE INDEX IF NOT EXISTS idx_whatsapp_mensagens_conversa ON whatsapp_mensagens (usuario_id, telefone, data_mensagem DESC);
+CREATE INDEX IF NOT EXISTS idx_whatsapp_mensagens_nao_lidas ON whatsapp_mensagens (usuario_id) WHERE direcao = 'ENTRADA' AND NOT lida;
+CREATE INDEX IF NOT EXISTS idx_whatsapp_mensagens_cliente_id ON whatsapp_mensagens (cliente_id);
+
+COMMENT ON TABLE whatsapp_mensagens IS 'Caixa de entrada do WhatsApp (mensagens recebidas e enviadas por conversa)';
+COMMENT ON COLUMN whatsapp_mensagens.id_externo IS 'ID da mensagem no WhatsApp, usado para ignorar eventos duplicados';
+COMMENT ON COLUMN whatsapp_mensagens.enviado_por_id IS 'Membro da equipe que respondeu pelo painel';
@@ -0,0 +1,7 @@
+-- Reverte 018: remove o auto-responder e o opt-out de lembretes no cliente
+
+DROP TABLE IF EXISTS respostas_automaticas_disparos;
+DROP TABLE IF EXISTS respostas_automaticas_config;
+DROP TABLE IF EXISTS respostas_automaticas;
+
+ALTER TABLE clientes DROP COLUMN IF EXISTS lembretes_ativos;
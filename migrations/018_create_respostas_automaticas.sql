@@ -0,0 +1,58 @@
+-- Migration: Auto-responder do WhatsApp por palavra-chave
+-- Data: 2026-10-19
+-- Descrição: Regras de resposta automática (2VIA, PIX, SAIR...), configuração por conta,
+--            log de respostas enviadas e opt-out de lembretes no cliente.
+
+ALTER TABLE clientes ADD COLUMN IF NOT EXISTS lembretes_ativos BOOLEAN NOT NULL DEFAULT TRUE;
+
+COMMENT ON COLUMN clientes.lembretes_ativos IS 'Lembretes automáticos de cobrança (FALSE quando o cliente responde SAIR)';
+
+CREATE TABLE IF NOT EXISTS respostas_automaticas (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    usuario_id UUID NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    palavra_chave VARCHAR(50) NOT NULL,
+    acao VARCHAR(20) NOT NULL CHECK (acao IN ('TEXTO', 'SEGUNDA_VIA', 'PIX', 'DESCADASTRAR')),
+    mensagem TEXT,
+    ativo BOOLEAN NOT NULL DEFAULT TRUE,
+    data_criacao TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    data_atualizacao TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_respostas_automaticas_palavra ON respostas_automaticas (usuario_id, palavra_chave);
+
+COMMENT ON TABLE respostas_automaticas IS 'Regras do auto-responder do WhatsApp';
+COMMENT ON COLUMN respostas_automaticas.palavra_chave IS 'Normalizada: maiúsculas, sem acentos e sem pontuação';
+
+CREATE TABLE IF NOT EXISTS respostas_automaticas_config (
+    usuario_id UUID PRIMARY KEY REFERENCES usuarios(id) ON DELETE CASCADE,
+    ativo BOOLEAN NOT NULL DEFAULT FALSE,
+    mensagem_padrao TEXT,
+    chave_pix VARCHAR(77),
+    recebedor_pix VARCHAR(25),
+    cidade_pix VARCHAR(15),
+    data_criacao TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    data_atualizacao TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON COLUMN respostas_automaticas_config.mensagem_padrao IS 'Resposta quando nenhuma palavra-chave é reconhecida (no máximo uma vez a cada 24h por conversa)';
+
+CREATE TABLE IF NOT EXISTS respostas_automaticas_disparos (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    usuario_id UUID NOT NULL REFERENCES usuarios(id) ON DELETE CASCADE,
+    resposta_id UUID REFERENCES respostas_automaticas(id) ON DELETE SET NULL,
+    mensagem_id UUID NOT NULL,
+    palavra_chave VARCHAR(50),
+    acao VARCHAR(20),
+    telefone VARCHAR(20) NOT NULL,
+    cliente_id UUID,
+    cobranca_id UUID,
+    sucesso BOOLEAN NOT NULL DEFAULT FALSE,
+    erro TEXT,
+    data_criacao TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_respostas_automaticas_disparos_usuario ON respostas_automaticas_disparos (usuario_id, data_criacao DESC);
+CREATE INDEX IF NOT EXISTS idx_respostas_automaticas_disparos_resposta_id ON respostas_automaticas_disparos (resposta_id);
+CREATE INDEX IF NOT EXISTS idx_respostas_automaticas_disparos_telefone ON respostas_automaticas_disparos (usuario_id, telefone, data_criacao);
+
+COMMENT ON TABLE respostas_automaticas_disparos IS 'Log de respostas automáticas (resposta_id nulo = resposta padrão)';
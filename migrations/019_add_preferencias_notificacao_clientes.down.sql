@@ -0,0 +1,13 @@
+-- Reverte 019: volta clientes.lembretes_ativos a partir do opt-out
+-- (canal e horário de silêncio são perdidos)
+
+ALTER TABLE clientes ADD COLUMN IF NOT EXISTS lembretes_ativos BOOLEAN NOT NULL DEFAULT TRUE;
+UPDATE clientes SET lembretes_ativos = FALSE WHERE data_opt_out IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_clientes_opt_out;
+
+ALTER TABLE clientes DROP COLUMN IF EXISTS canal_notificacao;
+ALTER TABLE clientes DROP COLUMN IF EXISTS silencio_inicio;
+ALTER TABLE clientes DROP COLUMN IF EXISTS silencio_fim;
+ALTER TABLE clientes DROP COLUMN IF EXISTS data_opt_out;
+ALTER TABLE clientes DROP COLUMN IF EXISTS origem_opt_out;
@@ -0,0 +1,32 @@
+-- Migration: Preferências de notificação do cliente
+-- Data: 2026-10-19
+-- Descrição: Canal de lembretes (WhatsApp/email/nenhum), horário de silêncio e registro
+--            do opt-out (data e origem). Substitui clientes.lembretes_ativos.
+
+ALTER TABLE clientes ADD COLUMN IF NOT EXISTS canal_notificacao VARCHAR(20) NOT NULL DEFAULT 'TODOS'
+    CHECK (canal_notificacao IN ('TODOS', 'WHATSAPP', 'EMAIL', 'NENHUM'));
+ALTER TABLE clientes ADD COLUMN IF NOT EXISTS silencio_inicio VARCHAR(5);
+ALTER TABLE clientes ADD COLUMN IF NOT EXISTS silencio_fim VARCHAR(5);
+ALTER TABLE clientes ADD COLUMN IF NOT EXISTS data_opt_out TIMESTAMP;
+ALTER TABLE clientes ADD COLUMN IF NOT EXISTS origem_opt_out VARCHAR(20)
+    CHECK (origem_opt_out IN ('WHATSAPP', 'EMAIL', 'PAINEL'));
+
+-- Clientes que responderam SAIR antes desta migration
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_name = 'clientes' AND column_name = 'lembretes_ativos') THEN
+        UPDATE clientes SET data_opt_out = NOW(), origem_opt_out = 'WHATSAPP'
+        WHERE lembretes_ativos = FALSE AND data_opt_out IS NULL;
+    END IF;
+END $$;
+
+ALTER TABLE clientes DROP COLUMN IF EXISTS lembretes_ativos;
+
+CREATE INDEX IF NOT EXISTS idx_clientes_opt_out ON clientes (data_opt_out) WHERE data_opt_out IS NOT NULL;
+
+COMMENT ON COLUMN clientes.canal_notificacao IS 'Canal dos lembretes automáticos: TODOS, WHATSAPP, EMAIL ou NENHUM';
+COMMENT ON COLUMN clientes.silencio_inicio IS 'Início do horário de silêncio (HH:MM, horário de Brasília)';
+COMMENT ON COLUMN clientes.silencio_fim IS 'Fim do horário de silêncio (HH:MM, pode virar a meia-noite)';
+COMMENT ON COLUMN clientes.data_opt_out IS 'Quando o cliente pediu para não receber lembretes (NULL = recebe)';
+COMMENT ON COLUMN clientes.origem_opt_out IS 'Origem do opt-out: WHATSAPP (SAIR), EMAIL (link) ou PAINEL';
@@ -0,0 +1,4 @@
+-- Reverte 020
+
+ALTER TABLE clientes DROP COLUMN IF EXISTS whatsapp_valido;
+ALTER TABLE clientes DROP COLUMN IF EXISTS data_verificacao_whatsapp;
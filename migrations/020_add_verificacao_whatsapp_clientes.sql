@@ -0,0 +1,7 @@
+-- Migration: Verificação do número do cliente no WhatsApp
+-- Data: 2026-10-19
+-- Descrição: Guarda o resultado da consulta à Evolution API (número existe no WhatsApp)
+--            para não repetir a consulta a cada envio. Limpo quando o telefone muda.
+
+ALTER TABLE clientes ADD COLUMN IF NOT EXISTS whatsapp_valido BOOLEAN;
+ALTER TABLE clientes ADD COLUMN IF NOT EXISTS data_verificacao_whatsapp TIMESTAMP;
@@ -0,0 +1,4 @@
+-- Reverte 021: remove a unicidade por usuário (os documentos continuam sem máscara)
+
+DROP INDEX IF EXISTS idx_clientes_usuario_cpf;
+DROP INDEX IF EXISTS idx_clientes_usuario_cnpj;
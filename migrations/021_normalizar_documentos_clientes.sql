@@ -0,0 +1,32 @@
+-- Migration: CPF/CNPJ sem máscara e únicos por usuário
+-- Data: 2026-10-19
+-- Descrição: Documentos passam a ser gravados sem máscara (o CNPJ pode ser alfanumérico)
+--            e devolvidos formatados pela API. Um CPF/CNPJ só pode aparecer em um cliente
+--            de cada usuário.
+
+UPDATE clientes SET cpf = NULLIF(UPPER(REGEXP_REPLACE(cpf, '[^0-9A-Za-z]', '', 'g')), '')
+WHERE cpf IS NOT NULL;
+UPDATE clientes SET cnpj = NULLIF(UPPER(REGEXP_REPLACE(cnpj, '[^0-9A-Za-z]', '', 'g')), '')
+WHERE cnpj IS NOT NULL;
+UPDATE usuarios SET cnpj = UPPER(REGEXP_REPLACE(cnpj, '[^0-9A-Za-z]', '', 'g'))
+WHERE cnpj IS NOT NULL;
+
+-- Duplicados antigos impedem o índice: ficam para correção manual, sem travar a migration
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM clientes WHERE cpf IS NOT NULL
+               GROUP BY usuario_id, cpf HAVING COUNT(*) > 1) THEN
+        RAISE NOTICE 'CPFs duplicados em clientes: índice idx_clientes_usuario_cpf não criado';
+    ELSE
+        CREATE UNIQUE INDEX IF NOT EXISTS idx_clientes_usuario_cpf ON clientes (usuario_id, cpf)
+            WHERE cpf IS NOT NULL;
+    END IF;
+
+    IF EXISTS (SELECT 1 FROM clientes WHERE cnpj IS NOT NULL
+               GROUP BY usuario_id, cnpj HAVING COUNT(*) > 1) THEN
+        RAISE NOTICE 'CNPJs duplicados em clientes: índice idx_clientes_usuario_cnpj não criado';
+    ELSE
+        CREATE UNIQUE INDEX IF NOT EXISTS idx_clientes_usuario_cnpj ON clientes (usuario_id, cnpj)
+            WHERE cnpj IS NOT NULL;
+    END IF;
+END $$;
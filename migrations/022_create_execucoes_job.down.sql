@@ -0,0 +1,3 @@
+-- Reverte 022: remove o histórico de execuções dos jobs
+
+DROP TABLE IF EXISTS execucoes_job;
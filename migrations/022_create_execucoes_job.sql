@@ -0,0 +1,26 @@
+-- Migration: Histórico das execuções dos jobs agendados
+-- Data: 2026-10-19
+-- Descrição: Uma linha por execução de job do agendador (início, fim, resultado e contagens).
+--            A unicidade (job, agendado_para) garante uma execução por horário no cluster,
+--            mesmo que a trava no Redis expire no meio do job.
+
+CREATE TABLE IF NOT EXISTS execucoes_job (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    job VARCHAR(100) NOT NULL,
+    agendado_para TIMESTAMP NOT NULL,
+    instancia VARCHAR(255) NOT NULL,
+    token_fencing BIGINT NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('EM_ANDAMENTO', 'SUCESSO', 'FALHA')),
+    contagens JSONB NOT NULL DEFAULT '{}',
+    erro TEXT,
+    data_inicio TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    data_fim TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_execucoes_job_horario ON execucoes_job (job, agendado_para);
+CREATE INDEX IF NOT EXISTS idx_execucoes_job_inicio ON execucoes_job (data_inicio DESC);
+
+COMMENT ON TABLE execucoes_job IS 'Execuções dos jobs agendados (uma por job e horário no cluster)';
+COMMENT ON COLUMN execucoes_job.instancia IS 'Processo que executou o job (hostname do pod + sufixo aleatório)';
+COMMENT ON COLUMN execucoes_job.token_fencing IS 'Token da trava no Redis (crescente por job; 0 sem Redis)';
+COMMENT ON COLUMN execucoes_job.contagens IS 'Contagens do job, ex: {"encontradas": 10, "enfileiradas": 10}';
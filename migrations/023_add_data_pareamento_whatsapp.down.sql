@@ -0,0 +1,3 @@
+-- Reverte 023
+
+ALTER TABLE whatsapp_conexoes DROP COLUMN IF EXISTS data_pareamento;
@@ -0,0 +1,11 @@
+-- Migration: Data de pareamento do número WhatsApp
+-- Data: 2026-10-19
+-- Descrição: O aquecimento do número (ritmo de envio menor nos primeiros dias) conta a partir
+--            do pareamento. A conexão é reaproveitada quando outro número é pareado com o mesmo
+--            nome, então data_criacao não serve; data_conexao muda a cada reconexão.
+
+ALTER TABLE whatsapp_conexoes ADD COLUMN IF NOT EXISTS data_pareamento TIMESTAMP;
+
+-- Conexões existentes: sem histórico de pareamento, usa a criação
+UPDATE whatsapp_conexoes SET data_pareamento = data_criacao
+WHERE data_pareamento IS NULL AND status = 'CONECTADO';
@@ -0,0 +1,3 @@
+-- Reverte 024
+
+ALTER TABLE whatsapp_conexoes DROP COLUMN IF EXISTS verificacoes_sem_instancia;
@@ -0,0 +1,7 @@
+-- Migration: Verificações seguidas sem instância na Evolution API
+-- Data: 2026-10-19
+-- Descrição: O monitor só remove uma conexão órfã depois de não encontrar a instância em
+--            várias verificações seguidas. O contador fica no banco porque o job roda em
+--            qualquer réplica.
+
+ALTER TABLE whatsapp_conexoes ADD COLUMN IF NOT EXISTS verificacoes_sem_instancia INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+-- Reverte 025
+
+DROP INDEX IF EXISTS idx_webhook_entregas_pendentes;
@@ -0,0 +1,9 @@
+-- Migration: Índice das entregas de webhook pendentes
+-- Data: 2026-10-19
+-- Descrição: A varredura periódica reenfileira entregas pendentes que saíram da fila do
+--            Redis sem serem concluídas (réplica encerrada no meio da entrega, falha ao
+--            enfileirar). O índice parcial mantém a consulta barata com o log crescendo.
+
+CREATE INDEX IF NOT EXISTS idx_webhook_entregas_pendentes
+    ON webhook_entregas(data_atualizacao)
+    WHERE status = 'PENDENTE';
@@ -0,0 +1,4 @@
+-- Reverte 026: remove a unicidade por usuário
+
+DROP INDEX IF EXISTS idx_clientes_usuario_cpf;
+DROP INDEX IF EXISTS idx_clientes_usuario_cnpj;
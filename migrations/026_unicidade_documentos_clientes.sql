@@ -0,0 +1,31 @@
+-- Migration: Garante a unicidade de CPF/CNPJ por usuário
+-- Data: 2026-10-19
+-- Descrição: A 021 não criava os índices únicos quando havia documentos duplicados e era
+--            registrada como aplicada mesmo assim. Esta migration falha listando os
+--            duplicados; corrija-os (mescle ou limpe o documento) e rode de novo.
+
+DO $$
+DECLARE
+    duplicados TEXT;
+BEGIN
+    SELECT string_agg(format('usuario %s, %s %s (clientes: %s)', usuario_id, tipo, documento, clientes), E'\n')
+    INTO duplicados
+    FROM (
+        SELECT usuario_id, 'CPF' AS tipo, cpf AS documento, string_agg(id::text, ', ') AS clientes
+        FROM clientes WHERE cpf IS NOT NULL
+        GROUP BY usuario_id, cpf HAVING COUNT(*) > 1
+        UNION ALL
+        SELECT usuario_id, 'CNPJ', cnpj, string_agg(id::text, ', ')
+        FROM clientes WHERE cnpj IS NOT NULL
+        GROUP BY usuario_id, cnpj HAVING COUNT(*) > 1
+    ) d;
+
+    IF duplicados IS NOT NULL THEN
+        RAISE EXCEPTION E'Documentos duplicados em clientes, corrija antes de migrar:\n%', duplicados;
+    END IF;
+END $$;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_clientes_usuario_cpf ON clientes (usuario_id, cpf)
+    WHERE cpf IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_clientes_usuario_cnpj ON clientes (usuario_id, cnpj)
+    WHERE cnpj IS NOT NULL;
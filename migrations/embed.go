@@ -0,0 +1,9 @@
+// Package migrations embute os arquivos SQL de schema no binário da API
+package migrations
+
+import "embed"
+
+// Arquivos contém NNN_nome.sql (up) e NNN_nome.down.sql (down, opcional)
+//
+//go:embed *.sql
+var Arquivos embed.FS
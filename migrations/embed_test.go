@@ -0,0 +1,20 @@
+package migrations
+
+import (
+	"testing"
+
+	"github.com/ifinu/ifinu-api-go/util"
+)
+
+// O rollback alcança a 012; a partir dela toda migration precisa de down
+func TestMigracoesTemDown(t *testing.T) {
+	migracoes, err := util.LerMigracoes(Arquivos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range migracoes {
+		if m.Versao >= 12 && !m.TemDown() {
+			t.Errorf("migration %03d_%s sem arquivo .down.sql", m.Versao, m.Nome)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package rastreamento
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// Chave do span da consulta nas configurações da instrução do GORM
+const chaveSpanGORM = "rastreamento:span"
+
+// RegistrarGORM cria um span para cada consulta feita com um contexto rastreado
+// (db.WithContext ou ComContexto dos repositórios). O SQL vai sem os valores, que
+// podem conter dados pessoais.
+func RegistrarGORM(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("rastreamento:antes_create", iniciarConsulta("create")),
+		cb.Create().After("gorm:create").Register("rastreamento:depois_create", finalizarConsulta),
+		cb.Query().Before("gorm:query").Register("rastreamento:antes_query", iniciarConsulta("query")),
+		cb.Query().After("gorm:query").Register("rastreamento:depois_query", finalizarConsulta),
+		cb.Update().Before("gorm:update").Register("rastreamento:antes_update", iniciarConsulta("update")),
+		cb.Update().After("gorm:update").Register("rastreamento:depois_update", finalizarConsulta),
+		cb.Delete().Before("gorm:delete").Register("rastreamento:antes_delete", iniciarConsulta("delete")),
+		cb.Delete().After("gorm:delete").Register("rastreamento:depois_delete", finalizarConsulta),
+		cb.Row().Before("gorm:row").Register("rastreamento:antes_row", iniciarConsulta("row")),
+		cb.Row().After("gorm:row").Register("rastreamento:depois_row", finalizarConsulta),
+		cb.Raw().Before("gorm:raw").Register("rastreamento:antes_raw", iniciarConsulta("raw")),
+		cb.Raw().After("gorm:raw").Register("rastreamento:depois_raw", finalizarConsulta),
+	)
+}
+
+func iniciarConsulta(operacao string) func(*gorm.DB) {
+	return func(tx *gorm.DB) {
+		if !temSpan(tx.Statement.Context) {
+			return
+		}
+		ctx, span := otel.Tracer(nomeInstrumentacao).Start(tx.Statement.Context, "gorm."+operacao,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBOperationName(operacao)),
+		)
+		tx.Statement.Context = ctx
+		tx.InstanceSet(chaveSpanGORM, span)
+	}
+}
+
+func finalizarConsulta(tx *gorm.DB) {
+	valor, ok := tx.InstanceGet(chaveSpanGORM)
+	if !ok {
+		return
+	}
+	span := valor.(trace.Span)
+
+	if tx.Statement.Table != "" {
+		span.SetAttributes(semconv.DBCollectionName(tx.Statement.Table))
+	}
+	span.SetAttributes(semconv.DBQueryText(tx.Statement.SQL.String()))
+
+	// Registro não encontrado é resultado esperado de consulta, não falha
+	err := tx.Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = nil
+	}
+	Finalizar(span, err)
+}
@@ -0,0 +1,148 @@
+// Package rastreamento configura o tracing OpenTelemetry da API: spans das requisições,
+// dos services, das consultas GORM, dos comandos Redis e das chamadas HTTP de saída.
+// Sem exportador configurado o provider é no-op e nada é coletado.
+package rastreamento
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Nome do tracer dos spans criados pela própria API
+const nomeInstrumentacao = "github.com/ifinu/ifinu-api-go"
+
+// Exportadores aceitos em Opcoes.Exportador (mesmos valores de OTEL_TRACES_EXPORTER)
+const (
+	ExportadorNenhum = "none"
+	ExportadorOTLP   = "otlp"
+	ExportadorStdout = "console"
+)
+
+// Opcoes configura o provider criado por Configurar
+type Opcoes struct {
+	// "none" (padrão), "otlp" (OTLP/HTTP; endpoint e headers vêm das variáveis
+	// OTEL_EXPORTER_OTLP_*) ou "console" (stdout, para desenvolvimento)
+	Exportador  string
+	NomeServico string
+	Ambiente    string
+	// Fração dos traces iniciados aqui que são amostrados (0 a 1). Traces que chegam com
+	// traceparent seguem a decisão de quem chamou.
+	Amostragem float64
+}
+
+// Configurar registra o provider global e o propagador W3C (traceparent e baggage).
+// A função retornada descarrega os spans pendentes no encerramento.
+func Configurar(ctx context.Context, opcoes Opcoes) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exportador sdktrace.SpanExporter
+	var err error
+	switch strings.ToLower(opcoes.Exportador) {
+	case "", ExportadorNenhum:
+		return func(context.Context) error { return nil }, nil
+	case ExportadorOTLP:
+		exportador, err = otlptracehttp.New(ctx)
+	case ExportadorStdout:
+		exportador, err = stdouttrace.New()
+	default:
+		return nil, fmt.Errorf("exportador de traces desconhecido: %s (use none, otlp ou console)", opcoes.Exportador)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar exportador de traces: %w", err)
+	}
+
+	recurso, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(opcoes.NomeServico),
+		semconv.DeploymentEnvironment(opcoes.Ambiente),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao montar recurso dos traces: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exportador),
+		sdktrace.WithResource(recurso),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opcoes.Amostragem))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Iniciar abre um span filho do span em ctx. Quem chama encerra com Finalizar ou span.End.
+func Iniciar(ctx context.Context, nome string, atributos ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(nomeInstrumentacao).Start(ctx, nome, trace.WithAttributes(atributos...))
+}
+
+// Finalizar registra err no span (quando houver) e o encerra
+func Finalizar(span trace.Span, err error) {
+	RegistrarErro(span, err)
+	span.End()
+}
+
+// RegistrarErro marca o span como falho quando err não é nil
+func RegistrarErro(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+// Injetar serializa o trace de ctx para seguir junto com uma mensagem de fila
+func Injetar(ctx context.Context) map[string]string {
+	portador := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, portador)
+	if len(portador) == 0 {
+		return nil
+	}
+	return portador
+}
+
+// Extrair devolve ctx com o trace serializado por Injetar, para o worker continuar o
+// trace de quem enfileirou
+func Extrair(ctx context.Context, portador map[string]string) context.Context {
+	if len(portador) == 0 {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(portador))
+}
+
+// Transporte cria um span para cada chamada HTTP de saída e repassa o traceparent
+// (exceto nas chamadas feitas com um contexto de SemRastreamento)
+func Transporte(base http.RoundTripper) http.RoundTripper {
+	return otelhttp.NewTransport(base,
+		otelhttp.WithSpanNameFormatter(func(_ string, req *http.Request) string {
+			return req.Method + " " + req.URL.Host
+		}),
+		otelhttp.WithFilter(func(req *http.Request) bool {
+			return req.Context().Value(chaveSemRastreamento{}) == nil
+		}),
+	)
+}
+
+type chaveSemRastreamento struct{}
+
+// SemRastreamento marca ctx para que as chamadas HTTP de saída feitas com ele não virem
+// traces (ex.: as verificações das probes, repetidas a cada poucos segundos)
+func SemRastreamento(ctx context.Context) context.Context {
+	return context.WithValue(ctx, chaveSemRastreamento{}, true)
+}
+
+// temSpan indica se ctx faz parte de um trace. GORM e Redis só criam spans nesse caso,
+// para que consultas de rotinas internas não virem traces avulsos.
+func temSpan(ctx context.Context) bool {
+	return ctx != nil && trace.SpanContextFromContext(ctx).IsValid()
+}
@@ -0,0 +1,70 @@
+package rastreamento
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HookRedis cria um span para cada comando (ou pipeline) executado com um contexto
+// rastreado. Os argumentos não entram no span, só o nome do comando.
+func HookRedis() redis.Hook {
+	return hookRedis{}
+}
+
+type hookRedis struct{}
+
+// Guarda no contexto o span aberto pelo hook, para não encerrar o span de quem chamou
+type chaveSpanRedis struct{}
+
+func (hookRedis) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	return iniciarComando(ctx, "redis."+cmd.Name(), cmd.Name()), nil
+}
+
+func (hookRedis) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	finalizarComando(ctx, cmd.Err())
+	return nil
+}
+
+func (hookRedis) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	return iniciarComando(ctx, "redis.pipeline", "pipeline"), nil
+}
+
+func (hookRedis) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	var err error
+	for _, cmd := range cmds {
+		if cmd.Err() != nil && !errors.Is(cmd.Err(), redis.Nil) {
+			err = cmd.Err()
+			break
+		}
+	}
+	finalizarComando(ctx, err)
+	return nil
+}
+
+func iniciarComando(ctx context.Context, nome, operacao string) context.Context {
+	if !temSpan(ctx) {
+		return ctx
+	}
+	ctx, span := otel.Tracer(nomeInstrumentacao).Start(ctx, nome,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemRedis, semconv.DBOperationName(operacao)),
+	)
+	return context.WithValue(ctx, chaveSpanRedis{}, span)
+}
+
+func finalizarComando(ctx context.Context, err error) {
+	span, ok := ctx.Value(chaveSpanRedis{}).(trace.Span)
+	if !ok {
+		return
+	}
+	// Chave inexistente (redis.Nil) é resposta normal, não falha
+	if errors.Is(err, redis.Nil) {
+		err = nil
+	}
+	Finalizar(span, err)
+}
@@ -1,6 +1,8 @@
 package repositorio
 
 import (
+	"context"
+
 	"github.com/google/uuid"
 	"github.com/ifinu/ifinu-api-go/dominio/entidades"
 	"gorm.io/gorm"
@@ -14,6 +16,11 @@ func NovoAssinaturaRepositorio(db *gorm.DB) *AssinaturaRepositorio {
 	return &AssinaturaRepositorio{db: db}
 }
 
+// ComContexto retorna um repositório cujas consultas usam ctx (cancelamento e rastreamento)
+func (r *AssinaturaRepositorio) ComContexto(ctx context.Context) *AssinaturaRepositorio {
+	return &AssinaturaRepositorio{db: r.db.WithContext(ctx)}
+}
+
 // BuscarPorUsuario encontra a assinatura de um usuário
 func (r *AssinaturaRepositorio) BuscarPorUsuario(usuarioID uuid.UUID) (*entidades.AssinaturaUsuario, error) {
 	var assinatura entidades.AssinaturaUsuario
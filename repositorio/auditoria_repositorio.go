@@ -0,0 +1,87 @@
+package repositorio
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/dominio/entidades"
+	"gorm.io/gorm"
+)
+
+type AuditoriaRepositorio struct {
+	db *gorm.DB
+}
+
+func NovoAuditoriaRepositorio(db *gorm.DB) *AuditoriaRepositorio {
+	return &AuditoriaRepositorio{db: db}
+}
+
+// ComTransacao retorna um repositório que opera dentro da transação informada
+func (r *AuditoriaRepositorio) ComTransacao(tx *gorm.DB) *AuditoriaRepositorio {
+	return &AuditoriaRepositorio{db: tx}
+}
+
+// ComContexto retorna um repositório cujas consultas usam ctx (cancelamento e rastreamento)
+func (r *AuditoriaRepositorio) ComContexto(ctx context.Context) *AuditoriaRepositorio {
+	return &AuditoriaRepositorio{db: r.db.WithContext(ctx)}
+}
+
+// Transacao executa fn dentro de uma transação do banco
+func (r *AuditoriaRepositorio) Transacao(fn func(tx *gorm.DB) error) error {
+	return r.db.Transaction(fn)
+}
+
+// Registrar insere um registro de auditoria (a tabela não aceita update/delete)
+func (r *AuditoriaRepositorio) Registrar(registro *entidades.RegistroAuditoria) error {
+	return r.db.Create(registro).Error
+}
+
+// Buscar lista registros de auditoria de uma conta com filtros e paginação
+func (r *AuditoriaRepositorio) Buscar(
+	usuarioID uuid.UUID,
+	entidade string,
+	entidadeID string,
+	atorID *uuid.UUID,
+	dataInicio *time.Time,
+	dataFim *time.Time,
+	pagina int,
+	tamanhoPagina int,
+) ([]entidades.RegistroAuditoria, int64, error) {
+	var registros []entidades.RegistroAuditoria
+	var total int64
+
+	query := r.db.Model(&entidades.RegistroAuditoria{}).Where("usuario_id = ?", usuarioID)
+
+	if entidade != "" {
+		query = query.Where("entidade = ?", entidade)
+	}
+
+	if entidadeID != "" {
+		query = query.Where("entidade_id = ?", entidadeID)
+	}
+
+	if atorID != nil {
+		query = query.Where("ator_id = ?", *atorID)
+	}
+
+	if dataInicio != nil {
+		query = query.Where("data_criacao >= ?", *dataInicio)
+	}
+
+	if dataFim != nil {
+		query = query.Where("data_criacao <= ?", *dataFim)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (pagina - 1) * tamanhoPagina
+	err := query.Order("data_criacao DESC").
+		Offset(offset).
+		Limit(tamanhoPagina).
+		Find(&registros).Error
+
+	return registros, total, err
+}
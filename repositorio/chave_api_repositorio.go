@@ -0,0 +1,69 @@
+package repositorio
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/dominio/entidades"
+	"gorm.io/gorm"
+)
+
+type ChaveAPIRepositorio struct {
+	db *gorm.DB
+}
+
+func NovoChaveAPIRepositorio(db *gorm.DB) *ChaveAPIRepositorio {
+	return &ChaveAPIRepositorio{db: db}
+}
+
+// ComContexto retorna um repositório cujas consultas usam ctx (cancelamento e rastreamento)
+func (r *ChaveAPIRepositorio) ComContexto(ctx context.Context) *ChaveAPIRepositorio {
+	return &ChaveAPIRepositorio{db: r.db.WithContext(ctx)}
+}
+
+// Criar cria uma nova chave de API
+func (r *ChaveAPIRepositorio) Criar(chave *entidades.ChaveAPI) error {
+	return r.db.Create(chave).Error
+}
+
+// Atualizar atualiza uma chave de API existente
+func (r *ChaveAPIRepositorio) Atualizar(chave *entidades.ChaveAPI) error {
+	return r.db.Save(chave).Error
+}
+
+// BuscarPorHash encontra uma chave pelo hash do segredo
+func (r *ChaveAPIRepositorio) BuscarPorHash(hash string) (*entidades.ChaveAPI, error) {
+	var chave entidades.ChaveAPI
+	err := r.db.Where("hash = ?", hash).First(&chave).Error
+	if err != nil {
+		return nil, err
+	}
+	return &chave, nil
+}
+
+// BuscarPorID encontra uma chave pelo ID (com validação de usuário)
+func (r *ChaveAPIRepositorio) BuscarPorID(id uuid.UUID, usuarioID uuid.UUID) (*entidades.ChaveAPI, error) {
+	var chave entidades.ChaveAPI
+	err := r.db.Where("id = ? AND usuario_id = ?", id, usuarioID).First(&chave).Error
+	if err != nil {
+		return nil, err
+	}
+	return &chave, nil
+}
+
+// ListarPorUsuario lista as chaves não revogadas de uma conta
+func (r *ChaveAPIRepositorio) ListarPorUsuario(usuarioID uuid.UUID) ([]entidades.ChaveAPI, error) {
+	var chaves []entidades.ChaveAPI
+	err := r.db.Where("usuario_id = ? AND data_revogacao IS NULL", usuarioID).
+		Order("data_criacao DESC").
+		Find(&chaves).Error
+	return chaves, err
+}
+
+// RegistrarUso atualiza a data de último uso da chave
+func (r *ChaveAPIRepositorio) RegistrarUso(id uuid.UUID, momento time.Time) error {
+	return r.db.Model(&entidades.ChaveAPI{}).
+		Where("id = ?", id).
+		UpdateColumn("ultimo_uso", momento).Error
+}
@@ -1,8 +1,12 @@
 package repositorio
 
 import (
+	"context"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/ifinu/ifinu-api-go/dominio/entidades"
+	"github.com/ifinu/ifinu-api-go/util"
 	"gorm.io/gorm"
 )
 
@@ -14,6 +18,16 @@ func NovoClienteRepositorio(db *gorm.DB) *ClienteRepositorio {
 	return &ClienteRepositorio{db: db}
 }
 
+// ComTransacao retorna um repositório que opera dentro da transação informada
+func (r *ClienteRepositorio) ComTransacao(tx *gorm.DB) *ClienteRepositorio {
+	return &ClienteRepositorio{db: tx}
+}
+
+// ComContexto retorna um repositório cujas consultas usam ctx (cancelamento e rastreamento)
+func (r *ClienteRepositorio) ComContexto(ctx context.Context) *ClienteRepositorio {
+	return &ClienteRepositorio{db: r.db.WithContext(ctx)}
+}
+
 // BuscarPorID encontra um cliente pelo ID (com validação de usuário)
 func (r *ClienteRepositorio) BuscarPorID(id uuid.UUID, usuarioID uuid.UUID) (*entidades.Cliente, error) {
 	var cliente entidades.Cliente
@@ -24,6 +38,17 @@ func (r *ClienteRepositorio) BuscarPorID(id uuid.UUID, usuarioID uuid.UUID) (*en
 	return &cliente, nil
 }
 
+// BuscarPorIDSemUsuario encontra um cliente apenas pelo ID.
+// Uso restrito a fluxos públicos autenticados por token assinado (ex: link de descadastro).
+func (r *ClienteRepositorio) BuscarPorIDSemUsuario(id uuid.UUID) (*entidades.Cliente, error) {
+	var cliente entidades.Cliente
+	err := r.db.Where("id = ?", id).First(&cliente).Error
+	if err != nil {
+		return nil, err
+	}
+	return &cliente, nil
+}
+
 // BuscarPorUsuario retorna todos os clientes de um usuário
 func (r *ClienteRepositorio) BuscarPorUsuario(usuarioID uuid.UUID) ([]entidades.Cliente, error) {
 	var clientes []entidades.Cliente
@@ -41,10 +66,18 @@ func (r *ClienteRepositorio) BuscarPorEmail(email string, usuarioID uuid.UUID) (
 	return &cliente, nil
 }
 
-// BuscarPorTelefone encontra um cliente pelo telefone (com validação de usuário)
+// BuscarPorTelefone encontra um cliente pelo telefone (com validação de usuário).
+// Compara apenas os dígitos, aceitando o número com ou sem 55 e nono dígito.
 func (r *ClienteRepositorio) BuscarPorTelefone(telefone string, usuarioID uuid.UUID) (*entidades.Cliente, error) {
+	variantes := util.VariantesTelefoneBrasileiro(telefone)
+	if len(variantes) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
 	var cliente entidades.Cliente
-	err := r.db.Where("telefone = ? AND usuario_id = ?", telefone, usuarioID).First(&cliente).Error
+	err := r.db.Where("usuario_id = ? AND regexp_replace(telefone, '[^0-9]', '', 'g') IN ?", usuarioID, variantes).
+		Order("ativo DESC, data_criacao ASC").
+		First(&cliente).Error
 	if err != nil {
 		return nil, err
 	}
@@ -61,12 +94,20 @@ func (r *ClienteRepositorio) Atualizar(cliente *entidades.Cliente) error {
 	return r.db.Save(cliente).Error
 }
 
+// AtualizarVerificacaoWhatsApp grava o resultado da verificação do número sem tocar nos demais campos.
+// Não altera nada se o telefone mudou desde a consulta.
+func (r *ClienteRepositorio) AtualizarVerificacaoWhatsApp(id uuid.UUID, telefone string, valido bool, data time.Time) error {
+	return r.db.Model(&entidades.Cliente{}).
+		Where("id = ? AND telefone = ?", id, telefone).
+		UpdateColumns(map[string]interface{}{"whatsapp_valido": valido, "data_verificacao_whatsapp": data}).Error
+}
+
 // Deletar remove um cliente (com validação de usuário)
 func (r *ClienteRepositorio) Deletar(id uuid.UUID, usuarioID uuid.UUID) error {
 	return r.db.Where("id = ? AND usuario_id = ?", id, usuarioID).Delete(&entidades.Cliente{}).Error
 }
 
-// Buscar com filtros (nome, email, telefone)
+// Buscar com filtros (nome, email, telefone, CPF/CNPJ)
 func (r *ClienteRepositorio) Buscar(usuarioID uuid.UUID, termo string, pagina int, tamanhoPagina int) ([]entidades.Cliente, int64, error) {
 	var clientes []entidades.Cliente
 	var total int64
@@ -74,8 +115,10 @@ func (r *ClienteRepositorio) Buscar(usuarioID uuid.UUID, termo string, pagina in
 	query := r.db.Model(&entidades.Cliente{}).Where("usuario_id = ?", usuarioID)
 
 	if termo != "" {
-		query = query.Where("nome ILIKE ? OR email ILIKE ? OR telefone ILIKE ?",
-			"%"+termo+"%", "%"+termo+"%", "%"+termo+"%")
+		// Documentos são gravados sem máscara
+		documento := util.NormalizarDocumento(termo)
+		query = query.Where("nome ILIKE ? OR email ILIKE ? OR telefone ILIKE ? OR (? <> '' AND (cpf LIKE ? OR cnpj LIKE ?))",
+			"%"+termo+"%", "%"+termo+"%", "%"+termo+"%", documento, documento+"%", documento+"%")
 	}
 
 	// Contar total
@@ -98,6 +141,15 @@ func (r *ClienteRepositorio) ContarPorUsuario(usuarioID uuid.UUID) (int64, error
 	return count, err
 }
 
+// ExisteDocumento verifica se outro cliente do usuário já usa o CPF ou CNPJ (sem máscara)
+func (r *ClienteRepositorio) ExisteDocumento(documento string, usuarioID uuid.UUID, ignorarID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&entidades.Cliente{}).
+		Where("usuario_id = ? AND id <> ? AND (cpf = ? OR cnpj = ?)", usuarioID, ignorarID, documento, documento).
+		Count(&count).Error
+	return count > 0, err
+}
+
 // ExistePorEmail verifica se um email já está cadastrado para o usuário
 func (r *ClienteRepositorio) ExistePorEmail(email string, usuarioID uuid.UUID) (bool, error) {
 	var count int64
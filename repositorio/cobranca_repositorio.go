@@ -1,6 +1,7 @@
 package repositorio
 
 import (
+	"context"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,6 +18,16 @@ func NovoCobrancaRepositorio(db *gorm.DB) *CobrancaRepositorio {
 	return &CobrancaRepositorio{db: db}
 }
 
+// ComTransacao retorna um repositório que opera dentro da transação informada
+func (r *CobrancaRepositorio) ComTransacao(tx *gorm.DB) *CobrancaRepositorio {
+	return &CobrancaRepositorio{db: tx}
+}
+
+// ComContexto retorna um repositório cujas consultas usam ctx (cancelamento e rastreamento)
+func (r *CobrancaRepositorio) ComContexto(ctx context.Context) *CobrancaRepositorio {
+	return &CobrancaRepositorio{db: r.db.WithContext(ctx)}
+}
+
 // BuscarPorID encontra uma cobrança pelo ID (com validação de usuário)
 func (r *CobrancaRepositorio) BuscarPorID(id uuid.UUID, usuarioID uuid.UUID) (*entidades.Cobranca, error) {
 	var cobranca entidades.Cobranca
@@ -49,6 +60,18 @@ func (r *CobrancaRepositorio) BuscarPorCliente(clienteID uuid.UUID, usuarioID uu
 	return cobrancas, err
 }
 
+// BuscarEmAbertoPorCliente retorna as cobranças pendentes ou vencidas do cliente,
+// da mais antiga para a mais recente
+func (r *CobrancaRepositorio) BuscarEmAbertoPorCliente(clienteID uuid.UUID, usuarioID uuid.UUID) ([]entidades.Cobranca, error) {
+	var cobrancas []entidades.Cobranca
+	err := r.db.Preload("Cliente").
+		Where("cliente_id = ? AND usuario_id = ? AND status IN ?", clienteID, usuarioID,
+			[]enums.StatusCobranca{enums.StatusCobrancaPendente, enums.StatusCobrancaVencido}).
+		Order("data_vencimento ASC").
+		Find(&cobrancas).Error
+	return cobrancas, err
+}
+
 // BuscarPorStatus retorna cobranças por status
 func (r *CobrancaRepositorio) BuscarPorStatus(status enums.StatusCobranca, usuarioID uuid.UUID) ([]entidades.Cobranca, error) {
 	var cobrancas []entidades.Cobranca
@@ -126,6 +149,7 @@ func (r *CobrancaRepositorio) BuscarCobrancasVencendoHoje() ([]entidades.Cobranc
 	err := r.db.Preload("Cliente").Preload("Usuario").
 		Where("status = ? AND data_vencimento >= ? AND data_vencimento < ? AND notificacao_vencimento_enviada = ?",
 			enums.StatusCobrancaPendente, hoje, amanha, false).
+		Where("cliente_id IN (SELECT id FROM clientes WHERE data_opt_out IS NULL AND canal_notificacao <> ?)", enums.CanalNotificacaoNenhum).
 		Find(&cobrancas).Error
 
 	return cobrancas, err
@@ -140,14 +164,15 @@ func (r *CobrancaRepositorio) BuscarCobrancasParaLembrete() ([]entidades.Cobranc
 	err := r.db.Preload("Cliente").Preload("Usuario").
 		Where("status = ? AND data_vencimento >= ? AND data_vencimento < ? AND notificacao_lembrete_enviada = ?",
 			enums.StatusCobrancaPendente, tresDiasDepois, quatroDiasDepois, false).
+		Where("cliente_id IN (SELECT id FROM clientes WHERE data_opt_out IS NULL AND canal_notificacao <> ?)", enums.CanalNotificacaoNenhum).
 		Find(&cobrancas).Error
 
 	return cobrancas, err
 }
 
-// BuscarCobrancasVencidas retorna cobranças vencidas
-func (r *CobrancaRepositorio) BuscarCobrancasVencidas() ([]entidades.Cobranca, error) {
-	hoje := time.Now().Truncate(24 * time.Hour)
+// BuscarCobrancasVencidas retorna cobranças pendentes com vencimento antes do dia informado
+func (r *CobrancaRepositorio) BuscarCobrancasVencidas(data time.Time) ([]entidades.Cobranca, error) {
+	hoje := data.Truncate(24 * time.Hour)
 
 	var cobrancas []entidades.Cobranca
 	err := r.db.Preload("Cliente").Preload("Usuario").
@@ -0,0 +1,62 @@
+package repositorio
+
+import (
+	"context"
+	"time"
+
+	"github.com/ifinu/ifinu-api-go/dominio/entidades"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ExecucaoJobRepositorio struct {
+	db *gorm.DB
+}
+
+func NovoExecucaoJobRepositorio(db *gorm.DB) *ExecucaoJobRepositorio {
+	return &ExecucaoJobRepositorio{db: db}
+}
+
+// ComContexto retorna um repositório cujas consultas usam ctx (cancelamento e rastreamento)
+func (r *ExecucaoJobRepositorio) ComContexto(ctx context.Context) *ExecucaoJobRepositorio {
+	return &ExecucaoJobRepositorio{db: r.db.WithContext(ctx)}
+}
+
+// Iniciar grava a execução se ainda não houver outra do mesmo job para o mesmo horário.
+// Retorna false se outra réplica já registrou esse disparo.
+func (r *ExecucaoJobRepositorio) Iniciar(execucao *entidades.ExecucaoJob) (bool, error) {
+	resultado := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "job"}, {Name: "agendado_para"}},
+		DoNothing: true,
+	}).Create(execucao)
+	return resultado.RowsAffected > 0, resultado.Error
+}
+
+// Finalizar grava o resultado da execução. O token confere que a linha é da mesma trava.
+func (r *ExecucaoJobRepositorio) Finalizar(execucao *entidades.ExecucaoJob) error {
+	return r.db.Model(&entidades.ExecucaoJob{}).
+		Where("id = ? AND token_fencing = ?", execucao.ID, execucao.TokenFencing).
+		Updates(map[string]interface{}{
+			"status":    execucao.Status,
+			"contagens": execucao.Contagens,
+			"erro":      execucao.Erro,
+			"data_fim":  execucao.DataFim,
+		}).Error
+}
+
+// ListarRecentes lista as últimas execuções, de todos os jobs ou só do informado
+func (r *ExecucaoJobRepositorio) ListarRecentes(job string, limite int) ([]entidades.ExecucaoJob, error) {
+	var execucoes []entidades.ExecucaoJob
+	query := r.db.Order("data_inicio DESC").Limit(limite)
+	if job != "" {
+		query = query.Where("job = ?", job)
+	}
+	err := query.Find(&execucoes).Error
+	return execucoes, err
+}
+
+// RemoverAnteriores apaga as execuções iniciadas antes de data. Retorna quantas foram removidas.
+func (r *ExecucaoJobRepositorio) RemoverAnteriores(data time.Time) (int64, error) {
+	resultado := r.db.Where("data_inicio < ?", data).Delete(&entidades.ExecucaoJob{})
+	return resultado.RowsAffected, resultado.Error
+}
@@ -0,0 +1,142 @@
+package repositorio
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/dominio/entidades"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ResumoConversaWhatsApp é uma linha da lista de conversas da caixa de entrada
+type ResumoConversaWhatsApp struct {
+	Telefone           string
+	ClienteID          *uuid.UUID
+	NomeCliente        string
+	NomeContato        string
+	UltimaMensagem     string
+	UltimaDirecao      entidades.DirecaoMensagem
+	DataUltimaMensagem time.Time
+	NaoLidas           int64
+}
+
+type MensagemWhatsAppRepositorio struct {
+	db *gorm.DB
+}
+
+func NovoMensagemWhatsAppRepositorio(db *gorm.DB) *MensagemWhatsAppRepositorio {
+	return &MensagemWhatsAppRepositorio{db: db}
+}
+
+// ComContexto retorna um repositório cujas consultas usam ctx (cancelamento e rastreamento)
+func (r *MensagemWhatsAppRepositorio) ComContexto(ctx context.Context) *MensagemWhatsAppRepositorio {
+	return &MensagemWhatsAppRepositorio{db: r.db.WithContext(ctx)}
+}
+
+// Criar grava a mensagem ignorando duplicatas (mesmo ID externo na mesma conexão).
+// Retorna false se a mensagem já existia.
+func (r *MensagemWhatsAppRepositorio) Criar(mensagem *entidades.MensagemWhatsApp) (bool, error) {
+	resultado := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "conexao_id"}, {Name: "id_externo"}},
+		DoNothing: true,
+	}).Create(mensagem)
+	return resultado.RowsAffected > 0, resultado.Error
+}
+
+// BuscarUltimaMensagem retorna a mensagem mais recente de uma conversa
+func (r *MensagemWhatsAppRepositorio) BuscarUltimaMensagem(usuarioID uuid.UUID, telefone string) (*entidades.MensagemWhatsApp, error) {
+	var mensagem entidades.MensagemWhatsApp
+	err := r.db.Where("usuario_id = ? AND telefone = ?", usuarioID, telefone).
+		Order("data_mensagem DESC").
+		First(&mensagem).Error
+	if err != nil {
+		return nil, err
+	}
+	return &mensagem, nil
+}
+
+// ListarMensagens lista as mensagens de uma conversa com paginação (mais recentes primeiro)
+func (r *MensagemWhatsAppRepositorio) ListarMensagens(usuarioID uuid.UUID, telefone string, pagina, tamanhoPagina int) ([]entidades.MensagemWhatsApp, int64, error) {
+	var mensagens []entidades.MensagemWhatsApp
+	var total int64
+
+	query := r.db.Model(&entidades.MensagemWhatsApp{}).
+		Where("usuario_id = ? AND telefone = ?", usuarioID, telefone)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (pagina - 1) * tamanhoPagina
+	err := query.Order("data_mensagem DESC").
+		Offset(offset).
+		Limit(tamanhoPagina).
+		Find(&mensagens).Error
+
+	return mensagens, total, err
+}
+
+// ListarConversas agrupa as mensagens por telefone, da conversa mais recente para a mais antiga
+func (r *MensagemWhatsAppRepositorio) ListarConversas(usuarioID uuid.UUID, pagina, tamanhoPagina int) ([]ResumoConversaWhatsApp, int64, error) {
+	var total int64
+	err := r.db.Model(&entidades.MensagemWhatsApp{}).
+		Where("usuario_id = ?", usuarioID).
+		Distinct("telefone").
+		Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var conversas []ResumoConversaWhatsApp
+	offset := (pagina - 1) * tamanhoPagina
+	err = r.db.Raw(`
+		SELECT ultima.telefone,
+		       ultima.cliente_id,
+		       COALESCE(c.nome, '') AS nome_cliente,
+		       COALESCE((
+		           SELECT e.nome_contato FROM whatsapp_mensagens e
+		           WHERE e.usuario_id = ultima.usuario_id AND e.telefone = ultima.telefone
+		             AND e.direcao = ? AND e.nome_contato <> ''
+		           ORDER BY e.data_mensagem DESC LIMIT 1
+		       ), '') AS nome_contato,
+		       ultima.texto AS ultima_mensagem,
+		       ultima.direcao AS ultima_direcao,
+		       ultima.data_mensagem AS data_ultima_mensagem,
+		       (
+		           SELECT COUNT(*) FROM whatsapp_mensagens n
+		           WHERE n.usuario_id = ultima.usuario_id AND n.telefone = ultima.telefone
+		             AND n.direcao = ? AND NOT n.lida
+		       ) AS nao_lidas
+		FROM (
+		    SELECT DISTINCT ON (telefone) *
+		    FROM whatsapp_mensagens
+		    WHERE usuario_id = ?
+		    ORDER BY telefone, data_mensagem DESC
+		) ultima
+		LEFT JOIN clientes c ON c.id = ultima.cliente_id
+		ORDER BY ultima.data_mensagem DESC
+		OFFSET ? LIMIT ?`,
+		entidades.DirecaoMensagemEntrada, entidades.DirecaoMensagemEntrada, usuarioID, offset, tamanhoPagina,
+	).Scan(&conversas).Error
+
+	return conversas, total, err
+}
+
+// ContarNaoLidas conta as mensagens recebidas ainda não lidas da conta
+func (r *MensagemWhatsAppRepositorio) ContarNaoLidas(usuarioID uuid.UUID) (int64, error) {
+	var total int64
+	err := r.db.Model(&entidades.MensagemWhatsApp{}).
+		Where("usuario_id = ? AND direcao = ? AND NOT lida", usuarioID, entidades.DirecaoMensagemEntrada).
+		Count(&total).Error
+	return total, err
+}
+
+// MarcarComoLidas marca as mensagens recebidas de uma conversa como lidas
+func (r *MensagemWhatsAppRepositorio) MarcarComoLidas(usuarioID uuid.UUID, telefone string) (int64, error) {
+	resultado := r.db.Model(&entidades.MensagemWhatsApp{}).
+		Where("usuario_id = ? AND telefone = ? AND direcao = ? AND NOT lida", usuarioID, telefone, entidades.DirecaoMensagemEntrada).
+		Updates(map[string]interface{}{"lida": true, "data_leitura": time.Now()})
+	return resultado.RowsAffected, resultado.Error
+}
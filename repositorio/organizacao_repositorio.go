@@ -0,0 +1,130 @@
+package repositorio
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/dominio/entidades"
+	"github.com/ifinu/ifinu-api-go/dominio/enums"
+	"gorm.io/gorm"
+)
+
+type OrganizacaoRepositorio struct {
+	db *gorm.DB
+}
+
+func NovoOrganizacaoRepositorio(db *gorm.DB) *OrganizacaoRepositorio {
+	return &OrganizacaoRepositorio{db: db}
+}
+
+// ComTransacao retorna um repositório que opera dentro da transação informada
+func (r *OrganizacaoRepositorio) ComTransacao(tx *gorm.DB) *OrganizacaoRepositorio {
+	return &OrganizacaoRepositorio{db: tx}
+}
+
+// ComContexto retorna um repositório cujas consultas usam ctx (cancelamento e rastreamento)
+func (r *OrganizacaoRepositorio) ComContexto(ctx context.Context) *OrganizacaoRepositorio {
+	return &OrganizacaoRepositorio{db: r.db.WithContext(ctx)}
+}
+
+// BuscarPorTitular encontra a organização de um usuário titular
+func (r *OrganizacaoRepositorio) BuscarPorTitular(titularID uuid.UUID) (*entidades.Organizacao, error) {
+	var organizacao entidades.Organizacao
+	err := r.db.Where("titular_id = ?", titularID).First(&organizacao).Error
+	if err != nil {
+		return nil, err
+	}
+	return &organizacao, nil
+}
+
+// Criar cria uma nova organização junto com o membro titular
+func (r *OrganizacaoRepositorio) Criar(organizacao *entidades.Organizacao, titular *entidades.MembroOrganizacao) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(organizacao).Error; err != nil {
+			return err
+		}
+		titular.OrganizacaoID = organizacao.ID
+		return tx.Create(titular).Error
+	})
+}
+
+// Atualizar atualiza uma organização existente
+func (r *OrganizacaoRepositorio) Atualizar(organizacao *entidades.Organizacao) error {
+	return r.db.Save(organizacao).Error
+}
+
+// ListarMembros retorna os membros (ativos e convidados) de uma organização
+func (r *OrganizacaoRepositorio) ListarMembros(organizacaoID uuid.UUID) ([]entidades.MembroOrganizacao, error) {
+	var membros []entidades.MembroOrganizacao
+	err := r.db.Where("organizacao_id = ? AND status <> ?", organizacaoID, entidades.StatusMembroRemovido).
+		Order("data_criacao ASC").
+		Find(&membros).Error
+	return membros, err
+}
+
+// BuscarMembroPorID encontra um membro pelo ID (com validação de organização)
+func (r *OrganizacaoRepositorio) BuscarMembroPorID(id uuid.UUID, organizacaoID uuid.UUID) (*entidades.MembroOrganizacao, error) {
+	var membro entidades.MembroOrganizacao
+	err := r.db.Where("id = ? AND organizacao_id = ?", id, organizacaoID).First(&membro).Error
+	if err != nil {
+		return nil, err
+	}
+	return &membro, nil
+}
+
+// BuscarMembroPorEmail encontra um membro não removido pelo email (com validação de organização)
+func (r *OrganizacaoRepositorio) BuscarMembroPorEmail(email string, organizacaoID uuid.UUID) (*entidades.MembroOrganizacao, error) {
+	var membro entidades.MembroOrganizacao
+	err := r.db.Where("LOWER(email) = LOWER(?) AND organizacao_id = ? AND status <> ?", email, organizacaoID, entidades.StatusMembroRemovido).
+		First(&membro).Error
+	if err != nil {
+		return nil, err
+	}
+	return &membro, nil
+}
+
+// BuscarMembroPorToken encontra um convite pendente pelo token
+func (r *OrganizacaoRepositorio) BuscarMembroPorToken(token string) (*entidades.MembroOrganizacao, error) {
+	var membro entidades.MembroOrganizacao
+	err := r.db.Preload("Organizacao").
+		Where("token_convite = ? AND status = ?", token, entidades.StatusMembroConvidado).
+		First(&membro).Error
+	if err != nil {
+		return nil, err
+	}
+	return &membro, nil
+}
+
+// BuscarMembroAtivoPorUsuario encontra a associação ativa de um usuário.
+// Associações como convidado (papel diferente de OWNER) têm prioridade sobre
+// a organização própria do usuário.
+func (r *OrganizacaoRepositorio) BuscarMembroAtivoPorUsuario(usuarioID uuid.UUID) (*entidades.MembroOrganizacao, error) {
+	var membro entidades.MembroOrganizacao
+	err := r.db.Preload("Organizacao").
+		Where("usuario_id = ? AND status = ?", usuarioID, entidades.StatusMembroAtivo).
+		Order(gorm.Expr("CASE WHEN papel = ? THEN 1 ELSE 0 END", enums.PapelOwner)).
+		First(&membro).Error
+	if err != nil {
+		return nil, err
+	}
+	return &membro, nil
+}
+
+// ExisteAssociacaoConvidado verifica se o usuário já é membro ativo de outra organização
+func (r *OrganizacaoRepositorio) ExisteAssociacaoConvidado(usuarioID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&entidades.MembroOrganizacao{}).
+		Where("usuario_id = ? AND status = ? AND papel <> ?", usuarioID, entidades.StatusMembroAtivo, enums.PapelOwner).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// CriarMembro cria um novo membro/convite
+func (r *OrganizacaoRepositorio) CriarMembro(membro *entidades.MembroOrganizacao) error {
+	return r.db.Create(membro).Error
+}
+
+// AtualizarMembro atualiza um membro existente
+func (r *OrganizacaoRepositorio) AtualizarMembro(membro *entidades.MembroOrganizacao) error {
+	return r.db.Save(membro).Error
+}
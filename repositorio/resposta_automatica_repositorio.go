@@ -0,0 +1,130 @@
+package repositorio
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/dominio/entidades"
+	"gorm.io/gorm"
+)
+
+type RespostaAutomaticaRepositorio struct {
+	db *gorm.DB
+}
+
+func NovoRespostaAutomaticaRepositorio(db *gorm.DB) *RespostaAutomaticaRepositorio {
+	return &RespostaAutomaticaRepositorio{db: db}
+}
+
+// ComTransacao retorna um repositório que opera dentro da transação informada
+func (r *RespostaAutomaticaRepositorio) ComTransacao(tx *gorm.DB) *RespostaAutomaticaRepositorio {
+	return &RespostaAutomaticaRepositorio{db: tx}
+}
+
+// ComContexto retorna um repositório cujas consultas usam ctx (cancelamento e rastreamento)
+func (r *RespostaAutomaticaRepositorio) ComContexto(ctx context.Context) *RespostaAutomaticaRepositorio {
+	return &RespostaAutomaticaRepositorio{db: r.db.WithContext(ctx)}
+}
+
+// CriarRegra cria uma nova regra do auto-responder
+func (r *RespostaAutomaticaRepositorio) CriarRegra(regra *entidades.RespostaAutomatica) error {
+	return r.db.Create(regra).Error
+}
+
+// AtualizarRegra atualiza uma regra existente
+func (r *RespostaAutomaticaRepositorio) AtualizarRegra(regra *entidades.RespostaAutomatica) error {
+	return r.db.Save(regra).Error
+}
+
+// DeletarRegra remove uma regra (com validação de usuário)
+func (r *RespostaAutomaticaRepositorio) DeletarRegra(id uuid.UUID, usuarioID uuid.UUID) error {
+	return r.db.Where("id = ? AND usuario_id = ?", id, usuarioID).Delete(&entidades.RespostaAutomatica{}).Error
+}
+
+// BuscarRegraPorID encontra uma regra pelo ID (com validação de usuário)
+func (r *RespostaAutomaticaRepositorio) BuscarRegraPorID(id uuid.UUID, usuarioID uuid.UUID) (*entidades.RespostaAutomatica, error) {
+	var regra entidades.RespostaAutomatica
+	err := r.db.Where("id = ? AND usuario_id = ?", id, usuarioID).First(&regra).Error
+	if err != nil {
+		return nil, err
+	}
+	return &regra, nil
+}
+
+// ListarRegras lista as regras da conta em ordem alfabética de palavra-chave
+func (r *RespostaAutomaticaRepositorio) ListarRegras(usuarioID uuid.UUID) ([]entidades.RespostaAutomatica, error) {
+	var regras []entidades.RespostaAutomatica
+	err := r.db.Where("usuario_id = ?", usuarioID).
+		Order("palavra_chave ASC").
+		Find(&regras).Error
+	return regras, err
+}
+
+// ListarRegrasAtivas lista as regras ativas, das palavras-chave mais longas para as mais curtas
+// (a regra mais específica vence quando mais de uma aparece na mensagem)
+func (r *RespostaAutomaticaRepositorio) ListarRegrasAtivas(usuarioID uuid.UUID) ([]entidades.RespostaAutomatica, error) {
+	var regras []entidades.RespostaAutomatica
+	err := r.db.Where("usuario_id = ? AND ativo = ?", usuarioID, true).
+		Order("LENGTH(palavra_chave) DESC, palavra_chave ASC").
+		Find(&regras).Error
+	return regras, err
+}
+
+// ExistePalavraChave verifica se a palavra-chave já está em uso por outra regra da conta
+func (r *RespostaAutomaticaRepositorio) ExistePalavraChave(usuarioID uuid.UUID, palavraChave string, excetoID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&entidades.RespostaAutomatica{}).
+		Where("usuario_id = ? AND palavra_chave = ? AND id <> ?", usuarioID, palavraChave, excetoID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// BuscarConfig retorna a configuração do auto-responder da conta
+func (r *RespostaAutomaticaRepositorio) BuscarConfig(usuarioID uuid.UUID) (*entidades.ConfigRespostaAutomatica, error) {
+	var config entidades.ConfigRespostaAutomatica
+	err := r.db.Where("usuario_id = ?", usuarioID).First(&config).Error
+	if err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// SalvarConfig cria ou atualiza a configuração do auto-responder
+func (r *RespostaAutomaticaRepositorio) SalvarConfig(config *entidades.ConfigRespostaAutomatica) error {
+	return r.db.Save(config).Error
+}
+
+// RegistrarDisparo grava uma resposta automática no log
+func (r *RespostaAutomaticaRepositorio) RegistrarDisparo(disparo *entidades.DisparoRespostaAutomatica) error {
+	return r.db.Create(disparo).Error
+}
+
+// ListarDisparos lista o log de respostas automáticas com paginação
+func (r *RespostaAutomaticaRepositorio) ListarDisparos(usuarioID uuid.UUID, pagina, tamanhoPagina int) ([]entidades.DisparoRespostaAutomatica, int64, error) {
+	var disparos []entidades.DisparoRespostaAutomatica
+	var total int64
+
+	query := r.db.Model(&entidades.DisparoRespostaAutomatica{}).Where("usuario_id = ?", usuarioID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (pagina - 1) * tamanhoPagina
+	err := query.Order("data_criacao DESC").
+		Offset(offset).
+		Limit(tamanhoPagina).
+		Find(&disparos).Error
+
+	return disparos, total, err
+}
+
+// ExisteDisparoRecente verifica se o telefone recebeu alguma resposta automática desde o horário informado
+func (r *RespostaAutomaticaRepositorio) ExisteDisparoRecente(usuarioID uuid.UUID, telefone string, desde time.Time) (bool, error) {
+	var count int64
+	err := r.db.Model(&entidades.DisparoRespostaAutomatica{}).
+		Where("usuario_id = ? AND telefone = ? AND sucesso = ? AND data_criacao >= ?", usuarioID, telefone, true, desde).
+		Count(&count).Error
+	return count > 0, err
+}
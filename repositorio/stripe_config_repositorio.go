@@ -1,6 +1,8 @@
 package repositorio
 
 import (
+	"context"
+
 	"github.com/google/uuid"
 	"github.com/ifinu/ifinu-api-go/dominio/entidades"
 	"gorm.io/gorm"
@@ -14,6 +16,16 @@ func NovoStripeConfigRepositorio(db *gorm.DB) *StripeConfigRepositorio {
 	return &StripeConfigRepositorio{db: db}
 }
 
+// ComTransacao retorna um repositório que opera dentro da transação informada
+func (r *StripeConfigRepositorio) ComTransacao(tx *gorm.DB) *StripeConfigRepositorio {
+	return &StripeConfigRepositorio{db: tx}
+}
+
+// ComContexto retorna um repositório cujas consultas usam ctx (cancelamento e rastreamento)
+func (r *StripeConfigRepositorio) ComContexto(ctx context.Context) *StripeConfigRepositorio {
+	return &StripeConfigRepositorio{db: r.db.WithContext(ctx)}
+}
+
 // BuscarPorUsuario encontra a configuração Stripe de um usuário
 func (r *StripeConfigRepositorio) BuscarPorUsuario(usuarioID uuid.UUID) (*entidades.StripeConfig, error) {
 	var config entidades.StripeConfig
@@ -47,3 +59,18 @@ func (r *StripeConfigRepositorio) Existe(usuarioID uuid.UUID) (bool, error) {
 		Count(&count).Error
 	return count > 0, err
 }
+
+// ListarTodas lista as configurações Stripe de todas as contas
+func (r *StripeConfigRepositorio) ListarTodas() ([]entidades.StripeConfig, error) {
+	var configs []entidades.StripeConfig
+	err := r.db.Order("id").Find(&configs).Error
+	return configs, err
+}
+
+// TrocarSecretKeyCriptografada grava o novo texto cifrado apenas se o atual ainda for o
+// informado (a configuração pode ter sido alterada enquanto era recriptografada)
+func (r *StripeConfigRepositorio) TrocarSecretKeyCriptografada(id int64, atual, nova string) error {
+	return r.db.Model(&entidades.StripeConfig{}).
+		Where("id = ? AND secret_key_encrypted = ?", id, atual).
+		Update("secret_key_encrypted", nova).Error
+}
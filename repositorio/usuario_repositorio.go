@@ -1,6 +1,8 @@
 package repositorio
 
 import (
+	"context"
+
 	"github.com/google/uuid"
 	"github.com/ifinu/ifinu-api-go/dominio/entidades"
 	"gorm.io/gorm"
@@ -14,6 +16,16 @@ func NovoUsuarioRepositorio(db *gorm.DB) *UsuarioRepositorio {
 	return &UsuarioRepositorio{db: db}
 }
 
+// ComTransacao retorna um repositório que opera dentro da transação informada
+func (r *UsuarioRepositorio) ComTransacao(tx *gorm.DB) *UsuarioRepositorio {
+	return &UsuarioRepositorio{db: tx}
+}
+
+// ComContexto retorna um repositório cujas consultas usam ctx (cancelamento e rastreamento)
+func (r *UsuarioRepositorio) ComContexto(ctx context.Context) *UsuarioRepositorio {
+	return &UsuarioRepositorio{db: r.db.WithContext(ctx)}
+}
+
 // BuscarPorEmail encontra um usuário pelo email
 func (r *UsuarioRepositorio) BuscarPorEmail(email string) (*entidades.Usuario, error) {
 	var usuario entidades.Usuario
@@ -0,0 +1,160 @@
+package repositorio
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/dominio/entidades"
+	"gorm.io/gorm"
+)
+
+type WebhookRepositorio struct {
+	db *gorm.DB
+}
+
+func NovoWebhookRepositorio(db *gorm.DB) *WebhookRepositorio {
+	return &WebhookRepositorio{db: db}
+}
+
+// ComContexto retorna um repositório cujas consultas usam ctx (cancelamento e rastreamento)
+func (r *WebhookRepositorio) ComContexto(ctx context.Context) *WebhookRepositorio {
+	return &WebhookRepositorio{db: r.db.WithContext(ctx)}
+}
+
+// CriarEndpoint cria um novo endpoint de webhook
+func (r *WebhookRepositorio) CriarEndpoint(endpoint *entidades.EndpointWebhook) error {
+	return r.db.Create(endpoint).Error
+}
+
+// AtualizarEndpoint atualiza um endpoint existente
+func (r *WebhookRepositorio) AtualizarEndpoint(endpoint *entidades.EndpointWebhook) error {
+	return r.db.Save(endpoint).Error
+}
+
+// DeletarEndpoint remove um endpoint (com validação de usuário)
+func (r *WebhookRepositorio) DeletarEndpoint(id uuid.UUID, usuarioID uuid.UUID) error {
+	return r.db.Where("id = ? AND usuario_id = ?", id, usuarioID).Delete(&entidades.EndpointWebhook{}).Error
+}
+
+// BuscarEndpointPorID encontra um endpoint pelo ID (com validação de usuário)
+func (r *WebhookRepositorio) BuscarEndpointPorID(id uuid.UUID, usuarioID uuid.UUID) (*entidades.EndpointWebhook, error) {
+	var endpoint entidades.EndpointWebhook
+	err := r.db.Where("id = ? AND usuario_id = ?", id, usuarioID).First(&endpoint).Error
+	if err != nil {
+		return nil, err
+	}
+	return &endpoint, nil
+}
+
+// ListarEndpoints lista os endpoints de uma conta
+func (r *WebhookRepositorio) ListarEndpoints(usuarioID uuid.UUID) ([]entidades.EndpointWebhook, error) {
+	var endpoints []entidades.EndpointWebhook
+	err := r.db.Where("usuario_id = ?", usuarioID).
+		Order("data_criacao DESC").
+		Find(&endpoints).Error
+	return endpoints, err
+}
+
+// ListarEndpointsAtivos lista os endpoints ativos de uma conta
+func (r *WebhookRepositorio) ListarEndpointsAtivos(usuarioID uuid.UUID) ([]entidades.EndpointWebhook, error) {
+	var endpoints []entidades.EndpointWebhook
+	err := r.db.Where("usuario_id = ? AND ativo = ?", usuarioID, true).Find(&endpoints).Error
+	return endpoints, err
+}
+
+// ListarTodosEndpoints lista os endpoints de todas as contas
+func (r *WebhookRepositorio) ListarTodosEndpoints() ([]entidades.EndpointWebhook, error) {
+	var endpoints []entidades.EndpointWebhook
+	err := r.db.Order("data_criacao").Find(&endpoints).Error
+	return endpoints, err
+}
+
+// TrocarSegredoEndpoint grava o novo segredo cifrado apenas se o atual ainda for o informado
+func (r *WebhookRepositorio) TrocarSegredoEndpoint(id uuid.UUID, atual, novo string) error {
+	return r.db.Model(&entidades.EndpointWebhook{}).
+		Where("id = ? AND segredo_encriptado = ?", id, atual).
+		Update("segredo_encriptado", novo).Error
+}
+
+// CriarEntrega registra uma nova entrega
+func (r *WebhookRepositorio) CriarEntrega(entrega *entidades.EntregaWebhook) error {
+	return r.db.Create(entrega).Error
+}
+
+// AtualizarEntrega atualiza uma entrega existente
+func (r *WebhookRepositorio) AtualizarEntrega(entrega *entidades.EntregaWebhook) error {
+	return r.db.Save(entrega).Error
+}
+
+// BuscarEntregaPorID encontra uma entrega pelo ID, com o endpoint carregado
+func (r *WebhookRepositorio) BuscarEntregaPorID(id uuid.UUID) (*entidades.EntregaWebhook, error) {
+	var entrega entidades.EntregaWebhook
+	err := r.db.Preload("Endpoint").Where("id = ?", id).First(&entrega).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entrega, nil
+}
+
+// BuscarEntregaDoUsuario encontra uma entrega pelo ID (com validação de usuário)
+func (r *WebhookRepositorio) BuscarEntregaDoUsuario(id uuid.UUID, usuarioID uuid.UUID) (*entidades.EntregaWebhook, error) {
+	var entrega entidades.EntregaWebhook
+	err := r.db.Where("id = ? AND usuario_id = ?", id, usuarioID).First(&entrega).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entrega, nil
+}
+
+// ReservarTentativa registra o início de uma tentativa. Só uma execução reserva cada
+// tentativa: se a entrega estiver repetida na fila, as demais recebem false.
+func (r *WebhookRepositorio) ReservarTentativa(id uuid.UUID, tentativas int) (bool, error) {
+	resultado := r.db.Model(&entidades.EntregaWebhook{}).
+		Where("id = ? AND status = ? AND tentativas = ?", id, entidades.StatusEntregaPendente, tentativas).
+		Updates(map[string]interface{}{"tentativas": tentativas + 1, "data_atualizacao": time.Now()})
+	return resultado.RowsAffected == 1, resultado.Error
+}
+
+// ReservarPendentesAtrasadas marca para reenfileirar até limite entregas pendentes paradas
+// desde antes de atrasadasDesde (perdidas da fila ou com o retry vencido) e retorna os IDs.
+// A data de atualização é renovada para que a mesma entrega não volte na próxima varredura.
+func (r *WebhookRepositorio) ReservarPendentesAtrasadas(atrasadasDesde time.Time, limite int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	agora := time.Now()
+	err := r.db.Raw(`
+		UPDATE webhook_entregas SET proxima_tentativa = ?, data_atualizacao = ?
+		WHERE id IN (
+			SELECT id FROM webhook_entregas
+			WHERE status = ? AND data_atualizacao < ?
+				AND (proxima_tentativa IS NULL OR proxima_tentativa < ?)
+			ORDER BY data_atualizacao
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id`,
+		agora, agora, entidades.StatusEntregaPendente, atrasadasDesde, atrasadasDesde, limite).
+		Scan(&ids).Error
+	return ids, err
+}
+
+// ListarEntregas lista as entregas de um endpoint com paginação
+func (r *WebhookRepositorio) ListarEntregas(endpointID uuid.UUID, usuarioID uuid.UUID, pagina, tamanhoPagina int) ([]entidades.EntregaWebhook, int64, error) {
+	var entregas []entidades.EntregaWebhook
+	var total int64
+
+	query := r.db.Model(&entidades.EntregaWebhook{}).
+		Where("endpoint_id = ? AND usuario_id = ?", endpointID, usuarioID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (pagina - 1) * tamanhoPagina
+	err := query.Order("data_criacao DESC").
+		Offset(offset).
+		Limit(tamanhoPagina).
+		Find(&entregas).Error
+
+	return entregas, total, err
+}
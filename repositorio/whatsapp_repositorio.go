@@ -1,6 +1,9 @@
 package repositorio
 
 import (
+	"context"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/ifinu/ifinu-api-go/dominio/entidades"
 	"gorm.io/gorm"
@@ -14,20 +17,71 @@ func NovoWhatsAppRepositorio(db *gorm.DB) *WhatsAppRepositorio {
 	return &WhatsAppRepositorio{db: db}
 }
 
-// BuscarPorUsuario encontra a conexão WhatsApp de um usuário
+// ComContexto retorna um repositório cujas consultas usam ctx (cancelamento e rastreamento)
+func (r *WhatsAppRepositorio) ComContexto(ctx context.Context) *WhatsAppRepositorio {
+	return &WhatsAppRepositorio{db: r.db.WithContext(ctx)}
+}
+
+// BuscarPorUsuario encontra a conexão WhatsApp padrão de um usuário
+// (ou a mais antiga, se nenhuma estiver marcada como padrão)
 func (r *WhatsAppRepositorio) BuscarPorUsuario(usuarioID uuid.UUID) (*entidades.WhatsAppConexao, error) {
 	var conexao entidades.WhatsAppConexao
-	err := r.db.Where("usuario_id = ?", usuarioID).First(&conexao).Error
+	err := r.db.Where("usuario_id = ?", usuarioID).
+		Order("padrao DESC, id ASC").
+		First(&conexao).Error
+	if err != nil {
+		return nil, err
+	}
+	return &conexao, nil
+}
+
+// BuscarPorID encontra uma conexão do usuário pelo ID
+func (r *WhatsAppRepositorio) BuscarPorID(id int64, usuarioID uuid.UUID) (*entidades.WhatsAppConexao, error) {
+	var conexao entidades.WhatsAppConexao
+	err := r.db.Where("id = ? AND usuario_id = ?", id, usuarioID).First(&conexao).Error
 	if err != nil {
 		return nil, err
 	}
 	return &conexao, nil
 }
 
+// BuscarPorNome encontra uma conexão do usuário pelo nome
+func (r *WhatsAppRepositorio) BuscarPorNome(usuarioID uuid.UUID, nome string) (*entidades.WhatsAppConexao, error) {
+	var conexao entidades.WhatsAppConexao
+	err := r.db.Where("usuario_id = ? AND LOWER(nome) = LOWER(?)", usuarioID, nome).First(&conexao).Error
+	if err != nil {
+		return nil, err
+	}
+	return &conexao, nil
+}
+
+// ListarPorUsuario retorna todas as conexões do usuário, a padrão primeiro
+func (r *WhatsAppRepositorio) ListarPorUsuario(usuarioID uuid.UUID) ([]entidades.WhatsAppConexao, error) {
+	var conexoes []entidades.WhatsAppConexao
+	err := r.db.Where("usuario_id = ?", usuarioID).
+		Order("padrao DESC, id ASC").
+		Find(&conexoes).Error
+	return conexoes, err
+}
+
+// DefinirPadrao marca a conexão como padrão e desmarca as demais do usuário
+func (r *WhatsAppRepositorio) DefinirPadrao(id int64, usuarioID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entidades.WhatsAppConexao{}).
+			Where("usuario_id = ? AND id <> ?", usuarioID, id).
+			Update("padrao", false).Error; err != nil {
+			return err
+		}
+		return tx.Model(&entidades.WhatsAppConexao{}).
+			Where("usuario_id = ? AND id = ?", usuarioID, id).
+			Update("padrao", true).Error
+	})
+}
+
 // BuscarPorNomeInstancia encontra uma conexão pelo nome da instância
 func (r *WhatsAppRepositorio) BuscarPorNomeInstancia(nomeInstancia string) (*entidades.WhatsAppConexao, error) {
 	var conexao entidades.WhatsAppConexao
-	err := r.db.Where("nome_instancia = ?", nomeInstancia).First(&conexao).Error
+	err := r.db.Where("instance_name = ?", nomeInstancia).First(&conexao).Error
 	if err != nil {
 		return nil, err
 	}
@@ -39,25 +93,75 @@ func (r *WhatsAppRepositorio) Criar(conexao *entidades.WhatsAppConexao) error {
 	return r.db.Create(conexao).Error
 }
 
-// Atualizar atualiza uma conexão existente
-func (r *WhatsAppRepositorio) Atualizar(conexao *entidades.WhatsAppConexao) error {
-	return r.db.Save(conexao).Error
+// AtualizarCampos grava só as colunas informadas da conexão. Não recria a linha se ela
+// foi removida e não sobrescreve o que outro processo alterou nas demais colunas.
+func (r *WhatsAppRepositorio) AtualizarCampos(conexao *entidades.WhatsAppConexao, colunas ...string) error {
+	return r.db.Model(conexao).Select(colunas).Updates(conexao).Error
+}
+
+// RegistrarEnvio incrementa no banco os contadores de envio da conexão, sem perder
+// incrementos de outros workers enviando pela mesma conexão
+func (r *WhatsAppRepositorio) RegistrarEnvio(id int64, sucesso bool, data time.Time) error {
+	colunaResultado := "mensagens_falha"
+	if sucesso {
+		colunaResultado = "mensagens_sucesso"
+	}
+	return r.db.Model(&entidades.WhatsAppConexao{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"mensagens_enviadas":    gorm.Expr("mensagens_enviadas + 1"),
+			colunaResultado:         gorm.Expr(colunaResultado + " + 1"),
+			"data_ultima_atividade": data,
+		}).Error
 }
 
-// Deletar remove uma conexão WhatsApp
-func (r *WhatsAppRepositorio) Deletar(usuarioID uuid.UUID) error {
-	return r.db.Where("usuario_id = ?", usuarioID).Delete(&entidades.WhatsAppConexao{}).Error
+// RegistrarAusenciaInstancia soma uma verificação sem a instância na Evolution API e
+// retorna quantas verificações seguidas ela não foi encontrada
+func (r *WhatsAppRepositorio) RegistrarAusenciaInstancia(id int64) (int, error) {
+	var verificacoes int
+	err := r.db.Raw(`
+		UPDATE whatsapp_conexoes SET verificacoes_sem_instancia = verificacoes_sem_instancia + 1
+		WHERE id = ?
+		RETURNING verificacoes_sem_instancia`, id).Scan(&verificacoes).Error
+	return verificacoes, err
 }
 
-// BuscarConexoesAtivas retorna todas as conexões ativas
+// ZerarAusenciaInstancia zera as verificações sem instância quando ela volta a aparecer
+func (r *WhatsAppRepositorio) ZerarAusenciaInstancia(id int64) error {
+	return r.db.Model(&entidades.WhatsAppConexao{}).
+		Where("id = ? AND verificacoes_sem_instancia > 0", id).
+		UpdateColumn("verificacoes_sem_instancia", 0).Error
+}
+
+// Deletar remove uma conexão WhatsApp e desvincula os clientes roteados para ela
+func (r *WhatsAppRepositorio) Deletar(id int64, usuarioID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entidades.Cliente{}).
+			Where("usuario_id = ? AND whatsapp_conexao_id = ?", usuarioID, id).
+			Update("whatsapp_conexao_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ? AND usuario_id = ?", id, usuarioID).
+			Delete(&entidades.WhatsAppConexao{}).Error
+	})
+}
+
+// BuscarConexoesAtivas retorna as conexões conectadas e as que caíram aguardando reconexão
 func (r *WhatsAppRepositorio) BuscarConexoesAtivas() ([]entidades.WhatsAppConexao, error) {
 	var conexoes []entidades.WhatsAppConexao
 	err := r.db.Preload("Usuario").
-		Where("conectado = ?", true).
+		Where("status IN ?", []entidades.StatusConexao{entidades.StatusConexaoConectado, entidades.StatusConexaoErro}).
 		Find(&conexoes).Error
 	return conexoes, err
 }
 
+// ListarTodas retorna as conexões de todas as contas (limpeza de órfãs)
+func (r *WhatsAppRepositorio) ListarTodas() ([]entidades.WhatsAppConexao, error) {
+	var conexoes []entidades.WhatsAppConexao
+	err := r.db.Order("id").Find(&conexoes).Error
+	return conexoes, err
+}
+
 // ExistePorUsuario verifica se já existe uma conexão para o usuário
 func (r *WhatsAppRepositorio) ExistePorUsuario(usuarioID uuid.UUID) (bool, error) {
 	var count int64
@@ -1,17 +1,25 @@
 package servico
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/ifinu/ifinu-api-go/dominio/entidades"
 	"github.com/ifinu/ifinu-api-go/dominio/enums"
+	"github.com/ifinu/ifinu-api-go/dto"
 	"github.com/ifinu/ifinu-api-go/integracao"
+	"github.com/ifinu/ifinu-api-go/logs"
 	"github.com/ifinu/ifinu-api-go/repositorio"
 	"github.com/ifinu/ifinu-api-go/util"
 	"github.com/robfig/cron/v3"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
 )
 
 type AgendadorServico struct {
@@ -19,213 +27,330 @@ type AgendadorServico struct {
 	whatsappRepo     *repositorio.WhatsAppRepositorio
 	usuarioRepo      *repositorio.UsuarioRepositorio
 	assinaturaRepo   *repositorio.AssinaturaRepositorio
+	respostaRepo     *repositorio.RespostaAutomaticaRepositorio
 	evolutionAPI     *integracao.EvolutionAPICliente
 	resendAPI        *integracao.ResendCliente
 	cron             *cron.Cron
 	horarioComercial *util.HorarioComercial
 	filaMensagem     *FilaMensagemServico
+	whatsappServico  *WhatsAppServico
+	webhookServico   *WebhookServico
+	auditoriaServico *AuditoriaServico
+	execucaoJob      *ExecucaoJobServico
+	logger           *slog.Logger
+
+	// Batimento do cron (Unix), renovado a cada IntervaloBatimentoCron enquanto o
+	// agendador dispara; parado fica verdadeiro em Parar
+	batimentoCron atomic.Int64
+	parado        atomic.Bool
+
+	// Contexto dos jobs, cancelado em Parar quando o prazo de encerramento acaba
+	ctxJobs      context.Context
+	cancelarJobs context.CancelFunc
 }
 
+// Nomes dos jobs agendados (chave da trava e coluna job de execucoes_job)
+const (
+	JobNotificacoesLembrete   = "notificacoes-lembrete"
+	JobNotificacoesVencimento = "notificacoes-vencimento"
+	JobMonitorWhatsApp        = "monitor-whatsapp"
+	JobCobrancasVencidas      = "cobrancas-vencidas"
+	JobLimparExecucoes        = "limpar-execucoes-job"
+	JobReenfileirarWebhooks   = "reenfileirar-webhooks"
+)
+
+// O cron renova o batimento a cada IntervaloBatimentoCron; sem renovação por
+// LimiteAtrasoCron a liveness falha
+const (
+	IntervaloBatimentoCron = 1 * time.Minute
+	LimiteAtrasoCron       = 3 * time.Minute
+)
+
+// Após cancelar os jobs no encerramento, espera esse tempo para que registrem o resultado
+const EsperaCancelamentoJobs = 5 * time.Second
+
 func NovoAgendadorServico(
 	cobrancaRepo *repositorio.CobrancaRepositorio,
 	whatsappRepo *repositorio.WhatsAppRepositorio,
 	usuarioRepo *repositorio.UsuarioRepositorio,
 	assinaturaRepo *repositorio.AssinaturaRepositorio,
+	respostaRepo *repositorio.RespostaAutomaticaRepositorio,
 	evolutionAPI *integracao.EvolutionAPICliente,
 	resendAPI *integracao.ResendCliente,
 	whatsappServico *WhatsAppServico,
+	webhookServico *WebhookServico,
+	auditoriaServico *AuditoriaServico,
+	execucaoJob *ExecucaoJobServico,
 	redisAddr string,
+	logger *slog.Logger,
 ) *AgendadorServico {
-	// Inicializar fila de mensagens
-	filaMensagem := NovoFilaMensagemServico(redisAddr, whatsappServico, resendAPI)
-
-	// Iniciar worker pool (10 workers processando em paralelo)
-	if filaMensagem != nil {
-		filaMensagem.IniciarWorkerPool(10)
-	}
+	// Inicializar fila de mensagens (os workers sobem em Iniciar)
+	filaMensagem := NovoFilaMensagemServico(redisAddr, whatsappServico, resendAPI, cobrancaRepo, logger)
+	ctxJobs, cancelarJobs := context.WithCancel(context.Background())
 
 	return &AgendadorServico{
 		cobrancaRepo:     cobrancaRepo,
 		whatsappRepo:     whatsappRepo,
 		usuarioRepo:      usuarioRepo,
 		assinaturaRepo:   assinaturaRepo,
+		respostaRepo:     respostaRepo,
 		evolutionAPI:     evolutionAPI,
 		resendAPI:        resendAPI,
 		cron:             cron.New(),
 		horarioComercial: util.HorarioComercialPadrao(),
 		filaMensagem:     filaMensagem,
+		whatsappServico:  whatsappServico,
+		webhookServico:   webhookServico,
+		auditoriaServico: auditoriaServico,
+		execucaoJob:      execucaoJob,
+		logger:           logger,
+		ctxJobs:          ctxJobs,
+		cancelarJobs:     cancelarJobs,
 	}
 }
 
 // Iniciar inicia o agendador de tarefas
 func (s *AgendadorServico) Iniciar() {
-	log.Println("📅 Iniciando agendador de notificações...")
-	log.Printf("⏰ Horário comercial configurado: %dh às %dh (dias úteis)",
-		s.horarioComercial.HoraInicio, s.horarioComercial.HoraFim)
+	s.logger.Info("📅 Iniciando agendador de notificações",
+		"horario_comercial_inicio", s.horarioComercial.HoraInicio, "horario_comercial_fim", s.horarioComercial.HoraFim)
+
+	// Iniciar worker pool (10 workers processando em paralelo)
+	if s.filaMensagem != nil {
+		s.filaMensagem.IniciarWorkerPool(10)
+	}
+
+	// Cada disparo roda em uma única réplica (ver agendarJob)
 
 	// Enviar notificações de lembrete (3 dias antes) - executa todos os dias às 9h
-	s.cron.AddFunc("0 9 * * *", func() {
-		log.Println("⏰ Executando job: Notificações de lembrete")
-		s.EnviarNotificacoesLembrete()
-	})
+	s.agendarJob("0 9 * * *", JobNotificacoesLembrete, s.EnviarNotificacoesLembrete)
 
 	// Enviar notificações de vencimento (dia do vencimento) - executa todos os dias às 9h
-	s.cron.AddFunc("0 9 * * *", func() {
-		log.Println("⏰ Executando job: Notificações de vencimento")
-		s.EnviarNotificacoesVencimento()
-	})
+	s.agendarJob("0 9 * * *", JobNotificacoesVencimento, s.EnviarNotificacoesVencimento)
 
 	// REMOVIDO: Job de processar pendentes causava duplicação de mensagens
 	// As notificações já são enviadas pelos jobs específicos às 9h
-	// Se precisar reprocessar, implementar com lock distribuído no Redis
+	// Se precisar reprocessar, registrar com agendarJob (trava distribuída)
+
+	// Monitorar números WhatsApp (status, reconexão e órfãs) - executa a cada 5 minutos
+	s.agendarJob("*/5 * * * *", JobMonitorWhatsApp, s.MonitorarConexoesWhatsApp)
 
 	// Verificar cobranças vencidas - executa todos os dias às 23h
-	s.cron.AddFunc("0 23 * * *", func() {
-		log.Println("⏰ Executando job: Atualizar cobranças vencidas")
-		s.AtualizarCobrancasVencidas()
-	})
+	s.agendarJob("0 23 * * *", JobCobrancasVencidas, s.AtualizarCobrancasVencidas)
+
+	// Apagar o histórico de execuções antigo - executa todos os dias às 3h
+	s.agendarJob("0 3 * * *", JobLimparExecucoes, s.execucaoJob.LimparExecucoesAntigas)
+
+	// Reenfileirar entregas de webhook pendentes que saíram da fila - executa a cada 5 minutos
+	if s.webhookServico != nil {
+		s.agendarJob("*/5 * * * *", JobReenfileirarWebhooks, s.webhookServico.ReenfileirarPendentes)
+	}
+
+	// Batimento para a liveness: só é renovado se o cron continuar disparando
+	s.batimentoCron.Store(time.Now().Unix())
+	if _, err := s.cron.AddFunc(fmt.Sprintf("@every %s", IntervaloBatimentoCron), func() {
+		s.batimentoCron.Store(time.Now().Unix())
+	}); err != nil {
+		s.logger.Error("❌ Erro ao agendar batimento do cron", logs.Erro(err))
+	}
 
 	s.cron.Start()
-	log.Println("✅ Agendador iniciado com sucesso")
+	s.logger.Info("✅ Agendador iniciado com sucesso")
 }
 
-// Parar para o agendador
-func (s *AgendadorServico) Parar() {
-	log.Println("🛑 Parando agendador...")
-	s.cron.Stop()
+// FilaMensagem retorna a fila de notificações do agendador (nil sem Redis)
+func (s *AgendadorServico) FilaMensagem() *FilaMensagemServico {
+	return s.filaMensagem
+}
+
+// agendarJob registra o job no cron. O disparo é identificado pelo minuto agendado: todas
+// as réplicas disparam no mesmo minuto, mas só uma registra e executa.
+func (s *AgendadorServico) agendarJob(spec, nome string, job FuncaoJob) {
+	if _, err := s.cron.AddFunc(spec, func() {
+		s.execucaoJob.Executar(s.ctxJobs, nome, time.Now().Truncate(time.Minute), job)
+	}); err != nil {
+		s.logger.Error("❌ Erro ao agendar job", "job", nome, logs.Erro(err))
+	}
 }
 
-// EnviarNotificacoesLembrete envia notificações de lembrete (3 dias antes do vencimento)
-func (s *AgendadorServico) EnviarNotificacoesLembrete() {
+// verificarCron indica se o cron continua disparando
+func (s *AgendadorServico) verificarCron() error {
+	if s.parado.Load() {
+		return nil // Encerrando
+	}
+	batimento := s.batimentoCron.Load()
+	if batimento == 0 {
+		return errors.New("agendador não iniciado")
+	}
+	if atraso := time.Since(time.Unix(batimento, 0)); atraso > LimiteAtrasoCron {
+		return fmt.Errorf("cron sem disparar há %s", atraso.Truncate(time.Second))
+	}
+	return nil
+}
+
+// Parar para o cron, aguarda os jobs em execução e encerra os workers da fila de
+// mensagens. ctx limita quanto tempo esperar; ao expirar, os jobs são cancelados e as
+// mensagens em andamento voltam para a fila.
+func (s *AgendadorServico) Parar(ctx context.Context) error {
+	s.logger.Info("🛑 Parando agendador")
+	s.parado.Store(true)
+	defer s.cancelarJobs()
+
+	jobsConcluidos := s.cron.Stop().Done()
+	select {
+	case <-jobsConcluidos:
+	case <-ctx.Done():
+		s.logger.Warn("⚠️  Jobs do agendador ainda em execução no encerramento. Cancelando")
+		s.cancelarJobs()
+		select {
+		case <-jobsConcluidos:
+		case <-time.After(EsperaCancelamentoJobs):
+			s.logger.Warn("⚠️  Jobs do agendador não terminaram após o cancelamento")
+		}
+	}
+
+	if s.filaMensagem != nil {
+		return s.filaMensagem.Parar(ctx)
+	}
+	return nil
+}
+
+// EnviarNotificacoesLembrete envia notificações de lembrete (3 dias antes do vencimento).
+// Retorna quantas cobranças foram encontradas e quantas enfileiradas.
+func (s *AgendadorServico) EnviarNotificacoesLembrete(ctx context.Context) (map[string]int, error) {
 	agora := time.Now()
 
 	// Verificar se está dentro do horário comercial
 	if !s.horarioComercial.EstaDentroHorarioComercial(agora) {
 		proximoHorario := s.horarioComercial.FormatarProximoHorario(agora)
-		log.Printf("⏸️  Fora do horário comercial. Próxima tentativa: %s", proximoHorario)
-		return
+		s.logger.InfoContext(ctx, "⏸️  Fora do horário comercial", "proxima_tentativa", proximoHorario)
+		return map[string]int{"fora_do_horario": 1}, nil
 	}
 
-	cobrancas, err := s.cobrancaRepo.BuscarCobrancasParaLembrete()
+	cobrancas, err := s.cobrancaRepo.ComContexto(ctx).BuscarCobrancasParaLembrete()
 	if err != nil {
-		log.Printf("❌ Erro ao buscar cobranças para lembrete: %v", err)
-		return
+		s.logger.ErrorContext(ctx, "❌ Erro ao buscar cobranças para lembrete", logs.Erro(err))
+		return nil, err
 	}
 
 	if len(cobrancas) == 0 {
-		log.Println("📭 Nenhuma cobrança para enviar lembrete")
-		return
+		s.logger.InfoContext(ctx, "📭 Nenhuma cobrança para enviar lembrete")
+		return map[string]int{"encontradas": 0}, nil
 	}
 
-	log.Printf("📬 Enfileirando %d notificações de lembrete...", len(cobrancas))
+	s.logger.InfoContext(ctx, "📬 Enfileirando notificações de lembrete", "cobrancas", len(cobrancas))
 
 	// Enfileirar todas as mensagens para processamento assíncrono
 	enfileiradas := 0
 	for _, cobranca := range cobrancas {
 		// Se fila não disponível, enviar direto (fallback)
 		if s.filaMensagem == nil {
-			s.enviarNotificacaoLembrete(&cobranca)
+			s.enviarNotificacaoLembrete(ctx, &cobranca)
 			continue
 		}
 
 		// Enfileirar mensagem
 		msg := &MensagemFila{
-			ID:              fmt.Sprintf("lembrete_%d_%d", cobranca.ID, time.Now().Unix()),
-			TipoNotificacao: "lembrete",
+			ID:              fmt.Sprintf("lembrete_%s_%d", cobranca.ID, time.Now().Unix()),
+			TipoNotificacao: enums.TipoNotificacaoLembrete,
 			Cobranca:        &cobranca,
+			Mensagem:        s.mensagemCobranca(ctx, &cobranca, enums.TipoNotificacaoLembrete),
 			Tentativas:      0,
 		}
 
-		if err := s.filaMensagem.EnfileirarMensagem(msg); err != nil {
-			log.Printf("❌ Erro ao enfileirar: %v. Enviando direto...", err)
-			s.enviarNotificacaoLembrete(&cobranca)
+		if err := s.filaMensagem.EnfileirarMensagem(ctx, msg); err != nil {
+			s.logger.ErrorContext(ctx, "❌ Erro ao enfileirar. Enviando direto", "cobranca_id", cobranca.ID, logs.Erro(err))
+			s.enviarNotificacaoLembrete(ctx, &cobranca)
 		} else {
 			enfileiradas++
 			// Marcar como processada APENAS após enfileirar com sucesso
 			// O envio real será feito pelos workers da fila
 			cobranca.NotificacaoLembreteEnviada = true
-			s.cobrancaRepo.Atualizar(&cobranca)
+			s.cobrancaRepo.ComContexto(ctx).Atualizar(&cobranca)
 		}
 	}
 
 	if enfileiradas > 0 {
-		log.Printf("✅ %d notificações de lembrete enfileiradas para processamento", enfileiradas)
+		s.logger.InfoContext(ctx, "✅ Notificações de lembrete enfileiradas para processamento", "enfileiradas", enfileiradas)
 	}
+	return map[string]int{"encontradas": len(cobrancas), "enfileiradas": enfileiradas}, nil
 }
 
-// EnviarNotificacoesVencimento envia notificações de vencimento (dia do vencimento)
-func (s *AgendadorServico) EnviarNotificacoesVencimento() {
+// EnviarNotificacoesVencimento envia notificações de vencimento (dia do vencimento).
+// Retorna quantas cobranças foram encontradas e quantas enfileiradas.
+func (s *AgendadorServico) EnviarNotificacoesVencimento(ctx context.Context) (map[string]int, error) {
 	agora := time.Now()
 
 	// Verificar se está dentro do horário comercial
 	if !s.horarioComercial.EstaDentroHorarioComercial(agora) {
 		proximoHorario := s.horarioComercial.FormatarProximoHorario(agora)
-		log.Printf("⏸️  Fora do horário comercial. Próxima tentativa: %s", proximoHorario)
-		return
+		s.logger.InfoContext(ctx, "⏸️  Fora do horário comercial", "proxima_tentativa", proximoHorario)
+		return map[string]int{"fora_do_horario": 1}, nil
 	}
 
-	cobrancas, err := s.cobrancaRepo.BuscarCobrancasVencendoHoje()
+	cobrancas, err := s.cobrancaRepo.ComContexto(ctx).BuscarCobrancasVencendoHoje()
 	if err != nil {
-		log.Printf("❌ Erro ao buscar cobranças vencendo hoje: %v", err)
-		return
+		s.logger.ErrorContext(ctx, "❌ Erro ao buscar cobranças vencendo hoje", logs.Erro(err))
+		return nil, err
 	}
 
 	if len(cobrancas) == 0 {
-		log.Println("📭 Nenhuma cobrança vencendo hoje")
-		return
+		s.logger.InfoContext(ctx, "📭 Nenhuma cobrança vencendo hoje")
+		return map[string]int{"encontradas": 0}, nil
 	}
 
-	log.Printf("📬 Enfileirando %d notificações de vencimento...", len(cobrancas))
+	s.logger.InfoContext(ctx, "📬 Enfileirando notificações de vencimento", "cobrancas", len(cobrancas))
 
 	// Enfileirar todas as mensagens para processamento assíncrono
 	enfileiradas := 0
 	for _, cobranca := range cobrancas {
 		// Se fila não disponível, enviar direto (fallback)
 		if s.filaMensagem == nil {
-			s.enviarNotificacaoVencimento(&cobranca)
+			s.enviarNotificacaoVencimento(ctx, &cobranca)
 			continue
 		}
 
 		// Enfileirar mensagem
 		msg := &MensagemFila{
-			ID:              fmt.Sprintf("vencimento_%d_%d", cobranca.ID, time.Now().Unix()),
-			TipoNotificacao: "vencimento",
+			ID:              fmt.Sprintf("vencimento_%s_%d", cobranca.ID, time.Now().Unix()),
+			TipoNotificacao: enums.TipoNotificacaoVencimento,
 			Cobranca:        &cobranca,
+			Mensagem:        s.mensagemCobranca(ctx, &cobranca, enums.TipoNotificacaoVencimento),
 			Tentativas:      0,
 		}
 
-		if err := s.filaMensagem.EnfileirarMensagem(msg); err != nil {
-			log.Printf("❌ Erro ao enfileirar: %v. Enviando direto...", err)
-			s.enviarNotificacaoVencimento(&cobranca)
+		if err := s.filaMensagem.EnfileirarMensagem(ctx, msg); err != nil {
+			s.logger.ErrorContext(ctx, "❌ Erro ao enfileirar. Enviando direto", "cobranca_id", cobranca.ID, logs.Erro(err))
+			s.enviarNotificacaoVencimento(ctx, &cobranca)
 		} else {
 			enfileiradas++
 			// Marcar como processada APENAS após enfileirar com sucesso
 			// O envio real será feito pelos workers da fila
 			cobranca.NotificacaoVencimentoEnviada = true
-			s.cobrancaRepo.Atualizar(&cobranca)
+			s.cobrancaRepo.ComContexto(ctx).Atualizar(&cobranca)
 		}
 	}
 
 	if enfileiradas > 0 {
-		log.Printf("✅ %d notificações de vencimento enfileiradas para processamento", enfileiradas)
+		s.logger.InfoContext(ctx, "✅ Notificações de vencimento enfileiradas para processamento", "enfileiradas", enfileiradas)
 	}
+	return map[string]int{"encontradas": len(cobrancas), "enfileiradas": enfileiradas}, nil
 }
 
 // ProcessarNotificacoesPendentes processa notificações que ficaram pendentes fora do horário comercial
-func (s *AgendadorServico) ProcessarNotificacoesPendentes() {
+func (s *AgendadorServico) ProcessarNotificacoesPendentes(ctx context.Context) {
 	// Processar lembretes pendentes
-	s.EnviarNotificacoesLembrete()
+	s.EnviarNotificacoesLembrete(ctx)
 
 	// Processar vencimentos pendentes
-	s.EnviarNotificacoesVencimento()
+	s.EnviarNotificacoesVencimento(ctx)
 }
 
 // usuarioTemAssinaturaAtiva verifica se usuário tem assinatura ativa ou trial válido
-func (s *AgendadorServico) usuarioTemAssinaturaAtiva(usuarioID uuid.UUID) bool {
+func (s *AgendadorServico) usuarioTemAssinaturaAtiva(ctx context.Context, usuarioID uuid.UUID) bool {
 	// Buscar usuário
-	usuario, err := s.usuarioRepo.BuscarPorID(usuarioID)
+	usuario, err := s.usuarioRepo.ComContexto(ctx).BuscarPorID(usuarioID)
 	if err != nil {
-		log.Printf("⚠️  Erro ao buscar usuário %s: %v", usuarioID, err)
+		s.logger.WarnContext(ctx, "⚠️  Erro ao buscar usuário", "usuario", usuarioID, logs.Erro(err))
 		return false
 	}
 
@@ -240,7 +365,7 @@ func (s *AgendadorServico) usuarioTemAssinaturaAtiva(usuarioID uuid.UUID) bool {
 	}
 
 	// Verificar se tem assinatura ativa
-	assinatura, err := s.assinaturaRepo.BuscarPorUsuario(usuarioID)
+	assinatura, err := s.assinaturaRepo.ComContexto(ctx).BuscarPorUsuario(usuarioID)
 	if err == nil && assinatura.IsAtiva() {
 		return true
 	}
@@ -249,172 +374,240 @@ func (s *AgendadorServico) usuarioTemAssinaturaAtiva(usuarioID uuid.UUID) bool {
 }
 
 // enviarNotificacaoLembrete envia notificação de lembrete para uma cobrança
-func (s *AgendadorServico) enviarNotificacaoLembrete(cobranca *entidades.Cobranca) {
+func (s *AgendadorServico) enviarNotificacaoLembrete(ctx context.Context, cobranca *entidades.Cobranca) {
 	// VALIDAÇÃO CRÍTICA: Verificar isolamento de dados
 	if cobranca.UsuarioID == uuid.Nil {
-		log.Printf("⛔ SEGURANÇA: Cobrança %d sem usuário associado", cobranca.ID)
+		s.logger.ErrorContext(ctx, "⛔ SEGURANÇA: Cobrança sem usuário associado", "cobranca_id", cobranca.ID)
 		return
 	}
 
 	// VALIDAÇÃO DE NEGÓCIO: Verificar se usuário tem assinatura ativa
-	if !s.usuarioTemAssinaturaAtiva(cobranca.UsuarioID) {
-		log.Printf("🚫 Usuário %s sem assinatura ativa. Pulando envio de lembrete para cobrança %d",
-			cobranca.UsuarioID, cobranca.ID)
+	if !s.usuarioTemAssinaturaAtiva(ctx, cobranca.UsuarioID) {
+		s.logger.InfoContext(ctx, "🚫 Usuário sem assinatura ativa. Pulando envio de lembrete", "cobranca_id", cobranca.ID)
+		return
+	}
+
+	// Preferências do cliente: opt-out, canais e horário de silêncio
+	if !s.clientePodeReceber(ctx, cobranca, s.enviarNotificacaoLembrete) {
 		return
 	}
 
-	// Enviar WhatsApp
-	conexao, err := s.whatsappRepo.BuscarPorUsuario(cobranca.UsuarioID)
-	if err == nil && conexao.IsConectado() {
-		// VALIDAÇÃO CRÍTICA: Garantir que a conexão pertence ao mesmo usuário da cobrança
-		if conexao.UsuarioID != cobranca.UsuarioID {
-			log.Printf("⛔ SEGURANÇA: Tentativa de usar conexão WhatsApp de usuário diferente! Cobrança: %s, Conexão: %s",
-				cobranca.UsuarioID, conexao.UsuarioID)
-			return
+	// Enviar WhatsApp (o serviço escolhe o número da rota e faz failover)
+	if cobranca.Cliente.RecebePorWhatsApp() {
+		s.logger.InfoContext(ctx, "📤 Enviando lembrete", "cliente_id", cobranca.ClienteID, "telefone", cobranca.Cliente.Telefone)
+
+		_, err := s.whatsappServico.EnviarConteudoSincrono(
+			ctx,
+			cobranca.UsuarioID,
+			RotaCobranca(cobranca, enums.TipoNotificacaoLembrete),
+			cobranca.Cliente.Telefone,
+			*s.mensagemCobranca(ctx, cobranca, enums.TipoNotificacaoLembrete),
+		)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "❌ Erro ao enviar WhatsApp", "cliente_id", cobranca.ClienteID, logs.Erro(err))
 		}
+	}
 
-		log.Printf("📤 Enviando lembrete: Usuário=%s, Cliente=%s (ID:%d), Telefone=%s",
-			cobranca.UsuarioID, cobranca.Cliente.Nome, cobranca.ClienteID, cobranca.Cliente.Telefone)
-
-		mensagem := fmt.Sprintf(
-			"🔔 *Lembrete de Cobrança*\n\n"+
-				"Olá, %s!\n\n"+
-				"Sua cobrança vence em 3 dias:\n"+
-				"💰 Valor: R$ %.2f\n"+
-				"📝 Descrição: %s\n"+
-				"📅 Vencimento: %s\n\n"+
-				"Atenciosamente,\nEquipe IFINU",
+	// Enviar Email (com link de descadastro)
+	if cobranca.Cliente.RecebePorEmail() {
+		err := s.resendAPI.EnviarEmailLembrete(ctx,
+			cobranca.Cliente.Email,
 			cobranca.Cliente.Nome,
-			cobranca.Valor,
 			cobranca.Descricao,
+			cobranca.Valor,
 			cobranca.DataVencimento.Format("02/01/2006"),
-		)
-
-		_, err := s.evolutionAPI.EnviarMensagemTexto(
-			conexao.InstanceName,
-			cobranca.Cliente.Telefone,
-			mensagem,
+			linkDescadastro(cobranca.ClienteID),
 		)
 		if err != nil {
-			log.Printf("❌ Erro ao enviar WhatsApp para %s: %v", cobranca.Cliente.Nome, err)
+			s.logger.ErrorContext(ctx, "❌ Erro ao enviar email", "cliente_id", cobranca.ClienteID, logs.Erro(err))
 		} else {
-			log.Printf("✅ WhatsApp enviado para %s (Cliente ID: %d, Usuário: %s)",
-				cobranca.Cliente.Nome, cobranca.ClienteID, cobranca.UsuarioID)
+			s.logger.InfoContext(ctx, "✅ Email enviado", "cliente_id", cobranca.ClienteID, "email", cobranca.Cliente.Email)
 		}
 	}
 
-	// Enviar Email
-	err = s.resendAPI.EnviarEmailLembrete(
-		cobranca.Cliente.Email,
-		cobranca.Cliente.Nome,
-		cobranca.Descricao,
-		cobranca.Valor,
-		cobranca.DataVencimento.Format("02/01/2006"),
-	)
-	if err != nil {
-		log.Printf("❌ Erro ao enviar email para %s: %v", cobranca.Cliente.Nome, err)
-	} else {
-		log.Printf("✅ Email enviado para %s", cobranca.Cliente.Nome)
-	}
-
 	// Marcar notificação como enviada
 	cobranca.NotificacaoLembreteEnviada = true
-	s.cobrancaRepo.Atualizar(cobranca)
+	s.cobrancaRepo.ComContexto(ctx).Atualizar(cobranca)
 }
 
 // enviarNotificacaoVencimento envia notificação de vencimento para uma cobrança
-func (s *AgendadorServico) enviarNotificacaoVencimento(cobranca *entidades.Cobranca) {
+func (s *AgendadorServico) enviarNotificacaoVencimento(ctx context.Context, cobranca *entidades.Cobranca) {
 	// VALIDAÇÃO CRÍTICA: Verificar isolamento de dados
 	if cobranca.UsuarioID == uuid.Nil {
-		log.Printf("⛔ SEGURANÇA: Cobrança %d sem usuário associado", cobranca.ID)
+		s.logger.ErrorContext(ctx, "⛔ SEGURANÇA: Cobrança sem usuário associado", "cobranca_id", cobranca.ID)
 		return
 	}
 
 	// VALIDAÇÃO DE NEGÓCIO: Verificar se usuário tem assinatura ativa
-	if !s.usuarioTemAssinaturaAtiva(cobranca.UsuarioID) {
-		log.Printf("🚫 Usuário %s sem assinatura ativa. Pulando envio de vencimento para cobrança %d",
-			cobranca.UsuarioID, cobranca.ID)
+	if !s.usuarioTemAssinaturaAtiva(ctx, cobranca.UsuarioID) {
+		s.logger.InfoContext(ctx, "🚫 Usuário sem assinatura ativa. Pulando envio de vencimento", "cobranca_id", cobranca.ID)
 		return
 	}
 
-	// Enviar WhatsApp
-	conexao, err := s.whatsappRepo.BuscarPorUsuario(cobranca.UsuarioID)
-	if err == nil && conexao.IsConectado() {
-		// VALIDAÇÃO CRÍTICA: Garantir que a conexão pertence ao mesmo usuário da cobrança
-		if conexao.UsuarioID != cobranca.UsuarioID {
-			log.Printf("⛔ SEGURANÇA: Tentativa de usar conexão WhatsApp de usuário diferente! Cobrança: %s, Conexão: %s",
-				cobranca.UsuarioID, conexao.UsuarioID)
-			return
-		}
+	// Preferências do cliente: opt-out, canais e horário de silêncio
+	if !s.clientePodeReceber(ctx, cobranca, s.enviarNotificacaoVencimento) {
+		return
+	}
 
-		log.Printf("📤 Enviando vencimento: Usuário=%s, Cliente=%s (ID:%d), Telefone=%s",
-			cobranca.UsuarioID, cobranca.Cliente.Nome, cobranca.ClienteID, cobranca.Cliente.Telefone)
+	// Enviar WhatsApp (o serviço escolhe o número da rota e faz failover)
+	if cobranca.Cliente.RecebePorWhatsApp() {
+		s.logger.InfoContext(ctx, "📤 Enviando vencimento", "cliente_id", cobranca.ClienteID, "telefone", cobranca.Cliente.Telefone)
 
-		mensagem := fmt.Sprintf(
-			"⚠️ *Cobrança Vence Hoje*\n\n"+
-				"Olá, %s!\n\n"+
-				"Sua cobrança vence HOJE:\n"+
-				"💰 Valor: R$ %.2f\n"+
-				"📝 Descrição: %s\n\n"+
-				"Atenciosamente,\nEquipe IFINU",
-			cobranca.Cliente.Nome,
-			cobranca.Valor,
-			cobranca.Descricao,
+		_, err := s.whatsappServico.EnviarConteudoSincrono(
+			ctx,
+			cobranca.UsuarioID,
+			RotaCobranca(cobranca, enums.TipoNotificacaoVencimento),
+			cobranca.Cliente.Telefone,
+			*s.mensagemCobranca(ctx, cobranca, enums.TipoNotificacaoVencimento),
 		)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "❌ Erro ao enviar WhatsApp", "cliente_id", cobranca.ClienteID, logs.Erro(err))
+		}
+	}
 
-		_, err := s.evolutionAPI.EnviarMensagemTexto(
-			conexao.InstanceName,
-			cobranca.Cliente.Telefone,
-			mensagem,
+	// Enviar Email (com link de descadastro)
+	if cobranca.Cliente.RecebePorEmail() {
+		err := s.resendAPI.EnviarEmailVencimento(ctx,
+			cobranca.Cliente.Email,
+			cobranca.Cliente.Nome,
+			cobranca.Descricao,
+			cobranca.Valor,
+			linkDescadastro(cobranca.ClienteID),
 		)
 		if err != nil {
-			log.Printf("❌ Erro ao enviar WhatsApp para %s: %v", cobranca.Cliente.Nome, err)
+			s.logger.ErrorContext(ctx, "❌ Erro ao enviar email", "cliente_id", cobranca.ClienteID, logs.Erro(err))
 		} else {
-			log.Printf("✅ WhatsApp enviado para %s (Cliente ID: %d, Usuário: %s)",
-				cobranca.Cliente.Nome, cobranca.ClienteID, cobranca.UsuarioID)
+			s.logger.InfoContext(ctx, "✅ Email enviado", "cliente_id", cobranca.ClienteID, "email", cobranca.Cliente.Email)
 		}
 	}
 
-	// Enviar Email
-	err = s.resendAPI.EnviarEmailVencimento(
-		cobranca.Cliente.Email,
-		cobranca.Cliente.Nome,
-		cobranca.Descricao,
-		cobranca.Valor,
-	)
+	// Marcar notificação como enviada
+	cobranca.NotificacaoVencimentoEnviada = true
+	s.cobrancaRepo.ComContexto(ctx).Atualizar(cobranca)
+}
+
+// mensagemCobranca monta a notificação de WhatsApp com link de pagamento e PIX da conta
+func (s *AgendadorServico) mensagemCobranca(ctx context.Context, cobranca *entidades.Cobranca, tipo enums.TipoNotificacao) *dto.MensagemWhatsApp {
+	// Sem configuração, o lembrete segue sem o PIX
+	configPix, _ := s.respostaRepo.ComContexto(ctx).BuscarConfig(cobranca.UsuarioID)
+
+	mensagem, err := montarMensagemCobranca(cobranca, tipo, configPix)
 	if err != nil {
-		log.Printf("❌ Erro ao enviar email para %s: %v", cobranca.Cliente.Nome, err)
-	} else {
-		log.Printf("✅ Email enviado para %s", cobranca.Cliente.Nome)
+		s.logger.WarnContext(ctx, "⚠️  Erro ao montar mensagem da cobrança", "cobranca_id", cobranca.ID, logs.Erro(err))
+		return &dto.MensagemWhatsApp{}
 	}
+	return mensagem
+}
 
-	// Marcar notificação como enviada
-	cobranca.NotificacaoVencimentoEnviada = true
-	s.cobrancaRepo.Atualizar(cobranca)
+// clientePodeReceber aplica as preferências do cliente antes do envio direto (sem fila).
+// Em horário de silêncio, agenda o reenvio para o fim da janela e retorna false.
+func (s *AgendadorServico) clientePodeReceber(ctx context.Context, cobranca *entidades.Cobranca, reenviar func(context.Context, *entidades.Cobranca)) bool {
+	cliente := &cobranca.Cliente
+
+	if !cliente.RecebeLembretes() {
+		s.logger.InfoContext(ctx, "🔕 Cliente não recebe lembretes (opt-out ou canal NENHUM). Cobrança ignorada",
+			"cliente_id", cliente.ID, "cobranca_id", cobranca.ID)
+		return false
+	}
+
+	if fim, silencio := cliente.FimDoSilencio(time.Now()); silencio {
+		s.logger.InfoContext(ctx, "🌙 Cliente em horário de silêncio. Envio adiado",
+			"cliente_id", cliente.ID, "cobranca_id", cobranca.ID, "proxima_tentativa", fim)
+		time.AfterFunc(time.Until(fim), func() { reenviar(ctx, cobranca) })
+		return false
+	}
+
+	return true
+}
+
+// MonitorarConexoesWhatsApp verifica as instâncias WhatsApp, avisa por email os usuários
+// cujos números caíram e pausa a fila das contas sem nenhum número conectado
+func (s *AgendadorServico) MonitorarConexoesWhatsApp(ctx context.Context) (map[string]int, error) {
+	resultado, err := s.whatsappServico.MonitorarConexoes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	linkReconectar := strings.TrimSuffix(viper.GetString("APP_FRONTEND_URL"), "/") + "/whatsapp"
+	for i := range resultado.Quedas {
+		conexao := &resultado.Quedas[i]
+		if conexao.Usuario.Email == "" {
+			continue
+		}
+		ctx := logs.ComUsuarioID(ctx, conexao.UsuarioID)
+		if err := s.resendAPI.EnviarEmailWhatsAppDesconectado(
+			ctx,
+			conexao.Usuario.Email,
+			conexao.Usuario.NomeCompleto,
+			conexao.Nome,
+			linkReconectar,
+		); err != nil {
+			s.logger.ErrorContext(ctx, "❌ Erro ao avisar queda do WhatsApp", "email", conexao.Usuario.Email, logs.Erro(err))
+		}
+	}
+
+	if s.filaMensagem != nil {
+		if err := s.filaMensagem.DefinirContasPausadas(resultado.ContasSemConexao); err != nil {
+			s.logger.ErrorContext(ctx, "❌ Erro ao atualizar contas pausadas na fila", logs.Erro(err))
+		}
+	}
+
+	if len(resultado.Quedas) > 0 || resultado.ReconexoesTentadas > 0 || resultado.OrfasRemovidas > 0 {
+		s.logger.InfoContext(ctx, "📡 Monitor WhatsApp", "quedas", len(resultado.Quedas), "reconexoes_tentadas", resultado.ReconexoesTentadas,
+			"orfas_removidas", resultado.OrfasRemovidas, "contas_pausadas", len(resultado.ContasSemConexao))
+	}
+	return map[string]int{
+		"quedas":              len(resultado.Quedas),
+		"reconexoes_tentadas": resultado.ReconexoesTentadas,
+		"orfas_removidas":     resultado.OrfasRemovidas,
+		"contas_pausadas":     len(resultado.ContasSemConexao),
+	}, nil
 }
 
 // AtualizarCobrancasVencidas atualiza o status de cobranças vencidas
-func (s *AgendadorServico) AtualizarCobrancasVencidas() {
-	cobrancas, err := s.cobrancaRepo.BuscarCobrancasVencidas()
+func (s *AgendadorServico) AtualizarCobrancasVencidas(ctx context.Context) (map[string]int, error) {
+	atualizadas, err := s.AtualizarCobrancasVencidasEm(ctx, time.Now())
 	if err != nil {
-		log.Printf("❌ Erro ao buscar cobranças vencidas: %v", err)
-		return
+		return nil, err
+	}
+	return map[string]int{"atualizadas": atualizadas}, nil
+}
+
+// AtualizarCobrancasVencidasEm marca como vencidas as cobranças pendentes com vencimento
+// antes de data, como o job faria naquele dia. Retorna quantas foram atualizadas.
+func (s *AgendadorServico) AtualizarCobrancasVencidasEm(ctx context.Context, data time.Time) (int, error) {
+	cobrancas, err := s.cobrancaRepo.ComContexto(ctx).BuscarCobrancasVencidas(data)
+	if err != nil {
+		return 0, err
 	}
 
 	if len(cobrancas) == 0 {
-		log.Println("📭 Nenhuma cobrança vencida para atualizar")
-		return
+		s.logger.InfoContext(ctx, "📭 Nenhuma cobrança vencida para atualizar")
+		return 0, nil
 	}
 
-	log.Printf("🔄 Atualizando %d cobranças vencidas...", len(cobrancas))
+	s.logger.InfoContext(ctx, "🔄 Atualizando cobranças vencidas", "cobrancas", len(cobrancas))
 
+	atualizadas := 0
 	for _, cobranca := range cobrancas {
+		antes := cobranca
 		cobranca.Status = enums.StatusCobrancaVencido
-		err := s.cobrancaRepo.Atualizar(&cobranca)
+		err := s.auditoriaServico.Transacao(func(tx *gorm.DB) error {
+			if err := s.cobrancaRepo.ComTransacao(tx).Atualizar(&cobranca); err != nil {
+				return err
+			}
+			return s.auditoriaServico.Registrar(tx, cobranca.UsuarioID, dto.AtorSistema, enums.AcaoAuditoriaAtualizar,
+				enums.EntidadeAuditoriaCobranca, cobranca.ID.String(), &antes, &cobranca)
+		})
 		if err != nil {
-			log.Printf("❌ Erro ao atualizar cobrança %d: %v", cobranca.ID, err)
+			s.logger.ErrorContext(logs.ComUsuarioID(ctx, cobranca.UsuarioID), "❌ Erro ao atualizar cobrança", "cobranca_id", cobranca.ID, logs.Erro(err))
+			continue
 		}
+
+		atualizadas++
+		s.webhookServico.Disparar(cobranca.UsuarioID, enums.EventoCobrancaVencida, mapearCobrancaParaDTO(&cobranca))
 	}
 
-	log.Println("✅ Cobranças vencidas atualizadas")
+	s.logger.InfoContext(ctx, "✅ Cobranças vencidas atualizadas", "atualizadas", atualizadas)
+	return atualizadas, nil
 }
@@ -0,0 +1,60 @@
+package servico
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+func TestPararCancelaJobsAoFimDoPrazo(t *testing.T) {
+	ctxJobs, cancelarJobs := context.WithCancel(context.Background())
+	s := &AgendadorServico{
+		cron:         cron.New(cron.WithSeconds()),
+		logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+		ctxJobs:      ctxJobs,
+		cancelarJobs: cancelarJobs,
+	}
+
+	// Job que só termina quando o contexto dos jobs é cancelado
+	iniciado := make(chan struct{})
+	cancelado := make(chan struct{})
+	if _, err := s.cron.AddFunc("* * * * * *", func() {
+		select {
+		case <-iniciado:
+			return
+		default:
+		}
+		close(iniciado)
+		<-s.ctxJobs.Done()
+		close(cancelado)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	s.cron.Start()
+
+	select {
+	case <-iniciado:
+	case <-time.After(3 * time.Second):
+		t.Fatal("job não iniciou")
+	}
+
+	ctx, cancelar := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancelar()
+	inicio := time.Now()
+	if err := s.Parar(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-cancelado:
+	default:
+		t.Fatal("Parar retornou sem cancelar o job")
+	}
+	if duracao := time.Since(inicio); duracao > EsperaCancelamentoJobs {
+		t.Fatalf("Parar levou %s", duracao)
+	}
+}
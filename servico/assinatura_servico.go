@@ -1,24 +1,33 @@
 package servico
 
 import (
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/dominio/entidades"
+	"github.com/ifinu/ifinu-api-go/dominio/enums"
+	"github.com/ifinu/ifinu-api-go/dto"
 	"github.com/ifinu/ifinu-api-go/repositorio"
+	"gorm.io/gorm"
 )
 
 type AssinaturaServico struct {
-	assinaturaRepo *repositorio.AssinaturaRepositorio
-	usuarioRepo    *repositorio.UsuarioRepositorio
+	assinaturaRepo   *repositorio.AssinaturaRepositorio
+	usuarioRepo      *repositorio.UsuarioRepositorio
+	auditoriaServico *AuditoriaServico
 }
 
-func NovoAssinaturaServico(assinaturaRepo *repositorio.AssinaturaRepositorio, usuarioRepo *repositorio.UsuarioRepositorio) *AssinaturaServico {
+func NovoAssinaturaServico(assinaturaRepo *repositorio.AssinaturaRepositorio, usuarioRepo *repositorio.UsuarioRepositorio, auditoriaServico *AuditoriaServico) *AssinaturaServico {
 	return &AssinaturaServico{
-		assinaturaRepo: assinaturaRepo,
-		usuarioRepo:    usuarioRepo,
+		assinaturaRepo:   assinaturaRepo,
+		usuarioRepo:      usuarioRepo,
+		auditoriaServico: auditoriaServico,
 	}
 }
 
-// ObterStatus retorna o status da assinatura do usuário
-func (s *AssinaturaServico) ObterStatus(email string) (map[string]interface{}, error) {
-	usuario, err := s.usuarioRepo.BuscarPorEmail(email)
+// ObterStatus retorna o status da assinatura da conta
+func (s *AssinaturaServico) ObterStatus(usuarioID uuid.UUID) (map[string]interface{}, error) {
+	usuario, err := s.usuarioRepo.BuscarPorID(usuarioID)
 	if err != nil {
 		return nil, err
 	}
@@ -47,9 +56,9 @@ func (s *AssinaturaServico) ObterStatus(email string) (map[string]interface{}, e
 	return status, nil
 }
 
-// CancelarAssinatura cancela a assinatura do usuário
-func (s *AssinaturaServico) CancelarAssinatura(email string) error {
-	usuario, err := s.usuarioRepo.BuscarPorEmail(email)
+// CancelarAssinatura cancela a assinatura da conta
+func (s *AssinaturaServico) CancelarAssinatura(usuarioID uuid.UUID) error {
+	usuario, err := s.usuarioRepo.BuscarPorID(usuarioID)
 	if err != nil {
 		return err
 	}
@@ -66,3 +75,44 @@ func (s *AssinaturaServico) CancelarAssinatura(email string) error {
 	// Salvar alterações
 	return s.assinaturaRepo.Atualizar(assinatura)
 }
+
+// DefinirVitalicio concede ou revoga o acesso vitalício da conta (sem cobrança)
+func (s *AssinaturaServico) DefinirVitalicio(usuarioID uuid.UUID, ator dto.Ator, vitalicio bool) (*entidades.Usuario, error) {
+	return s.atualizarUsuario(usuarioID, ator, func(usuario *entidades.Usuario) {
+		usuario.Vitalicio = vitalicio
+	})
+}
+
+// EstenderTrial reativa o trial da conta e o estende em dias
+func (s *AssinaturaServico) EstenderTrial(usuarioID uuid.UUID, ator dto.Ator, dias int) (*entidades.Usuario, error) {
+	if dias < 1 {
+		return nil, errors.New("quantidade de dias deve ser maior que zero")
+	}
+
+	return s.atualizarUsuario(usuarioID, ator, func(usuario *entidades.Usuario) {
+		usuario.EstenderTrial(dias)
+	})
+}
+
+// atualizarUsuario aplica a alteração e grava a auditoria na mesma transação
+func (s *AssinaturaServico) atualizarUsuario(usuarioID uuid.UUID, ator dto.Ator, alterar func(usuario *entidades.Usuario)) (*entidades.Usuario, error) {
+	usuario, err := s.usuarioRepo.BuscarPorID(usuarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	antes := *usuario
+	alterar(usuario)
+
+	err = s.auditoriaServico.Transacao(func(tx *gorm.DB) error {
+		if err := s.usuarioRepo.ComTransacao(tx).Atualizar(usuario); err != nil {
+			return err
+		}
+		return s.auditoriaServico.Registrar(tx, usuario.ID, ator, enums.AcaoAuditoriaAtualizar,
+			enums.EntidadeAuditoriaUsuario, usuario.ID.String(), &antes, usuario)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return usuario, nil
+}
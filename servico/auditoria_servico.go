@@ -0,0 +1,143 @@
+package servico
+
+import (
+	"encoding/json"
+	"math"
+
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/dominio/entidades"
+	"github.com/ifinu/ifinu-api-go/dominio/enums"
+	"github.com/ifinu/ifinu-api-go/dto"
+	"github.com/ifinu/ifinu-api-go/repositorio"
+	"github.com/ifinu/ifinu-api-go/util"
+	"gorm.io/gorm"
+)
+
+// camposIgnoradosAuditoria não entram no diff (timestamps automáticos e relacionamentos)
+var camposIgnoradosAuditoria = []string{"dataCriacao", "dataAtualizacao", "cliente"}
+
+type AuditoriaServico struct {
+	auditoriaRepo *repositorio.AuditoriaRepositorio
+	usuarioRepo   *repositorio.UsuarioRepositorio
+}
+
+func NovoAuditoriaServico(auditoriaRepo *repositorio.AuditoriaRepositorio, usuarioRepo *repositorio.UsuarioRepositorio) *AuditoriaServico {
+	return &AuditoriaServico{
+		auditoriaRepo: auditoriaRepo,
+		usuarioRepo:   usuarioRepo,
+	}
+}
+
+// Transacao executa a alteração e seu registro de auditoria na mesma transação
+func (s *AuditoriaServico) Transacao(fn func(tx *gorm.DB) error) error {
+	return s.auditoriaRepo.Transacao(fn)
+}
+
+// Registrar grava o diff entre antes e depois dentro da transação informada.
+// Use antes = nil para criações e depois = nil para exclusões. Atualizações
+// sem campos alterados não geram registro.
+func (s *AuditoriaServico) Registrar(
+	tx *gorm.DB,
+	usuarioID uuid.UUID,
+	ator dto.Ator,
+	acao enums.AcaoAuditoria,
+	entidade string,
+	entidadeID string,
+	antes interface{},
+	depois interface{},
+) error {
+	diff, err := util.DiffCampos(antes, depois, camposIgnoradosAuditoria...)
+	if err != nil {
+		return err
+	}
+	if acao == enums.AcaoAuditoriaAtualizar && len(diff) == 0 {
+		return nil
+	}
+
+	alteracoes, err := json.Marshal(diff)
+	if err != nil {
+		return err
+	}
+
+	registro := &entidades.RegistroAuditoria{
+		UsuarioID:  usuarioID,
+		Acao:       acao,
+		Entidade:   entidade,
+		EntidadeID: entidadeID,
+		Alteracoes: string(alteracoes),
+		IP:         ator.IP,
+	}
+	if ator.ID != uuid.Nil {
+		atorID := ator.ID
+		registro.AtorID = &atorID
+	}
+
+	return s.auditoriaRepo.ComTransacao(tx).Registrar(registro)
+}
+
+// Buscar consulta a trilha de auditoria da conta por entidade, ator e período
+func (s *AuditoriaServico) Buscar(usuarioID uuid.UUID, req dto.BuscarAuditoriaRequest) (*dto.AuditoriaListResponse, error) {
+	paginaOriginal := req.Pagina
+	if req.Pagina == 0 {
+		req.Pagina = 1
+	}
+	if req.TamanhoPagina == 0 {
+		req.TamanhoPagina = 50
+	}
+
+	registros, total, err := s.auditoriaRepo.Buscar(
+		usuarioID,
+		req.Entidade,
+		req.EntidadeID,
+		req.AtorID,
+		req.DataInicio,
+		req.DataFim,
+		req.Pagina,
+		req.TamanhoPagina,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolver emails dos atores uma única vez por página
+	emails := make(map[uuid.UUID]string)
+	registrosDTO := make([]dto.RegistroAuditoriaResponse, len(registros))
+	for i, registro := range registros {
+		var alteracoes interface{}
+		if err := json.Unmarshal([]byte(registro.Alteracoes), &alteracoes); err != nil {
+			alteracoes = registro.Alteracoes
+		}
+
+		atorEmail := ""
+		if registro.AtorID != nil {
+			email, ok := emails[*registro.AtorID]
+			if !ok {
+				if ator, err := s.usuarioRepo.BuscarPorID(*registro.AtorID); err == nil {
+					email = ator.Email
+				}
+				emails[*registro.AtorID] = email
+			}
+			atorEmail = email
+		}
+
+		registrosDTO[i] = dto.RegistroAuditoriaResponse{
+			ID:          registro.ID,
+			AtorID:      registro.AtorID,
+			AtorEmail:   atorEmail,
+			Acao:        registro.Acao,
+			Entidade:    registro.Entidade,
+			EntidadeID:  registro.EntidadeID,
+			Alteracoes:  alteracoes,
+			IP:          registro.IP,
+			DataCriacao: registro.DataCriacao,
+		}
+	}
+
+	return &dto.AuditoriaListResponse{
+		Registros:     registrosDTO,
+		Total:         total,
+		Pagina:        paginaOriginal,
+		TamanhoPagina: req.TamanhoPagina,
+		TotalPaginas:  int(math.Ceil(float64(total) / float64(req.TamanhoPagina))),
+	}, nil
+}
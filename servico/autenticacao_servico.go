@@ -95,6 +95,7 @@ func (s *AutenticacaoServico) Cadastro(req dto.CadastroRequest) (*dto.LoginRespo
 		ID:              uuid.New(),
 		NomeCompleto:    req.NomeCompleto,
 		Email:           req.Email,
+		CNPJ:            util.NormalizarDocumento(req.CNPJ),
 		SenhaHash:       senhaHash,
 		TrialAtivo:      true,
 		DataTrialInicio: &agora,
@@ -310,6 +311,7 @@ func (s *AutenticacaoServico) mapearUsuarioParaDTO(usuario *entidades.Usuario) d
 		ID:                  usuario.ID.String(),
 		NomeCompleto:        usuario.NomeCompleto,
 		Email:               usuario.Email,
+		CNPJ:                util.FormatarCNPJ(usuario.CNPJ),
 		TrialAtivo:          usuario.TrialAtivo,
 		DataTrialInicio:     usuario.DataTrialInicio,
 		TrialExpirado:       usuario.IsTrialExpirado(),
@@ -0,0 +1,104 @@
+package servico
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/ifinu/ifinu-api-go/dto"
+	"github.com/ifinu/ifinu-api-go/integracao"
+	"github.com/ifinu/ifinu-api-go/logs"
+	"github.com/ifinu/ifinu-api-go/util"
+)
+
+type CEPServico struct {
+	provedor integracao.ProvedorCEP
+	logger   *slog.Logger
+}
+
+func NovoCEPServico(provedor integracao.ProvedorCEP, logger *slog.Logger) *CEPServico {
+	return &CEPServico{
+		provedor: provedor,
+		logger:   logger,
+	}
+}
+
+// Endereco é o endereço de um cadastro (cliente), com CEP sem máscara
+type Endereco struct {
+	Logradouro string
+	Cidade     string
+	Estado     string
+	CEP        string
+}
+
+// BuscarCEP consulta o endereço de um CEP (com ou sem máscara)
+func (s *CEPServico) BuscarCEP(cep string) (*dto.EnderecoCEPResponse, error) {
+	normalizado, err := util.NormalizarCEP(cep)
+	if err != nil {
+		return nil, err
+	}
+
+	endereco, err := s.provedor.BuscarCEP(normalizado)
+	if err != nil {
+		if errors.Is(err, integracao.ErrCEPNaoEncontrado) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("erro ao consultar CEP: %w", err)
+	}
+
+	return &dto.EnderecoCEPResponse{
+		CEP:         util.FormatarCEP(endereco.CEP),
+		Logradouro:  endereco.Logradouro,
+		Complemento: endereco.Complemento,
+		Bairro:      endereco.Bairro,
+		Cidade:      endereco.Cidade,
+		Estado:      endereco.UF,
+		IBGE:        endereco.IBGE,
+	}, nil
+}
+
+// CompletarEndereco valida estado e CEP e, com CEP, usa a consulta para preencher cidade e
+// estado (e o logradouro, se vazio). Um estado informado diferente do estado do CEP é
+// rejeitado. Se o provedor estiver fora do ar, mantém o endereço como informado.
+func (s *CEPServico) CompletarEndereco(endereco Endereco) (Endereco, error) {
+	endereco.Logradouro = strings.TrimSpace(endereco.Logradouro)
+	endereco.Cidade = strings.TrimSpace(endereco.Cidade)
+	endereco.Estado = strings.ToUpper(strings.TrimSpace(endereco.Estado))
+
+	if endereco.Estado != "" && !util.ValidarUF(endereco.Estado) {
+		return endereco, fmt.Errorf("estado inválido: %s", endereco.Estado)
+	}
+	if strings.TrimSpace(endereco.CEP) == "" {
+		endereco.CEP = ""
+		return endereco, nil
+	}
+
+	cep, err := util.NormalizarCEP(endereco.CEP)
+	if err != nil {
+		return endereco, err
+	}
+	endereco.CEP = cep
+
+	consulta, err := s.provedor.BuscarCEP(cep)
+	if err != nil {
+		if errors.Is(err, integracao.ErrCEPNaoEncontrado) {
+			return endereco, err
+		}
+		s.logger.Warn("⚠️  CEP não consultado, endereço mantido como informado", "cep", cep, logs.Erro(err))
+		return endereco, nil
+	}
+
+	if endereco.Estado != "" && endereco.Estado != consulta.UF {
+		return endereco, fmt.Errorf("estado %s não confere com o CEP %s (%s)", endereco.Estado, util.FormatarCEP(cep), consulta.UF)
+	}
+
+	endereco.Estado = consulta.UF
+	if consulta.Cidade != "" {
+		endereco.Cidade = consulta.Cidade
+	}
+	if endereco.Logradouro == "" {
+		endereco.Logradouro = consulta.Logradouro
+	}
+	return endereco, nil
+}
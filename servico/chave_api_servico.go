@@ -0,0 +1,120 @@
+package servico
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/dominio/entidades"
+	"github.com/ifinu/ifinu-api-go/dominio/enums"
+	"github.com/ifinu/ifinu-api-go/dto"
+	"github.com/ifinu/ifinu-api-go/repositorio"
+	"github.com/ifinu/ifinu-api-go/util"
+	"gorm.io/gorm"
+)
+
+// tamanhoPrefixoVisivel é a quantidade de caracteres da chave exibida para identificação
+const tamanhoPrefixoVisivel = 12
+
+type ChaveAPIServico struct {
+	chaveAPIRepo *repositorio.ChaveAPIRepositorio
+}
+
+func NovoChaveAPIServico(chaveAPIRepo *repositorio.ChaveAPIRepositorio) *ChaveAPIServico {
+	return &ChaveAPIServico{
+		chaveAPIRepo: chaveAPIRepo,
+	}
+}
+
+// Criar gera uma nova chave de API para a conta. A chave completa só é
+// retornada nesta chamada; depois disso apenas o prefixo fica visível.
+func (s *ChaveAPIServico) Criar(contaID uuid.UUID, atorID uuid.UUID, papelAtor enums.PapelMembro, req dto.CriarChaveAPIRequest) (*dto.ChaveAPICriadaResponse, error) {
+	vistos := make(map[enums.EscopoAPI]bool)
+	var escopos []enums.EscopoAPI
+	for _, escopo := range req.Escopos {
+		if !escopo.Valido() {
+			return nil, errors.New("escopo inválido: " + escopo.String())
+		}
+		if !vistos[escopo] {
+			vistos[escopo] = true
+			escopos = append(escopos, escopo)
+		}
+	}
+
+	segredo, err := util.GerarTokenAleatorio(24)
+	if err != nil {
+		return nil, err
+	}
+	chaveCompleta := entidades.PrefixoChaveAPI + segredo
+
+	chave := &entidades.ChaveAPI{
+		UsuarioID:   contaID,
+		CriadoPorID: atorID,
+		Nome:        req.Nome,
+		Prefixo:     chaveCompleta[:tamanhoPrefixoVisivel],
+		Hash:        util.HashSHA256(chaveCompleta),
+		Papel:       papelAtor,
+	}
+	chave.DefinirEscopos(escopos)
+
+	if req.ExpiraEmDias != nil {
+		expiracao := time.Now().AddDate(0, 0, *req.ExpiraEmDias)
+		chave.DataExpiracao = &expiracao
+	}
+
+	if err := s.chaveAPIRepo.Criar(chave); err != nil {
+		return nil, err
+	}
+
+	return &dto.ChaveAPICriadaResponse{
+		ChaveAPIResponse: s.mapearParaDTO(chave),
+		Chave:            chaveCompleta,
+	}, nil
+}
+
+// Listar lista as chaves de API ativas da conta
+func (s *ChaveAPIServico) Listar(contaID uuid.UUID) ([]dto.ChaveAPIResponse, error) {
+	chaves, err := s.chaveAPIRepo.ListarPorUsuario(contaID)
+	if err != nil {
+		return nil, err
+	}
+
+	resposta := make([]dto.ChaveAPIResponse, len(chaves))
+	for i, chave := range chaves {
+		resposta[i] = s.mapearParaDTO(&chave)
+	}
+	return resposta, nil
+}
+
+// Revogar invalida uma chave de API imediatamente
+func (s *ChaveAPIServico) Revogar(contaID uuid.UUID, id uuid.UUID) error {
+	chave, err := s.chaveAPIRepo.BuscarPorID(id, contaID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("chave de API não encontrada")
+		}
+		return err
+	}
+
+	if chave.IsRevogada() {
+		return errors.New("chave de API já revogada")
+	}
+
+	agora := time.Now()
+	chave.DataRevogacao = &agora
+	return s.chaveAPIRepo.Atualizar(chave)
+}
+
+// mapearParaDTO converte ChaveAPI para ChaveAPIResponse
+func (s *ChaveAPIServico) mapearParaDTO(chave *entidades.ChaveAPI) dto.ChaveAPIResponse {
+	return dto.ChaveAPIResponse{
+		ID:            chave.ID,
+		Nome:          chave.Nome,
+		Prefixo:       chave.Prefixo,
+		Escopos:       chave.ListaEscopos(),
+		UltimoUso:     chave.UltimoUso,
+		DataExpiracao: chave.DataExpiracao,
+		DataCriacao:   chave.DataCriacao,
+		Expirada:      chave.IsExpirada(),
+	}
+}
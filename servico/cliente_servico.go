@@ -1,29 +1,55 @@
 package servico
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log"
 	"math"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/ifinu/ifinu-api-go/dominio/entidades"
+	"github.com/ifinu/ifinu-api-go/dominio/enums"
 	"github.com/ifinu/ifinu-api-go/dto"
 	"github.com/ifinu/ifinu-api-go/repositorio"
+	"github.com/ifinu/ifinu-api-go/util"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/spf13/viper"
 	"gorm.io/gorm"
 )
 
+// Por quanto tempo a verificação do número no WhatsApp é reaproveitada
+const ValidadeVerificacaoWhatsApp = 30 * 24 * time.Hour
+
 type ClienteServico struct {
-	clienteRepo *repositorio.ClienteRepositorio
+	clienteRepo      *repositorio.ClienteRepositorio
+	whatsappRepo     *repositorio.WhatsAppRepositorio
+	whatsappServico  *WhatsAppServico
+	cepServico       *CEPServico
+	webhookServico   *WebhookServico
+	auditoriaServico *AuditoriaServico
 }
 
-func NovoClienteServico(clienteRepo *repositorio.ClienteRepositorio) *ClienteServico {
+func NovoClienteServico(clienteRepo *repositorio.ClienteRepositorio, whatsappRepo *repositorio.WhatsAppRepositorio, whatsappServico *WhatsAppServico, cepServico *CEPServico, webhookServico *WebhookServico, auditoriaServico *AuditoriaServico) *ClienteServico {
 	return &ClienteServico{
-		clienteRepo: clienteRepo,
+		clienteRepo:      clienteRepo,
+		whatsappRepo:     whatsappRepo,
+		whatsappServico:  whatsappServico,
+		cepServico:       cepServico,
+		webhookServico:   webhookServico,
+		auditoriaServico: auditoriaServico,
 	}
 }
 
 // Criar cria um novo cliente
-func (s *ClienteServico) Criar(usuarioID uuid.UUID, req dto.ClienteRequest) (*dto.ClienteResponse, error) {
+func (s *ClienteServico) Criar(usuarioID uuid.UUID, ator dto.Ator, req dto.ClienteRequest) (*dto.ClienteResponse, error) {
+	telefone, err := util.NormalizarTelefone(req.Telefone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, req.Telefone)
+	}
+
 	// Verificar se email já existe para este usuário
 	existe, err := s.clienteRepo.ExistePorEmail(req.Email, usuarioID)
 	if err != nil {
@@ -33,24 +59,53 @@ func (s *ClienteServico) Criar(usuarioID uuid.UUID, req dto.ClienteRequest) (*dt
 		return nil, errors.New("já existe um cliente com este email")
 	}
 
+	if err := s.validarConexaoWhatsApp(usuarioID, req.WhatsAppConexaoID); err != nil {
+		return nil, err
+	}
+
+	cpf, cnpj, err := s.normalizarDocumentos(usuarioID, uuid.Nil, req)
+	if err != nil {
+		return nil, err
+	}
+
+	endereco, err := s.cepServico.CompletarEndereco(Endereco{Logradouro: req.Endereco, Cidade: req.Cidade, Estado: req.Estado, CEP: req.CEP})
+	if err != nil {
+		return nil, err
+	}
+
 	// Criar cliente
 	cliente := &entidades.Cliente{
 		UsuarioID:   usuarioID,
 		Nome:        req.Nome,
 		Email:       req.Email,
-		Telefone:    req.Telefone,
-		Endereco:    req.Endereco,
-		CPF:         req.CPF,
-		CNPJ:        req.CNPJ,
+		Telefone:    telefone,
+		Endereco:    endereco.Logradouro,
+		Cidade:      endereco.Cidade,
+		Estado:      endereco.Estado,
+		CEP:         endereco.CEP,
+		CPF:         cpf,
+		CNPJ:        cnpj,
 		DataCriacao: time.Now(),
+
+		WhatsAppConexaoID: req.WhatsAppConexaoID,
+		CanalNotificacao:  enums.CanalNotificacaoTodos,
 	}
 
-	err = s.clienteRepo.Criar(cliente)
+	err = s.auditoriaServico.Transacao(func(tx *gorm.DB) error {
+		if err := s.clienteRepo.ComTransacao(tx).Criar(cliente); err != nil {
+			return err
+		}
+		return s.auditoriaServico.Registrar(tx, usuarioID, ator, enums.AcaoAuditoriaCriar,
+			enums.EntidadeAuditoriaCliente, cliente.ID.String(), nil, cliente)
+	})
 	if err != nil {
-		return nil, err
+		return nil, erroDocumentoDuplicado(err)
 	}
 
-	return s.mapearParaDTO(cliente), nil
+	resposta := mapearClienteParaDTO(cliente)
+	s.webhookServico.Disparar(usuarioID, enums.EventoClienteCriado, resposta)
+
+	return resposta, nil
 }
 
 // BuscarPorID busca um cliente por ID
@@ -63,7 +118,7 @@ func (s *ClienteServico) BuscarPorID(usuarioID uuid.UUID, clienteID uuid.UUID) (
 		return nil, err
 	}
 
-	return s.mapearParaDTO(cliente), nil
+	return mapearClienteParaDTO(cliente), nil
 }
 
 // Listar lista todos os clientes do usuário
@@ -75,7 +130,7 @@ func (s *ClienteServico) Listar(usuarioID uuid.UUID) ([]dto.ClienteResponse, err
 
 	clientesDTO := make([]dto.ClienteResponse, len(clientes))
 	for i, cliente := range clientes {
-		clientesDTO[i] = *s.mapearParaDTO(&cliente)
+		clientesDTO[i] = *mapearClienteParaDTO(&cliente)
 	}
 
 	return clientesDTO, nil
@@ -98,7 +153,7 @@ func (s *ClienteServico) Buscar(usuarioID uuid.UUID, req dto.BuscarClientesReque
 
 	clientesDTO := make([]dto.ClienteResponse, len(clientes))
 	for i, cliente := range clientes {
-		clientesDTO[i] = *s.mapearParaDTO(&cliente)
+		clientesDTO[i] = *mapearClienteParaDTO(&cliente)
 	}
 
 	totalPaginas := int(math.Ceil(float64(total) / float64(req.TamanhoPagina)))
@@ -113,7 +168,12 @@ func (s *ClienteServico) Buscar(usuarioID uuid.UUID, req dto.BuscarClientesReque
 }
 
 // Atualizar atualiza um cliente
-func (s *ClienteServico) Atualizar(usuarioID uuid.UUID, clienteID uuid.UUID, req dto.ClienteRequest) (*dto.ClienteResponse, error) {
+func (s *ClienteServico) Atualizar(usuarioID uuid.UUID, ator dto.Ator, clienteID uuid.UUID, req dto.ClienteRequest) (*dto.ClienteResponse, error) {
+	telefone, err := util.NormalizarTelefone(req.Telefone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, req.Telefone)
+	}
+
 	// Buscar cliente
 	cliente, err := s.clienteRepo.BuscarPorID(clienteID, usuarioID)
 	if err != nil {
@@ -129,26 +189,56 @@ func (s *ClienteServico) Atualizar(usuarioID uuid.UUID, clienteID uuid.UUID, req
 		return nil, errors.New("já existe outro cliente com este email")
 	}
 
+	if err := s.validarConexaoWhatsApp(usuarioID, req.WhatsAppConexaoID); err != nil {
+		return nil, err
+	}
+
+	cpf, cnpj, err := s.normalizarDocumentos(usuarioID, clienteID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	endereco, err := s.cepServico.CompletarEndereco(Endereco{Logradouro: req.Endereco, Cidade: req.Cidade, Estado: req.Estado, CEP: req.CEP})
+	if err != nil {
+		return nil, err
+	}
+
 	// Atualizar dados
+	antes := *cliente
 	cliente.Nome = req.Nome
 	cliente.Email = req.Email
-	cliente.Telefone = req.Telefone
-	cliente.Endereco = req.Endereco
-	cliente.CPF = req.CPF
-	cliente.CNPJ = req.CNPJ
+	if cliente.Telefone != telefone {
+		// Verificação do WhatsApp era do número anterior
+		cliente.WhatsAppValido = nil
+		cliente.DataVerificacaoWhatsApp = nil
+	}
+	cliente.Telefone = telefone
+	cliente.Endereco = endereco.Logradouro
+	cliente.Cidade = endereco.Cidade
+	cliente.Estado = endereco.Estado
+	cliente.CEP = endereco.CEP
+	cliente.CPF = cpf
+	cliente.CNPJ = cnpj
+	cliente.WhatsAppConexaoID = req.WhatsAppConexaoID
 
-	err = s.clienteRepo.Atualizar(cliente)
+	err = s.auditoriaServico.Transacao(func(tx *gorm.DB) error {
+		if err := s.clienteRepo.ComTransacao(tx).Atualizar(cliente); err != nil {
+			return err
+		}
+		return s.auditoriaServico.Registrar(tx, usuarioID, ator, enums.AcaoAuditoriaAtualizar,
+			enums.EntidadeAuditoriaCliente, cliente.ID.String(), &antes, cliente)
+	})
 	if err != nil {
-		return nil, err
+		return nil, erroDocumentoDuplicado(err)
 	}
 
-	return s.mapearParaDTO(cliente), nil
+	return mapearClienteParaDTO(cliente), nil
 }
 
 // Deletar remove um cliente
-func (s *ClienteServico) Deletar(usuarioID uuid.UUID, clienteID uuid.UUID) error {
+func (s *ClienteServico) Deletar(usuarioID uuid.UUID, ator dto.Ator, clienteID uuid.UUID) error {
 	// Verificar se cliente existe
-	_, err := s.clienteRepo.BuscarPorID(clienteID, usuarioID)
+	cliente, err := s.clienteRepo.BuscarPorID(clienteID, usuarioID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return errors.New("cliente não encontrado")
@@ -156,19 +246,248 @@ func (s *ClienteServico) Deletar(usuarioID uuid.UUID, clienteID uuid.UUID) error
 		return err
 	}
 
-	return s.clienteRepo.Deletar(clienteID, usuarioID)
+	return s.auditoriaServico.Transacao(func(tx *gorm.DB) error {
+		if err := s.clienteRepo.ComTransacao(tx).Deletar(clienteID, usuarioID); err != nil {
+			return err
+		}
+		return s.auditoriaServico.Registrar(tx, usuarioID, ator, enums.AcaoAuditoriaExcluir,
+			enums.EntidadeAuditoriaCliente, clienteID.String(), cliente, nil)
+	})
+}
+
+// AtualizarPreferencias altera canal, horário de silêncio e opt-out de lembretes do cliente
+func (s *ClienteServico) AtualizarPreferencias(usuarioID uuid.UUID, ator dto.Ator, clienteID uuid.UUID, req dto.PreferenciasNotificacaoRequest) (*dto.ClienteResponse, error) {
+	if !req.CanalNotificacao.Valido() {
+		return nil, fmt.Errorf("canal de notificação inválido: %s", req.CanalNotificacao)
+	}
+	if (req.SilencioInicio == nil) != (req.SilencioFim == nil) {
+		return nil, errors.New("informe início e fim do horário de silêncio")
+	}
+	if req.SilencioInicio != nil {
+		janela := util.JanelaSilencio{Inicio: *req.SilencioInicio, Fim: *req.SilencioFim}
+		if err := janela.Validar(); err != nil {
+			return nil, err
+		}
+	}
+
+	cliente, err := s.clienteRepo.BuscarPorID(clienteID, usuarioID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("cliente não encontrado")
+		}
+		return nil, err
+	}
+
+	antes := *cliente
+	cliente.CanalNotificacao = req.CanalNotificacao
+	cliente.SilencioInicio = req.SilencioInicio
+	cliente.SilencioFim = req.SilencioFim
+	if req.OptOut {
+		cliente.RegistrarOptOut(enums.OrigemOptOutPainel)
+	} else {
+		cliente.DataOptOut = nil
+		cliente.OrigemOptOut = ""
+	}
+
+	err = s.auditoriaServico.Transacao(func(tx *gorm.DB) error {
+		if err := s.clienteRepo.ComTransacao(tx).Atualizar(cliente); err != nil {
+			return err
+		}
+		return s.auditoriaServico.Registrar(tx, usuarioID, ator, enums.AcaoAuditoriaAtualizar,
+			enums.EntidadeAuditoriaCliente, cliente.ID.String(), &antes, cliente)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mapearClienteParaDTO(cliente), nil
 }
 
-// mapearParaDTO converte Cliente para ClienteResponse
-func (s *ClienteServico) mapearParaDTO(cliente *entidades.Cliente) *dto.ClienteResponse {
+// RegistrarOptOut grava o pedido do próprio cliente para não receber mais lembretes
+func (s *ClienteServico) RegistrarOptOut(cliente *entidades.Cliente, origem enums.OrigemOptOut) error {
+	if cliente.DataOptOut != nil {
+		return nil
+	}
+
+	antes := *cliente
+	cliente.RegistrarOptOut(origem)
+
+	err := s.auditoriaServico.Transacao(func(tx *gorm.DB) error {
+		if err := s.clienteRepo.ComTransacao(tx).Atualizar(cliente); err != nil {
+			return err
+		}
+		return s.auditoriaServico.Registrar(tx, cliente.UsuarioID, dto.AtorSistema, enums.AcaoAuditoriaAtualizar,
+			enums.EntidadeAuditoriaCliente, cliente.ID.String(), &antes, cliente)
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("🔕 Opt-out registrado: Cliente=%s, Usuário=%s, Origem=%s", cliente.ID, cliente.UsuarioID, origem)
+	return nil
+}
+
+// DescadastrarPorToken registra o opt-out pelo link assinado enviado nos emails (sem login)
+func (s *ClienteServico) DescadastrarPorToken(token string) error {
+	clienteID, err := util.ValidarTokenDescadastro(token)
+	if err != nil {
+		return err
+	}
+
+	cliente, err := s.clienteRepo.BuscarPorIDSemUsuario(clienteID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("cliente não encontrado")
+		}
+		return err
+	}
+
+	return s.RegistrarOptOut(cliente, enums.OrigemOptOutEmail)
+}
+
+// linkDescadastro monta o link público de descadastro incluído nos emails ao cliente
+func linkDescadastro(clienteID uuid.UUID) string {
+	baseURL := strings.TrimSuffix(viper.GetString("APP_BACKEND_URL"), "/")
+	if baseURL == "" {
+		return ""
+	}
+	token, err := util.GerarTokenDescadastro(clienteID)
+	if err != nil {
+		log.Printf("❌ Link de descadastro não gerado: %v", err)
+		return ""
+	}
+	return fmt.Sprintf("%s/api/notificacoes/descadastrar?token=%s", baseURL, token)
+}
+
+// VerificarWhatsApp confere se o telefone do cliente tem conta no WhatsApp. O resultado fica
+// gravado no cliente e é reaproveitado por ValidadeVerificacaoWhatsApp, a menos que forcar seja true.
+func (s *ClienteServico) VerificarWhatsApp(ctx context.Context, usuarioID uuid.UUID, clienteID uuid.UUID, forcar bool) (*dto.VerificacaoWhatsAppResponse, error) {
+	cliente, err := s.clienteRepo.ComContexto(ctx).BuscarPorID(clienteID, usuarioID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("cliente não encontrado")
+		}
+		return nil, err
+	}
+
+	agora := time.Now()
+	if !forcar && cliente.WhatsAppVerificadoDesde(agora.Add(-ValidadeVerificacaoWhatsApp)) {
+		return &dto.VerificacaoWhatsAppResponse{
+			ClienteID:       cliente.ID,
+			Telefone:        cliente.Telefone,
+			WhatsAppValido:  *cliente.WhatsAppValido,
+			DataVerificacao: *cliente.DataVerificacaoWhatsApp,
+			Cache:           true,
+		}, nil
+	}
+
+	rota := RotaMensagem{ClienteConexaoID: cliente.WhatsAppConexaoID}
+	valido, err := s.whatsappServico.VerificarNumero(ctx, usuarioID, rota, cliente.Telefone)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.clienteRepo.ComContexto(ctx).AtualizarVerificacaoWhatsApp(cliente.ID, cliente.Telefone, valido, agora); err != nil {
+		log.Printf("⚠️  Verificação de WhatsApp do cliente %s não gravada: %v", cliente.ID, err)
+	}
+
+	return &dto.VerificacaoWhatsAppResponse{
+		ClienteID:       cliente.ID,
+		Telefone:        cliente.Telefone,
+		WhatsAppValido:  valido,
+		DataVerificacao: agora,
+	}, nil
+}
+
+// erroDocumentoDuplicado traduz a violação dos índices únicos de CPF/CNPJ por usuário.
+// A consulta em normalizarDocumentos não impede que duas requisições simultâneas gravem
+// o mesmo documento; o índice barra a segunda.
+func erroDocumentoDuplicado(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != "23505" {
+		return err
+	}
+	switch pgErr.ConstraintName {
+	case "idx_clientes_usuario_cpf":
+		return errors.New("já existe outro cliente com este CPF")
+	case "idx_clientes_usuario_cnpj":
+		return errors.New("já existe outro cliente com este CNPJ")
+	}
+	return err
+}
+
+// normalizarDocumentos valida CPF e CNPJ, remove a máscara (vazio vira nil) e garante que
+// nenhum outro cliente do usuário usa o mesmo documento
+func (s *ClienteServico) normalizarDocumentos(usuarioID uuid.UUID, clienteID uuid.UUID, req dto.ClienteRequest) (*string, *string, error) {
+	normalizar := func(documento *string, valido func(string) bool, nome string) (*string, error) {
+		if documento == nil || util.NormalizarDocumento(*documento) == "" {
+			return nil, nil
+		}
+		if !valido(*documento) {
+			return nil, fmt.Errorf("%s inválido", nome)
+		}
+
+		normalizado := util.NormalizarDocumento(*documento)
+		existe, err := s.clienteRepo.ExisteDocumento(normalizado, usuarioID, clienteID)
+		if err != nil {
+			return nil, err
+		}
+		if existe {
+			return nil, fmt.Errorf("já existe outro cliente com este %s", nome)
+		}
+		return &normalizado, nil
+	}
+
+	cpf, err := normalizar(req.CPF, util.ValidarCPF, "CPF")
+	if err != nil {
+		return nil, nil, err
+	}
+	cnpj, err := normalizar(req.CNPJ, util.ValidarCNPJ, "CNPJ")
+	if err != nil {
+		return nil, nil, err
+	}
+	return cpf, cnpj, nil
+}
+
+// validarConexaoWhatsApp garante que o número preferencial pertence à conta
+func (s *ClienteServico) validarConexaoWhatsApp(usuarioID uuid.UUID, conexaoID *int64) error {
+	if conexaoID == nil {
+		return nil
+	}
+
+	if _, err := s.whatsappRepo.BuscarPorID(*conexaoID, usuarioID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("conexão WhatsApp não encontrada")
+		}
+		return err
+	}
+	return nil
+}
+
+// mapearClienteParaDTO converte Cliente para ClienteResponse
+func mapearClienteParaDTO(cliente *entidades.Cliente) *dto.ClienteResponse {
 	return &dto.ClienteResponse{
 		ID:          cliente.ID,
 		Nome:        cliente.Nome,
 		Email:       cliente.Email,
 		Telefone:    cliente.Telefone,
 		Endereco:    cliente.Endereco,
-		CPF:         cliente.CPF,
-		CNPJ:        cliente.CNPJ,
+		Cidade:      cliente.Cidade,
+		Estado:      cliente.Estado,
+		CEP:         cliente.CEPFormatado(),
+		CPF:         cliente.CPFFormatado(),
+		CNPJ:        cliente.CNPJFormatado(),
 		DataCriacao: cliente.DataCriacao,
+
+		WhatsAppConexaoID: cliente.WhatsAppConexaoID,
+
+		CanalNotificacao: cliente.CanalNotificacao,
+		SilencioInicio:   cliente.SilencioInicio,
+		SilencioFim:      cliente.SilencioFim,
+		DataOptOut:       cliente.DataOptOut,
+		OrigemOptOut:     cliente.OrigemOptOut,
+
+		WhatsAppValido:          cliente.WhatsAppValido,
+		DataVerificacaoWhatsApp: cliente.DataVerificacaoWhatsApp,
 	}
 }
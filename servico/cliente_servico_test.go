@@ -0,0 +1,34 @@
+package servico
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestErroDocumentoDuplicado(t *testing.T) {
+	violacao := func(indice string) error {
+		return fmt.Errorf("erro na transação: %w", &pgconn.PgError{Code: "23505", ConstraintName: indice})
+	}
+	outro := errors.New("conexão recusada")
+
+	casos := []struct {
+		nome     string
+		err      error
+		esperado string
+	}{
+		{"CPF", violacao("idx_clientes_usuario_cpf"), "já existe outro cliente com este CPF"},
+		{"CNPJ", violacao("idx_clientes_usuario_cnpj"), "já existe outro cliente com este CNPJ"},
+		{"outro índice", violacao("clientes_pkey"), violacao("clientes_pkey").Error()},
+		{"outro erro", outro, outro.Error()},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			if obtido := erroDocumentoDuplicado(caso.err); obtido.Error() != caso.esperado {
+				t.Errorf("erroDocumentoDuplicado = %q, esperado %q", obtido, caso.esperado)
+			}
+		})
+	}
+}
@@ -14,19 +14,23 @@ import (
 )
 
 type CobrancaServico struct {
-	cobrancaRepo *repositorio.CobrancaRepositorio
-	clienteRepo  *repositorio.ClienteRepositorio
+	cobrancaRepo     *repositorio.CobrancaRepositorio
+	clienteRepo      *repositorio.ClienteRepositorio
+	webhookServico   *WebhookServico
+	auditoriaServico *AuditoriaServico
 }
 
-func NovoCobrancaServico(cobrancaRepo *repositorio.CobrancaRepositorio, clienteRepo *repositorio.ClienteRepositorio) *CobrancaServico {
+func NovoCobrancaServico(cobrancaRepo *repositorio.CobrancaRepositorio, clienteRepo *repositorio.ClienteRepositorio, webhookServico *WebhookServico, auditoriaServico *AuditoriaServico) *CobrancaServico {
 	return &CobrancaServico{
-		cobrancaRepo: cobrancaRepo,
-		clienteRepo:  clienteRepo,
+		cobrancaRepo:     cobrancaRepo,
+		clienteRepo:      clienteRepo,
+		webhookServico:   webhookServico,
+		auditoriaServico: auditoriaServico,
 	}
 }
 
 // Criar cria uma nova cobrança
-func (s *CobrancaServico) Criar(usuarioID uuid.UUID, req dto.CobrancaRequest) (*dto.CobrancaResponse, error) {
+func (s *CobrancaServico) Criar(usuarioID uuid.UUID, ator dto.Ator, req dto.CobrancaRequest) (*dto.CobrancaResponse, error) {
 	// Verificar se cliente existe e pertence ao usuário
 	cliente, err := s.clienteRepo.BuscarPorID(req.ClienteID, usuarioID)
 	if err != nil {
@@ -49,7 +53,13 @@ func (s *CobrancaServico) Criar(usuarioID uuid.UUID, req dto.CobrancaRequest) (*
 		DataCriacao:      time.Now(),
 	}
 
-	err = s.cobrancaRepo.Criar(cobranca)
+	err = s.auditoriaServico.Transacao(func(tx *gorm.DB) error {
+		if err := s.cobrancaRepo.ComTransacao(tx).Criar(cobranca); err != nil {
+			return err
+		}
+		return s.auditoriaServico.Registrar(tx, usuarioID, ator, enums.AcaoAuditoriaCriar,
+			enums.EntidadeAuditoriaCobranca, cobranca.ID.String(), nil, cobranca)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -57,6 +67,9 @@ func (s *CobrancaServico) Criar(usuarioID uuid.UUID, req dto.CobrancaRequest) (*
 	// Carregar cliente para resposta
 	cobranca.Cliente = *cliente
 
+	resposta := s.mapearParaDTO(cobranca)
+	s.webhookServico.Disparar(usuarioID, enums.EventoCobrancaCriada, resposta)
+
 	// TODO: Enviar notificações se solicitado
 	// if req.EnviarWhatsApp {
 	// 	go enviarNotificacaoWhatsApp(cobranca)
@@ -65,7 +78,7 @@ func (s *CobrancaServico) Criar(usuarioID uuid.UUID, req dto.CobrancaRequest) (*
 	// 	go enviarNotificacaoEmail(cobranca)
 	// }
 
-	return s.mapearParaDTO(cobranca), nil
+	return resposta, nil
 }
 
 // BuscarPorID busca uma cobrança por ID
@@ -138,7 +151,7 @@ func (s *CobrancaServico) Buscar(usuarioID uuid.UUID, req dto.BuscarCobrancasReq
 }
 
 // Atualizar atualiza uma cobrança
-func (s *CobrancaServico) Atualizar(usuarioID uuid.UUID, cobrancaID uuid.UUID, req dto.CobrancaRequest) (*dto.CobrancaResponse, error) {
+func (s *CobrancaServico) Atualizar(usuarioID uuid.UUID, ator dto.Ator, cobrancaID uuid.UUID, req dto.CobrancaRequest) (*dto.CobrancaResponse, error) {
 	// Buscar cobrança
 	cobranca, err := s.cobrancaRepo.BuscarPorID(cobrancaID, usuarioID)
 	if err != nil {
@@ -158,13 +171,14 @@ func (s *CobrancaServico) Atualizar(usuarioID uuid.UUID, cobrancaID uuid.UUID, r
 	}
 
 	// Atualizar dados
+	antes := *cobranca
 	cobranca.ClienteID = req.ClienteID
 	cobranca.Valor = req.Valor
 	cobranca.Descricao = req.Descricao
 	cobranca.DataVencimento = req.DataVencimento
 	cobranca.TipoRecorrencia = req.TipoRecorrencia
 
-	err = s.cobrancaRepo.Atualizar(cobranca)
+	err = s.atualizarComAuditoria(usuarioID, ator, &antes, cobranca)
 	if err != nil {
 		return nil, err
 	}
@@ -173,7 +187,7 @@ func (s *CobrancaServico) Atualizar(usuarioID uuid.UUID, cobrancaID uuid.UUID, r
 }
 
 // AtualizarStatus atualiza o status de uma cobrança
-func (s *CobrancaServico) AtualizarStatus(usuarioID uuid.UUID, cobrancaID uuid.UUID, novoStatus enums.StatusCobranca) (*dto.CobrancaResponse, error) {
+func (s *CobrancaServico) AtualizarStatus(usuarioID uuid.UUID, ator dto.Ator, cobrancaID uuid.UUID, novoStatus enums.StatusCobranca) (*dto.CobrancaResponse, error) {
 	// Buscar cobrança
 	cobranca, err := s.cobrancaRepo.BuscarPorID(cobrancaID, usuarioID)
 	if err != nil {
@@ -184,6 +198,8 @@ func (s *CobrancaServico) AtualizarStatus(usuarioID uuid.UUID, cobrancaID uuid.U
 	}
 
 	// Atualizar status
+	antes := *cobranca
+	statusAnterior := cobranca.Status
 	cobranca.Status = novoStatus
 
 	// Se marcada como paga, registrar data de pagamento
@@ -192,16 +208,26 @@ func (s *CobrancaServico) AtualizarStatus(usuarioID uuid.UUID, cobrancaID uuid.U
 		cobranca.DataPagamento = &agora
 	}
 
-	err = s.cobrancaRepo.Atualizar(cobranca)
+	err = s.atualizarComAuditoria(usuarioID, ator, &antes, cobranca)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.mapearParaDTO(cobranca), nil
+	resposta := s.mapearParaDTO(cobranca)
+	if statusAnterior != novoStatus {
+		switch novoStatus {
+		case enums.StatusCobrancaPago:
+			s.webhookServico.Disparar(usuarioID, enums.EventoCobrancaPaga, resposta)
+		case enums.StatusCobrancaVencido:
+			s.webhookServico.Disparar(usuarioID, enums.EventoCobrancaVencida, resposta)
+		}
+	}
+
+	return resposta, nil
 }
 
 // Deletar remove uma cobrança
-func (s *CobrancaServico) Deletar(usuarioID uuid.UUID, cobrancaID uuid.UUID) error {
+func (s *CobrancaServico) Deletar(usuarioID uuid.UUID, ator dto.Ator, cobrancaID uuid.UUID) error {
 	// Verificar se cobrança existe
 	cobranca, err := s.cobrancaRepo.BuscarPorID(cobrancaID, usuarioID)
 	if err != nil {
@@ -216,7 +242,24 @@ func (s *CobrancaServico) Deletar(usuarioID uuid.UUID, cobrancaID uuid.UUID) err
 		return errors.New("não é possível cancelar uma cobrança já paga")
 	}
 
-	return s.cobrancaRepo.Deletar(cobrancaID, usuarioID)
+	return s.auditoriaServico.Transacao(func(tx *gorm.DB) error {
+		if err := s.cobrancaRepo.ComTransacao(tx).Deletar(cobrancaID, usuarioID); err != nil {
+			return err
+		}
+		return s.auditoriaServico.Registrar(tx, usuarioID, ator, enums.AcaoAuditoriaExcluir,
+			enums.EntidadeAuditoriaCobranca, cobrancaID.String(), cobranca, nil)
+	})
+}
+
+// atualizarComAuditoria salva a cobrança e registra o diff na mesma transação
+func (s *CobrancaServico) atualizarComAuditoria(usuarioID uuid.UUID, ator dto.Ator, antes, depois *entidades.Cobranca) error {
+	return s.auditoriaServico.Transacao(func(tx *gorm.DB) error {
+		if err := s.cobrancaRepo.ComTransacao(tx).Atualizar(depois); err != nil {
+			return err
+		}
+		return s.auditoriaServico.Registrar(tx, usuarioID, ator, enums.AcaoAuditoriaAtualizar,
+			enums.EntidadeAuditoriaCobranca, depois.ID.String(), antes, depois)
+	})
 }
 
 // ObterEstatisticas retorna estatísticas de cobranças do usuário
@@ -245,6 +288,11 @@ func (s *CobrancaServico) ObterEstatisticas(usuarioID uuid.UUID) (*dto.Estatisti
 
 // mapearParaDTO converte Cobranca para CobrancaResponse
 func (s *CobrancaServico) mapearParaDTO(cobranca *entidades.Cobranca) *dto.CobrancaResponse {
+	return mapearCobrancaParaDTO(cobranca)
+}
+
+// mapearCobrancaParaDTO converte Cobranca para CobrancaResponse (usado também pelo agendador)
+func mapearCobrancaParaDTO(cobranca *entidades.Cobranca) *dto.CobrancaResponse {
 	// Calcular se está vencida e dias de atraso
 	hoje := time.Now().Truncate(24 * time.Hour)
 	dataVenc := cobranca.DataVencimento.Truncate(24 * time.Hour)
@@ -301,8 +349,11 @@ func (s *CobrancaServico) mapearParaDTO(cobranca *entidades.Cobranca) *dto.Cobra
 			Email:       cobranca.Cliente.Email,
 			Telefone:    cobranca.Cliente.Telefone,
 			Endereco:    cobranca.Cliente.Endereco,
-			CPF:         cobranca.Cliente.CPF,
-			CNPJ:        cobranca.Cliente.CNPJ,
+			Cidade:      cobranca.Cliente.Cidade,
+			Estado:      cobranca.Cliente.Estado,
+			CEP:         cobranca.Cliente.CEPFormatado(),
+			CPF:         cobranca.Cliente.CPFFormatado(),
+			CNPJ:        cobranca.Cliente.CNPJFormatado(),
 			DataCriacao: cobranca.Cliente.DataCriacao,
 		}
 	}
@@ -0,0 +1,60 @@
+package servico
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	descFilaDisponivel = prometheus.NewDesc("ifinu_fila_disponivel",
+		"1 se a fila está conectada ao Redis e o tamanho pôde ser lido, 0 caso contrário.", []string{"fila"}, nil)
+	descFilaTamanho = prometheus.NewDesc("ifinu_fila_tamanho",
+		"Itens em cada fila ou conjunto Redis (chave).", []string{"fila", "chave"}, nil)
+	descFilaWorkers = prometheus.NewDesc("ifinu_fila_workers_ativos",
+		"Workers desta réplica consumindo a fila.", []string{"fila"}, nil)
+)
+
+// ColetorFilas expõe a saúde das filas (notificações WhatsApp/email e webhooks) no
+// /metrics. O tamanho é lido do Redis a cada scrape.
+type ColetorFilas struct {
+	filaMensagem   *FilaMensagemServico
+	webhookServico *WebhookServico
+}
+
+// NovoColetorFilas cria o coletor; filaMensagem é nil quando o Redis não estava disponível
+func NovoColetorFilas(filaMensagem *FilaMensagemServico, webhookServico *WebhookServico) *ColetorFilas {
+	return &ColetorFilas{filaMensagem: filaMensagem, webhookServico: webhookServico}
+}
+
+func (c *ColetorFilas) Describe(ch chan<- *prometheus.Desc) {
+	ch <- descFilaDisponivel
+	ch <- descFilaTamanho
+	ch <- descFilaWorkers
+}
+
+func (c *ColetorFilas) Collect(ch chan<- prometheus.Metric) {
+	var workers int32
+	var filas []ResumoFila
+	var err error
+	if c.filaMensagem != nil {
+		workers = c.filaMensagem.workersAtivos.Load()
+		filas, err = c.filaMensagem.InspecionarFilas(0)
+	}
+	coletarFila(ch, "notificacoes", c.filaMensagem != nil && err == nil, workers, filas)
+
+	if c.webhookServico != nil {
+		filas, err = c.webhookServico.InspecionarFilas(0)
+		coletarFila(ch, "webhooks", err == nil, c.webhookServico.workersAtivos.Load(), filas)
+	}
+}
+
+func coletarFila(ch chan<- prometheus.Metric, fila string, disponivel bool, workers int32, resumos []ResumoFila) {
+	valor := 0.0
+	if disponivel {
+		valor = 1
+	}
+	ch <- prometheus.MustNewConstMetric(descFilaDisponivel, prometheus.GaugeValue, valor, fila)
+	ch <- prometheus.MustNewConstMetric(descFilaWorkers, prometheus.GaugeValue, float64(workers), fila)
+	for _, resumo := range resumos {
+		ch <- prometheus.MustNewConstMetric(descFilaTamanho, prometheus.GaugeValue, float64(resumo.Tamanho), fila, resumo.Nome)
+	}
+}
@@ -0,0 +1,155 @@
+package servico
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ifinu/ifinu-api-go/dominio/enums"
+	"github.com/ifinu/ifinu-api-go/dto"
+	"github.com/ifinu/ifinu-api-go/integracao"
+)
+
+// validarConteudo confere anexos e botões antes de tentar qualquer número
+func validarConteudo(conteudo dto.MensagemWhatsApp) error {
+	if strings.TrimSpace(conteudo.Texto) == "" {
+		return errors.New("texto da mensagem é obrigatório")
+	}
+	if len(conteudo.Botoes) > 0 && conteudo.Lista != nil {
+		return errors.New("use botões ou lista, não os dois")
+	}
+
+	for _, anexo := range conteudo.Anexos {
+		if !anexo.Tipo.Valido() {
+			return fmt.Errorf("tipo de anexo inválido: %s", anexo.Tipo)
+		}
+		if (anexo.URL == "") == (anexo.Base64 == "") {
+			return errors.New("informe a URL ou o base64 do anexo")
+		}
+		if anexo.Tipo == enums.TipoAnexoDocumento && anexo.NomeArquivo == "" {
+			return errors.New("nome do arquivo é obrigatório para documentos")
+		}
+	}
+
+	for _, botao := range conteudo.Botoes {
+		if !botao.Tipo.Valido() {
+			return fmt.Errorf("tipo de botão inválido: %s", botao.Tipo)
+		}
+		if botao.Tipo == enums.TipoBotaoLink && botao.URL == "" {
+			return errors.New("botão de link precisa de URL")
+		}
+		if botao.Tipo == enums.TipoBotaoCopiar && botao.Codigo == "" {
+			return errors.New("botão de copiar precisa de código")
+		}
+	}
+	return nil
+}
+
+// textoComBotoes acrescenta ao texto os links e códigos dos botões, para envio como texto simples
+func textoComBotoes(conteudo dto.MensagemWhatsApp) string {
+	var texto strings.Builder
+	texto.WriteString(conteudo.Texto)
+
+	for _, botao := range conteudo.Botoes {
+		switch botao.Tipo {
+		case enums.TipoBotaoLink:
+			fmt.Fprintf(&texto, "\n\n%s: %s", botao.Texto, botao.URL)
+		case enums.TipoBotaoCopiar:
+			fmt.Fprintf(&texto, "\n\n%s:\n%s", botao.Texto, botao.Codigo)
+		}
+	}
+
+	if conteudo.Rodape != "" {
+		texto.WriteString("\n\n_" + conteudo.Rodape + "_")
+	}
+	return texto.String()
+}
+
+// separarTitulo usa a primeira linha do texto como título da mensagem interativa
+func separarTitulo(texto string) (string, string) {
+	titulo, descricao, ok := strings.Cut(strings.TrimSpace(texto), "\n")
+	if !ok {
+		return "", titulo
+	}
+	return strings.Trim(titulo, "*"), strings.TrimSpace(descricao)
+}
+
+// botoesEvolution converte o conteúdo para o formato de botões da Evolution API
+func botoesEvolution(conteudo dto.MensagemWhatsApp) integracao.EnviarBotoesRequest {
+	titulo, descricao := separarTitulo(conteudo.Texto)
+	requisicao := integracao.EnviarBotoesRequest{
+		Title:       titulo,
+		Description: descricao,
+		Footer:      conteudo.Rodape,
+	}
+
+	for _, botao := range conteudo.Botoes {
+		convertido := integracao.BotaoEvolution{DisplayText: botao.Texto}
+		switch botao.Tipo {
+		case enums.TipoBotaoResposta:
+			convertido.Type = "reply"
+			convertido.ID = botao.ID
+			if convertido.ID == "" {
+				convertido.ID = botao.Texto
+			}
+		case enums.TipoBotaoLink:
+			convertido.Type = "url"
+			convertido.URL = botao.URL
+		case enums.TipoBotaoCopiar:
+			convertido.Type = "copy"
+			convertido.CopyCode = botao.Codigo
+		}
+		requisicao.Buttons = append(requisicao.Buttons, convertido)
+	}
+	return requisicao
+}
+
+// listaEvolution converte o conteúdo para o formato de lista da Evolution API
+func listaEvolution(conteudo dto.MensagemWhatsApp) integracao.EnviarListaRequest {
+	requisicao := integracao.EnviarListaRequest{
+		Title:       conteudo.Lista.Titulo,
+		Description: conteudo.Texto,
+		ButtonText:  conteudo.Lista.TextoBotao,
+		FooterText:  conteudo.Rodape,
+	}
+
+	for _, secao := range conteudo.Lista.Secoes {
+		convertida := integracao.SecaoListaEvolution{Title: secao.Titulo}
+		for _, item := range secao.Itens {
+			convertida.Rows = append(convertida.Rows, integracao.LinhaListaEvolution{
+				Title:       item.Titulo,
+				Description: item.Descricao,
+				RowID:       item.ID,
+			})
+		}
+		requisicao.Sections = append(requisicao.Sections, convertida)
+	}
+	return requisicao
+}
+
+// midiaEvolution converte um anexo para o formato de mídia da Evolution API
+func midiaEvolution(anexo dto.AnexoWhatsApp) integracao.EnviarMidiaRequest {
+	midia := integracao.EnviarMidiaRequest{
+		Mimetype: anexo.MimeType,
+		Caption:  anexo.Legenda,
+		Media:    anexo.URL,
+		FileName: anexo.NomeArquivo,
+	}
+	if midia.Media == "" {
+		midia.Media = anexo.Base64
+	}
+
+	switch anexo.Tipo {
+	case enums.TipoAnexoImagem:
+		midia.Mediatype = "image"
+		if midia.Mimetype == "" {
+			midia.Mimetype = "image/png"
+		}
+	case enums.TipoAnexoDocumento:
+		midia.Mediatype = "document"
+		if midia.Mimetype == "" {
+			midia.Mimetype = "application/pdf"
+		}
+	}
+	return midia
+}
@@ -0,0 +1,308 @@
+package servico
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/dominio/entidades"
+	"github.com/ifinu/ifinu-api-go/dominio/enums"
+	"github.com/ifinu/ifinu-api-go/dto"
+	"github.com/ifinu/ifinu-api-go/integracao"
+	"github.com/ifinu/ifinu-api-go/logs"
+	"github.com/ifinu/ifinu-api-go/repositorio"
+	"github.com/ifinu/ifinu-api-go/util"
+	"gorm.io/gorm"
+)
+
+// ConversaServico mantém a caixa de entrada do WhatsApp: grava as mensagens
+// recebidas pela Evolution API, vincula ao cliente e permite responder
+type ConversaServico struct {
+	mensagemRepo    *repositorio.MensagemWhatsAppRepositorio
+	whatsappRepo    *repositorio.WhatsAppRepositorio
+	clienteRepo     *repositorio.ClienteRepositorio
+	cobrancaRepo    *repositorio.CobrancaRepositorio
+	whatsappServico *WhatsAppServico
+	webhookServico  *WebhookServico
+	logger          *slog.Logger
+
+	respostaAutomaticaServico *RespostaAutomaticaServico
+}
+
+func NovoConversaServico(
+	mensagemRepo *repositorio.MensagemWhatsAppRepositorio,
+	whatsappRepo *repositorio.WhatsAppRepositorio,
+	clienteRepo *repositorio.ClienteRepositorio,
+	cobrancaRepo *repositorio.CobrancaRepositorio,
+	whatsappServico *WhatsAppServico,
+	webhookServico *WebhookServico,
+	respostaAutomaticaServico *RespostaAutomaticaServico,
+	logger *slog.Logger,
+) *ConversaServico {
+	return &ConversaServico{
+		mensagemRepo:    mensagemRepo,
+		whatsappRepo:    whatsappRepo,
+		clienteRepo:     clienteRepo,
+		cobrancaRepo:    cobrancaRepo,
+		whatsappServico: whatsappServico,
+		webhookServico:  webhookServico,
+		logger:          logger,
+
+		respostaAutomaticaServico: respostaAutomaticaServico,
+	}
+}
+
+// ProcessarEventoEvolution grava as mensagens de um evento MESSAGES_UPSERT.
+// Outros eventos e mensagens de grupos são ignorados.
+func (s *ConversaServico) ProcessarEventoEvolution(ctx context.Context, evento *integracao.EventoWebhookEvolution) error {
+	if !evento.IsMensagemRecebida() {
+		return nil
+	}
+
+	var dados integracao.MensagemEvolution
+	if err := json.Unmarshal(evento.Data, &dados); err != nil {
+		return fmt.Errorf("payload de mensagem inválido: %w", err)
+	}
+	if !dados.IsConversaIndividual() || dados.Key.ID == "" {
+		return nil
+	}
+
+	conexao, err := s.whatsappRepo.ComContexto(ctx).BuscarPorNomeInstancia(evento.Instance)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.logger.WarnContext(ctx, "⚠️  Mensagem recebida de instância desconhecida", "instancia", evento.Instance)
+			return nil
+		}
+		return err
+	}
+
+	mensagem := &entidades.MensagemWhatsApp{
+		UsuarioID:    conexao.UsuarioID,
+		ConexaoID:    conexao.ID,
+		Telefone:     util.TelefoneDoJID(dados.Key.RemoteJid),
+		Direcao:      entidades.DirecaoMensagemEntrada,
+		Texto:        dados.Texto(),
+		TipoMensagem: dados.MessageType,
+		IDExterno:    dados.Key.ID,
+		DataMensagem: dados.Data(),
+	}
+
+	// Mensagens enviadas pelo próprio número (fila, painel ou celular) entram como saída
+	if dados.Key.FromMe {
+		mensagem.Direcao = entidades.DirecaoMensagemSaida
+		mensagem.Lida = true
+	} else {
+		mensagem.NomeContato = dados.PushName
+	}
+
+	s.vincularCliente(ctx, mensagem)
+
+	inserida, err := s.mensagemRepo.ComContexto(ctx).Criar(mensagem)
+	if err != nil {
+		return err
+	}
+	if !inserida || !mensagem.IsEntrada() {
+		return nil
+	}
+
+	ctx = logs.ComUsuarioID(ctx, mensagem.UsuarioID)
+	s.logger.InfoContext(ctx, "📥 Mensagem recebida", "telefone", mensagem.Telefone, "cliente_id", mensagem.ClienteID)
+	s.webhookServico.Disparar(mensagem.UsuarioID, enums.EventoWhatsAppMensagem, mapearMensagemWhatsAppParaDTO(mensagem))
+
+	// Responde fora da requisição para não atrasar o webhook da Evolution API
+	ctx = context.WithoutCancel(ctx)
+	go s.respostaAutomaticaServico.Processar(ctx, mensagem)
+
+	return nil
+}
+
+// vincularCliente associa a mensagem ao cliente do telefone e à cobrança em aberto mais antiga
+func (s *ConversaServico) vincularCliente(ctx context.Context, mensagem *entidades.MensagemWhatsApp) {
+	cliente, err := s.clienteRepo.ComContexto(ctx).BuscarPorTelefone(mensagem.Telefone, mensagem.UsuarioID)
+	if err != nil {
+		return
+	}
+	mensagem.ClienteID = &cliente.ID
+
+	cobrancas, err := s.cobrancaRepo.ComContexto(ctx).BuscarEmAbertoPorCliente(cliente.ID, mensagem.UsuarioID)
+	if err == nil && len(cobrancas) > 0 {
+		mensagem.CobrancaID = &cobrancas[0].ID
+	}
+}
+
+// ListarConversas retorna as conversas da conta com a contagem de não lidas
+func (s *ConversaServico) ListarConversas(usuarioID uuid.UUID, req dto.BuscarConversasRequest) (*dto.ConversaListResponse, error) {
+	paginaOriginal := req.Pagina
+	if req.Pagina == 0 {
+		req.Pagina = 1
+	}
+	if req.TamanhoPagina == 0 {
+		req.TamanhoPagina = 20
+	}
+
+	resumos, total, err := s.mensagemRepo.ListarConversas(usuarioID, req.Pagina, req.TamanhoPagina)
+	if err != nil {
+		return nil, err
+	}
+
+	totalNaoLidas, err := s.mensagemRepo.ContarNaoLidas(usuarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	conversas := make([]dto.ConversaResponse, len(resumos))
+	for i, resumo := range resumos {
+		conversas[i] = dto.ConversaResponse{
+			Telefone:           resumo.Telefone,
+			ClienteID:          resumo.ClienteID,
+			NomeCliente:        resumo.NomeCliente,
+			NomeContato:        resumo.NomeContato,
+			UltimaMensagem:     resumo.UltimaMensagem,
+			UltimaDirecao:      string(resumo.UltimaDirecao),
+			DataUltimaMensagem: resumo.DataUltimaMensagem,
+			NaoLidas:           resumo.NaoLidas,
+		}
+	}
+
+	return &dto.ConversaListResponse{
+		Conversas:     conversas,
+		TotalNaoLidas: totalNaoLidas,
+		Total:         total,
+		Pagina:        paginaOriginal,
+		TamanhoPagina: req.TamanhoPagina,
+		TotalPaginas:  int(math.Ceil(float64(total) / float64(req.TamanhoPagina))),
+	}, nil
+}
+
+// ListarMensagens retorna o histórico da conversa com o cliente e suas cobranças em aberto
+func (s *ConversaServico) ListarMensagens(usuarioID uuid.UUID, telefone string, req dto.BuscarConversasRequest) (*dto.MensagensConversaResponse, error) {
+	paginaOriginal := req.Pagina
+	if req.Pagina == 0 {
+		req.Pagina = 1
+	}
+	if req.TamanhoPagina == 0 {
+		req.TamanhoPagina = 50
+	}
+
+	telefone = util.TelefoneDoJID(telefone)
+	mensagens, total, err := s.mensagemRepo.ListarMensagens(usuarioID, telefone, req.Pagina, req.TamanhoPagina)
+	if err != nil {
+		return nil, err
+	}
+	if total == 0 {
+		return nil, errors.New("conversa não encontrada")
+	}
+
+	resposta := &dto.MensagensConversaResponse{
+		Telefone:          telefone,
+		CobrancasEmAberto: []dto.CobrancaResponse{},
+		Mensagens:         make([]dto.MensagemWhatsAppResponse, len(mensagens)),
+		Total:             total,
+		Pagina:            paginaOriginal,
+		TamanhoPagina:     req.TamanhoPagina,
+		TotalPaginas:      int(math.Ceil(float64(total) / float64(req.TamanhoPagina))),
+	}
+	for i, mensagem := range mensagens {
+		resposta.Mensagens[i] = *mapearMensagemWhatsAppParaDTO(&mensagem)
+	}
+
+	// Contexto do cliente: o cadastro pode ter sido criado depois das mensagens
+	if cliente, err := s.clienteRepo.BuscarPorTelefone(telefone, usuarioID); err == nil {
+		resposta.Cliente = mapearClienteParaDTO(cliente)
+
+		cobrancas, err := s.cobrancaRepo.BuscarEmAbertoPorCliente(cliente.ID, usuarioID)
+		if err == nil {
+			for _, cobranca := range cobrancas {
+				resposta.CobrancasEmAberto = append(resposta.CobrancasEmAberto, *mapearCobrancaParaDTO(&cobranca))
+			}
+		}
+	}
+
+	return resposta, nil
+}
+
+// MarcarComoLida zera as mensagens não lidas da conversa
+func (s *ConversaServico) MarcarComoLida(usuarioID uuid.UUID, telefone string) (int64, error) {
+	return s.mensagemRepo.MarcarComoLidas(usuarioID, util.TelefoneDoJID(telefone))
+}
+
+// Responder envia uma mensagem da equipe pelo mesmo número em que a conversa aconteceu
+func (s *ConversaServico) Responder(ctx context.Context, usuarioID uuid.UUID, ator dto.Ator, telefone string, texto string) (*dto.MensagemWhatsAppResponse, error) {
+	telefone = util.TelefoneDoJID(telefone)
+
+	ultima, err := s.mensagemRepo.ComContexto(ctx).BuscarUltimaMensagem(usuarioID, telefone)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("conversa não encontrada")
+		}
+		return nil, err
+	}
+
+	rota := RotaMensagem{ConexaoID: &ultima.ConexaoID}
+	resultado, err := s.whatsappServico.EnviarMensagemSincrono(ctx, usuarioID, rota, telefone, texto)
+	if err != nil {
+		return nil, err
+	}
+
+	mensagem := novaMensagemSaida(ultima, texto, resultado.MessageID)
+	if ator.ID != uuid.Nil {
+		atorID := ator.ID
+		mensagem.EnviadoPorID = &atorID
+	}
+
+	// O eco da Evolution (fromMe) com o mesmo ID é descartado como duplicata
+	if _, err := s.mensagemRepo.ComContexto(ctx).Criar(mensagem); err != nil {
+		s.logger.WarnContext(ctx, "⚠️  Resposta enviada mas não registrada na conversa", "telefone", telefone, logs.Erro(err))
+	}
+
+	// Quem respondeu leu a conversa
+	if _, err := s.mensagemRepo.ComContexto(ctx).MarcarComoLidas(usuarioID, telefone); err != nil {
+		s.logger.WarnContext(ctx, "⚠️  Erro ao marcar conversa como lida", "telefone", telefone, logs.Erro(err))
+	}
+
+	return mapearMensagemWhatsAppParaDTO(mensagem), nil
+}
+
+// novaMensagemSaida monta a mensagem enviada em resposta, na mesma conversa e conexão da origem
+func novaMensagemSaida(origem *entidades.MensagemWhatsApp, texto, idExterno string) *entidades.MensagemWhatsApp {
+	if idExterno == "" {
+		idExterno = uuid.NewString()
+	}
+
+	return &entidades.MensagemWhatsApp{
+		UsuarioID:    origem.UsuarioID,
+		ConexaoID:    origem.ConexaoID,
+		Telefone:     origem.Telefone,
+		ClienteID:    origem.ClienteID,
+		CobrancaID:   origem.CobrancaID,
+		Direcao:      entidades.DirecaoMensagemSaida,
+		Texto:        texto,
+		TipoMensagem: "conversation",
+		IDExterno:    idExterno,
+		Lida:         true,
+		DataMensagem: time.Now(),
+	}
+}
+
+// mapearMensagemWhatsAppParaDTO converte MensagemWhatsApp para MensagemWhatsAppResponse
+func mapearMensagemWhatsAppParaDTO(mensagem *entidades.MensagemWhatsApp) *dto.MensagemWhatsAppResponse {
+	return &dto.MensagemWhatsAppResponse{
+		ID:           mensagem.ID,
+		ConexaoID:    mensagem.ConexaoID,
+		Telefone:     mensagem.Telefone,
+		ClienteID:    mensagem.ClienteID,
+		Direcao:      string(mensagem.Direcao),
+		Texto:        mensagem.Texto,
+		TipoMensagem: mensagem.TipoMensagem,
+		NomeContato:  mensagem.NomeContato,
+		CobrancaID:   mensagem.CobrancaID,
+		Lida:         mensagem.Lida,
+		EnviadoPorID: mensagem.EnviadoPorID,
+		DataMensagem: mensagem.DataMensagem,
+	}
+}
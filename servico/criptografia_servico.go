@@ -0,0 +1,123 @@
+package servico
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/ifinu/ifinu-api-go/logs"
+	"github.com/ifinu/ifinu-api-go/repositorio"
+	"github.com/ifinu/ifinu-api-go/util"
+)
+
+type CriptografiaServico struct {
+	stripeConfigRepo *repositorio.StripeConfigRepositorio
+	webhookRepo      *repositorio.WebhookRepositorio
+	logger           *slog.Logger
+}
+
+func NovoCriptografiaServico(stripeConfigRepo *repositorio.StripeConfigRepositorio, webhookRepo *repositorio.WebhookRepositorio, logger *slog.Logger) *CriptografiaServico {
+	return &CriptografiaServico{
+		stripeConfigRepo: stripeConfigRepo,
+		webhookRepo:      webhookRepo,
+		logger:           logger,
+	}
+}
+
+// ResultadoRecriptografia conta os segredos regravados com a chave atual
+type ResultadoRecriptografia struct {
+	StripeConfigs    int
+	EndpointsWebhook int
+	// Segredos que nenhuma chave configurada abre (ficam como estão)
+	Falhas int
+}
+
+// ContarSegredosPorChave conta os segredos guardados (secret keys do Stripe e segredos dos
+// webhooks) por ID da chave. Segredos anteriores ao chaveiro aparecem com ID vazio.
+// Uma chave antiga só pode sair de ENCRYPTION_KEYS quando não tiver mais segredos.
+func (s *CriptografiaServico) ContarSegredosPorChave() (map[string]int, error) {
+	contagem := make(map[string]int)
+
+	configs, err := s.stripeConfigRepo.ListarTodas()
+	if err != nil {
+		return nil, err
+	}
+	for _, config := range configs {
+		contagem[util.IDChaveCifrado(config.SecretKeyEncrypted)]++
+	}
+
+	endpoints, err := s.webhookRepo.ListarTodosEndpoints()
+	if err != nil {
+		return nil, err
+	}
+	for _, endpoint := range endpoints {
+		contagem[util.IDChaveCifrado(endpoint.SegredoEncriptado)]++
+	}
+
+	return contagem, nil
+}
+
+// RecriptografarSegredos regrava com a chave atual do chaveiro os segredos cifrados com
+// outra chave ou sem ID. Cada segredo é trocado individualmente e só se não mudou desde a
+// leitura, então o job pode ser interrompido e repetido (ou rodar em várias réplicas).
+func (s *CriptografiaServico) RecriptografarSegredos(ctx context.Context) (*ResultadoRecriptografia, error) {
+	chaveiro := util.ObterChaveiro()
+	resultado := &ResultadoRecriptografia{}
+
+	configs, err := s.stripeConfigRepo.ComContexto(ctx).ListarTodas()
+	if err != nil {
+		return nil, err
+	}
+	for _, config := range configs {
+		if !chaveiro.PrecisaRecriptografar(config.SecretKeyEncrypted) {
+			continue
+		}
+
+		cifrado, err := recriptografar(chaveiro, config.SecretKeyEncrypted)
+		if err != nil {
+			s.logger.ErrorContext(logs.ComUsuarioID(ctx, config.UsuarioID), "❌ Configuração Stripe não recriptografada", logs.Erro(err))
+			resultado.Falhas++
+			continue
+		}
+		if err := s.stripeConfigRepo.ComContexto(ctx).TrocarSecretKeyCriptografada(config.ID, config.SecretKeyEncrypted, cifrado); err != nil {
+			return resultado, err
+		}
+		resultado.StripeConfigs++
+	}
+
+	endpoints, err := s.webhookRepo.ComContexto(ctx).ListarTodosEndpoints()
+	if err != nil {
+		return resultado, err
+	}
+	for _, endpoint := range endpoints {
+		if !chaveiro.PrecisaRecriptografar(endpoint.SegredoEncriptado) {
+			continue
+		}
+
+		cifrado, err := recriptografar(chaveiro, endpoint.SegredoEncriptado)
+		if err != nil {
+			s.logger.ErrorContext(logs.ComUsuarioID(ctx, endpoint.UsuarioID), "❌ Endpoint de webhook não recriptografado",
+				"endpoint_id", endpoint.ID, logs.Erro(err))
+			resultado.Falhas++
+			continue
+		}
+		if err := s.webhookRepo.ComContexto(ctx).TrocarSegredoEndpoint(endpoint.ID, endpoint.SegredoEncriptado, cifrado); err != nil {
+			return resultado, err
+		}
+		resultado.EndpointsWebhook++
+	}
+
+	if resultado.StripeConfigs > 0 || resultado.EndpointsWebhook > 0 {
+		s.logger.InfoContext(ctx, "🔐 Segredos recriptografados", "chave", chaveiro.IDAtual(),
+			"stripe_configs", resultado.StripeConfigs, "endpoints_webhook", resultado.EndpointsWebhook)
+	}
+	return resultado, nil
+}
+
+func recriptografar(chaveiro *util.Chaveiro, cifrado string) (string, error) {
+	texto, err := chaveiro.Descriptografar(cifrado)
+	if err != nil {
+		return "", fmt.Errorf("segredo não abre com as chaves configuradas: %w", err)
+	}
+	return chaveiro.Criptografar(texto)
+}
@@ -0,0 +1,244 @@
+package servico
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/ifinu/ifinu-api-go/dominio/entidades"
+	"github.com/ifinu/ifinu-api-go/logs"
+	"github.com/ifinu/ifinu-api-go/metricas"
+	"github.com/ifinu/ifinu-api-go/rastreamento"
+	"github.com/ifinu/ifinu-api-go/repositorio"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// Trava por job: PrefixoTravaJob+job guarda o token de quem está executando e
+	// PrefixoFencingJob+job é o contador que gera tokens crescentes
+	PrefixoTravaJob   = "ifinu:job:trava:"
+	PrefixoFencingJob = "ifinu:job:fencing:"
+	// TTLTravaJob limita quanto tempo a trava de uma réplica que caiu segura o job;
+	// enquanto o job roda ela é renovada a cada IntervaloRenovacaoTravaJob
+	TTLTravaJob                = 2 * time.Minute
+	IntervaloRenovacaoTravaJob = 30 * time.Second
+
+	RetencaoExecucoesJob = 30 * 24 * time.Hour
+)
+
+// Libera ou renova a trava apenas se ela ainda for do token informado
+var (
+	scriptLiberarTrava = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+	scriptRenovarTrava = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+)
+
+// ExecucaoJobServico garante que cada disparo de um job agendado rode uma única vez no
+// cluster. A trava no Redis evita que duas réplicas executem o mesmo job ao mesmo tempo;
+// a unicidade (job, horário) em execucoes_job é o fencing: mesmo que a trava expire no
+// meio da execução, outra réplica não consegue registrar (e rodar) o mesmo disparo.
+type ExecucaoJobServico struct {
+	execucaoRepo *repositorio.ExecucaoJobRepositorio
+	redisClient  *redis.Client
+	instanciaID  string
+	logger       *slog.Logger
+}
+
+// FuncaoJob é o corpo de um job agendado. ctx traz o request_id da execução, repassado às
+// mensagens enfileiradas e às chamadas externas feitas pelo job.
+type FuncaoJob func(ctx context.Context) (map[string]int, error)
+
+func NovoExecucaoJobServico(execucaoRepo *repositorio.ExecucaoJobRepositorio, redisAddr string, logger *slog.Logger) *ExecucaoJobServico {
+	ctx := context.Background()
+
+	s := &ExecucaoJobServico{
+		execucaoRepo: execucaoRepo,
+		instanciaID:  novoIDInstancia(),
+		logger:       logger,
+	}
+
+	redisClient := redis.NewClient(&redis.Options{
+		Addr:         redisAddr,
+		DialTimeout:  5 * time.Second,
+		ReadTimeout:  3 * time.Second,
+		WriteTimeout: 3 * time.Second,
+	})
+	redisClient.AddHook(rastreamento.HookRedis())
+
+	// Sem Redis continua valendo a unicidade por horário no banco
+	if err := redisClient.Ping(ctx).Err(); err != nil {
+		logger.Warn("⚠️  Redis não disponível para travas dos jobs. Apenas o banco evitará execuções repetidas", logs.Erro(err))
+		return s
+	}
+
+	s.redisClient = redisClient
+	return s
+}
+
+// Executar roda job para o disparo agendadoPara, a menos que outra réplica já o esteja
+// executando ou já o tenha executado. O resultado (contagens ou erro) fica em execucoes_job.
+// O job recebe ctx (cancelado no encerramento); o registro do resultado e a liberação da
+// trava acontecem mesmo com ctx cancelado.
+func (s *ExecucaoJobServico) Executar(ctx context.Context, nome string, agendadoPara time.Time, job FuncaoJob) {
+	// Cada execução abre um trace próprio, seguido pelas notificações que enfileirar
+	ctxJob, span := rastreamento.Iniciar(logs.ComRequestID(ctx, logs.NovoRequestID()), "job "+nome,
+		attribute.String("ifinu.job", nome))
+	defer span.End()
+	ctx = context.WithoutCancel(ctxJob)
+	logger := s.logger.With("job", nome, "agendado_para", agendadoPara)
+
+	token, ok := s.adquirirTrava(ctx, logger, nome)
+	if !ok {
+		logger.InfoContext(ctx, "⏭️  Job já em execução em outra réplica")
+		span.SetAttributes(attribute.String("ifinu.job.status", "ignorada"))
+		metricas.ExecucoesJob.WithLabelValues(nome, "ignorada").Inc()
+		return
+	}
+	defer s.liberarTrava(ctx, logger, nome, token)
+
+	execucao := &entidades.ExecucaoJob{
+		Job:          nome,
+		AgendadoPara: agendadoPara,
+		Instancia:    s.instanciaID,
+		TokenFencing: token,
+		Status:       entidades.StatusExecucaoEmAndamento,
+		Contagens:    "{}",
+		DataInicio:   time.Now(),
+	}
+	iniciada, err := s.execucaoRepo.ComContexto(ctx).Iniciar(execucao)
+	if err != nil {
+		logger.ErrorContext(ctx, "❌ Erro ao registrar execução do job", logs.Erro(err))
+		rastreamento.RegistrarErro(span, err)
+		return
+	}
+	if !iniciada {
+		logger.InfoContext(ctx, "⏭️  Job já executado por outra réplica")
+		span.SetAttributes(attribute.String("ifinu.job.status", "ignorada"))
+		metricas.ExecucoesJob.WithLabelValues(nome, "ignorada").Inc()
+		return
+	}
+
+	logger.InfoContext(ctx, "⏰ Executando job")
+	pararRenovacao := s.renovarTrava(ctx, logger, nome, token)
+	contagens, err := executarProtegido(ctxJob, job)
+	pararRenovacao()
+
+	fim := time.Now()
+	execucao.DataFim = &fim
+	execucao.Status = entidades.StatusExecucaoSucesso
+	if err != nil {
+		execucao.Status = entidades.StatusExecucaoFalha
+		execucao.Erro = err.Error()
+		logger.ErrorContext(ctx, "❌ Job falhou", logs.Erro(err))
+	} else {
+		logger.InfoContext(ctx, "✅ Job concluído", "contagens", contagens, "duracao_ms", execucao.Duracao().Milliseconds())
+	}
+	if contagens != nil {
+		if dados, err := json.Marshal(contagens); err == nil {
+			execucao.Contagens = string(dados)
+		}
+	}
+
+	if err := s.execucaoRepo.ComContexto(ctx).Finalizar(execucao); err != nil {
+		logger.ErrorContext(ctx, "❌ Erro ao registrar fim do job", logs.Erro(err))
+	}
+
+	status := strings.ToLower(string(execucao.Status))
+	span.SetAttributes(attribute.String("ifinu.job.status", status))
+	rastreamento.RegistrarErro(span, err)
+	metricas.ExecucoesJob.WithLabelValues(nome, status).Inc()
+	metricas.DuracaoJob.WithLabelValues(nome, status).Observe(execucao.Duracao().Seconds())
+	metricas.UltimaExecucaoJob.WithLabelValues(nome, status).Set(float64(fim.Unix()))
+}
+
+// executarProtegido converte um panic do job em erro, para que a execução seja registrada
+func executarProtegido(ctx context.Context, job FuncaoJob) (contagens map[string]int, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return job(ctx)
+}
+
+// adquirirTrava tenta pegar a trava do job com um token novo. Sem Redis retorna token 0.
+func (s *ExecucaoJobServico) adquirirTrava(ctx context.Context, logger *slog.Logger, nome string) (int64, bool) {
+	if s.redisClient == nil {
+		return 0, true
+	}
+
+	token, err := s.redisClient.Incr(ctx, PrefixoFencingJob+nome).Result()
+	if err != nil {
+		logger.WarnContext(ctx, "⚠️  Erro ao gerar token do job. Apenas o banco evitará execuções repetidas", logs.Erro(err))
+		return 0, true
+	}
+
+	adquirida, err := s.redisClient.SetNX(ctx, PrefixoTravaJob+nome, token, TTLTravaJob).Result()
+	if err != nil {
+		logger.WarnContext(ctx, "⚠️  Erro ao adquirir trava do job. Apenas o banco evitará execuções repetidas", logs.Erro(err))
+		return token, true
+	}
+	return token, adquirida
+}
+
+// renovarTrava estende a trava enquanto o job roda. A função retornada para a renovação.
+func (s *ExecucaoJobServico) renovarTrava(ctx context.Context, logger *slog.Logger, nome string, token int64) func() {
+	if s.redisClient == nil || token == 0 {
+		return func() {}
+	}
+
+	ctx, cancelar := context.WithCancel(ctx)
+	go func() {
+		ticker := time.NewTicker(IntervaloRenovacaoTravaJob)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			renovada, err := scriptRenovarTrava.Run(ctx, s.redisClient, []string{PrefixoTravaJob + nome}, token, TTLTravaJob.Milliseconds()).Int()
+			if err == nil && renovada == 0 {
+				logger.WarnContext(ctx, "⚠️  Trava do job perdida durante a execução", "token", token)
+				return
+			}
+		}
+	}()
+	return cancelar
+}
+
+func (s *ExecucaoJobServico) liberarTrava(ctx context.Context, logger *slog.Logger, nome string, token int64) {
+	if s.redisClient == nil || token == 0 {
+		return
+	}
+	if err := scriptLiberarTrava.Run(ctx, s.redisClient, []string{PrefixoTravaJob + nome}, token).Err(); err != nil {
+		logger.WarnContext(ctx, "⚠️  Erro ao liberar trava do job", logs.Erro(err))
+	}
+}
+
+// ListarExecucoes retorna as últimas execuções (de todos os jobs, se job for vazio)
+func (s *ExecucaoJobServico) ListarExecucoes(job string, limite int) ([]entidades.ExecucaoJob, error) {
+	return s.execucaoRepo.ListarRecentes(job, limite)
+}
+
+// LimparExecucoesAntigas apaga o histórico anterior a RetencaoExecucoesJob
+func (s *ExecucaoJobServico) LimparExecucoesAntigas(ctx context.Context) (map[string]int, error) {
+	removidas, err := s.execucaoRepo.ComContexto(ctx).RemoverAnteriores(time.Now().Add(-RetencaoExecucoesJob))
+	if err != nil {
+		return nil, err
+	}
+	return map[string]int{"removidas": int(removidas)}, nil
+}
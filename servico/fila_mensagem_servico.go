@@ -3,30 +3,117 @@ package servico
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
 	"github.com/ifinu/ifinu-api-go/dominio/entidades"
+	"github.com/ifinu/ifinu-api-go/dominio/enums"
+	"github.com/ifinu/ifinu-api-go/dto"
 	"github.com/ifinu/ifinu-api-go/integracao"
+	"github.com/ifinu/ifinu-api-go/logs"
+	"github.com/ifinu/ifinu-api-go/metricas"
+	"github.com/ifinu/ifinu-api-go/rastreamento"
+	"github.com/ifinu/ifinu-api-go/repositorio"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 	"golang.org/x/time/rate"
 )
 
 const (
 	FilaMensagensWhatsApp = "ifinu:fila:whatsapp"
 	FilaMensagensEmail    = "ifinu:fila:email"
+
+	// Mensagens adiadas (retry, silêncio, limite de envio, conta pausada) aguardam aqui até
+	// ProximaTentativa, em vez de circular pela fila; o score é o horário em Unix
+	FilaMensagensAgendadas = "ifinu:fila:whatsapp:agendadas"
+	IntervaloAgendadasFila = 5 * time.Second
 	MaxRetentativas       = 3
 	TempoRetry            = 5 * time.Minute
+
+	// Contas sem nenhum número WhatsApp conectado (mantido pelo monitor de conexões)
+	ContasPausadasWhatsApp = "ifinu:fila:contas-pausadas"
+	TempoContaPausada      = 5 * time.Minute
+	MaxEsperaContaPausada  = 24 * time.Hour
+
+	// Cada worker move (BLMOVE) a mensagem para a sua lista de processamento e só a remove
+	// depois de concluir. ListasProcessamentoWhatsApp liga cada lista à instância dona, que
+	// renova PrefixoInstanciaFila+id enquanto está viva; listas de instâncias sem batimento
+	// voltam para a fila.
+	PrefixoProcessamentoWhatsApp = "ifinu:fila:whatsapp:processando:"
+	ListasProcessamentoWhatsApp  = "ifinu:fila:whatsapp:listas-processamento"
+	PrefixoInstanciaFila         = "ifinu:fila:whatsapp:instancia:"
+	TTLInstanciaFila             = 1 * time.Minute
+	IntervaloBatimentoFila       = 15 * time.Second
+	IntervaloRecuperacaoFila     = 1 * time.Minute
+
+	// Sem nenhum worker voltando a buscar mensagens por esse tempo, a liveness falha
+	LimiteInatividadeWorkers = 10 * time.Minute
+
+	// Idempotência do envio: PrefixoEnvioNotificacao+chave+":"+canal fica "enviando" durante
+	// a tentativa e "enviado" depois dela, para que um reprocessamento não repita o envio.
+	// O worker renova o "enviando" a cada IntervaloRenovacaoEnvio enquanto o envio dura
+	// (espera do limite, timeouts e failover); se o processo cair, a chave expira.
+	PrefixoEnvioNotificacao = "ifinu:notificacao:"
+	TTLEnvioEmAndamento     = 5 * time.Minute
+	IntervaloRenovacaoEnvio = 1 * time.Minute
+	TTLEnvioConcluido       = 7 * 24 * time.Hour
+)
+
+const (
+	canalWhatsApp = "whatsapp"
+	canalEmail    = "email"
+
+	estadoEnviando = "enviando"
+	estadoEnviado  = "enviado"
+	estadoIncerto  = "incerto" // A tentativa falhou sem garantia de que a mensagem não saiu
+)
+
+// resultadoEnvio é o desfecho de uma tentativa de envio por um canal
+type resultadoEnvio int
+
+const (
+	envioRealizado    resultadoEnvio = iota
+	envioNaoRealizado                // Certamente não saiu: pode ser tentado de novo
+	envioIncerto                     // Pode ter saído (timeout, 5xx): não é repetido
 )
 
 type MensagemFila struct {
 	ID              string                `json:"id"`
-	TipoNotificacao string                `json:"tipo_notificacao"` // "lembrete", "vencimento", "pagamento"
+	TipoNotificacao enums.TipoNotificacao `json:"tipo_notificacao"`
 	Cobranca        *entidades.Cobranca   `json:"cobranca"`
 	Tentativas      int                   `json:"tentativas"`
 	ProximaTentativa time.Time            `json:"proxima_tentativa"`
 	CriadoEm        time.Time             `json:"criado_em"`
+
+	// Identifica a notificação (tipo, cobrança e vencimento) para a idempotência do envio;
+	// cópias reenfileiradas ou enfileiradas de novo compartilham a mesma chave
+	ChaveIdempotencia string `json:"chave_idempotencia,omitempty"`
+
+	// ID da requisição ou execução de job que enfileirou a notificação, repassado aos logs
+	// do worker e às chamadas para a Evolution API e o Resend
+	RequestID string `json:"request_id,omitempty"`
+
+	// Trace de quem enfileirou (traceparent W3C), continuado pelo worker para que uma
+	// notificação possa ser seguida do job até a entrega
+	Rastreamento map[string]string `json:"rastreamento,omitempty"`
+
+	// Conteúdo do WhatsApp (texto, anexos e botões) montado no enfileiramento.
+	// Mensagens antigas sem conteúdo usam o texto padrão do tipo de notificação.
+	Mensagem *dto.MensagemWhatsApp `json:"mensagem,omitempty"`
+
+	// Canais já entregues, para que um retry não repita o que deu certo
+	WhatsAppEnviado bool `json:"whatsapp_enviado"`
+	EmailEnviado    bool `json:"email_enviado"`
+
+	// Espera pedida pelo limite de envio dos números (não conta como tentativa)
+	adiarPor time.Duration
 }
 
 type FilaMensagemServico struct {
@@ -35,12 +122,27 @@ type FilaMensagemServico struct {
 	rateLimiter  *rate.Limiter
 	whatsappSvc  *WhatsAppServico
 	emailSvc     *integracao.ResendCliente
+	cobrancaRepo *repositorio.CobrancaRepositorio
+	logger       *slog.Logger
+
+	// Listas de processamento dos workers desta instância
+	instanciaID         string
+	listasProcessamento []string
+
+	// Encerramento: ctxWorkers é cancelado em Parar
+	ctxWorkers    context.Context
+	cancelar      context.CancelFunc
+	workers       sync.WaitGroup
+	workersAtivos atomic.Int32
+	ultimoCiclo   atomic.Int64 // Unix da última vez que um worker foi buscar mensagem
 }
 
 func NovoFilaMensagemServico(
 	redisAddr string,
 	whatsappSvc *WhatsAppServico,
 	emailSvc *integracao.ResendCliente,
+	cobrancaRepo *repositorio.CobrancaRepositorio,
+	logger *slog.Logger,
 ) *FilaMensagemServico {
 	ctx := context.Background()
 
@@ -54,17 +156,20 @@ func NovoFilaMensagemServico(
 		PoolSize:     100,
 		MinIdleConns: 10,
 	})
+	redisClient.AddHook(rastreamento.HookRedis())
 
 	// Testar conexão
 	if err := redisClient.Ping(ctx).Err(); err != nil {
-		log.Printf("⚠️  Redis não disponível: %v. Fila desabilitada.", err)
+		logger.Warn("⚠️  Redis não disponível. Fila desabilitada", logs.Erro(err))
 		return nil
 	}
 
-	// Rate Limiter: 50 mensagens/segundo
+	// Rate Limiter global: 50 mensagens/segundo (o ritmo por número fica no LimitadorWhatsApp)
 	limiter := rate.NewLimiter(rate.Limit(50), 100) // 50 req/s, burst de 100
 
-	log.Println("✅ Fila de mensagens Redis conectada")
+	logger.Info("✅ Fila de mensagens Redis conectada")
+
+	ctxWorkers, cancelar := context.WithCancel(ctx)
 
 	return &FilaMensagemServico{
 		redisClient: redisClient,
@@ -72,17 +177,60 @@ func NovoFilaMensagemServico(
 		rateLimiter: limiter,
 		whatsappSvc: whatsappSvc,
 		emailSvc:    emailSvc,
+
+		cobrancaRepo: cobrancaRepo,
+		logger:       logger,
+
+		instanciaID: novoIDInstancia(),
+
+		ctxWorkers: ctxWorkers,
+		cancelar:   cancelar,
+	}
+}
+
+// novoIDInstancia identifica o processo (hostname do pod + sufixo aleatório, para que um
+// pod reiniciado não se confunda com a execução anterior)
+func novoIDInstancia() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "ifinu"
 	}
+	return hostname + "-" + uuid.NewString()[:8]
 }
 
-// EnfileirarMensagem adiciona mensagem na fila para processamento assíncrono
-func (s *FilaMensagemServico) EnfileirarMensagem(msg *MensagemFila) error {
+// ChaveIdempotenciaNotificacao identifica uma notificação: o mesmo tipo, para a mesma
+// cobrança e o mesmo vencimento, é enviado uma única vez
+func ChaveIdempotenciaNotificacao(cobranca *entidades.Cobranca, tipo enums.TipoNotificacao) string {
+	return fmt.Sprintf("%s:%s:%s", tipo, cobranca.ID, cobranca.DataVencimento.Format("2006-01-02"))
+}
+
+// EnfileirarMensagem adiciona mensagem na fila para processamento assíncrono. O request_id
+// e o trace de ctx seguem com a mensagem até o worker.
+func (s *FilaMensagemServico) EnfileirarMensagem(ctx context.Context, msg *MensagemFila) (err error) {
 	if s == nil || s.redisClient == nil {
 		return fmt.Errorf("fila não inicializada")
 	}
 
+	ctx, span := rastreamento.Iniciar(ctx, "fila.enfileirar "+FilaMensagensWhatsApp,
+		semconv.MessagingSystemKey.String("redis"),
+		semconv.MessagingDestinationName(FilaMensagensWhatsApp),
+		semconv.MessagingOperationTypePublish,
+		attribute.String("ifinu.tipo_notificacao", string(msg.TipoNotificacao)),
+		attribute.String("ifinu.cobranca_id", msg.Cobranca.ID.String()),
+	)
+	defer func() { rastreamento.Finalizar(span, err) }()
+
 	msg.CriadoEm = time.Now()
 	msg.ProximaTentativa = time.Now()
+	if msg.ChaveIdempotencia == "" {
+		msg.ChaveIdempotencia = ChaveIdempotenciaNotificacao(msg.Cobranca, msg.TipoNotificacao)
+	}
+	if msg.RequestID == "" {
+		msg.RequestID = logs.RequestID(ctx)
+	}
+	if msg.Rastreamento == nil {
+		msg.Rastreamento = rastreamento.Injetar(ctx)
+	}
 
 	data, err := json.Marshal(msg)
 	if err != nil {
@@ -90,86 +238,286 @@ func (s *FilaMensagemServico) EnfileirarMensagem(msg *MensagemFila) error {
 	}
 
 	// Adicionar na fila Redis (LPUSH = adiciona no início)
-	err = s.redisClient.LPush(s.ctx, FilaMensagensWhatsApp, data).Err()
+	err = s.redisClient.LPush(ctx, FilaMensagensWhatsApp, data).Err()
 	if err != nil {
 		return fmt.Errorf("erro ao enfileirar mensagem: %w", err)
 	}
 
-	log.Printf("📥 Mensagem enfileirada: %s (Cobrança ID: %d)", msg.TipoNotificacao, msg.Cobranca.ID)
+	s.logger.InfoContext(ctx, "📥 Mensagem enfileirada", "tipo", msg.TipoNotificacao, "cobranca_id", msg.Cobranca.ID)
 	return nil
 }
 
 // IniciarWorkerPool inicia pool de workers para processar fila
 func (s *FilaMensagemServico) IniciarWorkerPool(numWorkers int) {
 	if s == nil || s.redisClient == nil {
-		log.Println("⚠️  Fila não disponível. Worker pool desabilitado.")
+		slog.Warn("⚠️  Fila não disponível. Worker pool desabilitado")
 		return
 	}
 
-	log.Printf("🚀 Iniciando %d workers para processar fila de mensagens", numWorkers)
+	s.logger.Info("🚀 Iniciando workers da fila de mensagens", "workers", numWorkers, "instancia_fila", s.instanciaID)
 
+	// Batimento antes de registrar as listas, para que o recuperador não as considere órfãs
+	s.renovarBatimento()
+	s.listasProcessamento = make([]string, numWorkers)
 	for i := 1; i <= numWorkers; i++ {
-		go s.worker(i)
+		lista := fmt.Sprintf("%s%s:%d", PrefixoProcessamentoWhatsApp, s.instanciaID, i)
+		s.listasProcessamento[i-1] = lista
+		if err := s.redisClient.HSet(s.ctx, ListasProcessamentoWhatsApp, lista, s.instanciaID).Err(); err != nil {
+			s.logger.Error("❌ Erro ao registrar lista de processamento", "lista", lista, logs.Erro(err))
+		}
 	}
 
+	s.ultimoCiclo.Store(time.Now().Unix())
+	s.workers.Add(numWorkers)
+	for i := 1; i <= numWorkers; i++ {
+		go s.worker(i, s.listasProcessamento[i-1])
+	}
+
+	go s.manterBatimento()
+	go s.recuperarMensagensOrfas()
+	go s.moverAgendadas()
+
 	// Worker para limpar mensagens antigas
 	go s.limparMensagensAntigas()
 }
 
-// worker processa mensagens da fila
-func (s *FilaMensagemServico) worker(id int) {
-	log.Printf("👷 Worker %d iniciado", id)
+// Parar deixa de buscar mensagens e aguarda os workers concluírem as que estão em
+// processamento (entregues ou reenfileiradas). Se ctx expirar antes, devolve à fila
+// as mensagens ainda nas listas de processamento; a idempotência do envio evita que
+// a réplica que as pegar repita o que já foi entregue.
+func (s *FilaMensagemServico) Parar(ctx context.Context) error {
+	if s == nil || s.redisClient == nil {
+		return nil
+	}
+
+	s.cancelar()
+
+	concluido := make(chan struct{})
+	go func() {
+		s.workers.Wait()
+		close(concluido)
+	}()
+
+	esgotado := false
+	select {
+	case <-concluido:
+		s.logger.Info("✅ Workers da fila de mensagens encerrados")
+	case <-ctx.Done():
+		esgotado = true
+	}
+
+	// Mesmo com os workers encerrados pode sobrar mensagem movida por um BLMOVE interrompido
+	devolvidas := 0
+	for _, lista := range s.listasProcessamento {
+		n, err := devolverListaProcessamento(s.ctx, s.redisClient, lista)
+		devolvidas += n
+		if err != nil {
+			s.logger.Error("❌ Erro ao devolver mensagens à fila", "lista", lista, logs.Erro(err))
+			continue
+		}
+		s.redisClient.HDel(s.ctx, ListasProcessamentoWhatsApp, lista)
+	}
+	s.redisClient.Del(s.ctx, PrefixoInstanciaFila+s.instanciaID)
+
+	if esgotado {
+		return fmt.Errorf("prazo de encerramento esgotado: %d mensagem(ns) em andamento devolvida(s) à fila", devolvidas)
+	}
+	return nil
+}
+
+// worker processa mensagens da fila. Cada mensagem fica na lista de processamento do
+// worker até ser concluída (entregue, descartada ou reenfileirada).
+func (s *FilaMensagemServico) worker(id int, lista string) {
+	defer s.workers.Done()
+	s.workersAtivos.Add(1)
+	defer s.workersAtivos.Add(-1)
+	s.logger.Debug("👷 Worker iniciado", "worker", id)
 
 	for {
+		if s.ctxWorkers.Err() != nil {
+			s.logger.Debug("👷 Worker encerrado", "worker", id)
+			return
+		}
+		s.ultimoCiclo.Store(time.Now().Unix())
+
 		// Aguardar rate limiter
-		if err := s.rateLimiter.Wait(s.ctx); err != nil {
-			log.Printf("❌ Worker %d: erro no rate limiter: %v", id, err)
+		if err := s.rateLimiter.Wait(s.ctxWorkers); err != nil {
+			if s.ctxWorkers.Err() != nil {
+				continue
+			}
+			s.logger.Error("❌ Erro no rate limiter", "worker", id, logs.Erro(err))
 			time.Sleep(1 * time.Second)
 			continue
 		}
 
-		// Buscar próxima mensagem da fila (BRPOP = bloqueante, aguarda até ter mensagem)
-		result, err := s.redisClient.BRPop(s.ctx, 5*time.Second, FilaMensagensWhatsApp).Result()
+		// Buscar próxima mensagem da fila (BLMOVE = bloqueante, move para a lista de processamento)
+		dados, err := s.redisClient.BLMove(s.ctxWorkers, FilaMensagensWhatsApp, lista, "RIGHT", "LEFT", 5*time.Second).Result()
 		if err == redis.Nil {
 			// Timeout, nenhuma mensagem disponível
 			continue
 		}
 		if err != nil {
-			log.Printf("❌ Worker %d: erro ao buscar mensagem: %v", id, err)
+			if s.ctxWorkers.Err() != nil {
+				continue
+			}
+			s.logger.Error("❌ Erro ao buscar mensagem", "worker", id, logs.Erro(err))
 			time.Sleep(1 * time.Second)
 			continue
 		}
 
-		// result[0] = nome da fila, result[1] = dados
-		if len(result) < 2 {
-			continue
-		}
+		inicio := time.Now()
 
 		var msg MensagemFila
-		if err := json.Unmarshal([]byte(result[1]), &msg); err != nil {
-			log.Printf("❌ Worker %d: erro ao deserializar mensagem: %v", id, err)
+		if err := json.Unmarshal([]byte(dados), &msg); err != nil {
+			s.logger.Error("❌ Mensagem inválida descartada", "worker", id, logs.Erro(err))
+			s.concluirMensagem(lista, dados, nil)
+			metricas.ProcessamentoFila.WithLabelValues("invalida").Observe(time.Since(inicio).Seconds())
 			continue
 		}
 
-		// Processar mensagem
-		s.processarMensagem(id, &msg)
+		// Processar mensagem (a partir daqui o encerramento aguarda a conclusão, então o
+		// contexto da mensagem não deriva de ctxWorkers)
+		if s.processarMensagem(s.contextoMensagem(&msg), id, &msg) {
+			s.concluirMensagem(lista, dados, &msg)
+			metricas.ProcessamentoFila.WithLabelValues("reenfileirada").Observe(time.Since(inicio).Seconds())
+		} else {
+			s.concluirMensagem(lista, dados, nil)
+			metricas.ProcessamentoFila.WithLabelValues("concluida").Observe(time.Since(inicio).Seconds())
+		}
+	}
+}
+
+// verificarWorkers indica se os workers desta réplica continuam consumindo a fila. Um
+// worker preso em um envio não volta a buscar mensagens; só falha quando todos ficam
+// presos por mais de LimiteInatividadeWorkers.
+func (s *FilaMensagemServico) verificarWorkers() error {
+	if s.ctxWorkers.Err() != nil {
+		return nil // Encerrando
+	}
+	esperados := len(s.listasProcessamento)
+	if esperados == 0 {
+		return errors.New("workers não iniciados")
+	}
+	if ativos := int(s.workersAtivos.Load()); ativos < esperados {
+		return fmt.Errorf("%d de %d workers ativos", ativos, esperados)
+	}
+	if parado := time.Since(time.Unix(s.ultimoCiclo.Load(), 0)); parado > LimiteInatividadeWorkers {
+		return fmt.Errorf("nenhum worker buscou mensagens nos últimos %s", parado.Truncate(time.Second))
 	}
+	return nil
 }
 
-// processarMensagem processa uma mensagem individual
-func (s *FilaMensagemServico) processarMensagem(workerID int, msg *MensagemFila) {
-	log.Printf("⚙️  Worker %d processando: %s (Tentativa %d/%d)",
-		workerID, msg.TipoNotificacao, msg.Tentativas+1, MaxRetentativas)
+// concluirMensagem confirma a mensagem, removendo-a da lista de processamento. Com
+// reenfileirar, a nova versão volta para a fila na mesma transação (ou para as agendadas,
+// se a próxima tentativa ainda não chegou).
+func (s *FilaMensagemServico) concluirMensagem(lista, dados string, reenfileirar *MensagemFila) {
+	var novaVersao []byte
+	if reenfileirar != nil {
+		var err error
+		novaVersao, err = json.Marshal(reenfileirar)
+		if err != nil {
+			// Sem a nova versão, a original fica na lista e volta para a fila pelo recuperador
+			s.logger.Error("❌ Erro ao re-enfileirar mensagem", logs.Erro(err))
+			return
+		}
+	}
 
-	// Verificar se já passou o tempo de retry
+	_, err := s.redisClient.TxPipelined(s.ctx, func(pipe redis.Pipeliner) error {
+		switch {
+		case novaVersao == nil:
+		case reenfileirar.ProximaTentativa.After(time.Now()):
+			pipe.ZAdd(s.ctx, FilaMensagensAgendadas, &redis.Z{
+				Score:  float64(reenfileirar.ProximaTentativa.Unix()),
+				Member: novaVersao,
+			})
+		default:
+			// Adicionar no final da fila (LPUSH)
+			pipe.LPush(s.ctx, FilaMensagensWhatsApp, novaVersao)
+		}
+		pipe.LRem(s.ctx, lista, 1, dados)
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("❌ Erro ao confirmar mensagem", "lista", lista, logs.Erro(err))
+	}
+}
+
+// contextoMensagem monta o contexto do processamento com o request_id e o trace de quem
+// enfileirou (ou o ID da mensagem, nas enfileiradas antes dele) e a conta dona da cobrança
+func (s *FilaMensagemServico) contextoMensagem(msg *MensagemFila) context.Context {
+	requestID := msg.RequestID
+	if requestID == "" {
+		requestID = msg.ID
+	}
+	ctx := logs.ComRequestID(rastreamento.Extrair(s.ctx, msg.Rastreamento), requestID)
+	if msg.Cobranca != nil {
+		ctx = logs.ComUsuarioID(ctx, msg.Cobranca.UsuarioID)
+	}
+	return ctx
+}
+
+// processarMensagem processa uma mensagem individual. Retorna true quando a mensagem
+// deve voltar para a fila (retry, silêncio, limite de envio ou conta pausada).
+func (s *FilaMensagemServico) processarMensagem(ctx context.Context, workerID int, msg *MensagemFila) bool {
+	logger := s.logger.With("worker", workerID, "tipo", msg.TipoNotificacao, "cobranca_id", msg.Cobranca.ID)
+	logger.DebugContext(ctx, "⚙️  Processando mensagem", "tentativa", msg.Tentativas+1, "max_tentativas", MaxRetentativas)
+
+	// Verificar se já passou o tempo de retry (o score das agendadas é arredondado para
+	// segundos, então a mensagem pode chegar alguns instantes antes)
 	if time.Now().Before(msg.ProximaTentativa) {
 		// Re-enfileirar para processar depois
-		s.reenfileirarMensagem(msg)
-		return
+		return true
+	}
+	metricas.EsperaFila.Observe(time.Since(msg.ProximaTentativa).Seconds())
+
+	// Cada tentativa é um span filho do enfileiramento
+	ctx, span := rastreamento.Iniciar(ctx, "fila.processar "+FilaMensagensWhatsApp,
+		semconv.MessagingSystemKey.String("redis"),
+		semconv.MessagingDestinationName(FilaMensagensWhatsApp),
+		semconv.MessagingOperationTypeDeliver,
+		attribute.String("ifinu.tipo_notificacao", string(msg.TipoNotificacao)),
+		attribute.String("ifinu.cobranca_id", msg.Cobranca.ID.String()),
+		attribute.Int("ifinu.tentativa", msg.Tentativas+1),
+	)
+	defer span.End()
+
+	// Mensagens enfileiradas antes da chave de idempotência
+	if msg.ChaveIdempotencia == "" {
+		msg.ChaveIdempotencia = ChaveIdempotenciaNotificacao(msg.Cobranca, msg.TipoNotificacao)
+	}
+
+	// Preferências atuais do cliente (podem ter mudado depois do enfileiramento)
+	if cobranca, err := s.cobrancaRepo.ComContexto(ctx).BuscarPorID(msg.Cobranca.ID, msg.Cobranca.UsuarioID); err == nil {
+		msg.Cobranca = cobranca
 	}
+	cliente := &msg.Cobranca.Cliente
+
+	if msg.Cobranca.IsPaga() || !cliente.RecebeLembretes() {
+		logger.InfoContext(ctx, "🔕 Notificação descartada (cobrança paga ou cliente sem lembretes)", "cliente_id", cliente.ID)
+		return false
+	}
+
+	// WhatsApp da conta fora do ar: entrega o email e aguarda a reconexão sem gastar tentativas
+	if cliente.RecebePorWhatsApp() && !msg.WhatsAppEnviado && s.contaPausada(msg.Cobranca.UsuarioID) {
+		return s.aguardarReconexao(ctx, logger, msg)
+	}
+
+	if fim, silencio := cliente.FimDoSilencio(time.Now()); silencio {
+		msg.ProximaTentativa = fim
+		logger.InfoContext(ctx, "🌙 Cliente em horário de silêncio. Envio adiado", "proxima_tentativa", fim)
+		return true
+	}
+
+	// Enviar pelos canais aceitos pelo cliente
+	sucesso := s.enviarNotificacao(ctx, msg)
 
-	// Enviar mensagem WhatsApp
-	sucesso := s.enviarWhatsApp(msg)
+	if !sucesso && msg.adiarPor > 0 {
+		msg.ProximaTentativa = time.Now().Add(msg.adiarPor)
+		msg.adiarPor = 0
+		logger.InfoContext(ctx, "⏳ Envio adiado (limite dos números ou envio em andamento em outro worker)",
+			"proxima_tentativa", msg.ProximaTentativa)
+		return true
+	}
 
 	if !sucesso {
 		msg.Tentativas++
@@ -177,92 +525,369 @@ func (s *FilaMensagemServico) processarMensagem(workerID int, msg *MensagemFila)
 			// Re-enfileirar com delay exponencial
 			delay := time.Duration(msg.Tentativas*msg.Tentativas) * TempoRetry
 			msg.ProximaTentativa = time.Now().Add(delay)
-			s.reenfileirarMensagem(msg)
-			log.Printf("🔄 Worker %d: Re-enfileirando mensagem. Próxima tentativa em %v",
-				workerID, delay)
-		} else {
-			log.Printf("❌ Worker %d: Mensagem descartada após %d tentativas falhas",
-				workerID, MaxRetentativas)
-			// TODO: Salvar em DLQ (Dead Letter Queue) para análise
+			logger.WarnContext(ctx, "🔄 Re-enfileirando mensagem", "tentativa", msg.Tentativas, "atraso", delay.String())
+			return true
 		}
-	} else {
-		log.Printf("✅ Worker %d: Mensagem processada com sucesso", workerID)
+		logger.ErrorContext(ctx, "❌ Mensagem descartada após tentativas falhas", "tentativas", MaxRetentativas)
+		// TODO: Salvar em DLQ (Dead Letter Queue) para análise
+		return false
 	}
+
+	logger.InfoContext(ctx, "✅ Mensagem processada com sucesso")
+	return false
 }
 
-// enviarWhatsApp envia mensagem via WhatsApp
-func (s *FilaMensagemServico) enviarWhatsApp(msg *MensagemFila) bool {
-	cobranca := msg.Cobranca
+// aguardarReconexao adia a mensagem de uma conta pausada, descartando-a após MaxEsperaContaPausada.
+// Retorna true quando a mensagem deve voltar para a fila.
+func (s *FilaMensagemServico) aguardarReconexao(ctx context.Context, logger *slog.Logger, msg *MensagemFila) bool {
+	cliente := &msg.Cobranca.Cliente
 
-	// VALIDAÇÃO CRÍTICA: Verificar isolamento de dados
-	if cobranca.UsuarioID.String() == "00000000-0000-0000-0000-000000000000" {
-		log.Printf("⛔ SEGURANÇA: Cobrança %d sem usuário associado na fila", cobranca.ID)
+	if cliente.RecebePorEmail() && !msg.EmailEnviado {
+		if _, silencio := cliente.FimDoSilencio(time.Now()); !silencio {
+			msg.EmailEnviado = s.enviarUmaVez(ctx, msg, canalEmail, s.enviarEmail)
+		}
+	}
+
+	if time.Since(msg.CriadoEm) > MaxEsperaContaPausada {
+		logger.ErrorContext(ctx, "❌ WhatsApp da conta desconectado por tempo demais. Mensagem descartada",
+			"espera_maxima", MaxEsperaContaPausada.String())
+		return false
+	}
+
+	msg.ProximaTentativa = time.Now().Add(TempoContaPausada)
+	logger.InfoContext(ctx, "⏸️  WhatsApp da conta desconectado. Envio adiado", "proxima_tentativa", msg.ProximaTentativa)
+	return true
+}
+
+// DefinirContasPausadas substitui as contas cujo envio de WhatsApp está pausado.
+// O conjunto fica no Redis para valer em todas as réplicas.
+func (s *FilaMensagemServico) DefinirContasPausadas(usuarioIDs []uuid.UUID) error {
+	if s == nil || s.redisClient == nil {
+		return fmt.Errorf("fila não inicializada")
+	}
+
+	_, err := s.redisClient.TxPipelined(s.ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(s.ctx, ContasPausadasWhatsApp)
+		for _, usuarioID := range usuarioIDs {
+			pipe.SAdd(s.ctx, ContasPausadasWhatsApp, usuarioID.String())
+		}
+		return nil
+	})
+	return err
+}
+
+// contaPausada verifica se a conta está sem nenhum número WhatsApp conectado
+func (s *FilaMensagemServico) contaPausada(usuarioID uuid.UUID) bool {
+	pausada, err := s.redisClient.SIsMember(s.ctx, ContasPausadasWhatsApp, usuarioID.String()).Result()
+	return err == nil && pausada
+}
+
+// enviarNotificacao envia pelos canais aceitos pelo cliente que ainda não foram entregues.
+// Retorna true quando todos os canais necessários foram entregues.
+func (s *FilaMensagemServico) enviarNotificacao(ctx context.Context, msg *MensagemFila) bool {
+	cliente := &msg.Cobranca.Cliente
+
+	if cliente.RecebePorWhatsApp() && !msg.WhatsAppEnviado {
+		msg.WhatsAppEnviado = s.enviarUmaVez(ctx, msg, canalWhatsApp, s.enviarWhatsApp)
+	}
+	if cliente.RecebePorEmail() && !msg.EmailEnviado {
+		msg.EmailEnviado = s.enviarUmaVez(ctx, msg, canalEmail, s.enviarEmail)
+	}
+
+	whatsappOK := msg.WhatsAppEnviado || !cliente.RecebePorWhatsApp()
+	emailOK := msg.EmailEnviado || !cliente.RecebePorEmail()
+	return whatsappOK && emailOK
+}
+
+// enviarUmaVez envia pelo canal apenas se a notificação ainda não foi entregue por ele.
+// A chave fica "enviando" durante a tentativa (renovada enquanto ela durar): outra cópia
+// da mensagem (reprocessada após uma queda) é adiada em vez de enviar em paralelo. Se o
+// processo cair no meio do envio, a chave expira em TTLEnvioEmAndamento e o envio é
+// tentado de novo. Só uma falha em que a mensagem certamente não saiu libera a chave para
+// retry; com resultado incerto o canal é dado como concluído, para não duplicar a mensagem.
+func (s *FilaMensagemServico) enviarUmaVez(ctx context.Context, msg *MensagemFila, canal string, enviar func(context.Context, *MensagemFila) resultadoEnvio) bool {
+	chave := PrefixoEnvioNotificacao + msg.ChaveIdempotencia + ":" + canal
+
+	reservada, err := s.redisClient.SetNX(ctx, chave, estadoEnviando, TTLEnvioEmAndamento).Result()
+	if err != nil {
+		s.logger.ErrorContext(ctx, "❌ Erro ao reservar envio", "chave", chave, logs.Erro(err))
+		return false
+	}
+	if !reservada {
+		if estado, _ := s.redisClient.Get(ctx, chave).Result(); estado == estadoEnviado || estado == estadoIncerto {
+			s.logger.InfoContext(ctx, "⏭️  Notificação já enviada", "chave_idempotencia", msg.ChaveIdempotencia, "canal", canal, "estado", estado)
+			return true
+		}
+		msg.adiarPor = TTLEnvioEmAndamento
 		return false
 	}
 
-	log.Printf("📤 [FILA] Enviando %s: Usuário=%s, Cliente=%s (ID:%d), Telefone=%s",
-		msg.TipoNotificacao, cobranca.UsuarioID, cobranca.Cliente.Nome, cobranca.ClienteID, cobranca.Cliente.Telefone)
+	pararRenovacao := s.renovarEnvioEmAndamento(ctx, chave)
+	resultado := enviar(ctx, msg)
+	pararRenovacao()
+
+	estado := estadoEnviado
+	switch resultado {
+	case envioNaoRealizado:
+		s.redisClient.Del(ctx, chave)
+		return false
+	case envioIncerto:
+		estado = estadoIncerto
+		s.logger.WarnContext(ctx, "⚠️  Resultado incerto do envio. Não será repetido para não duplicar a mensagem",
+			"chave_idempotencia", msg.ChaveIdempotencia, "canal", canal)
+	}
+
+	if err := s.redisClient.Set(ctx, chave, estado, TTLEnvioConcluido).Err(); err != nil {
+		s.logger.ErrorContext(ctx, "❌ Erro ao registrar envio", "chave", chave, logs.Erro(err))
+	}
+	return true
+}
+
+// scriptRenovarEnvio estende a chave de idempotência apenas enquanto ela está "enviando"
+var scriptRenovarEnvio = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
 
-	// Montar mensagem baseada no tipo
-	var textoMensagem string
+// renovarEnvioEmAndamento mantém a chave "enviando" enquanto o envio dura. A função
+// retornada para a renovação.
+func (s *FilaMensagemServico) renovarEnvioEmAndamento(ctx context.Context, chave string) func() {
+	ctx, cancelar := context.WithCancel(ctx)
+	go func() {
+		ticker := time.NewTicker(IntervaloRenovacaoEnvio)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			err := scriptRenovarEnvio.Run(ctx, s.redisClient, []string{chave}, estadoEnviando, TTLEnvioEmAndamento.Milliseconds()).Err()
+			if err != nil && ctx.Err() == nil {
+				s.logger.WarnContext(ctx, "⚠️  Erro ao renovar envio em andamento", "chave", chave, logs.Erro(err))
+			}
+		}
+	}()
+	return cancelar
+}
+
+// enviarEmail envia o email da notificação com o link de descadastro. Falhas do Resend são
+// tentadas de novo.
+func (s *FilaMensagemServico) enviarEmail(ctx context.Context, msg *MensagemFila) resultadoEnvio {
+	cobranca := msg.Cobranca
+	link := linkDescadastro(cobranca.ClienteID)
+
+	var err error
 	switch msg.TipoNotificacao {
-	case "lembrete":
-		textoMensagem = fmt.Sprintf(
-			"🔔 *Lembrete de Cobrança*\n\n"+
-				"Olá, %s!\n\n"+
-				"Sua cobrança vence em 3 dias:\n"+
-				"💰 Valor: R$ %.2f\n"+
-				"📝 Descrição: %s\n"+
-				"📅 Vencimento: %s\n\n"+
-				"Atenciosamente,\nEquipe IFINU",
+	case enums.TipoNotificacaoLembrete:
+		err = s.emailSvc.EnviarEmailLembrete(ctx,
+			cobranca.Cliente.Email,
 			cobranca.Cliente.Nome,
-			cobranca.Valor,
 			cobranca.Descricao,
+			cobranca.Valor,
 			cobranca.DataVencimento.Format("02/01/2006"),
+			link,
 		)
-	case "vencimento":
-		textoMensagem = fmt.Sprintf(
-			"⚠️ *Cobrança Vence Hoje*\n\n"+
-				"Olá, %s!\n\n"+
-				"Sua cobrança vence HOJE:\n"+
-				"💰 Valor: R$ %.2f\n"+
-				"📝 Descrição: %s\n\n"+
-				"Atenciosamente,\nEquipe IFINU",
+	case enums.TipoNotificacaoVencimento:
+		err = s.emailSvc.EnviarEmailVencimento(ctx,
+			cobranca.Cliente.Email,
 			cobranca.Cliente.Nome,
-			cobranca.Valor,
 			cobranca.Descricao,
+			cobranca.Valor,
+			link,
 		)
 	default:
-		log.Printf("⚠️  Tipo de notificação desconhecido: %s", msg.TipoNotificacao)
-		return false
+		s.logger.WarnContext(ctx, "⚠️  Tipo de notificação desconhecido", "tipo", msg.TipoNotificacao)
+		return envioNaoRealizado
+	}
+
+	if err != nil {
+		s.logger.ErrorContext(ctx, "❌ Erro ao enviar email", "cliente_id", cobranca.ClienteID, logs.Erro(err))
+		return envioNaoRealizado
+	}
+
+	s.logger.InfoContext(ctx, "✅ Email enviado", "cliente_id", cobranca.ClienteID, "email", cobranca.Cliente.Email)
+	return envioRealizado
+}
+
+// enviarWhatsApp envia mensagem via WhatsApp
+func (s *FilaMensagemServico) enviarWhatsApp(ctx context.Context, msg *MensagemFila) resultadoEnvio {
+	cobranca := msg.Cobranca
+
+	// VALIDAÇÃO CRÍTICA: Verificar isolamento de dados
+	if cobranca.UsuarioID.String() == "00000000-0000-0000-0000-000000000000" {
+		s.logger.ErrorContext(ctx, "⛔ SEGURANÇA: Cobrança sem usuário associado na fila", "cobranca_id", cobranca.ID)
+		return envioNaoRealizado
+	}
+
+	s.logger.InfoContext(ctx, "📤 Enviando notificação por WhatsApp", "tipo", msg.TipoNotificacao,
+		"cliente_id", cobranca.ClienteID, "telefone", cobranca.Cliente.Telefone)
+
+	// Conteúdo montado no enfileiramento (com anexos) ou texto padrão do tipo
+	conteudo := msg.Mensagem
+	if conteudo == nil {
+		var err error
+		conteudo, err = montarMensagemCobranca(cobranca, msg.TipoNotificacao, nil)
+		if err != nil {
+			s.logger.WarnContext(ctx, "⚠️  Erro ao montar a mensagem", logs.Erro(err))
+			return envioNaoRealizado
+		}
 	}
 
 	// Enviar via WhatsApp de forma SÍNCRONA (fila já é assíncrona)
-	_, err := s.whatsappSvc.EnviarMensagemSincrono(
+	_, err := s.whatsappSvc.EnviarConteudoSincrono(
+		ctx,
 		cobranca.UsuarioID,
+		RotaCobranca(cobranca, msg.TipoNotificacao),
 		cobranca.Cliente.Telefone,
-		textoMensagem,
+		*conteudo,
 	)
 
-	if err == nil {
-		log.Printf("✅ [FILA] Mensagem enviada: Cliente=%s, Usuário=%s", cobranca.Cliente.Nome, cobranca.UsuarioID)
-	} else {
-		log.Printf("❌ [FILA] Erro ao enviar: %v", err)
+	var limite *ErrLimiteEnvio
+	if errors.As(err, &limite) {
+		msg.adiarPor = limite.Espera
+		return envioNaoRealizado
 	}
 
-	return err == nil
+	switch {
+	case err == nil:
+		return envioRealizado
+	case errors.Is(err, ErrEnvioIncerto):
+		s.logger.ErrorContext(ctx, "❌ Envio por WhatsApp com resultado incerto", "cliente_id", cobranca.ClienteID, logs.Erro(err))
+		return envioIncerto
+	default:
+		s.logger.ErrorContext(ctx, "❌ Erro ao enviar notificação por WhatsApp", "cliente_id", cobranca.ClienteID, logs.Erro(err))
+		return envioNaoRealizado
+	}
 }
 
-// reenfileirarMensagem coloca mensagem de volta na fila
-func (s *FilaMensagemServico) reenfileirarMensagem(msg *MensagemFila) {
-	data, err := json.Marshal(msg)
+// manterBatimento renova a chave da instância enquanto os workers estão de pé
+func (s *FilaMensagemServico) manterBatimento() {
+	ticker := time.NewTicker(IntervaloBatimentoFila)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctxWorkers.Done():
+			return
+		case <-ticker.C:
+			s.renovarBatimento()
+		}
+	}
+}
+
+func (s *FilaMensagemServico) renovarBatimento() {
+	if err := s.redisClient.Set(s.ctx, PrefixoInstanciaFila+s.instanciaID, time.Now().Unix(), TTLInstanciaFila).Err(); err != nil {
+		s.logger.Error("❌ Erro ao renovar batimento da fila", logs.Erro(err))
+	}
+}
+
+// recuperarMensagensOrfas devolve à fila as mensagens em processamento de instâncias que
+// pararam de renovar o batimento (pod que caiu sem encerrar os workers)
+func (s *FilaMensagemServico) recuperarMensagensOrfas() {
+	ticker := time.NewTicker(IntervaloRecuperacaoFila)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctxWorkers.Done():
+			return
+		case <-ticker.C:
+		}
+		s.recuperarListasOrfas()
+	}
+}
+
+// recuperarListasOrfas devolve à fila as listas de processamento de outras instâncias sem
+// batimento e as remove do registro
+func (s *FilaMensagemServico) recuperarListasOrfas() {
+	listas, err := s.redisClient.HGetAll(s.ctx, ListasProcessamentoWhatsApp).Result()
 	if err != nil {
-		log.Printf("❌ Erro ao re-enfileirar mensagem: %v", err)
 		return
 	}
 
-	// Adicionar no final da fila (LPUSH)
-	s.redisClient.LPush(s.ctx, FilaMensagensWhatsApp, data)
+	for lista, instancia := range listas {
+		if instancia == s.instanciaID {
+			continue
+		}
+		viva, err := s.redisClient.Exists(s.ctx, PrefixoInstanciaFila+instancia).Result()
+		if err != nil || viva > 0 {
+			continue
+		}
+
+		devolvidas, err := devolverListaProcessamento(s.ctx, s.redisClient, lista)
+		if err != nil {
+			s.logger.Error("❌ Erro ao recuperar mensagens órfãs", "lista", lista, logs.Erro(err))
+			continue
+		}
+		s.redisClient.HDel(s.ctx, ListasProcessamentoWhatsApp, lista)
+		if devolvidas > 0 {
+			s.logger.Warn("♻️  Mensagens de instância parada devolvidas à fila", "mensagens", devolvidas, "instancia_fila", instancia)
+		}
+	}
+}
+
+// devolverListaProcessamento move as mensagens da lista de processamento de volta para a
+// ponta consumida da fila, uma a uma (LMOVE é atômico, então duas réplicas não duplicam).
+// Começa pela mais recente, para que a mais antiga seja a próxima a ser consumida.
+func devolverListaProcessamento(ctx context.Context, client *redis.Client, lista string) (int, error) {
+	devolvidas := 0
+	for {
+		err := client.LMove(ctx, lista, FilaMensagensWhatsApp, "LEFT", "RIGHT").Err()
+		if err == redis.Nil {
+			return devolvidas, nil
+		}
+		if err != nil {
+			return devolvidas, err
+		}
+		devolvidas++
+	}
+}
+
+// scriptMoverAgendadas move para a fila as mensagens agendadas cujo horário chegou. Remover
+// e enfileirar no mesmo script garante que nenhuma se perca nem seja movida duas vezes.
+// KEYS[1] = agendadas, KEYS[2] = fila; ARGV[1] = agora (Unix), ARGV[2] = máximo por execução
+var scriptMoverAgendadas = redis.NewScript(`
+local itens = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, tonumber(ARGV[2]))
+for _, item in ipairs(itens) do
+	redis.call('ZREM', KEYS[1], item)
+	redis.call('LPUSH', KEYS[2], item)
+end
+return #itens
+`)
+
+// moverAgendadas devolve à fila as mensagens adiadas quando chega a próxima tentativa
+func (s *FilaMensagemServico) moverAgendadas() {
+	ticker := time.NewTicker(IntervaloAgendadasFila)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctxWorkers.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := moverMensagensAgendadas(s.ctx, s.redisClient, time.Now()); err != nil {
+			s.logger.Error("❌ Erro ao mover mensagens agendadas", logs.Erro(err))
+		}
+	}
+}
+
+// moverMensagensAgendadas move as mensagens com próxima tentativa até agora, em lotes de 500
+func moverMensagensAgendadas(ctx context.Context, client *redis.Client, agora time.Time) (int, error) {
+	movidas := 0
+	for {
+		n, err := scriptMoverAgendadas.Run(ctx, client,
+			[]string{FilaMensagensAgendadas, FilaMensagensWhatsApp}, agora.Unix(), 500).Int()
+		movidas += n
+		if err != nil || n < 500 {
+			return movidas, err
+		}
+	}
 }
 
 // limparMensagensAntigas remove mensagens muito antigas da fila
@@ -270,7 +895,13 @@ func (s *FilaMensagemServico) limparMensagensAntigas() {
 	ticker := time.NewTicker(1 * time.Hour)
 	defer ticker.Stop()
 
-	for range ticker.C {
+	for {
+		select {
+		case <-s.ctxWorkers.Done():
+			return
+		case <-ticker.C:
+		}
+
 		// Obter tamanho da fila
 		tamanho, err := s.redisClient.LLen(s.ctx, FilaMensagensWhatsApp).Result()
 		if err != nil {
@@ -278,7 +909,7 @@ func (s *FilaMensagemServico) limparMensagensAntigas() {
 		}
 
 		if tamanho > 0 {
-			log.Printf("📊 Fila de mensagens: %d pendentes", tamanho)
+			s.logger.Info("📊 Fila de mensagens", "pendentes", tamanho)
 		}
 
 		// TODO: Implementar limpeza de mensagens muito antigas (>24h)
@@ -296,9 +927,130 @@ func (s *FilaMensagemServico) ObterEstatisticas() (map[string]interface{}, error
 		return nil, err
 	}
 
+	emProcessamento, err := s.contarEmProcessamento()
+	if err != nil {
+		return nil, err
+	}
+
 	return map[string]interface{}{
-		"mensagens_pendentes": tamanho,
-		"rate_limit":          "50 msg/s",
-		"max_burst":           100,
+		"mensagens_pendentes":        tamanho,
+		"mensagens_em_processamento": emProcessamento,
+		"rate_limit":                 "50 msg/s",
+		"max_burst":                  100,
 	}, nil
 }
+
+// contarEmProcessamento soma as mensagens nas listas de processamento de todas as instâncias
+func (s *FilaMensagemServico) contarEmProcessamento() (int64, error) {
+	listas, err := s.redisClient.HKeys(s.ctx, ListasProcessamentoWhatsApp).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, lista := range listas {
+		n, err := s.redisClient.LLen(s.ctx, lista).Result()
+		if err != nil {
+			return 0, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// ResumoFila é o estado de uma fila (ou conjunto) Redis, para inspeção pelo CLI
+type ResumoFila struct {
+	Nome    string
+	Tamanho int64
+	// Primeiros itens, na ordem em que serão consumidos
+	Itens []string
+}
+
+// InspecionarFilas retorna a fila de notificações, as adiadas, o total em processamento pelos
+// workers e as contas pausadas, com até limite itens de cada
+func (s *FilaMensagemServico) InspecionarFilas(limite int64) ([]ResumoFila, error) {
+	if s == nil || s.redisClient == nil {
+		return nil, fmt.Errorf("fila não inicializada")
+	}
+
+	fila, err := inspecionarLista(s.ctx, s.redisClient, FilaMensagensWhatsApp, limite)
+	if err != nil {
+		return nil, err
+	}
+
+	agendadas := ResumoFila{Nome: FilaMensagensAgendadas}
+	if agendadas.Tamanho, err = s.redisClient.ZCard(s.ctx, FilaMensagensAgendadas).Result(); err != nil {
+		return nil, err
+	}
+	if limite > 0 && agendadas.Tamanho > 0 {
+		itens, err := s.redisClient.ZRangeWithScores(s.ctx, FilaMensagensAgendadas, 0, limite-1).Result()
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range itens {
+			quando := time.Unix(int64(item.Score), 0).Format("02/01/2006 15:04:05")
+			agendadas.Itens = append(agendadas.Itens, fmt.Sprintf("%v (%s)", item.Member, quando))
+		}
+	}
+
+	processando := ResumoFila{Nome: ListasProcessamentoWhatsApp}
+	if processando.Tamanho, err = s.contarEmProcessamento(); err != nil {
+		return nil, err
+	}
+
+	pausadas := ResumoFila{Nome: ContasPausadasWhatsApp}
+	if pausadas.Tamanho, err = s.redisClient.SCard(s.ctx, ContasPausadasWhatsApp).Result(); err != nil {
+		return nil, err
+	}
+	if limite > 0 && pausadas.Tamanho > 0 {
+		if pausadas.Itens, _, err = s.redisClient.SScan(s.ctx, ContasPausadasWhatsApp, 0, "", limite).Result(); err != nil {
+			return nil, err
+		}
+	}
+
+	return []ResumoFila{fila, agendadas, processando, pausadas}, nil
+}
+
+// EsvaziarFila descarta as notificações pendentes da fila e as adiadas. Retorna quantas
+// foram removidas.
+func (s *FilaMensagemServico) EsvaziarFila() (int64, error) {
+	if s == nil || s.redisClient == nil {
+		return 0, fmt.Errorf("fila não inicializada")
+	}
+
+	var fila, agendadas *redis.IntCmd
+	_, err := s.redisClient.TxPipelined(s.ctx, func(pipe redis.Pipeliner) error {
+		fila = pipe.LLen(s.ctx, FilaMensagensWhatsApp)
+		agendadas = pipe.ZCard(s.ctx, FilaMensagensAgendadas)
+		pipe.Del(s.ctx, FilaMensagensWhatsApp, FilaMensagensAgendadas)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return fila.Val() + agendadas.Val(), nil
+}
+
+// inspecionarLista lê até limite itens de uma lista consumida pelo fim (LPUSH + BRPOP/BLMOVE RIGHT)
+func inspecionarLista(ctx context.Context, client *redis.Client, nome string, limite int64) (ResumoFila, error) {
+	resumo := ResumoFila{Nome: nome}
+
+	tamanho, err := client.LLen(ctx, nome).Result()
+	if err != nil {
+		return resumo, err
+	}
+	resumo.Tamanho = tamanho
+	if limite <= 0 || tamanho == 0 {
+		return resumo, nil
+	}
+
+	itens, err := client.LRange(ctx, nome, -limite, -1).Result()
+	if err != nil {
+		return resumo, err
+	}
+	for i := len(itens) - 1; i >= 0; i-- {
+		resumo.Itens = append(resumo.Itens, itens[i])
+	}
+	return resumo, nil
+}
+
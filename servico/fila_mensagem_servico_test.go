@@ -0,0 +1,325 @@
+package servico
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/dominio/entidades"
+	"github.com/ifinu/ifinu-api-go/dominio/enums"
+)
+
+// novaFilaTeste cria a fila sobre um Redis em memória, sem workers
+func novaFilaTeste(t *testing.T) (*FilaMensagemServico, *miniredis.Miniredis) {
+	t.Helper()
+	servidor := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: servidor.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	ctxWorkers, cancelar := context.WithCancel(context.Background())
+	t.Cleanup(cancelar)
+
+	return &FilaMensagemServico{
+		redisClient: client,
+		ctx:         context.Background(),
+		logger:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+		instanciaID: "teste",
+		ctxWorkers:  ctxWorkers,
+		cancelar:    cancelar,
+	}, servidor
+}
+
+func novaMensagemTeste(proximaTentativa time.Time) *MensagemFila {
+	return &MensagemFila{
+		ID:                uuid.NewString(),
+		TipoNotificacao:   enums.TipoNotificacaoLembrete,
+		Cobranca:          &entidades.Cobranca{ID: uuid.New(), UsuarioID: uuid.New()},
+		ProximaTentativa:  proximaTentativa,
+		CriadoEm:          time.Now(),
+		ChaveIdempotencia: "LEMBRETE:teste",
+	}
+}
+
+// emProcessamento simula o BLMOVE do worker: a mensagem está na lista de processamento
+func emProcessamento(t *testing.T, s *FilaMensagemServico, lista string, msg *MensagemFila) string {
+	t.Helper()
+	dados, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.redisClient.LPush(s.ctx, lista, dados).Err(); err != nil {
+		t.Fatal(err)
+	}
+	return string(dados)
+}
+
+func TestConcluirMensagemAdiadaVaiParaAgendadas(t *testing.T) {
+	s, _ := novaFilaTeste(t)
+	lista := PrefixoProcessamentoWhatsApp + "teste:1"
+
+	msg := novaMensagemTeste(time.Now())
+	dados := emProcessamento(t, s, lista, msg)
+
+	adiada := *msg
+	adiada.ProximaTentativa = time.Now().Add(time.Hour)
+	s.concluirMensagem(lista, dados, &adiada)
+
+	if n := s.redisClient.LLen(s.ctx, lista).Val(); n != 0 {
+		t.Fatalf("lista de processamento com %d item(ns), esperado 0", n)
+	}
+	if n := s.redisClient.LLen(s.ctx, FilaMensagensWhatsApp).Val(); n != 0 {
+		t.Fatalf("fila com %d item(ns): mensagem adiada não deve circular pela fila", n)
+	}
+	itens := s.redisClient.ZRangeWithScores(s.ctx, FilaMensagensAgendadas, 0, -1).Val()
+	if len(itens) != 1 || int64(itens[0].Score) != adiada.ProximaTentativa.Unix() {
+		t.Fatalf("agendadas = %v, esperado a mensagem com score %d", itens, adiada.ProximaTentativa.Unix())
+	}
+
+	// Ainda não chegou a hora: nada se move
+	if movidas, err := moverMensagensAgendadas(s.ctx, s.redisClient, time.Now()); err != nil || movidas != 0 {
+		t.Fatalf("movidas=%d err=%v, esperado 0", movidas, err)
+	}
+
+	// Chegou a hora: volta para a fila
+	movidas, err := moverMensagensAgendadas(s.ctx, s.redisClient, adiada.ProximaTentativa)
+	if err != nil || movidas != 1 {
+		t.Fatalf("movidas=%d err=%v, esperado 1", movidas, err)
+	}
+	if n := s.redisClient.ZCard(s.ctx, FilaMensagensAgendadas).Val(); n != 0 {
+		t.Fatalf("agendadas com %d item(ns) após mover", n)
+	}
+	var devolvida MensagemFila
+	if err := json.Unmarshal([]byte(s.redisClient.RPop(s.ctx, FilaMensagensWhatsApp).Val()), &devolvida); err != nil {
+		t.Fatal(err)
+	}
+	if devolvida.ID != msg.ID {
+		t.Fatalf("mensagem devolvida %s, esperado %s", devolvida.ID, msg.ID)
+	}
+}
+
+func TestMoverMensagensAgendadasEmLotes(t *testing.T) {
+	s, _ := novaFilaTeste(t)
+	agora := time.Now()
+
+	for i := 0; i < 1200; i++ {
+		s.redisClient.ZAdd(s.ctx, FilaMensagensAgendadas, &redis.Z{Score: float64(agora.Unix() - 1), Member: uuid.NewString()})
+	}
+	s.redisClient.ZAdd(s.ctx, FilaMensagensAgendadas, &redis.Z{Score: float64(agora.Add(time.Hour).Unix()), Member: "futura"})
+
+	movidas, err := moverMensagensAgendadas(s.ctx, s.redisClient, agora)
+	if err != nil || movidas != 1200 {
+		t.Fatalf("movidas=%d err=%v, esperado 1200", movidas, err)
+	}
+	if n := s.redisClient.ZCard(s.ctx, FilaMensagensAgendadas).Val(); n != 1 {
+		t.Fatalf("agendadas com %d item(ns), esperado só a futura", n)
+	}
+}
+
+func TestInspecionarEEsvaziarIncluemAgendadas(t *testing.T) {
+	s, _ := novaFilaTeste(t)
+	lista := PrefixoProcessamentoWhatsApp + "teste:1"
+
+	s.redisClient.LPush(s.ctx, FilaMensagensWhatsApp, "pendente")
+	dados := emProcessamento(t, s, lista, novaMensagemTeste(time.Now()))
+	s.concluirMensagem(lista, dados, novaMensagemTeste(time.Now().Add(time.Hour)))
+
+	filas, err := s.InspecionarFilas(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tamanhos := map[string]int64{}
+	for _, f := range filas {
+		tamanhos[f.Nome] = f.Tamanho
+	}
+	if tamanhos[FilaMensagensWhatsApp] != 1 || tamanhos[FilaMensagensAgendadas] != 1 {
+		t.Fatalf("tamanhos %v", tamanhos)
+	}
+
+	removidas, err := s.EsvaziarFila()
+	if err != nil || removidas != 2 {
+		t.Fatalf("removidas=%d err=%v, esperado 2", removidas, err)
+	}
+}
+
+func TestConcluirMensagem(t *testing.T) {
+	s, _ := novaFilaTeste(t)
+	lista := PrefixoProcessamentoWhatsApp + "teste:1"
+
+	// Confirmação: sai da lista de processamento e não volta para a fila
+	dados := emProcessamento(t, s, lista, novaMensagemTeste(time.Now()))
+	s.concluirMensagem(lista, dados, nil)
+	if n := s.redisClient.LLen(s.ctx, lista).Val(); n != 0 {
+		t.Fatalf("lista de processamento com %d item(ns) após confirmar", n)
+	}
+	if n := s.redisClient.LLen(s.ctx, FilaMensagensWhatsApp).Val(); n != 0 {
+		t.Fatalf("fila com %d item(ns) após confirmar", n)
+	}
+
+	// Retry já vencido: a nova versão volta direto para a fila
+	msg := novaMensagemTeste(time.Now())
+	dados = emProcessamento(t, s, lista, msg)
+	msg.Tentativas = 1
+	s.concluirMensagem(lista, dados, msg)
+	if n := s.redisClient.LLen(s.ctx, lista).Val(); n != 0 {
+		t.Fatalf("lista de processamento com %d item(ns) após reenfileirar", n)
+	}
+	var devolvida MensagemFila
+	if err := json.Unmarshal([]byte(s.redisClient.RPop(s.ctx, FilaMensagensWhatsApp).Val()), &devolvida); err != nil {
+		t.Fatal(err)
+	}
+	if devolvida.ID != msg.ID || devolvida.Tentativas != 1 {
+		t.Fatalf("mensagem reenfileirada %+v, esperado a nova versão", devolvida)
+	}
+}
+
+func TestRecuperarListasOrfas(t *testing.T) {
+	s, _ := novaFilaTeste(t)
+
+	listaPropria := PrefixoProcessamentoWhatsApp + "teste:1"
+	listaViva := PrefixoProcessamentoWhatsApp + "viva:1"
+	listaMorta := PrefixoProcessamentoWhatsApp + "morta:1"
+	s.redisClient.HSet(s.ctx, ListasProcessamentoWhatsApp, listaPropria, "teste", listaViva, "viva", listaMorta, "morta")
+	s.redisClient.Set(s.ctx, PrefixoInstanciaFila+"viva", 1, TTLInstanciaFila)
+
+	emProcessamento(t, s, listaPropria, novaMensagemTeste(time.Now()))
+	emProcessamento(t, s, listaViva, novaMensagemTeste(time.Now()))
+	orfa := novaMensagemTeste(time.Now())
+	emProcessamento(t, s, listaMorta, orfa)
+	emProcessamento(t, s, listaMorta, novaMensagemTeste(time.Now()))
+
+	s.recuperarListasOrfas()
+
+	if n := s.redisClient.LLen(s.ctx, FilaMensagensWhatsApp).Val(); n != 2 {
+		t.Fatalf("fila com %d item(ns), esperado as 2 da instância sem batimento", n)
+	}
+	if n := s.redisClient.LLen(s.ctx, listaMorta).Val(); n != 0 {
+		t.Fatalf("lista órfã com %d item(ns)", n)
+	}
+	if s.redisClient.HExists(s.ctx, ListasProcessamentoWhatsApp, listaMorta).Val() {
+		t.Fatal("lista órfã continua registrada")
+	}
+	for _, lista := range []string{listaPropria, listaViva} {
+		if n := s.redisClient.LLen(s.ctx, lista).Val(); n != 1 {
+			t.Fatalf("%s com %d item(ns): lista de instância viva não deve ser recuperada", lista, n)
+		}
+	}
+
+	// A mais antiga da lista órfã é a próxima a ser consumida
+	var proxima MensagemFila
+	if err := json.Unmarshal([]byte(s.redisClient.RPop(s.ctx, FilaMensagensWhatsApp).Val()), &proxima); err != nil {
+		t.Fatal(err)
+	}
+	if proxima.ID != orfa.ID {
+		t.Fatalf("próxima mensagem %s, esperado %s", proxima.ID, orfa.ID)
+	}
+}
+
+func TestEnviarUmaVez(t *testing.T) {
+	s, _ := novaFilaTeste(t)
+	ctx := context.Background()
+	chave := PrefixoEnvioNotificacao + "LEMBRETE:teste:" + canalWhatsApp
+
+	envios := 0
+	enviar := func(resultado resultadoEnvio) func(context.Context, *MensagemFila) resultadoEnvio {
+		return func(ctx context.Context, _ *MensagemFila) resultadoEnvio {
+			envios++
+			if estado := s.redisClient.Get(ctx, chave).Val(); estado != estadoEnviando {
+				t.Errorf("chave %q durante o envio, esperado %q", estado, estadoEnviando)
+			}
+			return resultado
+		}
+	}
+
+	// Falha: a chave é liberada para o retry
+	msg := novaMensagemTeste(time.Now())
+	if s.enviarUmaVez(ctx, msg, canalWhatsApp, enviar(envioNaoRealizado)) {
+		t.Fatal("falha no envio retornou sucesso")
+	}
+	if s.redisClient.Exists(ctx, chave).Val() != 0 {
+		t.Fatal("chave mantida após falha no envio")
+	}
+
+	// Sucesso: fica registrado como enviado
+	if !s.enviarUmaVez(ctx, msg, canalWhatsApp, enviar(envioRealizado)) || envios != 2 {
+		t.Fatalf("envio não realizado (envios=%d)", envios)
+	}
+	if estado := s.redisClient.Get(ctx, chave).Val(); estado != estadoEnviado {
+		t.Fatalf("chave %q após o envio, esperado %q", estado, estadoEnviado)
+	}
+	if ttl := s.redisClient.TTL(ctx, chave).Val(); ttl <= TTLEnvioEmAndamento {
+		t.Fatalf("TTL %s após o envio, esperado TTLEnvioConcluido", ttl)
+	}
+
+	// Cópia reprocessada: não envia de novo
+	copia := novaMensagemTeste(time.Now())
+	if !s.enviarUmaVez(ctx, copia, canalWhatsApp, enviar(envioRealizado)) || envios != 2 {
+		t.Fatalf("cópia enviada de novo (envios=%d)", envios)
+	}
+
+	// Envio em andamento em outro worker: adia sem enviar em paralelo
+	s.redisClient.Set(ctx, chave, estadoEnviando, TTLEnvioEmAndamento)
+	if s.enviarUmaVez(ctx, copia, canalWhatsApp, enviar(envioRealizado)) || envios != 2 {
+		t.Fatalf("enviou em paralelo a outro worker (envios=%d)", envios)
+	}
+	if copia.adiarPor != TTLEnvioEmAndamento {
+		t.Fatalf("adiarPor=%s, esperado %s", copia.adiarPor, TTLEnvioEmAndamento)
+	}
+}
+
+func TestEnviarUmaVezResultadoIncertoNaoRepete(t *testing.T) {
+	s, _ := novaFilaTeste(t)
+	ctx := context.Background()
+	chave := PrefixoEnvioNotificacao + "LEMBRETE:teste:" + canalWhatsApp
+
+	envios := 0
+	enviar := func(resultado resultadoEnvio) func(context.Context, *MensagemFila) resultadoEnvio {
+		return func(context.Context, *MensagemFila) resultadoEnvio {
+			envios++
+			return resultado
+		}
+	}
+
+	// Timeout ou 5xx: a mensagem pode ter saído, então o canal é dado como concluído
+	if !s.enviarUmaVez(ctx, novaMensagemTeste(time.Now()), canalWhatsApp, enviar(envioIncerto)) {
+		t.Fatal("resultado incerto deixou o canal pendente para retry")
+	}
+	if estado := s.redisClient.Get(ctx, chave).Val(); estado != estadoIncerto {
+		t.Fatalf("chave %q, esperado %q", estado, estadoIncerto)
+	}
+
+	// Reprocessamento da mesma notificação não envia de novo
+	if !s.enviarUmaVez(ctx, novaMensagemTeste(time.Now()), canalWhatsApp, enviar(envioRealizado)) || envios != 1 {
+		t.Fatalf("reenviou após resultado incerto (envios=%d)", envios)
+	}
+}
+
+func TestRenovarEnvioEmAndamento(t *testing.T) {
+	s, _ := novaFilaTeste(t)
+	ctx := context.Background()
+	chave := PrefixoEnvioNotificacao + "LEMBRETE:teste:" + canalEmail
+
+	// Só renova enquanto a chave estiver "enviando"
+	s.redisClient.Set(ctx, chave, estadoEnviando, time.Second)
+	renovada, err := scriptRenovarEnvio.Run(ctx, s.redisClient, []string{chave}, estadoEnviando, TTLEnvioEmAndamento.Milliseconds()).Int()
+	if err != nil || renovada != 1 {
+		t.Fatalf("renovada=%d err=%v", renovada, err)
+	}
+	if ttl := s.redisClient.TTL(ctx, chave).Val(); ttl != TTLEnvioEmAndamento {
+		t.Fatalf("TTL %s, esperado %s", ttl, TTLEnvioEmAndamento)
+	}
+
+	s.redisClient.Set(ctx, chave, estadoEnviado, TTLEnvioConcluido)
+	renovada, err = scriptRenovarEnvio.Run(ctx, s.redisClient, []string{chave}, estadoEnviando, TTLEnvioEmAndamento.Milliseconds()).Int()
+	if err != nil || renovada != 0 {
+		t.Fatalf("renovou chave já enviada (renovada=%d err=%v)", renovada, err)
+	}
+	if ttl := s.redisClient.TTL(ctx, chave).Val(); ttl != TTLEnvioConcluido {
+		t.Fatalf("TTL %s, esperado %s", ttl, TTLEnvioConcluido)
+	}
+}
@@ -0,0 +1,304 @@
+package servico
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/dominio/entidades"
+	"github.com/ifinu/ifinu-api-go/logs"
+	"github.com/ifinu/ifinu-api-go/rastreamento"
+	"github.com/ifinu/ifinu-api-go/util"
+	"github.com/spf13/viper"
+)
+
+const (
+	// Pausa aleatória entre mensagens do mesmo número, para não parecer automação
+	PausaHumanaMinima = 2 * time.Second
+	PausaHumanaMaxima = 6 * time.Second
+
+	// Acima disso o envio não aguarda: tenta outro número ou volta para a fila
+	MaxEsperaEnvio = 30 * time.Second
+
+	// Limite diário padrão por conta (WHATSAPP_LIMITE_DIARIO_CONTA; 0 = sem limite)
+	LimiteDiarioContaPadrao = 1000
+)
+
+// FaixaAquecimento define o ritmo de um número conforme a idade da conexão:
+// números novos começam devagar para não serem banidos
+type FaixaAquecimento struct {
+	AteDias int // 0 = sem limite de idade (número aquecido)
+	Limite  util.LimiteEnvio
+}
+
+var FaixasAquecimento = []FaixaAquecimento{
+	{AteDias: 3, Limite: util.LimiteEnvio{PorMinuto: 2, Rajada: 2}},
+	{AteDias: 14, Limite: util.LimiteEnvio{PorMinuto: 6, Rajada: 4}},
+	{AteDias: 0, Limite: util.LimiteEnvio{PorMinuto: 20, Rajada: 8}},
+}
+
+// ErrLimiteEnvio indica que o envio deve ser tentado novamente depois de Espera
+type ErrLimiteEnvio struct {
+	Motivo string
+	Espera time.Duration
+}
+
+func (e *ErrLimiteEnvio) Error() string {
+	return fmt.Sprintf("%s: tente novamente em %v", e.Motivo, e.Espera.Round(time.Second))
+}
+
+// LimitadorWhatsApp controla o ritmo de envio por instância e o volume diário por conta.
+// O estado fica no Redis para valer em todas as réplicas; sem Redis, em memória.
+type LimitadorWhatsApp struct {
+	redisClient  *redis.Client
+	limiteDiario int
+	logger       *slog.Logger
+
+	mu      sync.Mutex
+	estados map[string]util.EstadoEnvio
+	diarios map[string]int
+}
+
+func NovoLimitadorWhatsApp(redisAddr string, logger *slog.Logger) *LimitadorWhatsApp {
+	limitador := &LimitadorWhatsApp{
+		limiteDiario: LimiteDiarioContaPadrao,
+		logger:       logger,
+		estados:      make(map[string]util.EstadoEnvio),
+		diarios:      make(map[string]int),
+	}
+	if viper.IsSet("WHATSAPP_LIMITE_DIARIO_CONTA") {
+		limitador.limiteDiario = viper.GetInt("WHATSAPP_LIMITE_DIARIO_CONTA")
+	}
+
+	redisClient := redis.NewClient(&redis.Options{
+		Addr:         redisAddr,
+		DialTimeout:  2 * time.Second,
+		ReadTimeout:  500 * time.Millisecond,
+		WriteTimeout: 500 * time.Millisecond,
+	})
+	redisClient.AddHook(rastreamento.HookRedis())
+	if err := redisClient.Ping(context.Background()).Err(); err != nil {
+		logger.Warn("⚠️  Redis não disponível para limite de envio WhatsApp. Usando limite em memória", logs.Erro(err))
+		return limitador
+	}
+
+	limitador.redisClient = redisClient
+	return limitador
+}
+
+// LimiteConexao retorna o ritmo de envio do número conforme a faixa de aquecimento, contada
+// a partir do pareamento (sem pareamento registrado, o número é tratado como novo)
+func LimiteConexao(conexao *entidades.WhatsAppConexao, agora time.Time) util.LimiteEnvio {
+	var idade time.Duration
+	if conexao.DataPareamento != nil {
+		idade = agora.Sub(*conexao.DataPareamento)
+	}
+	for _, faixa := range FaixasAquecimento {
+		if faixa.AteDias == 0 || idade < time.Duration(faixa.AteDias)*24*time.Hour {
+			return faixa.Limite
+		}
+	}
+	return FaixasAquecimento[len(FaixasAquecimento)-1].Limite
+}
+
+// ReservaEnvio é um horário reservado pelo Reservar: Espera é quanto aguardar antes de enviar
+type ReservaEnvio struct {
+	Espera      time.Duration
+	chaveDiaria string
+}
+
+// Reservar reserva o próximo horário de envio pela conexão e uma mensagem do limite diário.
+// Retorna *ErrLimiteEnvio se a espera passar de MaxEsperaEnvio ou a conta atingiu o limite diário.
+// Se a mensagem não for enviada, a reserva deve ser devolvida com Devolver.
+func (l *LimitadorWhatsApp) Reservar(ctx context.Context, conexao *entidades.WhatsAppConexao) (ReservaEnvio, error) {
+	if l == nil {
+		return ReservaEnvio{}, nil
+	}
+
+	agora := time.Now()
+	limite := LimiteConexao(conexao, agora)
+	pausa := PausaHumanaMinima + time.Duration(rand.Int63n(int64(PausaHumanaMaxima-PausaHumanaMinima)))
+
+	chaveInstancia := "ifinu:whatsapp:ritmo:" + conexao.InstanceName
+	chaveDiaria := chaveLimiteDiario(conexao.UsuarioID, agora)
+
+	var status int64
+	var espera time.Duration
+	var err error
+	if l.redisClient != nil {
+		status, espera, err = l.reservarRedis(ctx, chaveInstancia, chaveDiaria, agora, limite, pausa)
+		if err != nil {
+			l.logger.WarnContext(ctx, "⚠️  Limite de envio: erro no Redis, usando memória", logs.Erro(err))
+		}
+	}
+	if l.redisClient == nil || err != nil {
+		status, espera = l.reservarMemoria(chaveInstancia, chaveDiaria, agora, limite, pausa)
+	}
+
+	switch status {
+	case statusLimiteDiario:
+		return ReservaEnvio{}, &ErrLimiteEnvio{Motivo: "limite diário de mensagens da conta atingido", Espera: ateAmanha(agora)}
+	case statusAguardar:
+		return ReservaEnvio{}, &ErrLimiteEnvio{Motivo: "limite de envio do número atingido", Espera: espera}
+	}
+	return ReservaEnvio{Espera: espera, chaveDiaria: chaveDiaria}, nil
+}
+
+// Devolver estorna do limite diário uma reserva cuja mensagem certamente não foi enviada,
+// para que falhas e desistências não consumam a cota da conta. O ritmo do número não é
+// devolvido: a pausa entre tentativas continua valendo.
+func (l *LimitadorWhatsApp) Devolver(ctx context.Context, reserva ReservaEnvio) {
+	if l == nil || reserva.chaveDiaria == "" {
+		return
+	}
+
+	// Devolve também quando o envio desistiu porque ctx foi cancelado
+	ctx = context.WithoutCancel(ctx)
+	if l.redisClient != nil {
+		err := scriptDevolverEnvio.Run(ctx, l.redisClient, []string{reserva.chaveDiaria}).Err()
+		if err == nil || err == redis.Nil {
+			return
+		}
+		l.logger.WarnContext(ctx, "⚠️  Limite de envio: erro ao devolver reserva no Redis, usando memória", logs.Erro(err))
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.diarios[reserva.chaveDiaria] > 0 {
+		l.diarios[reserva.chaveDiaria]--
+	}
+}
+
+const (
+	statusLimiteDiario int64 = -1
+	statusAguardar     int64 = 0
+	statusReservado    int64 = 1
+)
+
+// scriptReservarEnvio aplica util.ReservarEnvio e o limite diário atomicamente.
+// Retorna {status, espera em ms}.
+var scriptReservarEnvio = redis.NewScript(`
+local chaveInstancia = KEYS[1]
+local chaveDiaria = KEYS[2]
+local agora = tonumber(ARGV[1])
+local intervalo = tonumber(ARGV[2])
+local tolerancia = tonumber(ARGV[3])
+local pausa = tonumber(ARGV[4])
+local maxEspera = tonumber(ARGV[5])
+local limiteDiario = tonumber(ARGV[6])
+
+if limiteDiario > 0 and tonumber(redis.call('GET', chaveDiaria) or '0') >= limiteDiario then
+	return {-1, 0}
+end
+
+local tat = tonumber(redis.call('HGET', chaveInstancia, 'tat') or '0')
+local ultimo = tonumber(redis.call('HGET', chaveInstancia, 'ultimo') or '0')
+if tat < agora then
+	tat = agora
+end
+
+local envio = math.max(agora, tat - tolerancia, ultimo + pausa)
+local espera = envio - agora
+if espera > maxEspera then
+	return {0, espera}
+end
+
+tat = math.max(tat, envio) + intervalo
+redis.call('HSET', chaveInstancia, 'tat', tat, 'ultimo', envio)
+redis.call('PEXPIRE', chaveInstancia, tat - agora + 60000)
+
+redis.call('INCR', chaveDiaria)
+redis.call('EXPIRE', chaveDiaria, 172800)
+return {1, espera}
+`)
+
+// scriptDevolverEnvio decrementa o contador diário sem deixá-lo negativo
+var scriptDevolverEnvio = redis.NewScript(`
+if tonumber(redis.call('GET', KEYS[1]) or '0') > 0 then
+	return redis.call('DECR', KEYS[1])
+end
+return 0
+`)
+
+func (l *LimitadorWhatsApp) reservarRedis(ctx context.Context, chaveInstancia, chaveDiaria string, agora time.Time, limite util.LimiteEnvio, pausa time.Duration) (int64, time.Duration, error) {
+	valores, err := scriptReservarEnvio.Run(ctx, l.redisClient, []string{chaveInstancia, chaveDiaria},
+		agora.UnixMilli(),
+		limite.Intervalo().Milliseconds(),
+		limite.Tolerancia().Milliseconds(),
+		pausa.Milliseconds(),
+		MaxEsperaEnvio.Milliseconds(),
+		l.limiteDiario,
+	).Int64Slice()
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(valores) != 2 {
+		return 0, 0, errors.New("resposta inesperada do script de limite de envio")
+	}
+	return valores[0], time.Duration(valores[1]) * time.Millisecond, nil
+}
+
+func (l *LimitadorWhatsApp) reservarMemoria(chaveInstancia, chaveDiaria string, agora time.Time, limite util.LimiteEnvio, pausa time.Duration) (int64, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.limiteDiario > 0 && l.diarios[chaveDiaria] >= l.limiteDiario {
+		return statusLimiteDiario, 0
+	}
+
+	estado, espera, ok := util.ReservarEnvio(l.estados[chaveInstancia], agora, limite, pausa, MaxEsperaEnvio)
+	if !ok {
+		return statusAguardar, espera
+	}
+
+	l.estados[chaveInstancia] = estado
+	l.diarios[chaveDiaria]++
+
+	// Limpeza de dias anteriores
+	if len(l.diarios) > 1000 {
+		for chave := range l.diarios {
+			if !strings.HasSuffix(chave, chaveDiaria[strings.LastIndex(chaveDiaria, ":"):]) {
+				delete(l.diarios, chave)
+			}
+		}
+	}
+	return statusReservado, espera
+}
+
+// chaveLimiteDiario agrupa os envios da conta pelo dia no horário de Brasília
+func chaveLimiteDiario(usuarioID uuid.UUID, agora time.Time) string {
+	location, _ := time.LoadLocation("America/Sao_Paulo")
+	return fmt.Sprintf("ifinu:whatsapp:diario:%s:%s", usuarioID, agora.In(location).Format("20060102"))
+}
+
+// ateAmanha retorna o tempo até a meia-noite de Brasília, quando o limite diário reinicia
+func ateAmanha(agora time.Time) time.Duration {
+	location, _ := time.LoadLocation("America/Sao_Paulo")
+	local := agora.In(location)
+	amanha := time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, location)
+	return amanha.Sub(agora)
+}
+
+// aguardar espera o horário reservado, desistindo se ctx for cancelado (encerramento ou
+// requisição abandonada)
+func aguardar(ctx context.Context, espera time.Duration) error {
+	if espera <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(espera)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
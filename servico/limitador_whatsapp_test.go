@@ -0,0 +1,134 @@
+package servico
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/dominio/entidades"
+	"github.com/ifinu/ifinu-api-go/util"
+)
+
+func novoLimitadorTeste(t *testing.T, comRedis bool, limiteDiario int) *LimitadorWhatsApp {
+	t.Helper()
+	limitador := &LimitadorWhatsApp{
+		limiteDiario: limiteDiario,
+		logger:       slog.Default(),
+		estados:      make(map[string]util.EstadoEnvio),
+		diarios:      make(map[string]int),
+	}
+	if comRedis {
+		servidor := miniredis.RunT(t)
+		limitador.redisClient = redis.NewClient(&redis.Options{Addr: servidor.Addr()})
+		t.Cleanup(func() { limitador.redisClient.Close() })
+	}
+	return limitador
+}
+
+func TestDevolverReservaLiberaLimiteDiario(t *testing.T) {
+	for nome, comRedis := range map[string]bool{"redis": true, "memoria": false} {
+		t.Run(nome, func(t *testing.T) {
+			limitador := novoLimitadorTeste(t, comRedis, 1)
+			ctx := context.Background()
+			pareamento := time.Now().Add(-30 * 24 * time.Hour)
+			usuarioID := uuid.New()
+
+			// Números diferentes, para o ritmo de cada um não interferir
+			conexao := func() *entidades.WhatsAppConexao {
+				return &entidades.WhatsAppConexao{UsuarioID: usuarioID, InstanceName: uuid.NewString(), DataPareamento: &pareamento}
+			}
+
+			reserva, err := limitador.Reservar(ctx, conexao())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := limitador.Reservar(ctx, conexao()); err == nil {
+				t.Fatal("segunda reserva aceita com limite diário 1")
+			}
+
+			// Envio que certamente não saiu devolve a cota
+			limitador.Devolver(ctx, reserva)
+			reserva, err = limitador.Reservar(ctx, conexao())
+			if err != nil {
+				t.Fatalf("reserva após devolução: %v", err)
+			}
+
+			// Devolver duas vezes não deixa o contador negativo
+			limitador.Devolver(ctx, reserva)
+			limitador.Devolver(ctx, reserva)
+			if _, err := limitador.Reservar(ctx, conexao()); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := limitador.Reservar(ctx, conexao()); err == nil {
+				t.Fatal("contador diário ficou negativo")
+			}
+		})
+	}
+}
+
+func TestLimiteConexaoContaDoPareamento(t *testing.T) {
+	agora := time.Now()
+	antigo := agora.Add(-60 * 24 * time.Hour)
+	recente := agora.Add(-time.Hour)
+
+	casos := []struct {
+		nome     string
+		conexao  entidades.WhatsAppConexao
+		esperado util.LimiteEnvio
+	}{
+		{"número antigo", entidades.WhatsAppConexao{DataCriacao: antigo, DataPareamento: &antigo}, FaixasAquecimento[2].Limite},
+		{"novo número na conexão reaproveitada", entidades.WhatsAppConexao{DataCriacao: antigo, DataPareamento: &recente}, FaixasAquecimento[0].Limite},
+		{"ainda não pareado", entidades.WhatsAppConexao{DataCriacao: antigo}, FaixasAquecimento[0].Limite},
+	}
+	for _, caso := range casos {
+		if limite := LimiteConexao(&caso.conexao, agora); limite != caso.esperado {
+			t.Errorf("%s: %+v, esperado %+v", caso.nome, limite, caso.esperado)
+		}
+	}
+}
+
+func TestPareamentoReiniciaAquecimento(t *testing.T) {
+	conexao := entidades.WhatsAppConexao{}
+	conexao.Conectar("5511999999999")
+	primeiro := conexao.DataPareamento
+	if primeiro == nil {
+		t.Fatal("primeira conexão não registrou o pareamento")
+	}
+
+	// Reconexão da mesma instância mantém o pareamento
+	conexao.MarcarQueda()
+	conexao.Conectar("5511999999999")
+	if conexao.DataPareamento != primeiro {
+		t.Fatal("reconexão reiniciou o aquecimento")
+	}
+
+	// Novo QR Code: o aquecimento recomeça quando o número conectar
+	conexao.IniciarPareamento("ifinu_nova", "qr")
+	if conexao.DataPareamento != nil {
+		t.Fatal("novo pareamento manteve a data anterior")
+	}
+	conexao.Conectar("5511888888888")
+	if conexao.DataPareamento == nil || conexao.DataPareamento == primeiro {
+		t.Fatal("novo pareamento não registrado")
+	}
+}
+
+func TestAguardarRespeitaContexto(t *testing.T) {
+	ctx, cancelar := context.WithCancel(context.Background())
+	cancelar()
+
+	inicio := time.Now()
+	if err := aguardar(ctx, time.Minute); err != context.Canceled {
+		t.Fatalf("err=%v, esperado context.Canceled", err)
+	}
+	if time.Since(inicio) > time.Second {
+		t.Fatal("aguardar ignorou o cancelamento")
+	}
+	if err := aguardar(context.Background(), time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,104 @@
+package servico
+
+import (
+	"encoding/base64"
+	"fmt"
+	"log"
+
+	"github.com/ifinu/ifinu-api-go/dominio/entidades"
+	"github.com/ifinu/ifinu-api-go/dominio/enums"
+	"github.com/ifinu/ifinu-api-go/dto"
+	"github.com/ifinu/ifinu-api-go/util"
+)
+
+// Lado da imagem do QR Code PIX enviada nos lembretes
+const tamanhoQRCodePix = 400
+
+// textoNotificacaoCobranca monta o texto do lembrete (3 dias antes) ou do aviso de vencimento
+func textoNotificacaoCobranca(cobranca *entidades.Cobranca, tipo enums.TipoNotificacao) (string, error) {
+	switch tipo {
+	case enums.TipoNotificacaoLembrete:
+		return fmt.Sprintf(
+			"🔔 *Lembrete de Cobrança*\n\n"+
+				"Olá, %s!\n\n"+
+				"Sua cobrança vence em 3 dias:\n"+
+				"💰 Valor: R$ %.2f\n"+
+				"📝 Descrição: %s\n"+
+				"📅 Vencimento: %s\n\n"+
+				"Atenciosamente,\nEquipe IFINU",
+			cobranca.Cliente.Nome,
+			cobranca.Valor,
+			cobranca.Descricao,
+			cobranca.DataVencimento.Format("02/01/2006"),
+		), nil
+	case enums.TipoNotificacaoVencimento:
+		return fmt.Sprintf(
+			"⚠️ *Cobrança Vence Hoje*\n\n"+
+				"Olá, %s!\n\n"+
+				"Sua cobrança vence HOJE:\n"+
+				"💰 Valor: R$ %.2f\n"+
+				"📝 Descrição: %s\n\n"+
+				"Atenciosamente,\nEquipe IFINU",
+			cobranca.Cliente.Nome,
+			cobranca.Valor,
+			cobranca.Descricao,
+		), nil
+	}
+	return "", fmt.Errorf("tipo de notificação desconhecido: %s", tipo)
+}
+
+// montarMensagemCobranca monta a notificação de WhatsApp da cobrança: o texto, o link de
+// pagamento com prévia e botão e, se a conta tiver chave PIX configurada, o QR Code em
+// imagem e um botão para copiar o código.
+func montarMensagemCobranca(cobranca *entidades.Cobranca, tipo enums.TipoNotificacao, configPix *entidades.ConfigRespostaAutomatica) (*dto.MensagemWhatsApp, error) {
+	texto, err := textoNotificacaoCobranca(cobranca, tipo)
+	if err != nil {
+		return nil, err
+	}
+
+	mensagem := &dto.MensagemWhatsApp{Texto: texto}
+
+	if cobranca.AsaasPaymentURL != "" {
+		mensagem.LinkPreview = true
+		mensagem.Botoes = append(mensagem.Botoes, dto.BotaoWhatsApp{
+			Tipo:  enums.TipoBotaoLink,
+			Texto: "Pagar agora",
+			URL:   cobranca.AsaasPaymentURL,
+		})
+	}
+
+	if configPix != nil && configPix.ChavePix != "" {
+		codigo, err := util.GerarPixCopiaECola(util.PixEstatico{
+			Chave:         configPix.ChavePix,
+			Recebedor:     configPix.RecebedorPix,
+			Cidade:        configPix.CidadePix,
+			Valor:         cobranca.Valor,
+			Identificador: cobranca.ID.String(),
+		})
+		if err != nil {
+			log.Printf("⚠️  PIX não incluído no lembrete da cobrança %s: %v", cobranca.ID, err)
+			return mensagem, nil
+		}
+
+		mensagem.Botoes = append(mensagem.Botoes, dto.BotaoWhatsApp{
+			Tipo:   enums.TipoBotaoCopiar,
+			Texto:  "Copiar PIX",
+			Codigo: codigo,
+		})
+
+		png, err := util.GerarQRCodePNG(codigo, tamanhoQRCodePix)
+		if err != nil {
+			log.Printf("⚠️  QR Code PIX não gerado para a cobrança %s: %v", cobranca.ID, err)
+			return mensagem, nil
+		}
+		mensagem.Anexos = append(mensagem.Anexos, dto.AnexoWhatsApp{
+			Tipo:        enums.TipoAnexoImagem,
+			Base64:      base64.StdEncoding.EncodeToString(png),
+			MimeType:    "image/png",
+			NomeArquivo: "pix.png",
+			Legenda:     "💠 QR Code PIX",
+		})
+	}
+
+	return mensagem, nil
+}
@@ -0,0 +1,195 @@
+package servico
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/dominio/entidades"
+	"github.com/ifinu/ifinu-api-go/logs"
+)
+
+// Conexões e instâncias alteradas há menos tempo que isso não são tratadas como
+// órfãs: podem estar no meio da criação (instância criada, conexão ainda não salva)
+const carenciaOrfa = 10 * time.Minute
+
+// Verificações seguidas sem a instância na Evolution API antes de remover a conexão
+// (com o monitor a cada 5 minutos, 15 minutos). Uma resposta incompleta da API não
+// apaga números que ainda existem.
+const verificacoesParaRemoverOrfa = 3
+
+// ResultadoMonitoramento resume uma verificação das conexões WhatsApp
+type ResultadoMonitoramento struct {
+	// Conexões que caíram nesta verificação (com o usuário carregado)
+	Quedas []entidades.WhatsAppConexao
+	// Contas com números ativos mas nenhum conectado
+	ContasSemConexao []uuid.UUID
+	// Conexões caídas com pedido de reconexão enviado
+	ReconexoesTentadas int
+	// Conexões sem instância e instâncias sem conexão removidas
+	OrfasRemovidas int
+}
+
+// MonitorarConexoes confere todas as instâncias na Evolution API: sincroniza o status
+// das conexões ativas, pede reconexão das que caíram e remove órfãs dos dois lados.
+func (s *WhatsAppServico) MonitorarConexoes(ctx context.Context) (*ResultadoMonitoramento, error) {
+	// Sem a lista da Evolution API não dá para distinguir queda de instabilidade da API
+	instancias, err := s.evolutionAPI.ListarInstancias(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar instâncias: %w", err)
+	}
+
+	conectadas := make(map[string]bool, len(instancias))
+	for i := range instancias {
+		conectadas[instancias[i].Nome()] = instancias[i].Conectada()
+	}
+
+	resultado := &ResultadoMonitoramento{}
+
+	conexoes, err := s.whatsappRepo.ComContexto(ctx).ListarTodas()
+	if err != nil {
+		return nil, err
+	}
+	if listaReconhecida(conexoes, conectadas) {
+		conhecidas := s.removerConexoesOrfas(ctx, conexoes, conectadas, resultado)
+		s.removerInstanciasOrfas(ctx, conectadas, conhecidas, resultado)
+	} else {
+		s.logger.WarnContext(ctx, "⚠️  Nenhuma instância conhecida na Evolution API, limpeza de órfãs ignorada",
+			"instancias", len(instancias), "conexoes", len(conexoes))
+	}
+
+	ativas, err := s.whatsappRepo.ComContexto(ctx).BuscarConexoesAtivas()
+	if err != nil {
+		return nil, err
+	}
+
+	// Conta -> algum número conectado
+	contas := make(map[uuid.UUID]bool)
+	for i := range ativas {
+		conexao := &ativas[i]
+
+		conectada, existe := conectadas[conexao.InstanceName]
+		if !existe {
+			continue // órfã em período de carência
+		}
+		ctx := logs.ComUsuarioID(ctx, conexao.UsuarioID)
+
+		if s.atualizarStatus(ctx, conexao, conectada, "monitor") {
+			resultado.Quedas = append(resultado.Quedas, *conexao)
+		}
+
+		if !conectada {
+			if err := s.evolutionAPI.Reconectar(ctx, conexao.InstanceName); err != nil {
+				s.logger.WarnContext(ctx, "⚠️  Erro ao reconectar instância", "instancia", conexao.InstanceName, logs.Erro(err))
+			} else {
+				resultado.ReconexoesTentadas++
+			}
+		}
+
+		contas[conexao.UsuarioID] = contas[conexao.UsuarioID] || conectada
+	}
+
+	for usuarioID, algumaConectada := range contas {
+		if !algumaConectada {
+			resultado.ContasSemConexao = append(resultado.ContasSemConexao, usuarioID)
+		}
+	}
+
+	return resultado, nil
+}
+
+// listaReconhecida indica se a lista da Evolution API tem alguma instância das conexões.
+// Uma lista vazia ou só com instâncias desconhecidas vem de uma falha da API ou de outro
+// servidor, e não de todos os números terem sido removidos.
+func listaReconhecida(conexoes []entidades.WhatsAppConexao, instancias map[string]bool) bool {
+	for i := range conexoes {
+		if _, existe := instancias[conexoes[i].InstanceName]; existe {
+			return true
+		}
+	}
+	return false
+}
+
+// removerConexoesOrfas apaga conexões cuja instância não aparece na Evolution API há
+// verificacoesParaRemoverOrfa verificações seguidas.
+// Retorna os nomes de instância das conexões existentes no banco.
+func (s *WhatsAppServico) removerConexoesOrfas(ctx context.Context, conexoes []entidades.WhatsAppConexao, instancias map[string]bool, resultado *ResultadoMonitoramento) map[string]bool {
+	conhecidas := make(map[string]bool, len(conexoes))
+	for i := range conexoes {
+		conexao := &conexoes[i]
+		conhecidas[conexao.InstanceName] = true
+		ctx := logs.ComUsuarioID(ctx, conexao.UsuarioID)
+
+		if _, existe := instancias[conexao.InstanceName]; existe {
+			if conexao.VerificacoesSemInstancia > 0 {
+				if err := s.whatsappRepo.ComContexto(ctx).ZerarAusenciaInstancia(conexao.ID); err != nil {
+					s.logger.WarnContext(ctx, "⚠️  Erro ao zerar verificações sem instância", "conexao_id", conexao.ID, logs.Erro(err))
+				}
+			}
+			continue
+		}
+		if time.Since(conexao.DataAtualizacao) < carenciaOrfa {
+			continue
+		}
+
+		verificacoes, err := s.whatsappRepo.ComContexto(ctx).RegistrarAusenciaInstancia(conexao.ID)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "❌ Erro ao registrar instância ausente", "conexao_id", conexao.ID, logs.Erro(err))
+			continue
+		}
+		if verificacoes < verificacoesParaRemoverOrfa {
+			s.logger.WarnContext(ctx, "⚠️  Instância da conexão não encontrada na Evolution API",
+				"conexao", conexao.Nome, "instancia", conexao.InstanceName, "verificacoes", verificacoes)
+			continue
+		}
+
+		if err := s.removerConexao(ctx, conexao); err != nil {
+			s.logger.ErrorContext(ctx, "❌ Erro ao remover conexão órfã", "conexao_id", conexao.ID, logs.Erro(err))
+			continue
+		}
+		if conexao.IsConectado() || conexao.IsQueda() {
+			s.dispararDesconexao(conexao, "orfa")
+		}
+
+		s.logger.InfoContext(ctx, "🧹 Conexão órfã removida (instância não existe na Evolution API)",
+			"conexao", conexao.Nome, "instancia", conexao.InstanceName)
+		resultado.OrfasRemovidas++
+	}
+
+	return conhecidas
+}
+
+// removerInstanciasOrfas apaga instâncias desta aplicação sem conexão no banco
+// (ex: instância antiga de um número reconectado)
+func (s *WhatsAppServico) removerInstanciasOrfas(ctx context.Context, instancias map[string]bool, conhecidas map[string]bool, resultado *ResultadoMonitoramento) {
+	for nome := range instancias {
+		if conhecidas[nome] || !strings.HasPrefix(nome, PrefixoInstancia) || instanciaRecente(nome) {
+			continue
+		}
+
+		if err := s.evolutionAPI.DeletarInstancia(ctx, nome); err != nil {
+			s.logger.ErrorContext(ctx, "❌ Erro ao remover instância órfã", "instancia", nome, logs.Erro(err))
+			continue
+		}
+
+		s.logger.InfoContext(ctx, "🧹 Instância órfã removida da Evolution API", "instancia", nome)
+		resultado.OrfasRemovidas++
+	}
+}
+
+// instanciaRecente lê o timestamp do fim do nome da instância (ifinu_<email>_<unix>)
+func instanciaRecente(nome string) bool {
+	posicao := strings.LastIndex(nome, "_")
+	if posicao < 0 {
+		return false
+	}
+
+	criacao, err := strconv.ParseInt(nome[posicao+1:], 10, 64)
+	if err != nil {
+		return false
+	}
+	return time.Since(time.Unix(criacao, 0)) < carenciaOrfa
+}
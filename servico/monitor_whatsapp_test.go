@@ -0,0 +1,31 @@
+package servico
+
+import (
+	"testing"
+
+	"github.com/ifinu/ifinu-api-go/dominio/entidades"
+)
+
+func TestListaReconhecida(t *testing.T) {
+	conexoes := []entidades.WhatsAppConexao{
+		{ID: 1, InstanceName: "ifinu_a@ifinu.com_1"},
+		{ID: 2, InstanceName: "ifinu_b@ifinu.com_2"},
+	}
+
+	casos := []struct {
+		nome       string
+		instancias map[string]bool
+		esperado   bool
+	}{
+		{"lista vazia", map[string]bool{}, false},
+		{"só instâncias desconhecidas", map[string]bool{"ifinu_outro@ifinu.com_3": true}, false},
+		{"uma conhecida desconectada", map[string]bool{"ifinu_a@ifinu.com_1": false}, true},
+	}
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			if obtido := listaReconhecida(conexoes, caso.instancias); obtido != caso.esperado {
+				t.Errorf("listaReconhecida = %v, esperado %v", obtido, caso.esperado)
+			}
+		})
+	}
+}
@@ -0,0 +1,297 @@
+package servico
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/dominio/entidades"
+	"github.com/ifinu/ifinu-api-go/dominio/enums"
+	"github.com/ifinu/ifinu-api-go/dto"
+	"github.com/ifinu/ifinu-api-go/integracao"
+	"github.com/ifinu/ifinu-api-go/repositorio"
+	"github.com/ifinu/ifinu-api-go/util"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+type OrganizacaoServico struct {
+	organizacaoRepo  *repositorio.OrganizacaoRepositorio
+	usuarioRepo      *repositorio.UsuarioRepositorio
+	resendAPI        *integracao.ResendCliente
+	auditoriaServico *AuditoriaServico
+}
+
+func NovoOrganizacaoServico(
+	organizacaoRepo *repositorio.OrganizacaoRepositorio,
+	usuarioRepo *repositorio.UsuarioRepositorio,
+	resendAPI *integracao.ResendCliente,
+	auditoriaServico *AuditoriaServico,
+) *OrganizacaoServico {
+	return &OrganizacaoServico{
+		organizacaoRepo:  organizacaoRepo,
+		usuarioRepo:      usuarioRepo,
+		resendAPI:        resendAPI,
+		auditoriaServico: auditoriaServico,
+	}
+}
+
+// ObterOrganizacao retorna a organização da conta com seus membros
+func (s *OrganizacaoServico) ObterOrganizacao(contaID uuid.UUID, papel enums.PapelMembro) (*dto.OrganizacaoResponse, error) {
+	organizacao, err := s.obterOuCriar(contaID)
+	if err != nil {
+		return nil, err
+	}
+
+	membros, err := s.organizacaoRepo.ListarMembros(organizacao.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	membrosDTO := make([]dto.MembroResponse, len(membros))
+	for i, membro := range membros {
+		membrosDTO[i] = s.mapearMembroParaDTO(&membro)
+	}
+
+	return &dto.OrganizacaoResponse{
+		ID:          organizacao.ID,
+		Nome:        organizacao.Nome,
+		TitularID:   organizacao.TitularID,
+		MeuPapel:    papel,
+		Membros:     membrosDTO,
+		DataCriacao: organizacao.DataCriacao,
+	}, nil
+}
+
+// ConvidarMembro cria um convite e envia por email
+func (s *OrganizacaoServico) ConvidarMembro(ctx context.Context, contaID uuid.UUID, ator dto.Ator, papelAtor enums.PapelMembro, req dto.ConvidarMembroRequest) (*dto.MembroResponse, error) {
+	if !req.Papel.Valido() || req.Papel == enums.PapelOwner {
+		return nil, errors.New("papel inválido")
+	}
+	if !papelAtor.Permite(req.Papel) {
+		return nil, errors.New("você não pode conceder um papel superior ao seu")
+	}
+
+	organizacao, err := s.obterOuCriar(contaID)
+	if err != nil {
+		return nil, err
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if _, err := s.organizacaoRepo.BuscarMembroPorEmail(email, organizacao.ID); err == nil {
+		return nil, errors.New("este email já é membro ou já foi convidado")
+	}
+
+	token, err := util.GerarTokenAleatorio(32)
+	if err != nil {
+		return nil, err
+	}
+
+	agora := time.Now()
+	membro := &entidades.MembroOrganizacao{
+		OrganizacaoID:  organizacao.ID,
+		Email:          email,
+		Papel:          req.Papel,
+		Status:         entidades.StatusMembroConvidado,
+		TokenConvite:   token,
+		ConvidadoPorID: &ator.ID,
+		DataConvite:    &agora,
+	}
+
+	err = s.auditoriaServico.Transacao(func(tx *gorm.DB) error {
+		if err := s.organizacaoRepo.ComTransacao(tx).CriarMembro(membro); err != nil {
+			return err
+		}
+		return s.auditoriaServico.Registrar(tx, contaID, ator, enums.AcaoAuditoriaCriar,
+			enums.EntidadeAuditoriaMembro, membro.ID.String(), nil, membro)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	link := fmt.Sprintf("%s/convite?token=%s", viper.GetString("APP_FRONTEND_URL"), token)
+	if err := s.resendAPI.EnviarEmailConviteMembro(ctx, email, organizacao.Nome, string(req.Papel), link); err != nil {
+		log.Printf("⚠️  Erro ao enviar convite para %s: %v", email, err)
+	}
+
+	resposta := s.mapearMembroParaDTO(membro)
+	return &resposta, nil
+}
+
+// AceitarConvite vincula o usuário autenticado ao convite pendente
+func (s *OrganizacaoServico) AceitarConvite(email string, token string) (*dto.MembroResponse, error) {
+	usuario, err := s.usuarioRepo.BuscarPorEmail(email)
+	if err != nil {
+		return nil, errors.New("usuário não encontrado")
+	}
+
+	membro, err := s.organizacaoRepo.BuscarMembroPorToken(token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("convite não encontrado ou já utilizado")
+		}
+		return nil, err
+	}
+
+	if !strings.EqualFold(membro.Email, usuario.Email) {
+		return nil, errors.New("este convite foi enviado para outro email")
+	}
+
+	if membro.Organizacao.TitularID == usuario.ID {
+		return nil, errors.New("você já é o titular desta organização")
+	}
+
+	jaMembro, err := s.organizacaoRepo.ExisteAssociacaoConvidado(usuario.ID)
+	if err != nil {
+		return nil, err
+	}
+	if jaMembro {
+		return nil, errors.New("usuário já participa de outra organização")
+	}
+
+	membro.AceitarConvite(usuario.ID)
+	if err := s.organizacaoRepo.AtualizarMembro(membro); err != nil {
+		return nil, err
+	}
+
+	resposta := s.mapearMembroParaDTO(membro)
+	return &resposta, nil
+}
+
+// AlterarPapel altera o papel de um membro da organização
+func (s *OrganizacaoServico) AlterarPapel(contaID uuid.UUID, ator dto.Ator, papelAtor enums.PapelMembro, membroID uuid.UUID, novoPapel enums.PapelMembro) (*dto.MembroResponse, error) {
+	if !novoPapel.Valido() || novoPapel == enums.PapelOwner {
+		return nil, errors.New("papel inválido")
+	}
+
+	membro, err := s.buscarMembroGerenciavel(contaID, papelAtor, membroID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !papelAtor.Permite(novoPapel) {
+		return nil, errors.New("você não pode conceder um papel superior ao seu")
+	}
+
+	antes := *membro
+	membro.Papel = novoPapel
+	if err := s.atualizarMembroComAuditoria(contaID, ator, &antes, membro); err != nil {
+		return nil, err
+	}
+
+	resposta := s.mapearMembroParaDTO(membro)
+	return &resposta, nil
+}
+
+// RemoverMembro remove um membro ou cancela um convite pendente
+func (s *OrganizacaoServico) RemoverMembro(contaID uuid.UUID, ator dto.Ator, papelAtor enums.PapelMembro, membroID uuid.UUID) error {
+	membro, err := s.buscarMembroGerenciavel(contaID, papelAtor, membroID)
+	if err != nil {
+		return err
+	}
+
+	antes := *membro
+	membro.Remover()
+	return s.atualizarMembroComAuditoria(contaID, ator, &antes, membro)
+}
+
+// atualizarMembroComAuditoria salva o membro e registra o diff na mesma transação
+func (s *OrganizacaoServico) atualizarMembroComAuditoria(contaID uuid.UUID, ator dto.Ator, antes, depois *entidades.MembroOrganizacao) error {
+	return s.auditoriaServico.Transacao(func(tx *gorm.DB) error {
+		if err := s.organizacaoRepo.ComTransacao(tx).AtualizarMembro(depois); err != nil {
+			return err
+		}
+		return s.auditoriaServico.Registrar(tx, contaID, ator, enums.AcaoAuditoriaAtualizar,
+			enums.EntidadeAuditoriaMembro, depois.ID.String(), antes, depois)
+	})
+}
+
+// buscarMembroGerenciavel busca um membro da conta que o ator pode alterar
+func (s *OrganizacaoServico) buscarMembroGerenciavel(contaID uuid.UUID, papelAtor enums.PapelMembro, membroID uuid.UUID) (*entidades.MembroOrganizacao, error) {
+	organizacao, err := s.organizacaoRepo.BuscarPorTitular(contaID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("membro não encontrado")
+		}
+		return nil, err
+	}
+
+	membro, err := s.organizacaoRepo.BuscarMembroPorID(membroID, organizacao.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("membro não encontrado")
+		}
+		return nil, err
+	}
+
+	if membro.Status == entidades.StatusMembroRemovido {
+		return nil, errors.New("membro não encontrado")
+	}
+
+	if membro.IsTitular() {
+		return nil, errors.New("não é possível alterar o titular da organização")
+	}
+
+	if !papelAtor.Permite(membro.Papel) {
+		return nil, errors.New("você não pode alterar um membro com papel superior ao seu")
+	}
+
+	return membro, nil
+}
+
+// obterOuCriar retorna a organização do titular, criando-a no primeiro acesso
+func (s *OrganizacaoServico) obterOuCriar(titularID uuid.UUID) (*entidades.Organizacao, error) {
+	organizacao, err := s.organizacaoRepo.BuscarPorTitular(titularID)
+	if err == nil {
+		return organizacao, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	titular, err := s.usuarioRepo.BuscarPorID(titularID)
+	if err != nil {
+		return nil, err
+	}
+
+	nome := titular.NomeEmpresa
+	if nome == "" {
+		nome = titular.NomeCompleto
+	}
+
+	agora := time.Now()
+	organizacao = &entidades.Organizacao{
+		TitularID: titularID,
+		Nome:      nome,
+	}
+	membroTitular := &entidades.MembroOrganizacao{
+		UsuarioID:  &titularID,
+		Email:      titular.Email,
+		Papel:      enums.PapelOwner,
+		Status:     entidades.StatusMembroAtivo,
+		DataAceite: &agora,
+	}
+
+	if err := s.organizacaoRepo.Criar(organizacao, membroTitular); err != nil {
+		return nil, err
+	}
+
+	return organizacao, nil
+}
+
+// mapearMembroParaDTO converte MembroOrganizacao para MembroResponse
+func (s *OrganizacaoServico) mapearMembroParaDTO(membro *entidades.MembroOrganizacao) dto.MembroResponse {
+	return dto.MembroResponse{
+		ID:          membro.ID,
+		UsuarioID:   membro.UsuarioID,
+		Email:       membro.Email,
+		Papel:       membro.Papel,
+		Status:      string(membro.Status),
+		DataConvite: membro.DataConvite,
+		DataAceite:  membro.DataAceite,
+	}
+}
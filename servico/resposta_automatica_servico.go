@@ -0,0 +1,563 @@
+package servico
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/dominio/entidades"
+	"github.com/ifinu/ifinu-api-go/dominio/enums"
+	"github.com/ifinu/ifinu-api-go/dto"
+	"github.com/ifinu/ifinu-api-go/logs"
+	"github.com/ifinu/ifinu-api-go/rastreamento"
+	"github.com/ifinu/ifinu-api-go/repositorio"
+	"github.com/ifinu/ifinu-api-go/util"
+	"go.opentelemetry.io/otel/attribute"
+	"gorm.io/gorm"
+)
+
+// IntervaloRespostaPadrao evita repetir a resposta padrão a cada mensagem da mesma conversa
+const IntervaloRespostaPadrao = 24 * time.Hour
+
+// regrasIniciais são criadas na primeira configuração do auto-responder
+var regrasIniciais = []entidades.RespostaAutomatica{
+	{PalavraChave: "2VIA", Acao: enums.AcaoRespostaSegundaVia},
+	{PalavraChave: "PIX", Acao: enums.AcaoRespostaPix},
+	{PalavraChave: "SAIR", Acao: enums.AcaoRespostaDescadastrar},
+}
+
+// mensagensPadraoAcao são usadas quando a regra não define um texto próprio.
+// Variáveis: {nome}, {valor}, {vencimento}, {descricao}, {link}
+var mensagensPadraoAcao = map[enums.AcaoRespostaAutomatica]string{
+	enums.AcaoRespostaSegundaVia: "📄 *Segunda via*\n\n" +
+		"Olá, {nome}!\n\n" +
+		"💰 Valor: R$ {valor}\n" +
+		"📝 Descrição: {descricao}\n" +
+		"📅 Vencimento: {vencimento}\n" +
+		"{link}",
+	enums.AcaoRespostaPix: "💠 *PIX copia e cola*\n\n" +
+		"Olá, {nome}! Segue o código para pagar R$ {valor} ({descricao}). " +
+		"Copie a próxima mensagem e cole no app do seu banco.",
+	enums.AcaoRespostaDescadastrar: "✅ Pronto, {nome}. Você não receberá mais lembretes automáticos de cobrança por aqui.",
+}
+
+const mensagemSemCobrancaEmAberto = "Olá, {nome}! Não encontramos cobranças em aberto para este número."
+
+// RespostaAutomaticaServico responde mensagens recebidas no WhatsApp por palavra-chave
+// (ex: 2VIA, PIX, SAIR) e registra cada resposta enviada
+type RespostaAutomaticaServico struct {
+	respostaRepo     *repositorio.RespostaAutomaticaRepositorio
+	mensagemRepo     *repositorio.MensagemWhatsAppRepositorio
+	clienteRepo      *repositorio.ClienteRepositorio
+	cobrancaRepo     *repositorio.CobrancaRepositorio
+	whatsappServico  *WhatsAppServico
+	clienteServico   *ClienteServico
+	auditoriaServico *AuditoriaServico
+	logger           *slog.Logger
+}
+
+func NovoRespostaAutomaticaServico(
+	respostaRepo *repositorio.RespostaAutomaticaRepositorio,
+	mensagemRepo *repositorio.MensagemWhatsAppRepositorio,
+	clienteRepo *repositorio.ClienteRepositorio,
+	cobrancaRepo *repositorio.CobrancaRepositorio,
+	whatsappServico *WhatsAppServico,
+	clienteServico *ClienteServico,
+	auditoriaServico *AuditoriaServico,
+	logger *slog.Logger,
+) *RespostaAutomaticaServico {
+	return &RespostaAutomaticaServico{
+		respostaRepo:     respostaRepo,
+		mensagemRepo:     mensagemRepo,
+		clienteRepo:      clienteRepo,
+		cobrancaRepo:     cobrancaRepo,
+		whatsappServico:  whatsappServico,
+		clienteServico:   clienteServico,
+		auditoriaServico: auditoriaServico,
+		logger:           logger,
+	}
+}
+
+// ObterConfig retorna a configuração do auto-responder (desativado se nunca configurado)
+func (s *RespostaAutomaticaServico) ObterConfig(usuarioID uuid.UUID) (*dto.ConfigRespostaAutomaticaResponse, error) {
+	config, err := s.respostaRepo.BuscarConfig(usuarioID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &dto.ConfigRespostaAutomaticaResponse{}, nil
+		}
+		return nil, err
+	}
+	return mapearConfigRespostaAutomaticaParaDTO(config), nil
+}
+
+// SalvarConfig atualiza a configuração do auto-responder. Na primeira configuração
+// de uma conta sem regras, cria as regras 2VIA, PIX e SAIR.
+func (s *RespostaAutomaticaServico) SalvarConfig(usuarioID uuid.UUID, ator dto.Ator, req dto.ConfigRespostaAutomaticaRequest) (*dto.ConfigRespostaAutomaticaResponse, error) {
+	config := &entidades.ConfigRespostaAutomatica{
+		UsuarioID:      usuarioID,
+		Ativo:          req.Ativo,
+		MensagemPadrao: strings.TrimSpace(req.MensagemPadrao),
+		ChavePix:       strings.TrimSpace(req.ChavePix),
+		RecebedorPix:   strings.TrimSpace(req.RecebedorPix),
+		CidadePix:      strings.TrimSpace(req.CidadePix),
+	}
+
+	if config.ChavePix != "" {
+		pix := util.PixEstatico{Chave: config.ChavePix, Recebedor: config.RecebedorPix, Cidade: config.CidadePix}
+		if _, err := util.GerarPixCopiaECola(pix); err != nil {
+			return nil, err
+		}
+	}
+
+	anterior, err := s.respostaRepo.BuscarConfig(usuarioID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	primeiraConfig := anterior == nil
+
+	var regrasCriadas []entidades.RespostaAutomatica
+	if primeiraConfig {
+		regras, err := s.respostaRepo.ListarRegras(usuarioID)
+		if err != nil {
+			return nil, err
+		}
+		if len(regras) == 0 {
+			regrasCriadas = make([]entidades.RespostaAutomatica, len(regrasIniciais))
+			for i, regra := range regrasIniciais {
+				regra.UsuarioID = usuarioID
+				regra.Ativo = true
+				regrasCriadas[i] = regra
+			}
+		}
+	} else {
+		config.DataCriacao = anterior.DataCriacao
+	}
+
+	err = s.auditoriaServico.Transacao(func(tx *gorm.DB) error {
+		repo := s.respostaRepo.ComTransacao(tx)
+		if err := repo.SalvarConfig(config); err != nil {
+			return err
+		}
+
+		acao := enums.AcaoAuditoriaAtualizar
+		var antes interface{}
+		if primeiraConfig {
+			acao = enums.AcaoAuditoriaCriar
+		} else {
+			antes = anterior
+		}
+		if err := s.auditoriaServico.Registrar(tx, usuarioID, ator, acao,
+			enums.EntidadeAuditoriaConfigRespostaAutomatica, usuarioID.String(), antes, config); err != nil {
+			return err
+		}
+
+		for i := range regrasCriadas {
+			if err := repo.CriarRegra(&regrasCriadas[i]); err != nil {
+				return err
+			}
+			if err := s.auditoriaServico.Registrar(tx, usuarioID, ator, enums.AcaoAuditoriaCriar,
+				enums.EntidadeAuditoriaRespostaAutomatica, regrasCriadas[i].ID.String(), nil, &regrasCriadas[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mapearConfigRespostaAutomaticaParaDTO(config), nil
+}
+
+// ListarRegras lista as regras do auto-responder da conta
+func (s *RespostaAutomaticaServico) ListarRegras(usuarioID uuid.UUID) ([]dto.RespostaAutomaticaResponse, error) {
+	regras, err := s.respostaRepo.ListarRegras(usuarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	resposta := make([]dto.RespostaAutomaticaResponse, len(regras))
+	for i, regra := range regras {
+		resposta[i] = *mapearRespostaAutomaticaParaDTO(&regra)
+	}
+	return resposta, nil
+}
+
+// CriarRegra cadastra uma nova regra do auto-responder
+func (s *RespostaAutomaticaServico) CriarRegra(usuarioID uuid.UUID, ator dto.Ator, req dto.RespostaAutomaticaRequest) (*dto.RespostaAutomaticaResponse, error) {
+	regra := &entidades.RespostaAutomatica{
+		UsuarioID: usuarioID,
+		Ativo:     req.Ativo == nil || *req.Ativo,
+	}
+	if err := s.aplicarRequest(regra, req); err != nil {
+		return nil, err
+	}
+
+	err := s.auditoriaServico.Transacao(func(tx *gorm.DB) error {
+		if err := s.respostaRepo.ComTransacao(tx).CriarRegra(regra); err != nil {
+			return err
+		}
+		return s.auditoriaServico.Registrar(tx, usuarioID, ator, enums.AcaoAuditoriaCriar,
+			enums.EntidadeAuditoriaRespostaAutomatica, regra.ID.String(), nil, regra)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mapearRespostaAutomaticaParaDTO(regra), nil
+}
+
+// AtualizarRegra altera palavra-chave, ação, texto e status de uma regra
+func (s *RespostaAutomaticaServico) AtualizarRegra(usuarioID uuid.UUID, ator dto.Ator, regraID uuid.UUID, req dto.RespostaAutomaticaRequest) (*dto.RespostaAutomaticaResponse, error) {
+	regra, err := s.respostaRepo.BuscarRegraPorID(regraID, usuarioID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("resposta automática não encontrada")
+		}
+		return nil, err
+	}
+
+	antes := *regra
+	if err := s.aplicarRequest(regra, req); err != nil {
+		return nil, err
+	}
+	if req.Ativo != nil {
+		regra.Ativo = *req.Ativo
+	}
+
+	err = s.auditoriaServico.Transacao(func(tx *gorm.DB) error {
+		if err := s.respostaRepo.ComTransacao(tx).AtualizarRegra(regra); err != nil {
+			return err
+		}
+		return s.auditoriaServico.Registrar(tx, usuarioID, ator, enums.AcaoAuditoriaAtualizar,
+			enums.EntidadeAuditoriaRespostaAutomatica, regra.ID.String(), &antes, regra)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mapearRespostaAutomaticaParaDTO(regra), nil
+}
+
+// RemoverRegra exclui uma regra do auto-responder
+func (s *RespostaAutomaticaServico) RemoverRegra(usuarioID uuid.UUID, ator dto.Ator, regraID uuid.UUID) error {
+	regra, err := s.respostaRepo.BuscarRegraPorID(regraID, usuarioID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("resposta automática não encontrada")
+		}
+		return err
+	}
+
+	return s.auditoriaServico.Transacao(func(tx *gorm.DB) error {
+		if err := s.respostaRepo.ComTransacao(tx).DeletarRegra(regraID, usuarioID); err != nil {
+			return err
+		}
+		return s.auditoriaServico.Registrar(tx, usuarioID, ator, enums.AcaoAuditoriaExcluir,
+			enums.EntidadeAuditoriaRespostaAutomatica, regraID.String(), regra, nil)
+	})
+}
+
+// ListarDisparos retorna o log de respostas automáticas enviadas
+func (s *RespostaAutomaticaServico) ListarDisparos(usuarioID uuid.UUID, req dto.BuscarDisparosRespostaAutomaticaRequest) (*dto.DisparoRespostaAutomaticaListResponse, error) {
+	paginaOriginal := req.Pagina
+	if req.Pagina == 0 {
+		req.Pagina = 1
+	}
+	if req.TamanhoPagina == 0 {
+		req.TamanhoPagina = 20
+	}
+
+	disparos, total, err := s.respostaRepo.ListarDisparos(usuarioID, req.Pagina, req.TamanhoPagina)
+	if err != nil {
+		return nil, err
+	}
+
+	resposta := make([]dto.DisparoRespostaAutomaticaResponse, len(disparos))
+	for i, disparo := range disparos {
+		resposta[i] = dto.DisparoRespostaAutomaticaResponse{
+			ID:           disparo.ID,
+			RespostaID:   disparo.RespostaID,
+			MensagemID:   disparo.MensagemID,
+			PalavraChave: disparo.PalavraChave,
+			Acao:         disparo.Acao,
+			Padrao:       disparo.IsPadrao(),
+			Telefone:     disparo.Telefone,
+			ClienteID:    disparo.ClienteID,
+			CobrancaID:   disparo.CobrancaID,
+			Sucesso:      disparo.Sucesso,
+			Erro:         disparo.Erro,
+			DataCriacao:  disparo.DataCriacao,
+		}
+	}
+
+	return &dto.DisparoRespostaAutomaticaListResponse{
+		Disparos:      resposta,
+		Total:         total,
+		Pagina:        paginaOriginal,
+		TamanhoPagina: req.TamanhoPagina,
+		TotalPaginas:  int(math.Ceil(float64(total) / float64(req.TamanhoPagina))),
+	}, nil
+}
+
+// Processar responde uma mensagem recebida conforme as regras da conta.
+// Sem regra correspondente, envia a resposta padrão no máximo uma vez por IntervaloRespostaPadrao.
+func (s *RespostaAutomaticaServico) Processar(ctx context.Context, mensagem *entidades.MensagemWhatsApp) {
+	if !mensagem.IsEntrada() || strings.TrimSpace(mensagem.Texto) == "" {
+		return
+	}
+
+	ctx, span := rastreamento.Iniciar(ctx, "resposta_automatica.processar")
+	defer span.End()
+
+	config, err := s.respostaRepo.ComContexto(ctx).BuscarConfig(mensagem.UsuarioID)
+	if err != nil || !config.Ativo {
+		return
+	}
+
+	regras, err := s.respostaRepo.ComContexto(ctx).ListarRegrasAtivas(mensagem.UsuarioID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "❌ Erro ao buscar respostas automáticas", logs.Erro(err))
+		return
+	}
+
+	regra := escolherRegra(regras, mensagem.Texto)
+
+	disparo := &entidades.DisparoRespostaAutomatica{
+		UsuarioID:  mensagem.UsuarioID,
+		MensagemID: mensagem.ID,
+		Telefone:   mensagem.Telefone,
+		ClienteID:  mensagem.ClienteID,
+		CobrancaID: mensagem.CobrancaID,
+	}
+
+	var textos []string
+	if regra != nil {
+		disparo.RespostaID = &regra.ID
+		disparo.PalavraChave = regra.PalavraChave
+		disparo.Acao = regra.Acao
+		textos, err = s.executarRegra(regra, config, mensagem)
+	} else {
+		if config.MensagemPadrao == "" {
+			return
+		}
+		recente, err := s.respostaRepo.ComContexto(ctx).ExisteDisparoRecente(mensagem.UsuarioID, mensagem.Telefone, time.Now().Add(-IntervaloRespostaPadrao))
+		if err != nil || recente {
+			return
+		}
+		textos = []string{s.preencherVariaveis(config.MensagemPadrao, mensagem, nil)}
+	}
+
+	if err == nil {
+		err = s.enviar(ctx, mensagem, textos)
+	}
+
+	disparo.Sucesso = err == nil
+	span.SetAttributes(attribute.String("ifinu.palavra_chave", disparo.PalavraChave))
+	rastreamento.RegistrarErro(span, err)
+	if err != nil {
+		disparo.Erro = err.Error()
+		s.logger.ErrorContext(ctx, "❌ Erro na resposta automática", "telefone", mensagem.Telefone, "palavra_chave", disparo.PalavraChave, logs.Erro(err))
+	} else {
+		s.logger.InfoContext(ctx, "🤖 Resposta automática enviada", "telefone", mensagem.Telefone, "palavra_chave", disparo.PalavraChave)
+	}
+
+	if err := s.respostaRepo.ComContexto(ctx).RegistrarDisparo(disparo); err != nil {
+		s.logger.WarnContext(ctx, "⚠️  Erro ao registrar resposta automática", logs.Erro(err))
+	}
+}
+
+// escolherRegra retorna a regra da mensagem. Regras com ação (2ª via, PIX, descadastro)
+// só valem quando a mensagem inteira é a palavra-chave: "não vou poder sair hoje" não pode
+// descadastrar o cliente. Respostas de texto também aceitam a palavra-chave dentro da frase.
+func escolherRegra(regras []entidades.RespostaAutomatica, texto string) *entidades.RespostaAutomatica {
+	for i := range regras {
+		if util.IgualPalavraChave(texto, regras[i].PalavraChave) {
+			return &regras[i]
+		}
+	}
+	for i := range regras {
+		if regras[i].Acao == enums.AcaoRespostaTexto && util.ContemPalavraChave(texto, regras[i].PalavraChave) {
+			return &regras[i]
+		}
+	}
+	return nil
+}
+
+// executarRegra executa a ação da regra e retorna as mensagens a enviar
+func (s *RespostaAutomaticaServico) executarRegra(regra *entidades.RespostaAutomatica, config *entidades.ConfigRespostaAutomatica, mensagem *entidades.MensagemWhatsApp) ([]string, error) {
+	modelo := regra.Mensagem
+	if modelo == "" {
+		modelo = mensagensPadraoAcao[regra.Acao]
+	}
+
+	switch regra.Acao {
+	case enums.AcaoRespostaTexto:
+		return []string{s.preencherVariaveis(modelo, mensagem, nil)}, nil
+
+	case enums.AcaoRespostaSegundaVia, enums.AcaoRespostaPix:
+		cobranca := s.buscarCobranca(mensagem)
+		if cobranca == nil {
+			return []string{s.preencherVariaveis(mensagemSemCobrancaEmAberto, mensagem, nil)}, nil
+		}
+		textos := []string{s.preencherVariaveis(modelo, mensagem, cobranca)}
+
+		if regra.Acao == enums.AcaoRespostaPix {
+			codigo, err := util.GerarPixCopiaECola(util.PixEstatico{
+				Chave:         config.ChavePix,
+				Recebedor:     config.RecebedorPix,
+				Cidade:        config.CidadePix,
+				Valor:         cobranca.Valor,
+				Identificador: cobranca.ID.String(),
+			})
+			if err != nil {
+				return nil, err
+			}
+			// Mensagem separada para o cliente copiar só o código
+			textos = append(textos, codigo)
+		}
+		return textos, nil
+
+	case enums.AcaoRespostaDescadastrar:
+		if mensagem.ClienteID == nil {
+			return nil, errors.New("telefone não vinculado a um cliente")
+		}
+		if err := s.descadastrarCliente(mensagem.UsuarioID, *mensagem.ClienteID); err != nil {
+			return nil, err
+		}
+		return []string{s.preencherVariaveis(modelo, mensagem, nil)}, nil
+	}
+
+	return nil, fmt.Errorf("ação de resposta automática desconhecida: %s", regra.Acao)
+}
+
+// buscarCobranca carrega a cobrança em aberto vinculada à mensagem no recebimento
+func (s *RespostaAutomaticaServico) buscarCobranca(mensagem *entidades.MensagemWhatsApp) *entidades.Cobranca {
+	if mensagem.CobrancaID == nil {
+		return nil
+	}
+
+	cobranca, err := s.cobrancaRepo.BuscarPorID(*mensagem.CobrancaID, mensagem.UsuarioID)
+	if err != nil || cobranca.IsPaga() {
+		return nil
+	}
+	return cobranca
+}
+
+// descadastrarCliente registra o opt-out de lembretes pedido pelo cliente no WhatsApp
+func (s *RespostaAutomaticaServico) descadastrarCliente(usuarioID uuid.UUID, clienteID uuid.UUID) error {
+	cliente, err := s.clienteRepo.BuscarPorID(clienteID, usuarioID)
+	if err != nil {
+		return err
+	}
+	return s.clienteServico.RegistrarOptOut(cliente, enums.OrigemOptOutWhatsApp)
+}
+
+// enviar manda as mensagens pelo mesmo número da conversa e as grava na caixa de entrada
+func (s *RespostaAutomaticaServico) enviar(ctx context.Context, origem *entidades.MensagemWhatsApp, textos []string) error {
+	rota := RotaMensagem{ConexaoID: &origem.ConexaoID}
+
+	for _, texto := range textos {
+		resultado, err := s.whatsappServico.EnviarMensagemSincrono(ctx, origem.UsuarioID, rota, origem.Telefone, texto)
+		if err != nil {
+			return err
+		}
+
+		if _, err := s.mensagemRepo.ComContexto(ctx).Criar(novaMensagemSaida(origem, texto, resultado.MessageID)); err != nil {
+			s.logger.WarnContext(ctx, "⚠️  Resposta automática enviada mas não registrada na conversa", "telefone", origem.Telefone, logs.Erro(err))
+		}
+	}
+	return nil
+}
+
+// preencherVariaveis substitui {nome}, {valor}, {vencimento}, {descricao} e {link} no texto
+func (s *RespostaAutomaticaServico) preencherVariaveis(modelo string, mensagem *entidades.MensagemWhatsApp, cobranca *entidades.Cobranca) string {
+	nome := mensagem.NomeContato
+	if cobranca != nil && cobranca.Cliente.Nome != "" {
+		nome = cobranca.Cliente.Nome
+	} else if mensagem.ClienteID != nil {
+		if cliente, err := s.clienteRepo.BuscarPorID(*mensagem.ClienteID, mensagem.UsuarioID); err == nil {
+			nome = cliente.Nome
+		}
+	}
+
+	var valor, vencimento, descricao, link string
+	if cobranca != nil {
+		valor = fmt.Sprintf("%.2f", cobranca.Valor)
+		vencimento = cobranca.DataVencimento.Format("02/01/2006")
+		descricao = cobranca.Descricao
+		if cobranca.AsaasPaymentURL != "" {
+			link = "🔗 Pagamento: " + cobranca.AsaasPaymentURL
+		}
+	}
+
+	texto := strings.NewReplacer(
+		"{nome}", nome,
+		"{valor}", valor,
+		"{vencimento}", vencimento,
+		"{descricao}", descricao,
+		"{link}", link,
+	).Replace(modelo)
+	return strings.TrimSpace(texto)
+}
+
+// aplicarRequest valida e copia os campos editáveis da regra
+func (s *RespostaAutomaticaServico) aplicarRequest(regra *entidades.RespostaAutomatica, req dto.RespostaAutomaticaRequest) error {
+	if !req.Acao.Valido() {
+		return fmt.Errorf("ação inválida: %s", req.Acao)
+	}
+
+	palavraChave := util.NormalizarTexto(req.PalavraChave)
+	if palavraChave == "" {
+		return errors.New("palavra-chave inválida")
+	}
+	if req.Acao == enums.AcaoRespostaTexto && strings.TrimSpace(req.Mensagem) == "" {
+		return errors.New("mensagem é obrigatória para respostas do tipo TEXTO")
+	}
+
+	existe, err := s.respostaRepo.ExistePalavraChave(regra.UsuarioID, palavraChave, regra.ID)
+	if err != nil {
+		return err
+	}
+	if existe {
+		return errors.New("já existe uma resposta automática com esta palavra-chave")
+	}
+
+	regra.PalavraChave = palavraChave
+	regra.Acao = req.Acao
+	regra.Mensagem = strings.TrimSpace(req.Mensagem)
+	return nil
+}
+
+// mapearRespostaAutomaticaParaDTO converte RespostaAutomatica para RespostaAutomaticaResponse
+func mapearRespostaAutomaticaParaDTO(regra *entidades.RespostaAutomatica) *dto.RespostaAutomaticaResponse {
+	return &dto.RespostaAutomaticaResponse{
+		ID:           regra.ID,
+		PalavraChave: regra.PalavraChave,
+		Acao:         regra.Acao,
+		Mensagem:     regra.Mensagem,
+		Ativo:        regra.Ativo,
+		DataCriacao:  regra.DataCriacao,
+	}
+}
+
+// mapearConfigRespostaAutomaticaParaDTO converte ConfigRespostaAutomatica para ConfigRespostaAutomaticaResponse
+func mapearConfigRespostaAutomaticaParaDTO(config *entidades.ConfigRespostaAutomatica) *dto.ConfigRespostaAutomaticaResponse {
+	resposta := &dto.ConfigRespostaAutomaticaResponse{
+		Ativo:          config.Ativo,
+		MensagemPadrao: config.MensagemPadrao,
+		ChavePix:       config.ChavePix,
+		RecebedorPix:   config.RecebedorPix,
+		CidadePix:      config.CidadePix,
+	}
+	if !config.DataAtualizacao.IsZero() {
+		dataAtualizacao := config.DataAtualizacao
+		resposta.DataAtualizacao = &dataAtualizacao
+	}
+	return resposta
+}
@@ -0,0 +1,40 @@
+package servico
+
+import (
+	"testing"
+
+	"github.com/ifinu/ifinu-api-go/dominio/entidades"
+	"github.com/ifinu/ifinu-api-go/dominio/enums"
+)
+
+func TestEscolherRegra(t *testing.T) {
+	regras := []entidades.RespostaAutomatica{
+		{PalavraChave: "2VIA", Acao: enums.AcaoRespostaSegundaVia},
+		{PalavraChave: "PIX", Acao: enums.AcaoRespostaPix},
+		{PalavraChave: "SAIR", Acao: enums.AcaoRespostaDescadastrar},
+		{PalavraChave: "HORARIO", Acao: enums.AcaoRespostaTexto},
+	}
+
+	casos := []struct {
+		texto    string
+		esperada string // palavra-chave da regra escolhida; vazio = nenhuma
+	}{
+		{"Sair", "SAIR"},
+		{"2 via", "2VIA"},
+		{"pix!", "PIX"},
+		{"não vou poder sair hoje, pago amanhã", ""},  // não descadastra
+		{"já fiz o pix ontem", ""},                    // não reenvia o código
+		{"qual o horario de atendimento?", "HORARIO"}, // texto aceita dentro da frase
+		{"bom dia", ""},
+	}
+	for _, caso := range casos {
+		regra := escolherRegra(regras, caso.texto)
+		obtida := ""
+		if regra != nil {
+			obtida = regra.PalavraChave
+		}
+		if obtida != caso.esperada {
+			t.Errorf("%q: regra %q, esperado %q", caso.texto, obtida, caso.esperada)
+		}
+	}
+}
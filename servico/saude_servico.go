@@ -0,0 +1,168 @@
+package servico
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/ifinu/ifinu-api-go/dto"
+	"github.com/ifinu/ifinu-api-go/integracao"
+	"github.com/ifinu/ifinu-api-go/rastreamento"
+	"gorm.io/gorm"
+)
+
+// Status das verificações de saúde e do resultado geral
+const (
+	StatusSaudeOK         = "ok"
+	StatusSaudeDegradado  = "degradado" // Falhou apenas verificação não crítica
+	StatusSaudeFalha      = "falha"
+	StatusSaudeEncerrando = "encerrando"
+)
+
+// SaudeServico executa as verificações das probes do Kubernetes.
+//
+// A liveness só olha o próprio processo (workers das filas e cron): reiniciar o pod não
+// resolve uma dependência fora do ar. A readiness verifica as dependências; só o banco é
+// crítico, porque sem Redis a API continua atendendo (rate limit em memória) e tirar
+// todas as réplicas do ar por causa dele derrubaria tudo.
+type SaudeServico struct {
+	db                *gorm.DB
+	redisClient       *redis.Client
+	filaMensagem      *FilaMensagemServico
+	webhookServico    *WebhookServico
+	agendador         *AgendadorServico
+	evolutionAPI      *integracao.EvolutionAPICliente
+	resendAPI         *integracao.ResendCliente
+	verificarExternos bool
+	timeout           time.Duration
+}
+
+// verificacaoSaude é uma dependência verificada; critica define se a falha derruba a probe
+type verificacaoSaude struct {
+	nome      string
+	critica   bool
+	verificar func(ctx context.Context) error
+}
+
+// NovoSaudeServico cria o serviço. Com verificarExternos a readiness também confirma que
+// Evolution API e Resend respondem (sem derrubar a probe). timeout limita cada verificação.
+func NovoSaudeServico(
+	db *gorm.DB,
+	redisAddr string,
+	agendador *AgendadorServico,
+	webhookServico *WebhookServico,
+	evolutionAPI *integracao.EvolutionAPICliente,
+	resendAPI *integracao.ResendCliente,
+	verificarExternos bool,
+	timeout time.Duration,
+) *SaudeServico {
+	return &SaudeServico{
+		db: db,
+		redisClient: redis.NewClient(&redis.Options{
+			Addr:        redisAddr,
+			DialTimeout: timeout,
+			ReadTimeout: timeout,
+			PoolSize:    2,
+			MaxRetries:  -1,
+		}),
+		filaMensagem:      agendador.FilaMensagem(),
+		webhookServico:    webhookServico,
+		agendador:         agendador,
+		evolutionAPI:      evolutionAPI,
+		resendAPI:         resendAPI,
+		verificarExternos: verificarExternos,
+		timeout:           timeout,
+	}
+}
+
+// Vivacidade verifica se os workers das filas e o cron desta réplica continuam rodando
+func (s *SaudeServico) Vivacidade(ctx context.Context) dto.SaudeResponse {
+	verificacoes := []verificacaoSaude{
+		{nome: "agendador", critica: true, verificar: func(context.Context) error { return s.agendador.verificarCron() }},
+		{nome: "webhooks", critica: true, verificar: func(context.Context) error { return s.webhookServico.verificarWorkers() }},
+	}
+	if s.filaMensagem != nil {
+		verificacoes = append(verificacoes, verificacaoSaude{nome: "fila", critica: true,
+			verificar: func(context.Context) error { return s.filaMensagem.verificarWorkers() }})
+	} else {
+		// Sem Redis na inicialização a fila fica desabilitada até o próximo restart; reiniciar
+		// em loop enquanto o Redis estiver fora não ajuda
+		verificacoes = append(verificacoes, verificacaoSaude{nome: "fila",
+			verificar: func(context.Context) error {
+				return errors.New("fila desabilitada: redis indisponível na inicialização")
+			}})
+	}
+	return s.executar(ctx, verificacoes)
+}
+
+// Prontidao verifica as dependências necessárias para atender requisições
+func (s *SaudeServico) Prontidao(ctx context.Context) dto.SaudeResponse {
+	verificacoes := []verificacaoSaude{
+		{nome: "banco", critica: true, verificar: s.verificarBanco},
+		{nome: "redis", verificar: func(ctx context.Context) error { return s.redisClient.Ping(ctx).Err() }},
+	}
+	if s.verificarExternos {
+		verificacoes = append(verificacoes,
+			verificacaoSaude{nome: "evolution", verificar: s.evolutionAPI.Verificar},
+			verificacaoSaude{nome: "resend", verificar: s.resendAPI.Verificar},
+		)
+	}
+	return s.executar(rastreamento.SemRastreamento(ctx), verificacoes)
+}
+
+func (s *SaudeServico) verificarBanco(ctx context.Context) error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// executar roda as verificações em paralelo, cada uma limitada por s.timeout. O status
+// geral é falha se alguma crítica falhar e degradado se só as não críticas falharem.
+func (s *SaudeServico) executar(ctx context.Context, verificacoes []verificacaoSaude) dto.SaudeResponse {
+	resposta := dto.SaudeResponse{
+		Status:       StatusSaudeOK,
+		Verificacoes: make(map[string]dto.VerificacaoSaudeResponse, len(verificacoes)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, v := range verificacoes {
+		wg.Add(1)
+		go func(v verificacaoSaude) {
+			defer wg.Done()
+
+			ctxVerificacao, cancelar := context.WithTimeout(ctx, s.timeout)
+			defer cancelar()
+
+			inicio := time.Now()
+			err := v.verificar(ctxVerificacao)
+			resultado := dto.VerificacaoSaudeResponse{
+				Status:     StatusSaudeOK,
+				Critica:    v.critica,
+				LatenciaMs: float64(time.Since(inicio).Microseconds()) / 1000,
+			}
+			if err != nil {
+				resultado.Status = StatusSaudeFalha
+				resultado.Erro = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			resposta.Verificacoes[v.nome] = resultado
+			switch {
+			case err == nil:
+			case v.critica:
+				resposta.Status = StatusSaudeFalha
+			case resposta.Status == StatusSaudeOK:
+				resposta.Status = StatusSaudeDegradado
+			}
+		}(v)
+	}
+	wg.Wait()
+
+	return resposta
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/ifinu/ifinu-api-go/dominio/entidades"
+	"github.com/ifinu/ifinu-api-go/dominio/enums"
 	"github.com/ifinu/ifinu-api-go/dto"
 	"github.com/ifinu/ifinu-api-go/repositorio"
 	"github.com/ifinu/ifinu-api-go/util"
@@ -15,11 +16,13 @@ import (
 
 type StripeConfigServico struct {
 	stripeConfigRepo *repositorio.StripeConfigRepositorio
+	auditoriaServico *AuditoriaServico
 }
 
-func NovoStripeConfigServico(stripeConfigRepo *repositorio.StripeConfigRepositorio) *StripeConfigServico {
+func NovoStripeConfigServico(stripeConfigRepo *repositorio.StripeConfigRepositorio, auditoriaServico *AuditoriaServico) *StripeConfigServico {
 	return &StripeConfigServico{
 		stripeConfigRepo: stripeConfigRepo,
+		auditoriaServico: auditoriaServico,
 	}
 }
 
@@ -43,7 +46,7 @@ func (s *StripeConfigServico) BuscarConfiguracao(usuarioID uuid.UUID) (*dto.Stri
 	}, nil
 }
 
-func (s *StripeConfigServico) SalvarConfiguracao(usuarioID uuid.UUID, req dto.SaveStripeConfigRequest) error {
+func (s *StripeConfigServico) SalvarConfiguracao(usuarioID uuid.UUID, ator dto.Ator, req dto.SaveStripeConfigRequest) error {
 	prefixoPublica := "pk_test_"
 	prefixoSecreta := "sk_test_"
 
@@ -66,6 +69,7 @@ func (s *StripeConfigServico) SalvarConfiguracao(usuarioID uuid.UUID, req dto.Sa
 		if err != nil {
 			return err
 		}
+		antes := resumoAuditoriaStripeConfig(configExistente)
 
 		configExistente.PublishableKey = req.PublishableKey
 		configExistente.TestMode = req.TestMode
@@ -83,7 +87,13 @@ func (s *StripeConfigServico) SalvarConfiguracao(usuarioID uuid.UUID, req dto.Sa
 			configExistente.SecretKeyEncrypted = secretKeyCriptografada
 		}
 
-		return s.stripeConfigRepo.Atualizar(configExistente)
+		return s.auditoriaServico.Transacao(func(tx *gorm.DB) error {
+			if err := s.stripeConfigRepo.ComTransacao(tx).Atualizar(configExistente); err != nil {
+				return err
+			}
+			return s.auditoriaServico.Registrar(tx, usuarioID, ator, enums.AcaoAuditoriaAtualizar,
+				enums.EntidadeAuditoriaStripeConfig, usuarioID.String(), antes, resumoAuditoriaStripeConfig(configExistente))
+		})
 	}
 
 	if req.SecretKey == "" {
@@ -108,20 +118,41 @@ func (s *StripeConfigServico) SalvarConfiguracao(usuarioID uuid.UUID, req dto.Sa
 		DataAtualizacao:    time.Now(),
 	}
 
-	return s.stripeConfigRepo.Criar(novaConfig)
+	return s.auditoriaServico.Transacao(func(tx *gorm.DB) error {
+		if err := s.stripeConfigRepo.ComTransacao(tx).Criar(novaConfig); err != nil {
+			return err
+		}
+		return s.auditoriaServico.Registrar(tx, usuarioID, ator, enums.AcaoAuditoriaCriar,
+			enums.EntidadeAuditoriaStripeConfig, usuarioID.String(), nil, resumoAuditoriaStripeConfig(novaConfig))
+	})
 }
 
-func (s *StripeConfigServico) DeletarConfiguracao(usuarioID uuid.UUID) error {
-	existe, err := s.stripeConfigRepo.Existe(usuarioID)
+func (s *StripeConfigServico) DeletarConfiguracao(usuarioID uuid.UUID, ator dto.Ator) error {
+	config, err := s.stripeConfigRepo.BuscarPorUsuario(usuarioID)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("configuração não encontrada")
+		}
 		return err
 	}
 
-	if !existe {
-		return errors.New("configuração não encontrada")
-	}
+	return s.auditoriaServico.Transacao(func(tx *gorm.DB) error {
+		if err := s.stripeConfigRepo.ComTransacao(tx).Deletar(usuarioID); err != nil {
+			return err
+		}
+		return s.auditoriaServico.Registrar(tx, usuarioID, ator, enums.AcaoAuditoriaExcluir,
+			enums.EntidadeAuditoriaStripeConfig, usuarioID.String(), resumoAuditoriaStripeConfig(config), nil)
+	})
+}
 
-	return s.stripeConfigRepo.Deletar(usuarioID)
+// resumoAuditoriaStripeConfig monta os campos auditáveis da configuração.
+// A chave secreta entra apenas mascarada, o suficiente para detectar a troca.
+func resumoAuditoriaStripeConfig(config *entidades.StripeConfig) map[string]interface{} {
+	return map[string]interface{}{
+		"publishableKey":  config.PublishableKey,
+		"secretKeyMasked": config.MaskSecretKey(),
+		"testMode":        config.TestMode,
+	}
 }
 
 func (s *StripeConfigServico) ObterChaveSecreta(usuarioID uuid.UUID) (string, error) {
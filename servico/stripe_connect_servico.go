@@ -1,12 +1,15 @@
 package servico
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"os"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/ifinu/ifinu-api-go/dto"
+	"github.com/ifinu/ifinu-api-go/logs"
 	"github.com/ifinu/ifinu-api-go/repositorio"
 	"github.com/stripe/stripe-go/v81"
 	"github.com/stripe/stripe-go/v81/account"
@@ -27,7 +30,7 @@ func NovoStripeConnectServico(usuarioRepo *repositorio.UsuarioRepositorio) *Stri
 }
 
 // CriarContaConnect cria uma conta Express no Stripe Connect
-func (s *StripeConnectServico) CriarContaConnect(usuarioID uuid.UUID, returnURL, refreshURL string) (*dto.CriarContaConnectResponse, error) {
+func (s *StripeConnectServico) CriarContaConnect(ctx context.Context, usuarioID uuid.UUID, returnURL, refreshURL string) (*dto.CriarContaConnectResponse, error) {
 	// Buscar usuário
 	usuario, err := s.usuarioRepo.BuscarPorID(usuarioID)
 	if err != nil {
@@ -37,7 +40,7 @@ func (s *StripeConnectServico) CriarContaConnect(usuarioID uuid.UUID, returnURL,
 	// Verificar se já tem conta conectada
 	if usuario.StripeAccountID != "" {
 		// Conta já existe, apenas gerar novo link de onboarding
-		return s.GerarLinkOnboarding(usuarioID, returnURL, refreshURL)
+		return s.GerarLinkOnboarding(ctx, usuarioID, returnURL, refreshURL)
 	}
 
 	// Criar conta Express no Stripe
@@ -64,6 +67,7 @@ func (s *StripeConnectServico) CriarContaConnect(usuarioID uuid.UUID, returnURL,
 	}
 
 	// Criar conta
+	accountParams.Context = ctx
 	acc, err := account.New(accountParams)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao criar conta Stripe Connect: %w", err)
@@ -82,6 +86,7 @@ func (s *StripeConnectServico) CriarContaConnect(usuarioID uuid.UUID, returnURL,
 		ReturnURL:  stripe.String(returnURL),
 		Type:       stripe.String("account_onboarding"),
 	}
+	linkParams.Context = ctx
 
 	link, err := accountlink.New(linkParams)
 	if err != nil {
@@ -96,7 +101,7 @@ func (s *StripeConnectServico) CriarContaConnect(usuarioID uuid.UUID, returnURL,
 }
 
 // GerarLinkOnboarding gera novo link de onboarding para conta existente
-func (s *StripeConnectServico) GerarLinkOnboarding(usuarioID uuid.UUID, returnURL, refreshURL string) (*dto.CriarContaConnectResponse, error) {
+func (s *StripeConnectServico) GerarLinkOnboarding(ctx context.Context, usuarioID uuid.UUID, returnURL, refreshURL string) (*dto.CriarContaConnectResponse, error) {
 	// Buscar usuário
 	usuario, err := s.usuarioRepo.BuscarPorID(usuarioID)
 	if err != nil {
@@ -114,6 +119,7 @@ func (s *StripeConnectServico) GerarLinkOnboarding(usuarioID uuid.UUID, returnUR
 		ReturnURL:  stripe.String(returnURL),
 		Type:       stripe.String("account_onboarding"),
 	}
+	linkParams.Context = ctx
 
 	link, err := accountlink.New(linkParams)
 	if err != nil {
@@ -128,7 +134,7 @@ func (s *StripeConnectServico) GerarLinkOnboarding(usuarioID uuid.UUID, returnUR
 }
 
 // ObterStatusConnect retorna status da conta conectada
-func (s *StripeConnectServico) ObterStatusConnect(usuarioID uuid.UUID) (*dto.StatusStripeConnectResponse, error) {
+func (s *StripeConnectServico) ObterStatusConnect(ctx context.Context, usuarioID uuid.UUID) (*dto.StatusStripeConnectResponse, error) {
 	// Buscar usuário
 	usuario, err := s.usuarioRepo.BuscarPorID(usuarioID)
 	if err != nil {
@@ -147,7 +153,7 @@ func (s *StripeConnectServico) ObterStatusConnect(usuarioID uuid.UUID) (*dto.Sta
 	}
 
 	// Buscar detalhes da conta no Stripe
-	acc, err := account.GetByID(usuario.StripeAccountID, nil)
+	acc, err := account.GetByID(usuario.StripeAccountID, &stripe.AccountParams{Params: stripe.Params{Context: ctx}})
 	if err != nil {
 		return nil, fmt.Errorf("erro ao buscar conta no Stripe: %w", err)
 	}
@@ -179,7 +185,7 @@ func (s *StripeConnectServico) ObterStatusConnect(usuarioID uuid.UUID) (*dto.Sta
 	if atualizou {
 		if err := s.usuarioRepo.Atualizar(usuario); err != nil {
 			// Log error mas não falhar
-			fmt.Printf("Erro ao atualizar status Stripe do usuário: %v\n", err)
+			slog.ErrorContext(ctx, "❌ Erro ao atualizar status Stripe do usuário", logs.Erro(err))
 		}
 	}
 
@@ -194,7 +200,7 @@ func (s *StripeConnectServico) ObterStatusConnect(usuarioID uuid.UUID) (*dto.Sta
 }
 
 // GerarDashboardLink gera link para usuário acessar dashboard Stripe
-func (s *StripeConnectServico) GerarDashboardLink(usuarioID uuid.UUID) (*dto.DashboardLinkResponse, error) {
+func (s *StripeConnectServico) GerarDashboardLink(ctx context.Context, usuarioID uuid.UUID) (*dto.DashboardLinkResponse, error) {
 	// Buscar usuário
 	usuario, err := s.usuarioRepo.BuscarPorID(usuarioID)
 	if err != nil {
@@ -209,6 +215,7 @@ func (s *StripeConnectServico) GerarDashboardLink(usuarioID uuid.UUID) (*dto.Das
 	params := &stripe.LoginLinkParams{
 		Account: stripe.String(usuario.StripeAccountID),
 	}
+	params.Context = ctx
 
 	link, err := loginlink.New(params)
 	if err != nil {
@@ -1,6 +1,7 @@
 package servico
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -14,6 +15,7 @@ import (
 	"github.com/stripe/stripe-go/v81"
 	"github.com/stripe/stripe-go/v81/checkout/session"
 	"github.com/stripe/stripe-go/v81/invoice"
+	"github.com/stripe/stripe-go/v81/subscription"
 )
 
 type StripeServico struct {
@@ -52,7 +54,7 @@ func (s *StripeServico) CriarCheckoutTrial(usuarioID uuid.UUID) (*dto.CreateChec
 
 // CriarCheckoutSession cria uma sessão de checkout Stripe com dados completos
 // IMPORTANTE: Usa Stripe Connect - dinheiro vai para conta conectada do usuário
-func (s *StripeServico) CriarCheckoutSession(usuarioID uuid.UUID, req *dto.CreateCheckoutRequest) (*dto.CreateCheckoutResponse, error) {
+func (s *StripeServico) CriarCheckoutSession(ctx context.Context, usuarioID uuid.UUID, req *dto.CreateCheckoutRequest) (*dto.CreateCheckoutResponse, error) {
 	// Buscar usuário e verificar se tem conta Stripe Connect
 	usuario, err := s.usuarioRepo.BuscarPorID(usuarioID)
 	if err != nil {
@@ -118,6 +120,7 @@ func (s *StripeServico) CriarCheckoutSession(usuarioID uuid.UUID, req *dto.Creat
 	}
 
 	// Criar sessão no Stripe
+	params.Context = ctx
 	sess, err := session.New(params)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao criar sessão Stripe: %w", err)
@@ -135,7 +138,7 @@ func (s *StripeServico) CriarCheckoutSession(usuarioID uuid.UUID, req *dto.Creat
 }
 
 // CriarCheckoutAssinatura cria uma sessão de checkout Stripe para assinatura recorrente
-func (s *StripeServico) CriarCheckoutAssinatura(usuarioID uuid.UUID, req dto.CheckoutAssinaturaRequest) (*dto.CheckoutAssinaturaResponse, error) {
+func (s *StripeServico) CriarCheckoutAssinatura(ctx context.Context, usuarioID uuid.UUID, req dto.CheckoutAssinaturaRequest) (*dto.CheckoutAssinaturaResponse, error) {
 	// Verificar se usuário existe
 	usuario, err := s.usuarioRepo.BuscarPorID(usuarioID)
 	if err != nil {
@@ -181,6 +184,7 @@ func (s *StripeServico) CriarCheckoutAssinatura(usuarioID uuid.UUID, req dto.Che
 	}
 
 	// Criar sessão no Stripe
+	params.Context = ctx
 	sess, err := session.New(params)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao criar sessão Stripe: %w", err)
@@ -361,6 +365,95 @@ func (s *StripeServico) ProcessarSubscriptionCancelada(subscriptionID string) er
 	return s.assinaturaRepo.Atualizar(assinatura)
 }
 
+// ReconciliarAssinatura consulta a subscription do usuário no Stripe e corrige status e
+// datas da assinatura local, para quando um webhook se perdeu. Sem o ID da subscription,
+// usa a mais recente do customer.
+func (s *StripeServico) ReconciliarAssinatura(ctx context.Context, usuarioID uuid.UUID) (*entidades.AssinaturaUsuario, error) {
+	assinatura, err := s.assinaturaRepo.BuscarPorUsuario(usuarioID)
+	if err != nil {
+		return nil, fmt.Errorf("assinatura não encontrada: %w", err)
+	}
+	if assinatura.Status == entidades.StatusVitalicia {
+		return nil, fmt.Errorf("assinatura vitalícia não é cobrada pelo Stripe")
+	}
+
+	sub, err := s.buscarSubscription(ctx, assinatura)
+	if err != nil {
+		return nil, err
+	}
+
+	assinatura.StripeSubscriptionID = sub.ID
+	if sub.Customer != nil {
+		assinatura.StripeCustomerID = sub.Customer.ID
+	}
+
+	switch sub.Status {
+	case stripe.SubscriptionStatusTrialing:
+		assinatura.Status = entidades.StatusPeriodoGratuito
+		if sub.TrialEnd > 0 {
+			fimTrial := time.Unix(sub.TrialEnd, 0)
+			assinatura.DataFimPeriodoGratuito = &fimTrial
+			assinatura.DataProximaCobranca = &fimTrial
+		}
+
+	case stripe.SubscriptionStatusActive:
+		assinatura.Status = entidades.StatusAtiva
+		ultimaCobranca := time.Unix(sub.CurrentPeriodStart, 0)
+		proximaCobranca := time.Unix(sub.CurrentPeriodEnd, 0)
+		assinatura.DataUltimaCobranca = &ultimaCobranca
+		assinatura.DataProximaCobranca = &proximaCobranca
+
+	case stripe.SubscriptionStatusPastDue, stripe.SubscriptionStatusIncomplete:
+		assinatura.Status = entidades.StatusPendentePagamento
+
+	case stripe.SubscriptionStatusCanceled, stripe.SubscriptionStatusIncompleteExpired:
+		assinatura.Status = entidades.StatusCancelada
+		if sub.CanceledAt > 0 {
+			cancelamento := time.Unix(sub.CanceledAt, 0)
+			assinatura.DataCancelamento = &cancelamento
+		}
+
+	case stripe.SubscriptionStatusUnpaid, stripe.SubscriptionStatusPaused:
+		assinatura.Status = entidades.StatusBloqueada
+	}
+
+	if err := s.assinaturaRepo.Atualizar(assinatura); err != nil {
+		return nil, err
+	}
+	return assinatura, nil
+}
+
+// buscarSubscription busca a subscription pelo ID salvo ou, sem ele, a mais recente do customer
+func (s *StripeServico) buscarSubscription(ctx context.Context, assinatura *entidades.AssinaturaUsuario) (*stripe.Subscription, error) {
+	if assinatura.StripeSubscriptionID != "" {
+		sub, err := subscription.Get(assinatura.StripeSubscriptionID, &stripe.SubscriptionParams{Params: stripe.Params{Context: ctx}})
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar subscription no Stripe: %w", err)
+		}
+		return sub, nil
+	}
+
+	if assinatura.StripeCustomerID == "" {
+		return nil, fmt.Errorf("assinatura sem customer no Stripe")
+	}
+
+	params := &stripe.SubscriptionListParams{
+		Customer: stripe.String(assinatura.StripeCustomerID),
+		Status:   stripe.String("all"),
+	}
+	params.Limit = stripe.Int64(1)
+	params.Context = ctx
+
+	iter := subscription.List(params)
+	if iter.Next() {
+		return iter.Subscription(), nil
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao listar subscriptions no Stripe: %w", err)
+	}
+	return nil, fmt.Errorf("nenhuma subscription do customer %s no Stripe", assinatura.StripeCustomerID)
+}
+
 // ProcessarPagamentoWebhook processa o webhook de pagamento confirmado (método legado)
 func (s *StripeServico) ProcessarPagamentoWebhook(sessionID string, metadata map[string]string) error {
 	// Extrair informações do metadata
@@ -416,7 +509,7 @@ func (s *StripeServico) ProcessarPagamentoWebhook(sessionID string, metadata map
 }
 
 // BuscarHistoricoFaturas busca o histórico de faturas do Stripe
-func (s *StripeServico) BuscarHistoricoFaturas(usuarioID uuid.UUID) (*dto.HistoricoFaturasResponse, error) {
+func (s *StripeServico) BuscarHistoricoFaturas(ctx context.Context, usuarioID uuid.UUID) (*dto.HistoricoFaturasResponse, error) {
 	// Buscar assinatura do usuário
 	assinatura, err := s.assinaturaRepo.BuscarPorUsuario(usuarioID)
 	if err != nil {
@@ -437,6 +530,7 @@ func (s *StripeServico) BuscarHistoricoFaturas(usuarioID uuid.UUID) (*dto.Histor
 		Customer: stripe.String(assinatura.StripeCustomerID),
 	}
 	params.Limit = stripe.Int64(10)
+	params.Context = ctx
 
 	faturas := []dto.FaturaInfo{}
 	i := invoice.List(params)
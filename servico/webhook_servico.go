@@ -0,0 +1,707 @@
+package servico
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/ifinu/ifinu-api-go/dominio/entidades"
+	"github.com/ifinu/ifinu-api-go/dominio/enums"
+	"github.com/ifinu/ifinu-api-go/dto"
+	"github.com/ifinu/ifinu-api-go/logs"
+	"github.com/ifinu/ifinu-api-go/rastreamento"
+	"github.com/ifinu/ifinu-api-go/repositorio"
+	"github.com/ifinu/ifinu-api-go/util"
+	"gorm.io/gorm"
+)
+
+const (
+	FilaWebhooks          = "ifinu:fila:webhooks"
+	FilaWebhooksAgendados = "ifinu:fila:webhooks:agendados" // sorted set: score = horário da próxima tentativa
+	MaxTentativasWebhook  = 6
+	TimeoutEntregaWebhook = 10 * time.Second
+
+	// Entregas pendentes paradas há mais que isso (sem retry agendado para depois) saíram da
+	// fila sem concluir e são reenfileiradas pela varredura
+	AtrasoReenfileirarWebhook = 10 * time.Minute
+	loteReenfileirarWebhooks  = 500
+	// O retry sai do sorted set pelo horário truncado em segundos, a cada 5s
+	toleranciaRetryWebhook = 5 * time.Second
+)
+
+// intervalosRetryWebhook define a espera após cada tentativa falha
+var intervalosRetryWebhook = []time.Duration{
+	1 * time.Minute,
+	5 * time.Minute,
+	30 * time.Minute,
+	2 * time.Hour,
+	6 * time.Hour,
+}
+
+// EventoWebhookPayload é o corpo enviado aos endpoints inscritos
+type EventoWebhookPayload struct {
+	ID       uuid.UUID           `json:"id"`
+	Evento   enums.EventoWebhook `json:"evento"`
+	CriadoEm time.Time           `json:"criadoEm"`
+	Dados    interface{}         `json:"dados"`
+}
+
+type WebhookServico struct {
+	webhookRepo *repositorio.WebhookRepositorio
+	redisClient *redis.Client
+	ctx         context.Context
+	httpClient  *http.Client
+	logger      *slog.Logger
+
+	// Encerramento: ctxWorkers é cancelado em Parar (os workers deixam de buscar entregas);
+	// ctxEntregas só quando o prazo acaba, interrompendo os POSTs em andamento
+	ctxWorkers       context.Context
+	cancelar         context.CancelFunc
+	ctxEntregas      context.Context
+	cancelarEntregas context.CancelFunc
+	workers          sync.WaitGroup
+	workersAtivos    atomic.Int32
+	numWorkers       int
+	ultimoCiclo      atomic.Int64 // Unix da última vez que um worker foi buscar entrega
+	emAndamento      atomic.Int32
+}
+
+func NovoWebhookServico(webhookRepo *repositorio.WebhookRepositorio, redisAddr string, logger *slog.Logger) *WebhookServico {
+	ctx := context.Background()
+	ctxWorkers, cancelar := context.WithCancel(ctx)
+	ctxEntregas, cancelarEntregas := context.WithCancel(ctx)
+
+	s := &WebhookServico{
+		webhookRepo: webhookRepo,
+		ctx:         ctx,
+		// Só conecta em IPs públicos (verificado a cada conexão) e não segue redirecionamentos
+		httpClient:       util.NovoClienteHTTPExterno(TimeoutEntregaWebhook),
+		logger:           logger,
+		ctxWorkers:       ctxWorkers,
+		cancelar:         cancelar,
+		ctxEntregas:      ctxEntregas,
+		cancelarEntregas: cancelarEntregas,
+	}
+
+	redisClient := redis.NewClient(&redis.Options{
+		Addr:         redisAddr,
+		DialTimeout:  5 * time.Second,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 3 * time.Second,
+	})
+	redisClient.AddHook(rastreamento.HookRedis())
+
+	// Sem Redis as entregas continuam funcionando, mas os retries ficam em memória
+	if err := redisClient.Ping(ctx).Err(); err != nil {
+		logger.Warn("⚠️  Redis não disponível para webhooks. Retries serão mantidos em memória", logs.Erro(err))
+		return s
+	}
+
+	s.redisClient = redisClient
+	logger.Info("✅ Fila de webhooks Redis conectada")
+	return s
+}
+
+// CriarEndpoint cadastra um endpoint. O segredo de assinatura só é retornado nesta chamada.
+func (s *WebhookServico) CriarEndpoint(usuarioID uuid.UUID, req dto.EndpointWebhookRequest) (*dto.EndpointWebhookCriadoResponse, error) {
+	if err := validarEndpointRequest(req); err != nil {
+		return nil, err
+	}
+
+	token, err := util.GerarTokenAleatorio(24)
+	if err != nil {
+		return nil, err
+	}
+	segredo := "whsec_" + token
+
+	segredoEncriptado, err := util.EncryptString(segredo)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao proteger segredo: %w", err)
+	}
+
+	endpoint := &entidades.EndpointWebhook{
+		UsuarioID:         usuarioID,
+		URL:               req.URL,
+		Descricao:         req.Descricao,
+		SegredoEncriptado: segredoEncriptado,
+		Ativo:             req.Ativo == nil || *req.Ativo,
+	}
+	endpoint.DefinirEventos(req.Eventos)
+
+	if err := s.webhookRepo.CriarEndpoint(endpoint); err != nil {
+		return nil, err
+	}
+
+	return &dto.EndpointWebhookCriadoResponse{
+		EndpointWebhookResponse: s.mapearEndpointParaDTO(endpoint),
+		Segredo:                 segredo,
+	}, nil
+}
+
+// ListarEndpoints lista os endpoints da conta
+func (s *WebhookServico) ListarEndpoints(usuarioID uuid.UUID) ([]dto.EndpointWebhookResponse, error) {
+	endpoints, err := s.webhookRepo.ListarEndpoints(usuarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	resposta := make([]dto.EndpointWebhookResponse, len(endpoints))
+	for i, endpoint := range endpoints {
+		resposta[i] = s.mapearEndpointParaDTO(&endpoint)
+	}
+	return resposta, nil
+}
+
+// AtualizarEndpoint altera URL, eventos e status de um endpoint
+func (s *WebhookServico) AtualizarEndpoint(usuarioID uuid.UUID, endpointID uuid.UUID, req dto.EndpointWebhookRequest) (*dto.EndpointWebhookResponse, error) {
+	if err := validarEndpointRequest(req); err != nil {
+		return nil, err
+	}
+
+	endpoint, err := s.buscarEndpoint(usuarioID, endpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint.URL = req.URL
+	endpoint.Descricao = req.Descricao
+	endpoint.DefinirEventos(req.Eventos)
+	if req.Ativo != nil {
+		endpoint.Ativo = *req.Ativo
+	}
+
+	if err := s.webhookRepo.AtualizarEndpoint(endpoint); err != nil {
+		return nil, err
+	}
+
+	resposta := s.mapearEndpointParaDTO(endpoint)
+	return &resposta, nil
+}
+
+// RemoverEndpoint remove um endpoint e seu log de entregas
+func (s *WebhookServico) RemoverEndpoint(usuarioID uuid.UUID, endpointID uuid.UUID) error {
+	if _, err := s.buscarEndpoint(usuarioID, endpointID); err != nil {
+		return err
+	}
+	return s.webhookRepo.DeletarEndpoint(endpointID, usuarioID)
+}
+
+// ListarEntregas retorna o log de entregas de um endpoint
+func (s *WebhookServico) ListarEntregas(usuarioID uuid.UUID, endpointID uuid.UUID, req dto.BuscarEntregasWebhookRequest) (*dto.EntregaWebhookListResponse, error) {
+	if _, err := s.buscarEndpoint(usuarioID, endpointID); err != nil {
+		return nil, err
+	}
+
+	paginaOriginal := req.Pagina
+	if req.Pagina == 0 {
+		req.Pagina = 1
+	}
+	if req.TamanhoPagina == 0 {
+		req.TamanhoPagina = 20
+	}
+
+	entregas, total, err := s.webhookRepo.ListarEntregas(endpointID, usuarioID, req.Pagina, req.TamanhoPagina)
+	if err != nil {
+		return nil, err
+	}
+
+	entregasDTO := make([]dto.EntregaWebhookResponse, len(entregas))
+	for i, entrega := range entregas {
+		entregasDTO[i] = s.mapearEntregaParaDTO(&entrega)
+	}
+
+	return &dto.EntregaWebhookListResponse{
+		Entregas:      entregasDTO,
+		Total:         total,
+		Pagina:        paginaOriginal,
+		TamanhoPagina: req.TamanhoPagina,
+		TotalPaginas:  int(math.Ceil(float64(total) / float64(req.TamanhoPagina))),
+	}, nil
+}
+
+// Reentregar reenvia manualmente uma entrega, reiniciando o ciclo de tentativas
+func (s *WebhookServico) Reentregar(usuarioID uuid.UUID, entregaID uuid.UUID) error {
+	entrega, err := s.webhookRepo.BuscarEntregaDoUsuario(entregaID, usuarioID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("entrega não encontrada")
+		}
+		return err
+	}
+
+	entrega.Status = entidades.StatusEntregaPendente
+	entrega.Tentativas = 0
+	entrega.ProximaTentativa = nil
+	if err := s.webhookRepo.AtualizarEntrega(entrega); err != nil {
+		return err
+	}
+
+	s.agendar(entrega.ID, time.Now())
+	return nil
+}
+
+// Disparar registra e enfileira o evento para todos os endpoints ativos inscritos.
+// Falhas são apenas registradas em log para não interromper a operação de origem.
+func (s *WebhookServico) Disparar(usuarioID uuid.UUID, evento enums.EventoWebhook, dados interface{}) {
+	if s == nil {
+		return
+	}
+
+	ctx := logs.ComUsuarioID(s.ctx, usuarioID)
+	endpoints, err := s.webhookRepo.ListarEndpointsAtivos(usuarioID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "❌ Erro ao buscar endpoints de webhook", logs.Erro(err))
+		return
+	}
+
+	for _, endpoint := range endpoints {
+		if !endpoint.InscritoEm(evento) {
+			continue
+		}
+
+		entregaID := uuid.New()
+		payload, err := json.Marshal(EventoWebhookPayload{
+			ID:       entregaID,
+			Evento:   evento,
+			CriadoEm: time.Now(),
+			Dados:    dados,
+		})
+		if err != nil {
+			s.logger.ErrorContext(ctx, "❌ Erro ao serializar evento de webhook", "evento", evento, logs.Erro(err))
+			return
+		}
+
+		entrega := &entidades.EntregaWebhook{
+			ID:         entregaID,
+			EndpointID: endpoint.ID,
+			UsuarioID:  usuarioID,
+			Evento:     evento,
+			Payload:    string(payload),
+			Status:     entidades.StatusEntregaPendente,
+		}
+		if err := s.webhookRepo.CriarEntrega(entrega); err != nil {
+			s.logger.ErrorContext(ctx, "❌ Erro ao registrar entrega de webhook", "evento", evento, "endpoint_id", endpoint.ID, logs.Erro(err))
+			continue
+		}
+
+		s.agendar(entrega.ID, time.Now())
+	}
+}
+
+// IniciarWorkers inicia os workers que consomem a fila de webhooks
+func (s *WebhookServico) IniciarWorkers(numWorkers int) {
+	if s.redisClient == nil {
+		s.logger.Warn("⚠️  Fila de webhooks não disponível. Entregas serão feitas em memória")
+		return
+	}
+
+	s.logger.Info("🚀 Iniciando workers de webhooks", "workers", numWorkers)
+
+	s.numWorkers = numWorkers
+	s.ultimoCiclo.Store(time.Now().Unix())
+	s.workers.Add(numWorkers)
+	for i := 1; i <= numWorkers; i++ {
+		go s.worker(i)
+	}
+
+	go s.moverAgendados()
+}
+
+// Parar deixa de buscar entregas e aguarda as tentativas em andamento. Se ctx expirar
+// antes, interrompe os POSTs e espera os workers reagendarem as entregas: uma entrega
+// ainda em execução nunca volta à fila, senão outra réplica faria o POST em paralelo.
+func (s *WebhookServico) Parar(ctx context.Context) error {
+	s.cancelar()
+	if s.redisClient == nil {
+		return nil
+	}
+
+	concluido := make(chan struct{})
+	go func() {
+		s.workers.Wait()
+		close(concluido)
+	}()
+
+	select {
+	case <-concluido:
+		s.logger.Info("✅ Workers de webhooks encerrados")
+		return nil
+	case <-ctx.Done():
+	}
+
+	interrompidas := s.emAndamento.Load()
+	s.cancelarEntregas()
+	select {
+	case <-concluido:
+		return fmt.Errorf("prazo de encerramento esgotado: %d entrega(s) interrompida(s) e reagendada(s)", interrompidas)
+	case <-time.After(EsperaCancelamentoJobs):
+		// As que não foram reagendadas seguem pendentes no banco e voltam pela varredura
+		return fmt.Errorf("prazo de encerramento esgotado: %d entrega(s) interrompida(s) ainda em andamento", s.emAndamento.Load())
+	}
+}
+
+// agendar coloca a entrega na fila para o horário indicado
+func (s *WebhookServico) agendar(entregaID uuid.UUID, quando time.Time) {
+	if s.redisClient == nil {
+		time.AfterFunc(time.Until(quando), func() { s.entregar(s.ctx, entregaID) })
+		return
+	}
+
+	var err error
+	if !quando.After(time.Now()) {
+		err = s.redisClient.LPush(s.ctx, FilaWebhooks, entregaID.String()).Err()
+	} else {
+		err = s.redisClient.ZAdd(s.ctx, FilaWebhooksAgendados, &redis.Z{
+			Score:  float64(quando.Unix()),
+			Member: entregaID.String(),
+		}).Err()
+	}
+
+	if err != nil {
+		// Continua pendente no banco: a varredura de atrasadas volta a enfileirá-la
+		s.logger.Error("❌ Erro ao enfileirar entrega de webhook", "entrega_id", entregaID, logs.Erro(err))
+	}
+}
+
+// ReenfileirarPendentes enfileira as entregas pendentes que ficaram fora da fila: réplica
+// encerrada no meio da entrega, falha ao enfileirar, fila esvaziada ou retries em memória
+// perdidos num restart. Uma entrega repetida na fila é descartada em entregar.
+func (s *WebhookServico) ReenfileirarPendentes(ctx context.Context) (map[string]int, error) {
+	total := 0
+	for {
+		ids, err := s.webhookRepo.ComContexto(ctx).ReservarPendentesAtrasadas(time.Now().Add(-AtrasoReenfileirarWebhook), loteReenfileirarWebhooks)
+		if err != nil {
+			return map[string]int{"reenfileiradas": total}, err
+		}
+		for _, id := range ids {
+			s.agendar(id, time.Now())
+		}
+		total += len(ids)
+		if len(ids) < loteReenfileirarWebhooks {
+			break
+		}
+	}
+
+	if total > 0 {
+		s.logger.InfoContext(ctx, "🔁 Entregas de webhook atrasadas reenfileiradas", "entregas", total)
+	}
+	return map[string]int{"reenfileiradas": total}, nil
+}
+
+// worker processa entregas da fila
+func (s *WebhookServico) worker(id int) {
+	defer s.workers.Done()
+	s.workersAtivos.Add(1)
+	defer s.workersAtivos.Add(-1)
+
+	for s.ctxWorkers.Err() == nil {
+		s.ultimoCiclo.Store(time.Now().Unix())
+		result, err := s.redisClient.BRPop(s.ctxWorkers, 5*time.Second, FilaWebhooks).Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			if s.ctxWorkers.Err() != nil {
+				continue
+			}
+			s.logger.Error("❌ Erro ao buscar entrega de webhook", "worker", id, logs.Erro(err))
+			time.Sleep(1 * time.Second)
+			continue
+		}
+
+		if len(result) < 2 {
+			continue
+		}
+
+		entregaID, err := uuid.Parse(result[1])
+		if err != nil {
+			s.logger.Error("❌ ID de entrega de webhook inválido", "worker", id, "entrega_id", result[1])
+			continue
+		}
+
+		s.emAndamento.Add(1)
+		s.entregar(s.ctxEntregas, entregaID)
+		s.emAndamento.Add(-1)
+	}
+}
+
+// verificarWorkers indica se os workers desta réplica continuam consumindo a fila de
+// webhooks (sem Redis as entregas são feitas em memória e não há workers)
+func (s *WebhookServico) verificarWorkers() error {
+	if s.redisClient == nil || s.ctxWorkers.Err() != nil {
+		return nil
+	}
+	if s.numWorkers == 0 {
+		return errors.New("workers não iniciados")
+	}
+	if ativos := int(s.workersAtivos.Load()); ativos < s.numWorkers {
+		return fmt.Errorf("%d de %d workers ativos", ativos, s.numWorkers)
+	}
+	if parado := time.Since(time.Unix(s.ultimoCiclo.Load(), 0)); parado > LimiteInatividadeWorkers {
+		return fmt.Errorf("nenhum worker buscou entregas nos últimos %s", parado.Truncate(time.Second))
+	}
+	return nil
+}
+
+// moverAgendados move para a fila as entregas cujo horário de retry chegou
+func (s *WebhookServico) moverAgendados() {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctxWorkers.Done():
+			return
+		case <-ticker.C:
+		}
+
+		agora := strconv.FormatInt(time.Now().Unix(), 10)
+		ids, err := s.redisClient.ZRangeByScore(s.ctx, FilaWebhooksAgendados, &redis.ZRangeBy{
+			Min: "-inf",
+			Max: agora,
+		}).Result()
+		if err != nil {
+			continue
+		}
+
+		for _, id := range ids {
+			// ZREM garante que apenas uma instância mova cada entrega
+			removidos, err := s.redisClient.ZRem(s.ctx, FilaWebhooksAgendados, id).Result()
+			if err != nil || removidos == 0 {
+				continue
+			}
+			s.redisClient.LPush(s.ctx, FilaWebhooks, id)
+		}
+	}
+}
+
+// entregar faz uma tentativa de envio e agenda a próxima em caso de falha. Cancelar ctx
+// interrompe o POST; a tentativa interrompida é reagendada para já.
+func (s *WebhookServico) entregar(ctx context.Context, entregaID uuid.UUID) {
+	entrega, err := s.webhookRepo.BuscarEntregaPorID(entregaID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "❌ Entrega de webhook não encontrada", "entrega_id", entregaID, logs.Erro(err))
+		return
+	}
+	ctx = logs.ComUsuarioID(ctx, entrega.UsuarioID)
+
+	if entrega.Status != entidades.StatusEntregaPendente {
+		return
+	}
+	// Repetida na fila depois de uma tentativa falha: o retry agendado faz a próxima
+	if entrega.ProximaTentativa != nil && entrega.ProximaTentativa.After(time.Now().Add(toleranciaRetryWebhook)) {
+		return
+	}
+
+	if !entrega.Endpoint.Ativo {
+		entrega.Status = entidades.StatusEntregaFalha
+		entrega.UltimoErro = "endpoint desativado"
+		entrega.ProximaTentativa = nil
+		s.webhookRepo.AtualizarEntrega(entrega)
+		return
+	}
+
+	reservada, err := s.webhookRepo.ReservarTentativa(entrega.ID, entrega.Tentativas)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "❌ Erro ao reservar tentativa da entrega de webhook", "entrega_id", entrega.ID, logs.Erro(err))
+		return
+	}
+	if !reservada {
+		return // Outro worker já está fazendo esta tentativa
+	}
+
+	entrega.Tentativas++
+	statusHTTP, err := s.enviar(ctx, &entrega.Endpoint, entrega)
+	entrega.UltimoStatusHTTP = statusHTTP
+
+	if err == nil {
+		agora := time.Now()
+		entrega.Status = entidades.StatusEntregaSucesso
+		entrega.UltimoErro = ""
+		entrega.ProximaTentativa = nil
+		entrega.DataEntrega = &agora
+		s.logger.InfoContext(ctx, "✅ Webhook entregue", "evento", entrega.Evento, "url", entrega.Endpoint.URL, "tentativa", entrega.Tentativas)
+	} else {
+		entrega.UltimoErro = err.Error()
+		if entrega.Tentativas < MaxTentativasWebhook {
+			proxima := time.Now().Add(intervalosRetryWebhook[entrega.Tentativas-1])
+			if ctx.Err() != nil {
+				proxima = time.Now() // Interrompida no encerramento: outra réplica tenta em seguida
+			}
+			entrega.ProximaTentativa = &proxima
+		} else {
+			entrega.Status = entidades.StatusEntregaFalha
+			entrega.ProximaTentativa = nil
+		}
+		s.logger.WarnContext(ctx, "❌ Falha ao entregar webhook", "evento", entrega.Evento, "url", entrega.Endpoint.URL,
+			"tentativa", entrega.Tentativas, "max_tentativas", MaxTentativasWebhook, logs.Erro(err))
+	}
+
+	if err := s.webhookRepo.AtualizarEntrega(entrega); err != nil {
+		s.logger.ErrorContext(ctx, "❌ Erro ao atualizar entrega de webhook", "entrega_id", entrega.ID, logs.Erro(err))
+		return
+	}
+
+	if entrega.Status == entidades.StatusEntregaPendente && entrega.ProximaTentativa != nil {
+		s.agendar(entrega.ID, *entrega.ProximaTentativa)
+	}
+}
+
+// enviar faz o POST assinado para o endpoint.
+// Assinatura: header X-Ifinu-Assinatura "t=<unix>,v1=<hex>", onde v1 é o
+// HMAC-SHA256 de "<t>.<corpo>" com o segredo do endpoint.
+func (s *WebhookServico) enviar(ctx context.Context, endpoint *entidades.EndpointWebhook, entrega *entidades.EntregaWebhook) (int, error) {
+	segredo, err := util.DecryptString(endpoint.SegredoEncriptado)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao obter segredo do endpoint: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	assinatura := util.AssinarHMAC(segredo, timestamp+"."+entrega.Payload)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewBufferString(entrega.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "IFINU-Webhooks/1.0")
+	req.Header.Set("X-Ifinu-Evento", entrega.Evento.String())
+	req.Header.Set("X-Ifinu-Entrega", entrega.ID.String())
+	req.Header.Set("X-Ifinu-Assinatura", fmt.Sprintf("t=%s,v1=%s", timestamp, assinatura))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		corpo, _ := io.ReadAll(io.LimitReader(resp.Body, 500))
+		return resp.StatusCode, fmt.Errorf("status %d: %s", resp.StatusCode, string(corpo))
+	}
+
+	return resp.StatusCode, nil
+}
+
+// buscarEndpoint busca um endpoint da conta
+func (s *WebhookServico) buscarEndpoint(usuarioID uuid.UUID, endpointID uuid.UUID) (*entidades.EndpointWebhook, error) {
+	endpoint, err := s.webhookRepo.BuscarEndpointPorID(endpointID, usuarioID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("endpoint não encontrado")
+		}
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+// validarEndpointRequest valida URL e eventos de um endpoint. A URL precisa apontar para
+// um endereço público: o servidor faz a chamada e não pode ser usado para alcançar a rede
+// interna ou os metadados da cloud.
+func validarEndpointRequest(req dto.EndpointWebhookRequest) error {
+	ctx, cancelar := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelar()
+	if err := util.ValidarURLExterna(ctx, req.URL); err != nil {
+		if errors.Is(err, util.ErrEnderecoInterno) {
+			return errors.New("URL do webhook não pode apontar para endereço local ou de rede privada")
+		}
+		return err
+	}
+
+	for _, evento := range req.Eventos {
+		if !evento.Valido() {
+			return errors.New("evento inválido: " + evento.String())
+		}
+	}
+	return nil
+}
+
+// mapearEndpointParaDTO converte EndpointWebhook para EndpointWebhookResponse
+func (s *WebhookServico) mapearEndpointParaDTO(endpoint *entidades.EndpointWebhook) dto.EndpointWebhookResponse {
+	return dto.EndpointWebhookResponse{
+		ID:          endpoint.ID,
+		URL:         endpoint.URL,
+		Descricao:   endpoint.Descricao,
+		Eventos:     endpoint.ListaEventos(),
+		Ativo:       endpoint.Ativo,
+		DataCriacao: endpoint.DataCriacao,
+	}
+}
+
+// mapearEntregaParaDTO converte EntregaWebhook para EntregaWebhookResponse
+func (s *WebhookServico) mapearEntregaParaDTO(entrega *entidades.EntregaWebhook) dto.EntregaWebhookResponse {
+	return dto.EntregaWebhookResponse{
+		ID:               entrega.ID,
+		Evento:           entrega.Evento,
+		Status:           string(entrega.Status),
+		Tentativas:       entrega.Tentativas,
+		UltimoStatusHTTP: entrega.UltimoStatusHTTP,
+		UltimoErro:       entrega.UltimoErro,
+		ProximaTentativa: entrega.ProximaTentativa,
+		DataEntrega:      entrega.DataEntrega,
+		DataCriacao:      entrega.DataCriacao,
+		Payload:          entrega.Payload,
+	}
+}
+
+// InspecionarFilas retorna a fila de entregas e os retries agendados, com até limite itens de cada
+func (s *WebhookServico) InspecionarFilas(limite int64) ([]ResumoFila, error) {
+	if s.redisClient == nil {
+		return nil, fmt.Errorf("fila de webhooks não inicializada")
+	}
+
+	fila, err := inspecionarLista(s.ctx, s.redisClient, FilaWebhooks, limite)
+	if err != nil {
+		return nil, err
+	}
+
+	agendados := ResumoFila{Nome: FilaWebhooksAgendados}
+	if agendados.Tamanho, err = s.redisClient.ZCard(s.ctx, FilaWebhooksAgendados).Result(); err != nil {
+		return nil, err
+	}
+	if limite > 0 && agendados.Tamanho > 0 {
+		itens, err := s.redisClient.ZRangeWithScores(s.ctx, FilaWebhooksAgendados, 0, limite-1).Result()
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range itens {
+			quando := time.Unix(int64(item.Score), 0).Format("02/01/2006 15:04:05")
+			agendados.Itens = append(agendados.Itens, fmt.Sprintf("%v (%s)", item.Member, quando))
+		}
+	}
+
+	return []ResumoFila{fila, agendados}, nil
+}
+
+// EsvaziarFilas descarta as entregas na fila e os retries agendados. As entregas continuam
+// pendentes no banco e a varredura volta a enfileirá-las depois de AtrasoReenfileirarWebhook
+// (desative o endpoint para não entregá-las). Retorna quantas foram removidas.
+func (s *WebhookServico) EsvaziarFilas() (int64, error) {
+	if s.redisClient == nil {
+		return 0, fmt.Errorf("fila de webhooks não inicializada")
+	}
+
+	var fila, agendados *redis.IntCmd
+	_, err := s.redisClient.TxPipelined(s.ctx, func(pipe redis.Pipeliner) error {
+		fila = pipe.LLen(s.ctx, FilaWebhooks)
+		agendados = pipe.ZCard(s.ctx, FilaWebhooksAgendados)
+		pipe.Del(s.ctx, FilaWebhooks, FilaWebhooksAgendados)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return fila.Val() + agendados.Val(), nil
+}
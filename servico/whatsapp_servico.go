@@ -1,78 +1,163 @@
 package servico
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/ifinu/ifinu-api-go/dominio/entidades"
+	"github.com/ifinu/ifinu-api-go/dominio/enums"
 	"github.com/ifinu/ifinu-api-go/dto"
 	"github.com/ifinu/ifinu-api-go/integracao"
+	"github.com/ifinu/ifinu-api-go/logs"
+	"github.com/ifinu/ifinu-api-go/metricas"
+	"github.com/ifinu/ifinu-api-go/rastreamento"
 	"github.com/ifinu/ifinu-api-go/repositorio"
 	"github.com/ifinu/ifinu-api-go/util"
+	"go.opentelemetry.io/otel/attribute"
 	"gorm.io/gorm"
 )
 
 type WhatsAppServico struct {
-	whatsappRepo *repositorio.WhatsAppRepositorio
-	usuarioRepo  *repositorio.UsuarioRepositorio
-	evolutionAPI *integracao.EvolutionAPICliente
+	whatsappRepo   *repositorio.WhatsAppRepositorio
+	usuarioRepo    *repositorio.UsuarioRepositorio
+	evolutionAPI   *integracao.EvolutionAPICliente
+	webhookServico *WebhookServico
+	limitador      *LimitadorWhatsApp
+	logger         *slog.Logger
+
+	// Envios assíncronos de EnviarMensagem, aguardados em Parar
+	enviosEmAndamento sync.WaitGroup
 }
 
 func NovoWhatsAppServico(
 	whatsappRepo *repositorio.WhatsAppRepositorio,
 	usuarioRepo *repositorio.UsuarioRepositorio,
 	evolutionAPI *integracao.EvolutionAPICliente,
+	webhookServico *WebhookServico,
+	limitador *LimitadorWhatsApp,
+	logger *slog.Logger,
 ) *WhatsAppServico {
 	return &WhatsAppServico{
-		whatsappRepo: whatsappRepo,
-		usuarioRepo:  usuarioRepo,
-		evolutionAPI: evolutionAPI,
+		whatsappRepo:   whatsappRepo,
+		usuarioRepo:    usuarioRepo,
+		evolutionAPI:   evolutionAPI,
+		webhookServico: webhookServico,
+		limitador:      limitador,
+		logger:         logger,
 	}
 }
 
-// Conectar inicia o processo de conexão do WhatsApp
-func (s *WhatsAppServico) Conectar(usuarioID uuid.UUID) (*dto.ConectarWhatsAppResponse, error) {
+// ErrEnvioIncerto indica que o envio falhou sem garantia de que a mensagem não saiu
+// (timeout, conexão interrompida, 5xx): repetir pode entregar a mensagem duas vezes
+var ErrEnvioIncerto = errors.New("resultado do envio incerto")
+
+// PrefixoInstancia identifica as instâncias da Evolution API criadas por esta aplicação
+const PrefixoInstancia = "ifinu_"
+
+// RotaMensagem descreve o envio para que o serviço escolha o número WhatsApp:
+// conexão forçada > conexão preferencial do cliente > conexão do tipo de
+// notificação > conexão padrão > demais conexões conectadas (failover)
+type RotaMensagem struct {
+	ConexaoID        *int64
+	ClienteConexaoID *int64
+	TipoNotificacao  enums.TipoNotificacao
+}
+
+// RotaCobranca monta a rota de envio de uma notificação de cobrança
+func RotaCobranca(cobranca *entidades.Cobranca, tipo enums.TipoNotificacao) RotaMensagem {
+	return RotaMensagem{
+		ClienteConexaoID: cobranca.Cliente.WhatsAppConexaoID,
+		TipoNotificacao:  tipo,
+	}
+}
+
+// ListarConexoes retorna os números WhatsApp da conta
+func (s *WhatsAppServico) ListarConexoes(usuarioID uuid.UUID) ([]dto.ConexaoWhatsAppResponse, error) {
+	conexoes, err := s.whatsappRepo.ListarPorUsuario(usuarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	conexoesDTO := make([]dto.ConexaoWhatsAppResponse, len(conexoes))
+	for i, conexao := range conexoes {
+		conexoesDTO[i] = *mapearConexaoParaDTO(&conexao)
+	}
+
+	return conexoesDTO, nil
+}
+
+// Conectar inicia o processo de conexão de um número WhatsApp.
+// Se já existir uma conexão com o mesmo nome e ela estiver desconectada, é reconectada.
+func (s *WhatsAppServico) Conectar(ctx context.Context, usuarioID uuid.UUID, req dto.ConectarWhatsAppRequest) (*dto.ConectarWhatsAppResponse, error) {
 	// Buscar usuário
-	usuario, err := s.usuarioRepo.BuscarPorID(usuarioID)
+	usuario, err := s.usuarioRepo.ComContexto(ctx).BuscarPorID(usuarioID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Verificar se já existe uma conexão
-	conexaoExistente, err := s.whatsappRepo.BuscarPorUsuario(usuarioID)
-	if err == nil && conexaoExistente.IsConectado() {
-		return nil, errors.New("já existe uma conexão WhatsApp ativa")
+	nome := strings.TrimSpace(req.Nome)
+	if nome == "" {
+		nome = entidades.NomeConexaoPadrao
+	}
+	if err := validarTiposNotificacao(req.TiposNotificacao); err != nil {
+		return nil, err
+	}
+
+	// Verificar se já existe uma conexão com este nome
+	conexaoExistente, err := s.whatsappRepo.ComContexto(ctx).BuscarPorNome(usuarioID, nome)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if conexaoExistente != nil && conexaoExistente.IsConectado() {
+		return nil, errors.New("já existe uma conexão WhatsApp ativa com este nome")
 	}
 
 	// Gerar nome de instância único
-	nomeInstancia := fmt.Sprintf("ifinu_%s_%d", usuario.Email, time.Now().Unix())
+	nomeInstancia := fmt.Sprintf("%s%s_%d", PrefixoInstancia, usuario.Email, time.Now().Unix())
 
 	// Criar instância no Evolution API
-	resultado, err := s.evolutionAPI.CriarInstancia(nomeInstancia)
+	resultado, err := s.evolutionAPI.CriarInstancia(ctx, nomeInstancia)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao criar instância: %w", err)
 	}
 
 	// Salvar ou atualizar conexão
+	var conexao *entidades.WhatsAppConexao
 	if conexaoExistente != nil {
 		// Atualizar conexão existente
-		conexaoExistente.InstanceName = nomeInstancia
-		conexaoExistente.QRCode = resultado.Qrcode.Base64
-		conexaoExistente.Status = entidades.StatusConexaoConectando
-		err = s.whatsappRepo.Atualizar(conexaoExistente)
+		conexao = conexaoExistente
+		conexao.IniciarPareamento(nomeInstancia, resultado.Qrcode.Base64)
+		if req.TiposNotificacao != nil {
+			conexao.DefinirTiposNotificacao(req.TiposNotificacao)
+		}
+		err = s.whatsappRepo.ComContexto(ctx).AtualizarCampos(conexao,
+			"instance_name", "qr_code", "status", "data_pareamento", "tipos_notificacao")
 	} else {
-		// Criar nova conexão
-		conexao := &entidades.WhatsAppConexao{
+		// A primeira conexão da conta é a padrão
+		existentes, errLista := s.whatsappRepo.ComContexto(ctx).ListarPorUsuario(usuarioID)
+		if errLista != nil {
+			return nil, errLista
+		}
+
+		conexao = &entidades.WhatsAppConexao{
 			UsuarioID:    usuarioID,
+			Nome:         nome,
+			Padrao:       len(existentes) == 0,
 			InstanceName: nomeInstancia,
 			QRCode:       resultado.Qrcode.Base64,
 			Status:       entidades.StatusConexaoConectando,
 			DataCriacao:  time.Now(),
 		}
-		err = s.whatsappRepo.Criar(conexao)
+		conexao.DefinirTiposNotificacao(req.TiposNotificacao)
+		err = s.whatsappRepo.ComContexto(ctx).Criar(conexao)
 	}
 
 	if err != nil {
@@ -80,15 +165,102 @@ func (s *WhatsAppServico) Conectar(usuarioID uuid.UUID) (*dto.ConectarWhatsAppRe
 	}
 
 	return &dto.ConectarWhatsAppResponse{
+		ConexaoID:     conexao.ID,
 		QRCode:        resultado.Qrcode.Base64,
 		NomeInstancia: nomeInstancia,
 	}, nil
 }
 
-// ObterStatus retorna o status da conexão WhatsApp
-func (s *WhatsAppServico) ObterStatus(usuarioID uuid.UUID) (*dto.StatusWhatsAppResponse, error) {
+// AtualizarConexao altera nome, padrão e tipos de notificação roteados de uma conexão
+func (s *WhatsAppServico) AtualizarConexao(usuarioID uuid.UUID, conexaoID int64, req dto.AtualizarConexaoWhatsAppRequest) (*dto.ConexaoWhatsAppResponse, error) {
+	conexao, err := s.whatsappRepo.BuscarPorID(conexaoID, usuarioID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("conexão WhatsApp não encontrada")
+		}
+		return nil, err
+	}
+
+	if req.Nome != nil {
+		nome := strings.TrimSpace(*req.Nome)
+		if nome == "" {
+			return nil, errors.New("nome da conexão é obrigatório")
+		}
+		outra, err := s.whatsappRepo.BuscarPorNome(usuarioID, nome)
+		if err == nil && outra.ID != conexao.ID {
+			return nil, errors.New("já existe outra conexão WhatsApp com este nome")
+		}
+		conexao.Nome = nome
+	}
+
+	if req.TiposNotificacao != nil {
+		if err := validarTiposNotificacao(req.TiposNotificacao); err != nil {
+			return nil, err
+		}
+		conexao.DefinirTiposNotificacao(req.TiposNotificacao)
+	}
+
+	if err := s.whatsappRepo.AtualizarCampos(conexao, "nome", "tipos_notificacao"); err != nil {
+		return nil, err
+	}
+
+	if req.Padrao != nil && *req.Padrao && !conexao.Padrao {
+		if err := s.whatsappRepo.DefinirPadrao(conexao.ID, usuarioID); err != nil {
+			return nil, err
+		}
+		conexao.Padrao = true
+	}
+
+	return mapearConexaoParaDTO(conexao), nil
+}
+
+// buscarConexao retorna a conexão informada ou, sem ID, a conexão padrão da conta
+func (s *WhatsAppServico) buscarConexao(ctx context.Context, usuarioID uuid.UUID, conexaoID *int64) (*entidades.WhatsAppConexao, error) {
+	if conexaoID != nil {
+		return s.whatsappRepo.ComContexto(ctx).BuscarPorID(*conexaoID, usuarioID)
+	}
+	return s.whatsappRepo.ComContexto(ctx).BuscarPorUsuario(usuarioID)
+}
+
+// conexoesCandidatas ordena as conexões do usuário pela prioridade da rota
+func (s *WhatsAppServico) conexoesCandidatas(ctx context.Context, usuarioID uuid.UUID, rota RotaMensagem) ([]entidades.WhatsAppConexao, error) {
+	if rota.ConexaoID != nil {
+		conexao, err := s.whatsappRepo.ComContexto(ctx).BuscarPorID(*rota.ConexaoID, usuarioID)
+		if err != nil {
+			return nil, err
+		}
+		return []entidades.WhatsAppConexao{*conexao}, nil
+	}
+
+	conexoes, err := s.whatsappRepo.ComContexto(ctx).ListarPorUsuario(usuarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	prioridade := func(c *entidades.WhatsAppConexao) int {
+		switch {
+		case rota.ClienteConexaoID != nil && c.ID == *rota.ClienteConexaoID:
+			return 0
+		case rota.TipoNotificacao != "" && c.AtendeTipoNotificacao(rota.TipoNotificacao):
+			return 1
+		case c.Padrao:
+			return 2
+		}
+		return 3
+	}
+
+	// ListarPorUsuario já ordena por padrão e ID; o sort estável preserva essa ordem no empate
+	sort.SliceStable(conexoes, func(i, j int) bool {
+		return prioridade(&conexoes[i]) < prioridade(&conexoes[j])
+	})
+
+	return conexoes, nil
+}
+
+// ObterStatus retorna o status da conexão WhatsApp (padrão, se não informada)
+func (s *WhatsAppServico) ObterStatus(ctx context.Context, usuarioID uuid.UUID, conexaoID *int64) (*dto.StatusWhatsAppResponse, error) {
 	// Buscar conexão
-	conexao, err := s.whatsappRepo.BuscarPorUsuario(usuarioID)
+	conexao, err := s.buscarConexao(ctx, usuarioID, conexaoID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return &dto.StatusWhatsAppResponse{
@@ -99,10 +271,11 @@ func (s *WhatsAppServico) ObterStatus(usuarioID uuid.UUID) (*dto.StatusWhatsAppR
 	}
 
 	// Verificar status no Evolution API
-	status, err := s.evolutionAPI.ObterStatus(conexao.InstanceName)
+	status, err := s.evolutionAPI.ObterStatus(ctx, conexao.InstanceName)
 	if err != nil {
 		// Se der erro, retornar status da base de dados
 		return &dto.StatusWhatsAppResponse{
+			ConexaoID:     conexao.ID,
 			Conectado:     conexao.IsConectado(),
 			NomeInstancia: conexao.InstanceName,
 			DataConexao:   conexao.DataConexao,
@@ -111,16 +284,10 @@ func (s *WhatsAppServico) ObterStatus(usuarioID uuid.UUID) (*dto.StatusWhatsAppR
 
 	// Atualizar status na base de dados se mudou
 	statusConectado := status.Instance.State == "open"
-	if statusConectado != conexao.IsConectado() {
-		if statusConectado {
-			conexao.Conectar("")
-		} else {
-			conexao.Desconectar()
-		}
-		s.whatsappRepo.Atualizar(conexao)
-	}
+	s.atualizarStatus(ctx, conexao, statusConectado, "status")
 
 	return &dto.StatusWhatsAppResponse{
+		ConexaoID:     conexao.ID,
 		Conectado:     statusConectado,
 		NomeInstancia: conexao.InstanceName,
 		QRCode:        conexao.QRCode,
@@ -128,10 +295,33 @@ func (s *WhatsAppServico) ObterStatus(usuarioID uuid.UUID) (*dto.StatusWhatsAppR
 	}, nil
 }
 
-// Desconectar desconecta o WhatsApp
-func (s *WhatsAppServico) Desconectar(usuarioID uuid.UUID) error {
+// atualizarStatus grava a mudança de estado da instância. Uma conexão que estava
+// conectada e fechou é marcada como queda, para o monitor tentar reconectar.
+// Retorna true quando a conexão caiu nesta verificação.
+func (s *WhatsAppServico) atualizarStatus(ctx context.Context, conexao *entidades.WhatsAppConexao, conectado bool, motivo string) bool {
+	if conectado == conexao.IsConectado() {
+		return false
+	}
+
+	if conectado {
+		conexao.Conectar(conexao.NumeroConectado)
+		s.whatsappRepo.ComContexto(ctx).AtualizarCampos(conexao,
+			"status", "numero_conectado", "data_conexao", "data_pareamento", "data_ultima_atividade", "qr_code")
+		s.logger.InfoContext(ctx, "🟢 WhatsApp reconectado", "conexao", conexao.Nome, "instancia", conexao.InstanceName)
+		return false
+	}
+
+	conexao.MarcarQueda()
+	s.whatsappRepo.ComContexto(ctx).AtualizarCampos(conexao, "status")
+	s.dispararDesconexao(conexao, motivo)
+	s.logger.WarnContext(ctx, "🔴 WhatsApp caiu", "conexao", conexao.Nome, "instancia", conexao.InstanceName)
+	return true
+}
+
+// Desconectar desconecta e remove um número WhatsApp (padrão, se não informado)
+func (s *WhatsAppServico) Desconectar(ctx context.Context, usuarioID uuid.UUID, conexaoID *int64) error {
 	// Buscar conexão
-	conexao, err := s.whatsappRepo.BuscarPorUsuario(usuarioID)
+	conexao, err := s.buscarConexao(ctx, usuarioID, conexaoID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return errors.New("nenhuma conexão WhatsApp encontrada")
@@ -140,71 +330,72 @@ func (s *WhatsAppServico) Desconectar(usuarioID uuid.UUID) error {
 	}
 
 	// Desconectar no Evolution API
-	err = s.evolutionAPI.Desconectar(conexao.InstanceName)
+	err = s.evolutionAPI.Desconectar(ctx, conexao.InstanceName)
 	if err != nil {
 		return err
 	}
 
 	// Deletar conexão da base de dados
-	return s.whatsappRepo.Deletar(usuarioID)
+	if err := s.removerConexao(ctx, conexao); err != nil {
+		return err
+	}
+
+	s.dispararDesconexao(conexao, "manual")
+	return nil
 }
 
-// EnviarMensagem envia uma mensagem via WhatsApp
-func (s *WhatsAppServico) EnviarMensagem(usuarioID uuid.UUID, telefone, mensagem string) (*dto.EnviarMensagemResponse, error) {
-	// Buscar conexão
-	conexao, err := s.whatsappRepo.BuscarPorUsuario(usuarioID)
+// removerConexao apaga a conexão e, se ela era a padrão, promove a próxima
+func (s *WhatsAppServico) removerConexao(ctx context.Context, conexao *entidades.WhatsAppConexao) error {
+	if err := s.whatsappRepo.ComContexto(ctx).Deletar(conexao.ID, conexao.UsuarioID); err != nil {
+		return err
+	}
+
+	if conexao.Padrao {
+		if proxima, err := s.whatsappRepo.ComContexto(ctx).BuscarPorUsuario(conexao.UsuarioID); err == nil {
+			return s.whatsappRepo.ComContexto(ctx).DefinirPadrao(proxima.ID, conexao.UsuarioID)
+		}
+	}
+	return nil
+}
+
+// dispararDesconexao notifica os webhooks da conta sobre a queda do WhatsApp
+func (s *WhatsAppServico) dispararDesconexao(conexao *entidades.WhatsAppConexao, motivo string) {
+	s.webhookServico.Disparar(conexao.UsuarioID, enums.EventoWhatsAppDesconectado, map[string]interface{}{
+		"nomeInstancia": conexao.InstanceName,
+		"motivo":        motivo,
+		"dataEvento":    time.Now(),
+	})
+}
+
+// EnviarMensagem envia uma mensagem via WhatsApp.
+// Sem conexão informada, usa a rota padrão com failover entre os números conectados.
+func (s *WhatsAppServico) EnviarMensagem(ctx context.Context, usuarioID uuid.UUID, conexaoID *int64, telefone string, conteudo dto.MensagemWhatsApp) (*dto.EnviarMensagemResponse, error) {
+	rota := RotaMensagem{ConexaoID: conexaoID, TipoNotificacao: enums.TipoNotificacaoManual}
+
+	if err := validarConteudo(conteudo); err != nil {
+		return nil, err
+	}
+
+	// Validar antes de responder que existe ao menos um número conectado
+	candidatas, err := s.conexoesCandidatas(ctx, usuarioID, rota)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("WhatsApp não conectado")
 		}
 		return nil, err
 	}
-
-	// VALIDAÇÃO CRÍTICA: Garantir que a conexão pertence ao usuário solicitado
-	if conexao.UsuarioID != usuarioID {
-		log.Printf("⛔ SEGURANÇA CRÍTICA: Conexão WhatsApp pertence a usuário diferente! Solicitado: %s, Conexão: %s",
-			usuarioID, conexao.UsuarioID)
-		return nil, errors.New("erro de isolamento de dados detectado")
-	}
-
-	if !conexao.IsConectado() {
+	if !algumaConectada(candidatas) {
 		return nil, errors.New("WhatsApp não está conectado")
 	}
 
-	// Formatar telefone brasileiro (adicionar código do país 55 se necessário)
-	telefoneFormatado := util.FormatarTelefoneBrasileiro(telefone)
-
-	// Incrementar contador antecipadamente (envio assíncrono)
-	conexao.MensagensEnviadas++
-	now := time.Now()
-	conexao.DataUltimaAtividade = &now
-	s.whatsappRepo.Atualizar(conexao)
-
-	// Enviar mensagem de forma assíncrona para evitar timeout
+	// Enviar mensagem de forma assíncrona para evitar timeout; o envio continua após a
+	// resposta, então não herda o cancelamento da requisição (só o request_id e a conta)
+	ctxEnvio := context.WithoutCancel(ctx)
+	s.enviosEmAndamento.Add(1)
 	go func() {
-		fmt.Printf("📤 [ASYNC] Iniciando envio para %s (instância: %s)\n", telefoneFormatado, conexao.InstanceName)
-
-		resultado, err := s.evolutionAPI.EnviarMensagemTexto(conexao.InstanceName, telefoneFormatado, mensagem)
-
-		if err != nil {
-			fmt.Printf("❌ [ASYNC] Erro ao enviar: %v\n", err)
-		} else {
-			fmt.Printf("✅ [ASYNC] Mensagem enviada com sucesso! MessageID: %s\n", resultado.Key.ID)
-		}
-
-		// Atualizar estatísticas após envio
-		conexaoAtual, errBusca := s.whatsappRepo.BuscarPorUsuario(usuarioID)
-		if errBusca == nil {
-			if err != nil {
-				conexaoAtual.MensagensFalha++
-				fmt.Printf("📊 [ASYNC] Falhas: %d\n", conexaoAtual.MensagensFalha)
-			} else if resultado != nil {
-				conexaoAtual.MensagensSucesso++
-				fmt.Printf("📊 [ASYNC] Sucessos: %d\n", conexaoAtual.MensagensSucesso)
-			}
-			s.whatsappRepo.Atualizar(conexaoAtual)
-		} else {
-			fmt.Printf("⚠️  [ASYNC] Erro ao atualizar estatísticas: %v\n", errBusca)
+		defer s.enviosEmAndamento.Done()
+		if _, err := s.EnviarConteudoSincrono(ctxEnvio, usuarioID, rota, telefone, conteudo); err != nil {
+			s.logger.ErrorContext(ctxEnvio, "❌ Erro no envio assíncrono de WhatsApp", logs.Erro(err))
 		}
 	}()
 
@@ -214,11 +405,41 @@ func (s *WhatsAppServico) EnviarMensagem(usuarioID uuid.UUID, telefone, mensagem
 	}, nil
 }
 
-// EnviarMensagemSincrono envia uma mensagem via WhatsApp de forma síncrona
-// Usado pela fila de mensagens para evitar dupla camada assíncrona
-func (s *WhatsAppServico) EnviarMensagemSincrono(usuarioID uuid.UUID, telefone, mensagem string) (*dto.EnviarMensagemResponse, error) {
-	// Buscar conexão
-	conexao, err := s.whatsappRepo.BuscarPorUsuario(usuarioID)
+// Parar aguarda os envios assíncronos em andamento. ctx limita quanto tempo esperar.
+func (s *WhatsAppServico) Parar(ctx context.Context) error {
+	concluido := make(chan struct{})
+	go func() {
+		s.enviosEmAndamento.Wait()
+		close(concluido)
+	}()
+
+	select {
+	case <-concluido:
+		return nil
+	case <-ctx.Done():
+		return errors.New("prazo de encerramento esgotado: envios de WhatsApp ainda em andamento")
+	}
+}
+
+// EnviarMensagemSincrono envia uma mensagem de texto via WhatsApp de forma síncrona
+// Usado pela fila de mensagens para evitar dupla camada assíncrona.
+func (s *WhatsAppServico) EnviarMensagemSincrono(ctx context.Context, usuarioID uuid.UUID, rota RotaMensagem, telefone, mensagem string) (*dto.EnviarMensagemResponse, error) {
+	return s.EnviarConteudoSincrono(ctx, usuarioID, rota, telefone, dto.MensagemWhatsApp{Texto: mensagem})
+}
+
+// EnviarConteudoSincrono envia texto, anexos, botões ou lista via WhatsApp de forma síncrona.
+// Tenta os números conectados na ordem da rota; se o envio falhar em um, tenta o próximo.
+// Cada número respeita o seu ritmo de envio; se todos estiverem no limite, retorna *ErrLimiteEnvio.
+func (s *WhatsAppServico) EnviarConteudoSincrono(ctx context.Context, usuarioID uuid.UUID, rota RotaMensagem, telefone string, conteudo dto.MensagemWhatsApp) (resposta *dto.EnviarMensagemResponse, err error) {
+	ctx, span := rastreamento.Iniciar(ctx, "whatsapp.enviar",
+		attribute.String("ifinu.tipo_notificacao", string(rota.TipoNotificacao)))
+	defer func() { rastreamento.Finalizar(span, err) }()
+
+	if err := validarConteudo(conteudo); err != nil {
+		return nil, err
+	}
+
+	candidatas, err := s.conexoesCandidatas(ctx, usuarioID, rota)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("WhatsApp não conectado")
@@ -226,52 +447,175 @@ func (s *WhatsAppServico) EnviarMensagemSincrono(usuarioID uuid.UUID, telefone,
 		return nil, err
 	}
 
-	// VALIDAÇÃO CRÍTICA: Garantir que a conexão pertence ao usuário solicitado
-	if conexao.UsuarioID != usuarioID {
-		log.Printf("⛔ SEGURANÇA CRÍTICA: Conexão WhatsApp pertence a usuário diferente! Solicitado: %s, Conexão: %s",
-			usuarioID, conexao.UsuarioID)
-		return nil, errors.New("erro de isolamento de dados detectado")
+	// Formatar telefone brasileiro
+	telefoneFormatado := util.FormatarTelefoneBrasileiro(telefone)
+
+	var ultimoErro error
+	var limitado *ErrLimiteEnvio
+	for i := range candidatas {
+		conexao := &candidatas[i]
+
+		// VALIDAÇÃO CRÍTICA: Garantir que a conexão pertence ao usuário solicitado
+		if conexao.UsuarioID != usuarioID {
+			s.logger.ErrorContext(ctx, "⛔ SEGURANÇA CRÍTICA: Conexão WhatsApp pertence a usuário diferente",
+				"usuario_solicitado", usuarioID, "usuario_conexao", conexao.UsuarioID)
+			return nil, errors.New("erro de isolamento de dados detectado")
+		}
+
+		if !conexao.IsConectado() {
+			continue
+		}
+
+		// Ritmo do número (aquecimento e pausa humana) e limite diário da conta
+		reserva, err := s.limitador.Reservar(ctx, conexao)
+		if err != nil {
+			var limite *ErrLimiteEnvio
+			if !errors.As(err, &limite) {
+				return nil, err
+			}
+			s.logger.InfoContext(ctx, "⏳ Instância no limite de envio", "instancia", conexao.InstanceName, logs.Erro(err))
+			if limitado == nil || limite.Espera < limitado.Espera {
+				limitado = limite
+			}
+			continue
+		}
+		if err := aguardar(ctx, reserva.Espera); err != nil {
+			s.limitador.Devolver(ctx, reserva)
+			return nil, err
+		}
+
+		if i > 0 {
+			s.logger.InfoContext(ctx, "🔀 Failover para outro número", "conexao", conexao.Nome, "instancia", conexao.InstanceName)
+		}
+		s.logger.InfoContext(ctx, "📤 Enviando WhatsApp", "telefone", telefoneFormatado, "instancia", conexao.InstanceName)
+
+		// Enviar mensagem de forma SÍNCRONA (sem goroutine)
+		resultado, err := s.enviarPelaInstancia(ctx, conexao.InstanceName, telefoneFormatado, conteudo)
+
+		// Atualizar estatísticas (incremento no banco: outros workers enviam pela mesma conexão)
+		if errContador := s.whatsappRepo.ComContexto(ctx).RegistrarEnvio(conexao.ID, err == nil, time.Now()); errContador != nil {
+			s.logger.WarnContext(ctx, "⚠️ Erro ao registrar envio na conexão", "instancia", conexao.InstanceName, logs.Erro(errContador))
+		}
+		// Pelo ID da conexão: o nome da instância contém o email da conta e muda a cada pareamento
+		metricas.EnviosWhatsApp.WithLabelValues(strconv.FormatInt(conexao.ID, 10), metricas.Resultado(err)).Inc()
+
+		if err != nil {
+			// Failover só quando é certo que a mensagem não saiu; com timeout ou 5xx ela pode
+			// ter sido entregue e tentar outro número mandaria a mesma cobrança duas vezes
+			if !integracao.EnvioNaoRealizado(err) {
+				s.logger.ErrorContext(ctx, "❌ Resultado incerto do envio, sem failover", "instancia", conexao.InstanceName, logs.Erro(err))
+				return nil, fmt.Errorf("%w: %w", ErrEnvioIncerto, err)
+			}
+			s.limitador.Devolver(ctx, reserva)
+			s.logger.WarnContext(ctx, "❌ Erro ao enviar pela instância", "instancia", conexao.InstanceName, logs.Erro(err))
+			ultimoErro = err
+			continue
+		}
+
+		s.logger.InfoContext(ctx, "✅ WhatsApp enviado", "instancia", conexao.InstanceName, "message_id", resultado.Key.ID)
+
+		return &dto.EnviarMensagemResponse{
+			Sucesso:   true,
+			Mensagem:  "Mensagem enviada com sucesso",
+			MessageID: resultado.Key.ID,
+		}, nil
 	}
 
-	if !conexao.IsConectado() {
-		return nil, errors.New("WhatsApp não está conectado")
+	if ultimoErro != nil {
+		return nil, fmt.Errorf("erro ao enviar mensagem: %w", ultimoErro)
+	}
+	if limitado != nil {
+		return nil, limitado
 	}
+	return nil, errors.New("WhatsApp não está conectado")
+}
 
-	// Formatar telefone brasileiro
-	telefoneFormatado := util.FormatarTelefoneBrasileiro(telefone)
+// enviarPelaInstancia envia o conteúdo por uma instância. A mensagem principal (texto, botões
+// ou lista) define o sucesso do envio; os anexos vão em seguida e uma falha neles só é
+// registrada, para que o failover não repita a mensagem principal em outro número.
+func (s *WhatsAppServico) enviarPelaInstancia(ctx context.Context, instancia, telefone string, conteudo dto.MensagemWhatsApp) (resultado *integracao.EnviarMensagemResponse, err error) {
+	ctx, span := rastreamento.Iniciar(ctx, "whatsapp.enviar_pela_instancia",
+		attribute.String("ifinu.instancia", instancia), attribute.Int("ifinu.anexos", len(conteudo.Anexos)))
+	defer func() { rastreamento.Finalizar(span, err) }()
+
+	switch {
+	case conteudo.Lista != nil:
+		resultado, err = s.evolutionAPI.EnviarLista(ctx, instancia, telefone, listaEvolution(conteudo))
+	case len(conteudo.Botoes) > 0:
+		resultado, err = s.evolutionAPI.EnviarBotoes(ctx, instancia, telefone, botoesEvolution(conteudo))
+	}
 
-	log.Printf("📤 [SYNC] Enviando para %s (instância: %s)", telefoneFormatado, conexao.InstanceName)
+	// Mensagens interativas dependem da versão do WhatsApp do cliente e da Evolution API:
+	// se forem recusadas, envia o texto com os links e códigos dos botões. Com resultado
+	// incerto não reenvia, para não duplicar a mensagem.
+	if err != nil && !integracao.EnvioNaoRealizado(err) {
+		return nil, err
+	}
+	if err != nil {
+		s.logger.WarnContext(ctx, "⚠️  Mensagem interativa recusada, enviando como texto", "instancia", instancia, logs.Erro(err))
+		resultado = nil
+	}
+	if resultado == nil {
+		texto := textoComBotoes(conteudo)
+		if conteudo.LinkPreview {
+			resultado, err = s.evolutionAPI.EnviarMensagemComPreview(ctx, instancia, telefone, texto)
+		} else {
+			resultado, err = s.evolutionAPI.EnviarMensagemTexto(ctx, instancia, telefone, texto)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	// Enviar mensagem de forma SÍNCRONA (sem goroutine)
-	resultado, err := s.evolutionAPI.EnviarMensagemTexto(conexao.InstanceName, telefoneFormatado, mensagem)
+	for _, anexo := range conteudo.Anexos {
+		if _, err := s.evolutionAPI.EnviarMidia(ctx, instancia, telefone, midiaEvolution(anexo)); err != nil {
+			s.logger.WarnContext(ctx, "⚠️  Erro ao enviar anexo", "arquivo", anexo.NomeArquivo, "instancia", instancia, logs.Erro(err))
+		}
+	}
 
-	// Atualizar estatísticas
-	conexao.MensagensEnviadas++
-	now := time.Now()
-	conexao.DataUltimaAtividade = &now
+	return resultado, nil
+}
 
+// VerificarNumero consulta na Evolution API, por um número conectado da conta (na ordem
+// da rota), se o telefone tem conta no WhatsApp
+func (s *WhatsAppServico) VerificarNumero(ctx context.Context, usuarioID uuid.UUID, rota RotaMensagem, telefone string) (bool, error) {
+	candidatas, err := s.conexoesCandidatas(ctx, usuarioID, rota)
 	if err != nil {
-		conexao.MensagensFalha++
-		log.Printf("❌ [SYNC] Erro ao enviar: %v", err)
-		s.whatsappRepo.Atualizar(conexao)
-		return nil, fmt.Errorf("erro ao enviar mensagem: %w", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, errors.New("WhatsApp não conectado")
+		}
+		return false, err
 	}
 
-	conexao.MensagensSucesso++
-	s.whatsappRepo.Atualizar(conexao)
+	telefoneFormatado := util.FormatarTelefoneBrasileiro(telefone)
 
-	log.Printf("✅ [SYNC] Mensagem enviada! MessageID: %s", resultado.Key.ID)
+	var ultimoErro error
+	for i := range candidatas {
+		conexao := &candidatas[i]
+		if conexao.UsuarioID != usuarioID || !conexao.IsConectado() {
+			continue
+		}
 
-	return &dto.EnviarMensagemResponse{
-		Sucesso:  true,
-		Mensagem: "Mensagem enviada com sucesso",
-	}, nil
+		numeros, err := s.evolutionAPI.VerificarNumeros(ctx, conexao.InstanceName, []string{telefoneFormatado})
+		if err != nil {
+			s.logger.WarnContext(ctx, "⚠️  Erro ao verificar número", "instancia", conexao.InstanceName, logs.Erro(err))
+			ultimoErro = err
+			continue
+		}
+
+		return len(numeros) > 0 && numeros[0].Exists, nil
+	}
+
+	if ultimoErro != nil {
+		return false, fmt.Errorf("erro ao verificar número: %w", ultimoErro)
+	}
+	return false, errors.New("WhatsApp não está conectado")
 }
 
 // TestarConexao testa a conexão WhatsApp
-func (s *WhatsAppServico) TestarConexao(usuarioID uuid.UUID) (*dto.TestarConexaoResponse, error) {
+func (s *WhatsAppServico) TestarConexao(ctx context.Context, usuarioID uuid.UUID, conexaoID *int64) (*dto.TestarConexaoResponse, error) {
 	// Buscar conexão
-	conexao, err := s.whatsappRepo.BuscarPorUsuario(usuarioID)
+	conexao, err := s.buscarConexao(ctx, usuarioID, conexaoID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return &dto.TestarConexaoResponse{
@@ -284,7 +628,7 @@ func (s *WhatsAppServico) TestarConexao(usuarioID uuid.UUID) (*dto.TestarConexao
 	}
 
 	// Verificar status no Evolution API
-	status, err := s.evolutionAPI.ObterStatus(conexao.InstanceName)
+	status, err := s.evolutionAPI.ObterStatus(ctx, conexao.InstanceName)
 	if err != nil {
 		return &dto.TestarConexaoResponse{
 			Sucesso:  false,
@@ -309,9 +653,9 @@ func (s *WhatsAppServico) TestarConexao(usuarioID uuid.UUID) (*dto.TestarConexao
 }
 
 // ObterQRCode retorna o QR code da conexão WhatsApp
-func (s *WhatsAppServico) ObterQRCode(usuarioID uuid.UUID) (map[string]interface{}, error) {
+func (s *WhatsAppServico) ObterQRCode(ctx context.Context, usuarioID uuid.UUID, conexaoID *int64) (map[string]interface{}, error) {
 	// Buscar conexão
-	conexao, err := s.whatsappRepo.BuscarPorUsuario(usuarioID)
+	conexao, err := s.buscarConexao(ctx, usuarioID, conexaoID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("nenhuma conexão WhatsApp encontrada")
@@ -339,11 +683,11 @@ func (s *WhatsAppServico) ObterQRCode(usuarioID uuid.UUID) (map[string]interface
 
 	// QR Code vazio no banco - buscar direto da Evolution API
 	// (Evolution API v2.2+ gera QR Code de forma assíncrona)
-	qrcode, err := s.evolutionAPI.ObterQRCode(conexao.InstanceName)
+	qrcode, err := s.evolutionAPI.ObterQRCode(ctx, conexao.InstanceName)
 	if err == nil && qrcode != "" {
 		// Salvar no banco para próximas consultas
 		conexao.QRCode = qrcode
-		s.whatsappRepo.Atualizar(conexao)
+		s.whatsappRepo.ComContexto(ctx).AtualizarCampos(conexao, "qr_code")
 
 		return map[string]interface{}{
 			"qrcode":        qrcode,
@@ -356,45 +700,59 @@ func (s *WhatsAppServico) ObterQRCode(usuarioID uuid.UUID) (map[string]interface
 }
 
 // LimparOrfaos remove conexões WhatsApp órfãs (sem instância válida na Evolution API)
-func (s *WhatsAppServico) LimparOrfaos(usuarioID uuid.UUID) (map[string]interface{}, error) {
-	// Buscar conexão do usuário
-	conexao, err := s.whatsappRepo.BuscarPorUsuario(usuarioID)
+func (s *WhatsAppServico) LimparOrfaos(ctx context.Context, usuarioID uuid.UUID) (map[string]interface{}, error) {
+	// Buscar conexões do usuário
+	conexoes, err := s.whatsappRepo.ComContexto(ctx).ListarPorUsuario(usuarioID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return map[string]interface{}{
-				"mensagem": "Nenhuma conexão encontrada",
-				"removido": false,
-			}, nil
-		}
 		return nil, err
 	}
+	if len(conexoes) == 0 {
+		return map[string]interface{}{
+			"mensagem": "Nenhuma conexão encontrada",
+			"removido": false,
+		}, nil
+	}
+
+	removidas := 0
+	for i := range conexoes {
+		conexao := &conexoes[i]
+
+		// Verificar se a instância existe na Evolution API
+		if _, err := s.evolutionAPI.ObterStatus(ctx, conexao.InstanceName); err == nil {
+			continue
+		}
 
-	// Verificar se a instância existe na Evolution API
-	_, err = s.evolutionAPI.ObterStatus(conexao.InstanceName)
-	if err != nil {
 		// Se der erro ao buscar status, a instância provavelmente não existe mais
 		// Remover conexão órfã
-		err = s.whatsappRepo.Deletar(usuarioID)
-		if err != nil {
+		if err := s.removerConexao(ctx, conexao); err != nil {
 			return nil, fmt.Errorf("erro ao remover conexão órfã: %w", err)
 		}
+		removidas++
+	}
 
+	if removidas > 0 {
 		return map[string]interface{}{
-			"mensagem": "Conexão órfã removida com sucesso",
-			"removido": true,
+			"mensagem":  fmt.Sprintf("%d conexão(ões) órfã(s) removida(s) com sucesso", removidas),
+			"removido":  true,
+			"removidas": removidas,
 		}, nil
 	}
 
 	return map[string]interface{}{
-		"mensagem": "Conexão válida, nada a remover",
+		"mensagem": "Conexões válidas, nada a remover",
 		"removido": false,
 	}, nil
 }
 
 // ObterEstatisticas retorna estatísticas sobre o WhatsApp
-func (s *WhatsAppServico) ObterEstatisticas(usuarioID uuid.UUID) (map[string]interface{}, error) {
+func (s *WhatsAppServico) ObterEstatisticas(ctx context.Context, usuarioID uuid.UUID, conexaoID *int64) (map[string]interface{}, error) {
 	// Buscar conexão do usuário
-	conexao, err := s.whatsappRepo.BuscarPorUsuario(usuarioID)
+	conexoes, err := s.whatsappRepo.ComContexto(ctx).ListarPorUsuario(usuarioID)
+	if err != nil {
+		return nil, err
+	}
+
+	conexao, err := s.buscarConexao(ctx, usuarioID, conexaoID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return map[string]interface{}{
@@ -407,6 +765,9 @@ func (s *WhatsAppServico) ObterEstatisticas(usuarioID uuid.UUID) (map[string]int
 	}
 
 	estatisticas := map[string]interface{}{
+		"totalConexoes":   len(conexoes),
+		"conexaoId":       conexao.ID,
+		"nome":            conexao.Nome,
 		"conectado":       conexao.IsConectado(),
 		"nomeInstancia":   conexao.InstanceName,
 		"status":          conexao.Status,
@@ -417,7 +778,7 @@ func (s *WhatsAppServico) ObterEstatisticas(usuarioID uuid.UUID) (map[string]int
 
 	// Se conectado, buscar informações adicionais da Evolution API
 	if conexao.IsConectado() {
-		status, err := s.evolutionAPI.ObterStatus(conexao.InstanceName)
+		status, err := s.evolutionAPI.ObterStatus(ctx, conexao.InstanceName)
 		if err == nil {
 			estatisticas["instanceName"] = status.Instance.InstanceName
 			estatisticas["state"] = status.Instance.State
@@ -426,3 +787,45 @@ func (s *WhatsAppServico) ObterEstatisticas(usuarioID uuid.UUID) (map[string]int
 
 	return estatisticas, nil
 }
+
+// algumaConectada verifica se há ao menos uma conexão conectada
+func algumaConectada(conexoes []entidades.WhatsAppConexao) bool {
+	for _, conexao := range conexoes {
+		if conexao.IsConectado() {
+			return true
+		}
+	}
+	return false
+}
+
+// validarTiposNotificacao rejeita tipos de notificação desconhecidos
+func validarTiposNotificacao(tipos []enums.TipoNotificacao) error {
+	for _, tipo := range tipos {
+		if !tipo.Valido() {
+			return fmt.Errorf("tipo de notificação inválido: %s", tipo)
+		}
+	}
+	return nil
+}
+
+// mapearConexaoParaDTO converte WhatsAppConexao para ConexaoWhatsAppResponse
+func mapearConexaoParaDTO(conexao *entidades.WhatsAppConexao) *dto.ConexaoWhatsAppResponse {
+	tipos := conexao.ListaTiposNotificacao()
+	if tipos == nil {
+		tipos = []enums.TipoNotificacao{}
+	}
+
+	return &dto.ConexaoWhatsAppResponse{
+		ID:                conexao.ID,
+		Nome:              conexao.Nome,
+		Padrao:            conexao.Padrao,
+		TiposNotificacao:  tipos,
+		Conectado:         conexao.IsConectado(),
+		Status:            string(conexao.Status),
+		NomeInstancia:     conexao.InstanceName,
+		NumeroConectado:   conexao.NumeroConectado,
+		MensagensEnviadas: conexao.MensagensEnviadas,
+		TaxaSucesso:       conexao.TaxaSucesso(),
+		DataConexao:       conexao.DataConexao,
+	}
+}
@@ -0,0 +1,26 @@
+package servico
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPararAguardaEnviosAssincronos(t *testing.T) {
+	s := &WhatsAppServico{}
+	s.enviosEmAndamento.Add(1)
+
+	ctx, cancelar := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancelar()
+	if err := s.Parar(ctx); err == nil {
+		t.Fatal("Parar retornou com envio em andamento")
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		s.enviosEmAndamento.Done()
+	}()
+	if err := s.Parar(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
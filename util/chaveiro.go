@@ -0,0 +1,192 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	// IDChaveLegada identifica a ENCRYPTION_KEY única usada antes do chaveiro
+	IDChaveLegada = "v1"
+	// IDChavePadrao identifica a chave de desenvolvimento usada quando nenhuma é configurada
+	IDChavePadrao = "padrao"
+
+	chavePadrao = "ifinu-default-encryption-key-change-me"
+)
+
+// Chaves públicas (fallback do código e valor do .env.example) que não protegem nada
+var chavesInseguras = map[string]bool{
+	chavePadrao: true,
+	"ifinu-encryption-key-change-in-production-min-32-chars": true,
+}
+
+var idChaveValido = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// Chaveiro guarda as chaves de criptografia por ID. Criptografa sempre com a chave atual
+// e grava o ID como prefixo do texto cifrado ("id:base64"), para que as chaves antigas
+// continuem abrindo os segredos até que sejam recriptografados.
+type Chaveiro struct {
+	atual  string
+	chaves map[string]string
+}
+
+// NovoChaveiro cria o chaveiro com as chaves informadas (ID -> chave) e o ID da atual
+func NovoChaveiro(chaves map[string]string, idAtual string) (*Chaveiro, error) {
+	if len(chaves) == 0 {
+		return nil, errors.New("nenhuma chave de criptografia configurada")
+	}
+	for id, chave := range chaves {
+		if !idChaveValido.MatchString(id) {
+			return nil, fmt.Errorf("ID de chave inválido: %q (use letras, números, '.', '_' ou '-')", id)
+		}
+		if chave == "" {
+			return nil, fmt.Errorf("chave %s vazia", id)
+		}
+	}
+	if _, ok := chaves[idAtual]; !ok {
+		return nil, fmt.Errorf("chave atual %s não está entre as chaves configuradas", idAtual)
+	}
+
+	copia := make(map[string]string, len(chaves))
+	for id, chave := range chaves {
+		copia[id] = chave
+	}
+	return &Chaveiro{atual: idAtual, chaves: copia}, nil
+}
+
+// MontarChaveiro monta o chaveiro a partir da configuração:
+//   - lista: "id:chave,id:chave" (ENCRYPTION_KEYS)
+//   - idAtual: chave usada para criptografar (ENCRYPTION_KEY_ID); padrão é a primeira da lista
+//   - chaveLegada: ENCRYPTION_KEY, registrada como IDChaveLegada
+//
+// Sem nenhuma chave, usa a chave padrão de desenvolvimento.
+func MontarChaveiro(lista, idAtual, chaveLegada string) (*Chaveiro, error) {
+	chaves := make(map[string]string)
+	primeira := ""
+	for _, item := range strings.Split(lista, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		id, chave, ok := strings.Cut(item, ":")
+		if !ok {
+			return nil, errors.New("ENCRYPTION_KEYS inválido: esperado id:chave separados por vírgula")
+		}
+		id = strings.TrimSpace(id)
+		if _, repetida := chaves[id]; repetida {
+			return nil, fmt.Errorf("chave %s repetida em ENCRYPTION_KEYS", id)
+		}
+		chaves[id] = chave
+		if primeira == "" {
+			primeira = id
+		}
+	}
+
+	if chaveLegada != "" {
+		if _, ok := chaves[IDChaveLegada]; !ok {
+			chaves[IDChaveLegada] = chaveLegada
+		}
+		if primeira == "" {
+			primeira = IDChaveLegada
+		}
+	}
+
+	if len(chaves) == 0 {
+		chaves[IDChavePadrao] = chavePadrao
+		primeira = IDChavePadrao
+	}
+
+	if idAtual == "" {
+		idAtual = primeira
+	}
+	return NovoChaveiro(chaves, idAtual)
+}
+
+// IDAtual retorna o ID da chave usada para criptografar
+func (c *Chaveiro) IDAtual() string {
+	return c.atual
+}
+
+// ChaveAtualInsegura indica se a chave atual é pública (padrão do código ou do .env.example)
+func (c *Chaveiro) ChaveAtualInsegura() bool {
+	return chavesInseguras[c.chaves[c.atual]]
+}
+
+// Criptografar cifra com a chave atual e prefixa o ID da chave
+func (c *Chaveiro) Criptografar(texto string) (string, error) {
+	cifrado, err := EncryptStringComChave(texto, c.chaves[c.atual])
+	if err != nil {
+		return "", err
+	}
+	return c.atual + ":" + cifrado, nil
+}
+
+// Descriptografar abre o texto com a chave indicada no prefixo. Textos sem prefixo
+// (gravados antes do chaveiro) são testados com todas as chaves, começando pela atual.
+func (c *Chaveiro) Descriptografar(cifrado string) (string, error) {
+	id := IDChaveCifrado(cifrado)
+	if id == "" {
+		return c.descriptografarSemID(cifrado)
+	}
+
+	chave, ok := c.chaves[id]
+	if !ok {
+		return "", fmt.Errorf("chave de criptografia %s não configurada", id)
+	}
+	return DecryptStringComChave(strings.TrimPrefix(cifrado, id+":"), chave)
+}
+
+func (c *Chaveiro) descriptografarSemID(cifrado string) (string, error) {
+	if texto, err := DecryptStringComChave(cifrado, c.chaves[c.atual]); err == nil {
+		return texto, nil
+	}
+	for id, chave := range c.chaves {
+		if id == c.atual {
+			continue
+		}
+		if texto, err := DecryptStringComChave(cifrado, chave); err == nil {
+			return texto, nil
+		}
+	}
+	return "", errors.New("nenhuma chave configurada abre o texto cifrado")
+}
+
+// PrecisaRecriptografar indica se o texto não foi cifrado com a chave atual
+func (c *Chaveiro) PrecisaRecriptografar(cifrado string) bool {
+	return IDChaveCifrado(cifrado) != c.atual
+}
+
+// IDChaveCifrado retorna o ID da chave de um texto cifrado, ou "" se não tiver prefixo.
+// Base64 não usa ':', então o prefixo não se confunde com o conteúdo.
+func IDChaveCifrado(cifrado string) string {
+	id, _, ok := strings.Cut(cifrado, ":")
+	if !ok || !idChaveValido.MatchString(id) {
+		return ""
+	}
+	return id
+}
+
+var chaveiro atomic.Pointer[Chaveiro]
+
+// DefinirChaveiro define o chaveiro usado por EncryptString e DecryptString
+func DefinirChaveiro(c *Chaveiro) {
+	chaveiro.Store(c)
+}
+
+// ObterChaveiro retorna o chaveiro configurado. Sem DefinirChaveiro (scripts e testes),
+// usa apenas a ENCRYPTION_KEY do ambiente, como antes do chaveiro.
+func ObterChaveiro() *Chaveiro {
+	if c := chaveiro.Load(); c != nil {
+		return c
+	}
+
+	id, chave := IDChaveLegada, os.Getenv("ENCRYPTION_KEY")
+	if chave == "" {
+		id, chave = IDChavePadrao, chavePadrao
+	}
+	return &Chaveiro{atual: id, chaves: map[string]string{id: chave}}
+}
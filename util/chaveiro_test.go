@@ -0,0 +1,121 @@
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMontarChaveiro(t *testing.T) {
+	tests := []struct {
+		nome       string
+		lista      string
+		idAtual    string
+		legada     string
+		atual      string
+		insegura   bool
+		esperarErr bool
+	}{
+		{nome: "sem configuração usa a padrão", atual: IDChavePadrao, insegura: true},
+		{nome: "só ENCRYPTION_KEY", legada: "segredo-antigo", atual: IDChaveLegada},
+		{nome: "primeira da lista é a atual", lista: "v3:nova, v2:anterior", legada: "antiga", atual: "v3"},
+		{nome: "ENCRYPTION_KEY_ID escolhe a atual", lista: "v3:nova,v2:anterior", idAtual: "v2", atual: "v2"},
+		{nome: "ENCRYPTION_KEY_ID inexistente", lista: "v3:nova", idAtual: "v9", esperarErr: true},
+		{nome: "item sem ID", lista: "so-a-chave", esperarErr: true},
+		{nome: "ID repetido", lista: "v2:a,v2:b", esperarErr: true},
+		{nome: "ID inválido", lista: "v 2:a", esperarErr: true},
+		{nome: "chave do .env.example", legada: "ifinu-encryption-key-change-in-production-min-32-chars", atual: IDChaveLegada, insegura: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			chaveiro, err := MontarChaveiro(tt.lista, tt.idAtual, tt.legada)
+			if tt.esperarErr {
+				if err == nil {
+					t.Fatal("esperado erro")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("erro inesperado: %v", err)
+			}
+			if chaveiro.IDAtual() != tt.atual {
+				t.Errorf("IDAtual() = %s, esperado %s", chaveiro.IDAtual(), tt.atual)
+			}
+			if chaveiro.ChaveAtualInsegura() != tt.insegura {
+				t.Errorf("ChaveAtualInsegura() = %v, esperado %v", chaveiro.ChaveAtualInsegura(), tt.insegura)
+			}
+		})
+	}
+}
+
+func TestChaveiroRotacao(t *testing.T) {
+	antigo, _ := MontarChaveiro("", "", "chave-antiga")
+	novo, _ := MontarChaveiro("v2:chave-nova", "", "chave-antiga")
+
+	cifradoAntigo, err := antigo.Criptografar("sk_live_123")
+	if err != nil {
+		t.Fatalf("erro ao criptografar: %v", err)
+	}
+	if !strings.HasPrefix(cifradoAntigo, "v1:") {
+		t.Errorf("texto cifrado sem ID da chave: %s", cifradoAntigo)
+	}
+
+	// A chave antiga continua abrindo os segredos depois da troca da atual
+	if texto, err := novo.Descriptografar(cifradoAntigo); err != nil || texto != "sk_live_123" {
+		t.Errorf("Descriptografar = %q, %v, esperado sk_live_123", texto, err)
+	}
+	if !novo.PrecisaRecriptografar(cifradoAntigo) {
+		t.Error("segredo da chave antiga deveria precisar de recriptografia")
+	}
+
+	cifradoNovo, _ := novo.Criptografar("sk_live_123")
+	if IDChaveCifrado(cifradoNovo) != "v2" || novo.PrecisaRecriptografar(cifradoNovo) {
+		t.Errorf("segredo novo deveria usar a chave v2: %s", cifradoNovo)
+	}
+
+	// Sem a chave no chaveiro o segredo não abre
+	if _, err := antigo.Descriptografar(cifradoNovo); err == nil {
+		t.Error("esperado erro com chave v2 não configurada")
+	}
+}
+
+func TestChaveiroTextoSemID(t *testing.T) {
+	// Segredos gravados antes do chaveiro não têm prefixo
+	legado, err := EncryptStringComChave("whsec_abc", "chave-antiga")
+	if err != nil {
+		t.Fatalf("erro ao criptografar: %v", err)
+	}
+	if IDChaveCifrado(legado) != "" {
+		t.Fatalf("texto legado não deveria ter ID: %s", legado)
+	}
+
+	chaveiro, _ := MontarChaveiro("v2:chave-nova", "", "chave-antiga")
+	if texto, err := chaveiro.Descriptografar(legado); err != nil || texto != "whsec_abc" {
+		t.Errorf("Descriptografar = %q, %v, esperado whsec_abc", texto, err)
+	}
+	if !chaveiro.PrecisaRecriptografar(legado) {
+		t.Error("texto legado deveria precisar de recriptografia")
+	}
+
+	semChave, _ := MontarChaveiro("v2:chave-nova", "", "")
+	if _, err := semChave.Descriptografar(legado); err == nil {
+		t.Error("esperado erro sem a chave antiga")
+	}
+}
+
+func TestEncryptStringUsaChaveiro(t *testing.T) {
+	chaveiro, _ := MontarChaveiro("v2:chave-nova", "", "")
+	DefinirChaveiro(chaveiro)
+	t.Cleanup(func() { DefinirChaveiro(nil) })
+
+	cifrado, err := EncryptString("segredo")
+	if err != nil {
+		t.Fatalf("erro ao criptografar: %v", err)
+	}
+	if IDChaveCifrado(cifrado) != "v2" {
+		t.Errorf("EncryptString deveria usar a chave atual do chaveiro: %s", cifrado)
+	}
+	if texto, err := DecryptString(cifrado); err != nil || texto != "segredo" {
+		t.Errorf("DecryptString = %q, %v, esperado segredo", texto, err)
+	}
+}
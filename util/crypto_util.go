@@ -3,31 +3,35 @@ package util
 import (
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/hmac"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"io"
-	"os"
 )
 
-// getEncryptionKey obtém a chave de criptografia do ambiente
-// e garante que tenha 32 bytes (AES-256)
-func getEncryptionKey() []byte {
-	key := os.Getenv("ENCRYPTION_KEY")
-	if key == "" {
-		// Fallback para chave padrão (DEVE ser configurada em produção!)
-		key = "ifinu-default-encryption-key-change-me"
-	}
-
-	// Usar SHA256 para garantir 32 bytes
-	hash := sha256.Sum256([]byte(key))
+// derivarChave garante que a chave tenha 32 bytes (AES-256)
+func derivarChave(chave string) []byte {
+	hash := sha256.Sum256([]byte(chave))
 	return hash[:]
 }
 
-// EncryptString criptografa uma string usando AES-256-GCM
+// EncryptString criptografa uma string com a chave atual do chaveiro (AES-256-GCM).
+// O resultado leva o ID da chave como prefixo.
 func EncryptString(plaintext string) (string, error) {
-	key := getEncryptionKey()
+	return ObterChaveiro().Criptografar(plaintext)
+}
+
+// DecryptString descriptografa uma string com a chave indicada no prefixo
+func DecryptString(ciphertext string) (string, error) {
+	return ObterChaveiro().Descriptografar(ciphertext)
+}
+
+// EncryptStringComChave criptografa uma string usando AES-256-GCM com a chave informada
+func EncryptStringComChave(plaintext string, chave string) (string, error) {
+	key := derivarChave(chave)
 
 	block, err := aes.NewCipher(key)
 	if err != nil {
@@ -48,9 +52,9 @@ func EncryptString(plaintext string) (string, error) {
 	return base64.StdEncoding.EncodeToString(ciphertext), nil
 }
 
-// DecryptString descriptografa uma string criptografada com AES-256-GCM
-func DecryptString(ciphertext string) (string, error) {
-	key := getEncryptionKey()
+// DecryptStringComChave descriptografa uma string criptografada com AES-256-GCM com a chave informada
+func DecryptStringComChave(ciphertext string, chave string) (string, error) {
+	key := derivarChave(chave)
 
 	data, err := base64.StdEncoding.DecodeString(ciphertext)
 	if err != nil {
@@ -80,3 +84,25 @@ func DecryptString(ciphertext string) (string, error) {
 
 	return string(plaintext), nil
 }
+
+// GerarTokenAleatorio gera um token hexadecimal seguro com n bytes de entropia
+func GerarTokenAleatorio(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(rand.Reader, buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// HashSHA256 retorna o hash SHA-256 em hexadecimal de um valor
+func HashSHA256(valor string) string {
+	hash := sha256.Sum256([]byte(valor))
+	return hex.EncodeToString(hash[:])
+}
+
+// AssinarHMAC retorna a assinatura HMAC-SHA256 em hexadecimal de uma mensagem
+func AssinarHMAC(segredo, mensagem string) string {
+	mac := hmac.New(sha256.New, []byte(segredo))
+	mac.Write([]byte(mensagem))
+	return hex.EncodeToString(mac.Sum(nil))
+}
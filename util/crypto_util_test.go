@@ -0,0 +1,41 @@
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEncryptStringComChave(t *testing.T) {
+	cifrado, err := EncryptStringComChave("sk_test_123", "chave-antiga")
+	if err != nil {
+		t.Fatalf("erro ao criptografar: %v", err)
+	}
+
+	texto, err := DecryptStringComChave(cifrado, "chave-antiga")
+	if err != nil || texto != "sk_test_123" {
+		t.Errorf("DecryptStringComChave = %q, %v, esperado sk_test_123", texto, err)
+	}
+
+	if _, err := DecryptStringComChave(cifrado, "chave-nova"); err == nil {
+		t.Error("esperado erro ao descriptografar com outra chave")
+	}
+}
+
+// Sem DefinirChaveiro (scripts/), EncryptString usa a ENCRYPTION_KEY do ambiente
+func TestEncryptStringUsaEncryptionKey(t *testing.T) {
+	t.Setenv("ENCRYPTION_KEY", "chave-do-ambiente")
+
+	cifrado, err := EncryptString("segredo")
+	if err != nil {
+		t.Fatalf("erro ao criptografar: %v", err)
+	}
+	if IDChaveCifrado(cifrado) != IDChaveLegada {
+		t.Errorf("EncryptString deveria usar a chave %s: %s", IDChaveLegada, cifrado)
+	}
+
+	_, semPrefixo, _ := strings.Cut(cifrado, ":")
+	texto, err := DecryptStringComChave(semPrefixo, "chave-do-ambiente")
+	if err != nil || texto != "segredo" {
+		t.Errorf("DecryptStringComChave = %q, %v, esperado segredo", texto, err)
+	}
+}
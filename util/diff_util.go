@@ -0,0 +1,69 @@
+package util
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// CampoAlterado representa o valor de um campo antes e depois de uma alteração
+type CampoAlterado struct {
+	Antes  interface{} `json:"antes"`
+	Depois interface{} `json:"depois"`
+}
+
+// DiffCampos compara a representação JSON de dois valores e retorna apenas os
+// campos que mudaram. antes ou depois podem ser nil (criação e exclusão).
+// Campos listados em ignorar (nomes JSON) não entram no resultado.
+func DiffCampos(antes, depois interface{}, ignorar ...string) (map[string]CampoAlterado, error) {
+	mapaAntes, err := paraMapa(antes)
+	if err != nil {
+		return nil, err
+	}
+	mapaDepois, err := paraMapa(depois)
+	if err != nil {
+		return nil, err
+	}
+
+	ignorados := make(map[string]bool, len(ignorar))
+	for _, campo := range ignorar {
+		ignorados[campo] = true
+	}
+
+	diff := make(map[string]CampoAlterado)
+	for campo, valorAntes := range mapaAntes {
+		if ignorados[campo] {
+			continue
+		}
+		valorDepois, existe := mapaDepois[campo]
+		if !existe || !reflect.DeepEqual(valorAntes, valorDepois) {
+			diff[campo] = CampoAlterado{Antes: valorAntes, Depois: valorDepois}
+		}
+	}
+	for campo, valorDepois := range mapaDepois {
+		if ignorados[campo] {
+			continue
+		}
+		if _, existe := mapaAntes[campo]; !existe {
+			diff[campo] = CampoAlterado{Antes: nil, Depois: valorDepois}
+		}
+	}
+
+	return diff, nil
+}
+
+// paraMapa converte um valor em mapa usando sua serialização JSON
+func paraMapa(valor interface{}) (map[string]interface{}, error) {
+	mapa := make(map[string]interface{})
+	if valor == nil || (reflect.ValueOf(valor).Kind() == reflect.Ptr && reflect.ValueOf(valor).IsNil()) {
+		return mapa, nil
+	}
+
+	data, err := json.Marshal(valor)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &mapa); err != nil {
+		return nil, err
+	}
+	return mapa, nil
+}
@@ -0,0 +1,81 @@
+package util
+
+import (
+	"testing"
+)
+
+type registroTeste struct {
+	Nome   string  `json:"nome"`
+	Valor  float64 `json:"valor"`
+	Status string  `json:"status"`
+}
+
+func TestDiffCampos(t *testing.T) {
+	antes := &registroTeste{Nome: "Mensalidade", Valor: 100, Status: "PENDENTE"}
+
+	tests := []struct {
+		nome     string
+		antes    interface{}
+		depois   interface{}
+		ignorar  []string
+		esperado map[string]CampoAlterado
+	}{
+		{
+			nome:   "Alteração de valor e status",
+			antes:  antes,
+			depois: &registroTeste{Nome: "Mensalidade", Valor: 150, Status: "PAGO"},
+			esperado: map[string]CampoAlterado{
+				"valor":  {Antes: 100.0, Depois: 150.0},
+				"status": {Antes: "PENDENTE", Depois: "PAGO"},
+			},
+		},
+		{
+			nome:     "Sem alterações",
+			antes:    antes,
+			depois:   &registroTeste{Nome: "Mensalidade", Valor: 100, Status: "PENDENTE"},
+			esperado: map[string]CampoAlterado{},
+		},
+		{
+			nome:    "Criação ignora campo",
+			antes:   nil,
+			depois:  antes,
+			ignorar: []string{"status"},
+			esperado: map[string]CampoAlterado{
+				"nome":  {Antes: nil, Depois: "Mensalidade"},
+				"valor": {Antes: nil, Depois: 100.0},
+			},
+		},
+		{
+			nome:   "Exclusão com ponteiro nulo",
+			antes:  antes,
+			depois: (*registroTeste)(nil),
+			esperado: map[string]CampoAlterado{
+				"nome":   {Antes: "Mensalidade", Depois: nil},
+				"valor":  {Antes: 100.0, Depois: nil},
+				"status": {Antes: "PENDENTE", Depois: nil},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			resultado, err := DiffCampos(tt.antes, tt.depois, tt.ignorar...)
+			if err != nil {
+				t.Fatalf("erro inesperado: %v", err)
+			}
+			if len(resultado) != len(tt.esperado) {
+				t.Fatalf("esperado %d campos, obtido %d: %v", len(tt.esperado), len(resultado), resultado)
+			}
+			for campo, esperado := range tt.esperado {
+				obtido, ok := resultado[campo]
+				if !ok {
+					t.Errorf("campo %s ausente no diff", campo)
+					continue
+				}
+				if obtido != esperado {
+					t.Errorf("campo %s: esperado %v, obtido %v", campo, esperado, obtido)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package util
+
+import (
+	"strings"
+)
+
+// NormalizarDocumento deixa apenas letras e dígitos em maiúsculas ("12.abc.345/01de-35" -> "12ABC34501DE35")
+func NormalizarDocumento(documento string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(documento) {
+		if (r >= '0' && r <= '9') || (r >= 'A' && r <= 'Z') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// ValidarCPF confere o tamanho e os dígitos verificadores de um CPF (com ou sem máscara)
+func ValidarCPF(cpf string) bool {
+	cpf = NormalizarDocumento(cpf)
+	if len(cpf) != 11 || !apenasDigitos(cpf) || repetido(cpf) {
+		return false
+	}
+
+	for posicao := 9; posicao <= 10; posicao++ {
+		soma := 0
+		for i := 0; i < posicao; i++ {
+			soma += int(cpf[i]-'0') * (posicao + 1 - i)
+		}
+		dv := soma * 10 % 11
+		if dv == 10 {
+			dv = 0
+		}
+		if int(cpf[posicao]-'0') != dv {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidarCNPJ confere o tamanho e os dígitos verificadores de um CNPJ (com ou sem máscara).
+// Aceita o CNPJ alfanumérico: as 12 primeiras posições podem ter letras, que valem o código
+// ASCII menos 48 no cálculo; os dois dígitos verificadores são sempre numéricos.
+func ValidarCNPJ(cnpj string) bool {
+	cnpj = NormalizarDocumento(cnpj)
+	if len(cnpj) != 14 || !apenasDigitos(cnpj[12:]) || repetido(cnpj) {
+		return false
+	}
+
+	for posicao := 12; posicao <= 13; posicao++ {
+		soma := 0
+		peso := posicao - 7 // 5 para o primeiro dígito, 6 para o segundo; volta a 9 depois do 2
+		for i := 0; i < posicao; i++ {
+			soma += int(cnpj[i]-'0') * peso
+			if peso--; peso < 2 {
+				peso = 9
+			}
+		}
+		dv := 11 - soma%11
+		if dv >= 10 {
+			dv = 0
+		}
+		if int(cnpj[posicao]-'0') != dv {
+			return false
+		}
+	}
+	return true
+}
+
+// FormatarCPF aplica a máscara 000.000.000-00 (devolve o valor como está se não tiver 11 caracteres)
+func FormatarCPF(cpf string) string {
+	normalizado := NormalizarDocumento(cpf)
+	if len(normalizado) != 11 {
+		return cpf
+	}
+	return normalizado[:3] + "." + normalizado[3:6] + "." + normalizado[6:9] + "-" + normalizado[9:]
+}
+
+// FormatarCNPJ aplica a máscara 00.000.000/0000-00 (devolve o valor como está se não tiver 14 caracteres)
+func FormatarCNPJ(cnpj string) string {
+	normalizado := NormalizarDocumento(cnpj)
+	if len(normalizado) != 14 {
+		return cnpj
+	}
+	return normalizado[:2] + "." + normalizado[2:5] + "." + normalizado[5:8] + "/" + normalizado[8:12] + "-" + normalizado[12:]
+}
+
+func apenasDigitos(texto string) bool {
+	for _, r := range texto {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// repetido detecta sequências como 111.111.111-11, que passam no cálculo mas não são válidas
+func repetido(texto string) bool {
+	return strings.Count(texto, texto[:1]) == len(texto)
+}
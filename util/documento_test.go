@@ -0,0 +1,74 @@
+package util
+
+import "testing"
+
+func TestValidarCPF(t *testing.T) {
+	tests := map[string]bool{
+		"529.982.247-25": true,
+		"52998224725":    true,
+		"529.982.247-24": false,
+		"111.111.111-11": false,
+		"5299822472":     false,
+		"52998224A25":    false,
+		"":               false,
+	}
+
+	for cpf, esperado := range tests {
+		if obtido := ValidarCPF(cpf); obtido != esperado {
+			t.Errorf("ValidarCPF(%q) = %v, esperado %v", cpf, obtido, esperado)
+		}
+	}
+}
+
+func TestValidarCNPJ(t *testing.T) {
+	tests := map[string]bool{
+		"11.222.333/0001-81": true,
+		"11222333000181":     true,
+		"12.ABC.345/01DE-35": true, // alfanumérico (exemplo da Receita Federal)
+		"12.abc.345/01de-35": true,
+		"11.222.333/0001-80": false,
+		"12.ABC.345/01DE-36": false,
+		"12.ABC.345/01DE-3A": false,
+		"00.000.000/0000-00": false,
+		"1122233300018":      false,
+	}
+
+	for cnpj, esperado := range tests {
+		if obtido := ValidarCNPJ(cnpj); obtido != esperado {
+			t.Errorf("ValidarCNPJ(%q) = %v, esperado %v", cnpj, obtido, esperado)
+		}
+	}
+}
+
+func TestFormatarDocumentos(t *testing.T) {
+	if obtido := FormatarCPF("52998224725"); obtido != "529.982.247-25" {
+		t.Errorf("FormatarCPF = %q", obtido)
+	}
+	if obtido := FormatarCNPJ("12abc34501de35"); obtido != "12.ABC.345/01DE-35" {
+		t.Errorf("FormatarCNPJ = %q", obtido)
+	}
+	if obtido := FormatarCNPJ("123"); obtido != "123" {
+		t.Errorf("FormatarCNPJ com tamanho inválido = %q, esperado o valor original", obtido)
+	}
+}
+
+func TestValidacaoDocumentos(t *testing.T) {
+	type pessoa struct {
+		CPF  *string `validate:"omitempty,cpf"`
+		CNPJ string  `validate:"omitempty,cnpj"`
+	}
+
+	cpf := "529.982.247-25"
+	if err := ValidarStruct(pessoa{CPF: &cpf, CNPJ: "12.ABC.345/01DE-35"}); err != nil {
+		t.Errorf("documentos válidos rejeitados: %v", err)
+	}
+	if err := ValidarStruct(pessoa{}); err != nil {
+		t.Errorf("documentos vazios rejeitados: %v", err)
+	}
+
+	invalido := "529.982.247-24"
+	err := ValidarStruct(pessoa{CPF: &invalido})
+	if err == nil || err.Error() != "CPF deve ser um CPF válido" {
+		t.Errorf("erro = %v, esperado CPF inválido", err)
+	}
+}
@@ -0,0 +1,39 @@
+package util
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrCEPInvalido = errors.New("CEP inválido")
+
+// ufsValidas são as siglas das 27 unidades da federação
+var ufsValidas = map[string]bool{
+	"AC": true, "AL": true, "AP": true, "AM": true, "BA": true, "CE": true, "DF": true,
+	"ES": true, "GO": true, "MA": true, "MT": true, "MS": true, "MG": true, "PA": true,
+	"PB": true, "PR": true, "PE": true, "PI": true, "RJ": true, "RN": true, "RS": true,
+	"RO": true, "RR": true, "SC": true, "SP": true, "SE": true, "TO": true,
+}
+
+// ValidarUF verifica se a sigla é de um estado brasileiro (maiúsculas ou minúsculas)
+func ValidarUF(uf string) bool {
+	return ufsValidas[strings.ToUpper(strings.TrimSpace(uf))]
+}
+
+// NormalizarCEP retorna os 8 dígitos do CEP ("01001-000" -> "01001000")
+func NormalizarCEP(cep string) (string, error) {
+	normalizado := NormalizarDocumento(cep)
+	if len(normalizado) != 8 || !apenasDigitos(normalizado) || normalizado == "00000000" {
+		return "", ErrCEPInvalido
+	}
+	return normalizado, nil
+}
+
+// FormatarCEP aplica a máscara 00000-000 (devolve o valor como está se não tiver 8 dígitos)
+func FormatarCEP(cep string) string {
+	normalizado, err := NormalizarCEP(cep)
+	if err != nil {
+		return cep
+	}
+	return normalizado[:5] + "-" + normalizado[5:]
+}
@@ -0,0 +1,43 @@
+package util
+
+import "testing"
+
+func TestNormalizarCEP(t *testing.T) {
+	validos := map[string]string{
+		"01001-000":   "01001000",
+		"01001000":    "01001000",
+		" 45.000-000": "45000000",
+	}
+	for cep, esperado := range validos {
+		if obtido, err := NormalizarCEP(cep); err != nil || obtido != esperado {
+			t.Errorf("NormalizarCEP(%q) = %q, %v; esperado %q", cep, obtido, err, esperado)
+		}
+	}
+
+	for _, cep := range []string{"", "0100100", "010010000", "0100A000", "00000-000"} {
+		if obtido, err := NormalizarCEP(cep); err == nil {
+			t.Errorf("NormalizarCEP(%q) = %q, esperado erro", cep, obtido)
+		}
+	}
+
+	if obtido := FormatarCEP("01001000"); obtido != "01001-000" {
+		t.Errorf("FormatarCEP = %q, esperado 01001-000", obtido)
+	}
+}
+
+func TestValidarUF(t *testing.T) {
+	tests := map[string]bool{
+		"SP": true,
+		"ba": true,
+		"DF": true,
+		"XX": false,
+		"S":  false,
+		"":   false,
+	}
+
+	for uf, esperado := range tests {
+		if obtido := ValidarUF(uf); obtido != esperado {
+			t.Errorf("ValidarUF(%q) = %v, esperado %v", uf, obtido, esperado)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// JanelaSilencio é um intervalo diário ("22:00" a "08:00") em que o cliente não
+// quer receber mensagens, no horário de Brasília. Pode atravessar a meia-noite.
+type JanelaSilencio struct {
+	Inicio string
+	Fim    string
+}
+
+// ValidarHorario verifica se o texto está no formato HH:MM
+func ValidarHorario(horario string) error {
+	if len(horario) != 5 {
+		return fmt.Errorf("horário inválido: %q (use HH:MM)", horario)
+	}
+	if _, err := time.Parse("15:04", horario); err != nil {
+		return fmt.Errorf("horário inválido: %q (use HH:MM)", horario)
+	}
+	return nil
+}
+
+// Validar verifica os horários da janela
+func (j JanelaSilencio) Validar() error {
+	if err := ValidarHorario(j.Inicio); err != nil {
+		return err
+	}
+	if err := ValidarHorario(j.Fim); err != nil {
+		return err
+	}
+	if j.Inicio == j.Fim {
+		return errors.New("início e fim do horário de silêncio devem ser diferentes")
+	}
+	return nil
+}
+
+// FimDoSilencio retorna quando termina a janela em andamento.
+// O segundo retorno é false se o instante estiver fora da janela (ou a janela for inválida).
+func (j JanelaSilencio) FimDoSilencio(agora time.Time) (time.Time, bool) {
+	if j.Validar() != nil {
+		return time.Time{}, false
+	}
+
+	location, _ := time.LoadLocation("America/Sao_Paulo")
+	agora = agora.In(location)
+
+	inicio, _ := time.Parse("15:04", j.Inicio)
+	fim, _ := time.Parse("15:04", j.Fim)
+	minutoInicio := inicio.Hour()*60 + inicio.Minute()
+	minutoFim := fim.Hour()*60 + fim.Minute()
+	minutoAgora := agora.Hour()*60 + agora.Minute()
+
+	fimHoje := time.Date(agora.Year(), agora.Month(), agora.Day(), fim.Hour(), fim.Minute(), 0, 0, location)
+
+	if minutoInicio < minutoFim {
+		// Janela no mesmo dia (ex: 12:00 às 14:00)
+		if minutoAgora >= minutoInicio && minutoAgora < minutoFim {
+			return fimHoje, true
+		}
+		return time.Time{}, false
+	}
+
+	// Janela que atravessa a meia-noite (ex: 22:00 às 08:00)
+	if minutoAgora >= minutoInicio {
+		return fimHoje.AddDate(0, 0, 1), true
+	}
+	if minutoAgora < minutoFim {
+		return fimHoje, true
+	}
+	return time.Time{}, false
+}
+
+// Contem verifica se o instante está dentro da janela
+func (j JanelaSilencio) Contem(agora time.Time) bool {
+	_, dentro := j.FimDoSilencio(agora)
+	return dentro
+}
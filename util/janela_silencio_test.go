@@ -0,0 +1,97 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJanelaSilencioFimDoSilencio(t *testing.T) {
+	location, _ := time.LoadLocation("America/Sao_Paulo")
+	noturna := JanelaSilencio{Inicio: "22:00", Fim: "08:00"}
+	almoco := JanelaSilencio{Inicio: "12:00", Fim: "14:00"}
+
+	tests := []struct {
+		nome     string
+		janela   JanelaSilencio
+		agora    time.Time
+		dentro   bool
+		esperado time.Time
+	}{
+		{
+			nome:     "Noturna - antes da meia-noite",
+			janela:   noturna,
+			agora:    time.Date(2026, 3, 10, 23, 30, 0, 0, location),
+			dentro:   true,
+			esperado: time.Date(2026, 3, 11, 8, 0, 0, 0, location),
+		},
+		{
+			nome:     "Noturna - depois da meia-noite",
+			janela:   noturna,
+			agora:    time.Date(2026, 3, 11, 6, 0, 0, 0, location),
+			dentro:   true,
+			esperado: time.Date(2026, 3, 11, 8, 0, 0, 0, location),
+		},
+		{
+			nome:   "Noturna - limite do fim",
+			janela: noturna,
+			agora:  time.Date(2026, 3, 11, 8, 0, 0, 0, location),
+			dentro: false,
+		},
+		{
+			nome:   "Noturna - durante o dia",
+			janela: noturna,
+			agora:  time.Date(2026, 3, 11, 9, 0, 0, 0, location),
+			dentro: false,
+		},
+		{
+			nome:     "Mesmo dia - dentro",
+			janela:   almoco,
+			agora:    time.Date(2026, 3, 11, 12, 0, 0, 0, location),
+			dentro:   true,
+			esperado: time.Date(2026, 3, 11, 14, 0, 0, 0, location),
+		},
+		{
+			nome:   "Mesmo dia - fora",
+			janela: almoco,
+			agora:  time.Date(2026, 3, 11, 9, 0, 0, 0, location),
+			dentro: false,
+		},
+		{
+			nome:     "Converte para o horário de Brasília",
+			janela:   almoco,
+			agora:    time.Date(2026, 3, 11, 16, 30, 0, 0, time.UTC), // 13:30 em Brasília
+			dentro:   true,
+			esperado: time.Date(2026, 3, 11, 14, 0, 0, 0, location),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			fim, dentro := tt.janela.FimDoSilencio(tt.agora)
+			if dentro != tt.dentro {
+				t.Fatalf("dentro = %v, esperado %v", dentro, tt.dentro)
+			}
+			if dentro && !fim.Equal(tt.esperado) {
+				t.Errorf("fim = %v, esperado %v", fim, tt.esperado)
+			}
+		})
+	}
+}
+
+func TestJanelaSilencioValidar(t *testing.T) {
+	invalidas := []JanelaSilencio{
+		{Inicio: "8:00", Fim: "18:00"},
+		{Inicio: "25:00", Fim: "08:00"},
+		{Inicio: "22:00", Fim: "22:00"},
+		{Inicio: "", Fim: "08:00"},
+	}
+	for _, janela := range invalidas {
+		if err := janela.Validar(); err == nil {
+			t.Errorf("esperado erro para %+v", janela)
+		}
+	}
+
+	if err := (JanelaSilencio{Inicio: "22:00", Fim: "08:00"}).Validar(); err != nil {
+		t.Errorf("erro inesperado: %v", err)
+	}
+}
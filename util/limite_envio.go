@@ -0,0 +1,61 @@
+package util
+
+import "time"
+
+// LimiteEnvio define o ritmo de envio de um número WhatsApp: mensagens por minuto
+// e quantas podem sair em sequência (rajada) antes de o ritmo ser imposto
+type LimiteEnvio struct {
+	PorMinuto int
+	Rajada    int
+}
+
+// Intervalo é o tempo médio entre mensagens no ritmo do limite
+func (l LimiteEnvio) Intervalo() time.Duration {
+	if l.PorMinuto <= 0 {
+		return time.Minute
+	}
+	return time.Minute / time.Duration(l.PorMinuto)
+}
+
+// Tolerancia é o adiantamento permitido pela rajada
+func (l LimiteEnvio) Tolerancia() time.Duration {
+	if l.Rajada <= 1 {
+		return 0
+	}
+	return l.Intervalo() * time.Duration(l.Rajada-1)
+}
+
+// EstadoEnvio guarda o horário teórico da próxima mensagem (TAT do GCRA)
+// e o horário do último envio reservado
+type EstadoEnvio struct {
+	TAT    time.Time
+	Ultimo time.Time
+}
+
+// ReservarEnvio calcula quando a próxima mensagem pode sair respeitando o limite (GCRA,
+// equivalente a um token bucket) e a pausa mínima desde o último envio. Só reserva o
+// horário se a espera não passar de maxEspera; retorna o novo estado, a espera e se reservou.
+func ReservarEnvio(estado EstadoEnvio, agora time.Time, limite LimiteEnvio, pausa, maxEspera time.Duration) (EstadoEnvio, time.Duration, bool) {
+	tat := estado.TAT
+	if tat.Before(agora) {
+		tat = agora
+	}
+
+	envio := tat.Add(-limite.Tolerancia())
+	if envio.Before(agora) {
+		envio = agora
+	}
+	if minimo := estado.Ultimo.Add(pausa); envio.Before(minimo) {
+		envio = minimo
+	}
+
+	espera := envio.Sub(agora)
+	if espera > maxEspera {
+		return estado, espera, false
+	}
+
+	if tat.Before(envio) {
+		tat = envio
+	}
+	return EstadoEnvio{TAT: tat.Add(limite.Intervalo()), Ultimo: envio}, espera, true
+}
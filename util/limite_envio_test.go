@@ -0,0 +1,51 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReservarEnvio(t *testing.T) {
+	limite := LimiteEnvio{PorMinuto: 6, Rajada: 3} // uma mensagem a cada 10s, rajada de 3
+	agora := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	maxEspera := time.Minute
+
+	var estado EstadoEnvio
+	var espera time.Duration
+	var ok bool
+
+	// Rajada: as 3 primeiras saem sem espera
+	for i := 0; i < 3; i++ {
+		estado, espera, ok = ReservarEnvio(estado, agora, limite, 0, maxEspera)
+		if !ok || espera != 0 {
+			t.Fatalf("mensagem %d: ok=%v espera=%v, esperado envio imediato", i+1, ok, espera)
+		}
+	}
+
+	// A quarta espera o intervalo do limite
+	estado, espera, ok = ReservarEnvio(estado, agora, limite, 0, maxEspera)
+	if !ok || espera != 10*time.Second {
+		t.Fatalf("ok=%v espera=%v, esperado 10s", ok, espera)
+	}
+
+	// Acima da espera máxima não reserva nem altera o estado
+	anterior := estado
+	novo, espera, ok := ReservarEnvio(estado, agora, limite, 0, 5*time.Second)
+	if ok || novo != anterior || espera != 20*time.Second {
+		t.Fatalf("ok=%v espera=%v, esperado recusa com 20s", ok, espera)
+	}
+
+	// Pausa mínima desde o último envio, mesmo com o balde cheio
+	estado = EstadoEnvio{Ultimo: agora}
+	_, espera, ok = ReservarEnvio(estado, agora.Add(time.Second), limite, 4*time.Second, maxEspera)
+	if !ok || espera != 3*time.Second {
+		t.Fatalf("ok=%v espera=%v, esperado 3s de pausa", ok, espera)
+	}
+
+	// Depois de um tempo parado o balde volta a encher
+	estado, _, _ = ReservarEnvio(EstadoEnvio{}, agora, limite, 0, maxEspera)
+	_, espera, _ = ReservarEnvio(estado, agora.Add(time.Hour), limite, 0, maxEspera)
+	if espera != 0 {
+		t.Fatalf("espera=%v, esperado envio imediato após pausa longa", espera)
+	}
+}
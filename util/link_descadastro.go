@@ -0,0 +1,65 @@
+package util
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+)
+
+var (
+	ErrTokenDescadastroInvalido = errors.New("link de descadastro inválido")
+	ErrSegredoDescadastro       = errors.New("UNSUBSCRIBE_SECRET (ou JWT_SECRET) não configurado")
+)
+
+// GerarTokenDescadastro assina o ID do cliente para o link de descadastro dos emails.
+// O token não expira: o link precisa funcionar em qualquer email já enviado.
+func GerarTokenDescadastro(clienteID uuid.UUID) (string, error) {
+	assinatura, err := assinaturaDescadastro(clienteID)
+	if err != nil {
+		return "", err
+	}
+	return clienteID.String() + "." + assinatura, nil
+}
+
+// ValidarTokenDescadastro confere a assinatura e retorna o ID do cliente
+func ValidarTokenDescadastro(token string) (uuid.UUID, error) {
+	id, assinatura, ok := strings.Cut(token, ".")
+	if !ok {
+		return uuid.Nil, ErrTokenDescadastroInvalido
+	}
+
+	clienteID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, ErrTokenDescadastroInvalido
+	}
+
+	esperada, err := assinaturaDescadastro(clienteID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if !hmac.Equal([]byte(assinatura), []byte(esperada)) {
+		return uuid.Nil, ErrTokenDescadastroInvalido
+	}
+	return clienteID, nil
+}
+
+// assinaturaDescadastro calcula o HMAC-SHA256 do ID do cliente com o segredo da aplicação.
+// Sem segredo configurado retorna erro: assinar com chave vazia tornaria o token forjável.
+func assinaturaDescadastro(clienteID uuid.UUID) (string, error) {
+	segredo := viper.GetString("UNSUBSCRIBE_SECRET")
+	if segredo == "" {
+		segredo = viper.GetString("JWT_SECRET")
+	}
+	if segredo == "" {
+		return "", ErrSegredoDescadastro
+	}
+
+	mac := hmac.New(sha256.New, []byte(segredo))
+	mac.Write([]byte("descadastro:" + clienteID.String()))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
@@ -0,0 +1,59 @@
+package util
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+)
+
+func TestTokenDescadastro(t *testing.T) {
+	viper.Set("UNSUBSCRIBE_SECRET", "segredo-de-teste")
+	defer viper.Set("UNSUBSCRIBE_SECRET", "")
+
+	clienteID := uuid.New()
+	token, err := GerarTokenDescadastro(clienteID)
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+
+	id, err := ValidarTokenDescadastro(token)
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if id != clienteID {
+		t.Errorf("id = %s, esperado %s", id, clienteID)
+	}
+
+	invalidos := []string{
+		"",
+		clienteID.String(),
+		uuid.New().String() + token[36:],
+		token[:len(token)-1] + "x",
+		"nao-e-uuid." + token[37:],
+	}
+	for _, invalido := range invalidos {
+		if _, err := ValidarTokenDescadastro(invalido); err == nil {
+			t.Errorf("esperado erro para token %q", invalido)
+		}
+	}
+}
+
+func TestTokenDescadastroSemSegredo(t *testing.T) {
+	viper.Set("UNSUBSCRIBE_SECRET", "segredo-de-teste")
+	token, err := GerarTokenDescadastro(uuid.New())
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+
+	viper.Set("UNSUBSCRIBE_SECRET", "")
+	viper.Set("JWT_SECRET", "")
+
+	if _, err := GerarTokenDescadastro(uuid.New()); !errors.Is(err, ErrSegredoDescadastro) {
+		t.Errorf("GerarTokenDescadastro: err=%v, esperado ErrSegredoDescadastro", err)
+	}
+	if _, err := ValidarTokenDescadastro(token); !errors.Is(err, ErrSegredoDescadastro) {
+		t.Errorf("ValidarTokenDescadastro: err=%v, esperado ErrSegredoDescadastro", err)
+	}
+}
@@ -0,0 +1,42 @@
+package util
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	emailNoTexto = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	// Telefones no texto: 10 a 13 dígitos seguidos, com "+" opcional (E.164, JID ou nacional)
+	telefoneNoTexto = regexp.MustCompile(`\+?\b\d{10,13}\b`)
+)
+
+// MascararTelefone mantém só os 4 últimos dígitos (ex: "+5577998616740" -> "+55*******6740")
+// e o DDI, quando houver "+"
+func MascararTelefone(telefone string) string {
+	digitos := regexp.MustCompile(`[^0-9]`).ReplaceAllString(telefone, "")
+	if len(digitos) <= 4 {
+		return strings.Repeat("*", len(digitos))
+	}
+
+	prefixo := ""
+	if strings.HasPrefix(strings.TrimSpace(telefone), "+") && len(digitos) > 8 {
+		prefixo, digitos = "+"+digitos[:2], digitos[2:]
+	}
+	return prefixo + strings.Repeat("*", len(digitos)-4) + digitos[len(digitos)-4:]
+}
+
+// MascararEmail mantém a primeira letra do usuário e o domínio (ex: "joao@ifinu.io" -> "j***@ifinu.io")
+func MascararEmail(email string) string {
+	usuario, dominio, ok := strings.Cut(email, "@")
+	if !ok || usuario == "" {
+		return "***"
+	}
+	return usuario[:1] + "***@" + dominio
+}
+
+// MascararTexto mascara emails e telefones que aparecem no meio de um texto livre
+func MascararTexto(texto string) string {
+	texto = emailNoTexto.ReplaceAllStringFunc(texto, MascararEmail)
+	return telefoneNoTexto.ReplaceAllStringFunc(texto, MascararTelefone)
+}
@@ -0,0 +1,41 @@
+package util
+
+import "testing"
+
+func TestMascararTelefone(t *testing.T) {
+	tests := map[string]string{
+		"+5577998616740":  "+55*******6740",
+		"5577998616740":   "*********6740",
+		"(77) 99861-6740": "*******6740",
+		"123":             "***",
+	}
+
+	for telefone, esperado := range tests {
+		if obtido := MascararTelefone(telefone); obtido != esperado {
+			t.Errorf("MascararTelefone(%q) = %q, esperado %q", telefone, obtido, esperado)
+		}
+	}
+}
+
+func TestMascararEmail(t *testing.T) {
+	tests := map[string]string{
+		"joao@ifinu.io": "j***@ifinu.io",
+		"@ifinu.io":     "***",
+		"sem-arroba":    "***",
+	}
+
+	for email, esperado := range tests {
+		if obtido := MascararEmail(email); obtido != esperado {
+			t.Errorf("MascararEmail(%q) = %q, esperado %q", email, obtido, esperado)
+		}
+	}
+}
+
+func TestMascararTexto(t *testing.T) {
+	texto := "Enviando para Maria (maria.silva@gmail.com), Telefone=5577998616740, Cobrança 3f2a"
+	esperado := "Enviando para Maria (m***@gmail.com), Telefone=*********6740, Cobrança 3f2a"
+
+	if obtido := MascararTexto(texto); obtido != esperado {
+		t.Errorf("MascararTexto() = %q, esperado %q", obtido, esperado)
+	}
+}
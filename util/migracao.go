@@ -0,0 +1,88 @@
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Migracao é um arquivo de migrations/: NNN_nome.sql (up) e, opcional, NNN_nome.down.sql
+type Migracao struct {
+	Versao int64
+	Nome   string
+	Up     string
+	Down   string
+}
+
+// Checksum identifica o conteúdo do up, para detectar migrations alteradas depois de aplicadas
+func (m Migracao) Checksum() string {
+	soma := sha256.Sum256([]byte(m.Up))
+	return hex.EncodeToString(soma[:])
+}
+
+// TemDown indica se a migration pode ser revertida
+func (m Migracao) TemDown() bool {
+	return strings.TrimSpace(m.Down) != ""
+}
+
+var arquivoMigracao = regexp.MustCompile(`^(\d+)_(.+?)(\.down)?\.sql$`)
+
+// LerMigracoes lê as migrations da raiz de fsys em ordem de versão. Falha com versões
+// repetidas, down sem up correspondente ou arquivos .sql fora do padrão.
+func LerMigracoes(fsys fs.FS) ([]Migracao, error) {
+	arquivos, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	porVersao := make(map[int64]*Migracao)
+	downs := make(map[int64]string)
+	for _, arquivo := range arquivos {
+		partes := arquivoMigracao.FindStringSubmatch(arquivo)
+		if partes == nil {
+			return nil, fmt.Errorf("migration com nome inválido: %s (esperado NNN_nome.sql)", arquivo)
+		}
+
+		versao, err := strconv.ParseInt(partes[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration com versão inválida: %s", arquivo)
+		}
+
+		conteudo, err := fs.ReadFile(fsys, arquivo)
+		if err != nil {
+			return nil, err
+		}
+
+		if partes[3] != "" {
+			downs[versao] = string(conteudo)
+			continue
+		}
+
+		if existente, ok := porVersao[versao]; ok {
+			return nil, fmt.Errorf("versão %d repetida: %s e %s", versao, existente.Nome, partes[2])
+		}
+		porVersao[versao] = &Migracao{Versao: versao, Nome: partes[2], Up: string(conteudo)}
+	}
+
+	for versao, down := range downs {
+		migracao, ok := porVersao[versao]
+		if !ok {
+			return nil, fmt.Errorf("migration %d tem down mas não tem up", versao)
+		}
+		migracao.Down = down
+	}
+
+	migracoes := make([]Migracao, 0, len(porVersao))
+	for _, migracao := range porVersao {
+		migracoes = append(migracoes, *migracao)
+	}
+	sort.Slice(migracoes, func(i, j int) bool {
+		return migracoes[i].Versao < migracoes[j].Versao
+	})
+	return migracoes, nil
+}
@@ -0,0 +1,54 @@
+package util
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestLerMigracoes(t *testing.T) {
+	fsys := fstest.MapFS{
+		"010_add_coluna.sql":      {Data: []byte("ALTER TABLE t ADD COLUMN c INT;")},
+		"010_add_coluna.down.sql": {Data: []byte("ALTER TABLE t DROP COLUMN c;")},
+		"002_create_tabela.sql":   {Data: []byte("CREATE TABLE t (id INT);")},
+		"README.md":               {Data: []byte("# Migrations")},
+	}
+
+	migracoes, err := LerMigracoes(fsys)
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if len(migracoes) != 2 {
+		t.Fatalf("%d migrations, esperado 2", len(migracoes))
+	}
+
+	if migracoes[0].Versao != 2 || migracoes[0].Nome != "create_tabela" || migracoes[0].TemDown() {
+		t.Errorf("primeira migration = %+v", migracoes[0])
+	}
+	if migracoes[1].Versao != 10 || migracoes[1].Down != "ALTER TABLE t DROP COLUMN c;" || !migracoes[1].TemDown() {
+		t.Errorf("segunda migration = %+v", migracoes[1])
+	}
+	if migracoes[0].Checksum() == migracoes[1].Checksum() {
+		t.Error("checksums iguais para conteúdos diferentes")
+	}
+}
+
+func TestLerMigracoesInvalidas(t *testing.T) {
+	casos := map[string]fstest.MapFS{
+		"versão repetida": {
+			"003_a.sql": {Data: []byte("SELECT 1;")},
+			"003_b.sql": {Data: []byte("SELECT 2;")},
+		},
+		"down sem up": {
+			"004_a.down.sql": {Data: []byte("SELECT 1;")},
+		},
+		"nome fora do padrão": {
+			"add_coluna.sql": {Data: []byte("SELECT 1;")},
+		},
+	}
+
+	for nome, fsys := range casos {
+		if _, err := LerMigracoes(fsys); err == nil {
+			t.Errorf("%s: esperado erro", nome)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// PixEstatico reúne os dados de um QR Code PIX estático (BR Code, padrão EMV do Banco Central)
+type PixEstatico struct {
+	Chave         string
+	Recebedor     string
+	Cidade        string
+	Valor         float64 // 0 = valor livre
+	Identificador string
+}
+
+// GerarPixCopiaECola monta o código "copia e cola" do PIX estático
+func GerarPixCopiaECola(pix PixEstatico) (string, error) {
+	chave := strings.TrimSpace(pix.Chave)
+	if chave == "" {
+		return "", errors.New("chave PIX não configurada")
+	}
+	if len(chave) > 77 {
+		return "", errors.New("chave PIX inválida")
+	}
+
+	recebedor := limitarCampoPix(pix.Recebedor, 25)
+	if recebedor == "" {
+		return "", errors.New("nome do recebedor PIX é obrigatório")
+	}
+	cidade := limitarCampoPix(pix.Cidade, 15)
+	if cidade == "" {
+		return "", errors.New("cidade do recebedor PIX é obrigatória")
+	}
+
+	// O identificador aceita apenas letras e números; "***" indica ausência
+	identificador := strings.ReplaceAll(NormalizarTexto(pix.Identificador), " ", "")
+	if len(identificador) > 25 {
+		identificador = identificador[:25]
+	}
+	if identificador == "" {
+		identificador = "***"
+	}
+
+	var codigo strings.Builder
+	codigo.WriteString(campoPix("00", "01"))
+	codigo.WriteString(campoPix("26", campoPix("00", "br.gov.bcb.pix")+campoPix("01", chave)))
+	codigo.WriteString(campoPix("52", "0000"))
+	codigo.WriteString(campoPix("53", "986"))
+	if pix.Valor > 0 {
+		codigo.WriteString(campoPix("54", fmt.Sprintf("%.2f", pix.Valor)))
+	}
+	codigo.WriteString(campoPix("58", "BR"))
+	codigo.WriteString(campoPix("59", recebedor))
+	codigo.WriteString(campoPix("60", cidade))
+	codigo.WriteString(campoPix("62", campoPix("05", identificador)))
+	codigo.WriteString("6304")
+
+	return codigo.String() + fmt.Sprintf("%04X", crc16CCITT(codigo.String())), nil
+}
+
+// campoPix formata um campo EMV: ID + tamanho com dois dígitos + valor
+func campoPix(id, valor string) string {
+	return fmt.Sprintf("%s%02d%s", id, len(valor), valor)
+}
+
+// limitarCampoPix remove acentos e corta o texto no tamanho máximo do campo
+func limitarCampoPix(texto string, max int) string {
+	texto = NormalizarTexto(texto)
+	if len(texto) > max {
+		texto = strings.TrimSpace(texto[:max])
+	}
+	return texto
+}
+
+// crc16CCITT calcula o CRC16-CCITT (polinômio 0x1021, valor inicial 0xFFFF) exigido pelo BR Code
+func crc16CCITT(dados string) uint16 {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(dados); i++ {
+		crc ^= uint16(dados[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
@@ -0,0 +1,58 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCrc16CCITT(t *testing.T) {
+	// Valor de verificação padrão do CRC-16/CCITT-FALSE
+	if crc := crc16CCITT("123456789"); crc != 0x29B1 {
+		t.Errorf("crc16CCITT = %04X, esperado 29B1", crc)
+	}
+}
+
+func TestGerarPixCopiaECola(t *testing.T) {
+	codigo, err := GerarPixCopiaECola(PixEstatico{
+		Chave:         "123e4567-e12b-12d1-a456-426655440000",
+		Recebedor:     "Fulano de Tal",
+		Cidade:        "Brasília",
+		Valor:         150.5,
+		Identificador: "cobranca-123",
+	})
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+
+	partes := []string{
+		"000201",
+		"26580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-426655440000",
+		"5303986",
+		"5406150.50",
+		"5802BR",
+		"5913FULANO DE TAL",
+		"6008BRASILIA",
+		"62150511COBRANCA123",
+	}
+	for _, parte := range partes {
+		if !strings.Contains(codigo, parte) {
+			t.Errorf("código %q não contém %q", codigo, parte)
+		}
+	}
+
+	// O CRC cobre o código inteiro, incluindo o ID e o tamanho do próprio campo
+	semCRC, crc := codigo[:len(codigo)-4], codigo[len(codigo)-4:]
+	if !strings.HasSuffix(semCRC, "6304") {
+		t.Fatalf("código sem campo CRC: %q", codigo)
+	}
+	if esperado := fmt.Sprintf("%04X", crc16CCITT(semCRC)); crc != esperado {
+		t.Errorf("CRC %s, esperado %s", crc, esperado)
+	}
+}
+
+func TestGerarPixCopiaEColaSemChave(t *testing.T) {
+	if _, err := GerarPixCopiaECola(PixEstatico{Recebedor: "Fulano", Cidade: "SP"}); err == nil {
+		t.Error("esperado erro para chave vazia")
+	}
+}
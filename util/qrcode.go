@@ -0,0 +1,43 @@
+package util
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/qr"
+)
+
+// GerarQRCodePNG gera a imagem PNG de um QR Code (ex: PIX copia e cola) com margem branca.
+// O tamanho é o lado da imagem em pixels, incluindo a margem.
+func GerarQRCodePNG(conteudo string, tamanho int) ([]byte, error) {
+	if conteudo == "" {
+		return nil, errors.New("conteúdo do QR Code é obrigatório")
+	}
+
+	codigo, err := qr.Encode(conteudo, qr.M, qr.Auto)
+	if err != nil {
+		return nil, err
+	}
+
+	// Margem de ~8% em cada lado para leitores de câmera
+	margem := tamanho / 12
+	codigo, err = barcode.Scale(codigo, tamanho-2*margem, tamanho-2*margem)
+	if err != nil {
+		return nil, err
+	}
+
+	imagem := image.NewGray(image.Rect(0, 0, tamanho, tamanho))
+	draw.Draw(imagem, imagem.Bounds(), &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+	draw.Draw(imagem, codigo.Bounds().Add(image.Pt(margem, margem)), codigo, image.Point{}, draw.Over)
+
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, imagem); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
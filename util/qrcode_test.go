@@ -0,0 +1,31 @@
+package util
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+)
+
+func TestGerarQRCodePNG(t *testing.T) {
+	dados, err := GerarQRCodePNG("00020126360014BR.GOV.BCB.PIX0114+5511999999999", 300)
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+
+	imagem, err := png.Decode(bytes.NewReader(dados))
+	if err != nil {
+		t.Fatalf("PNG inválido: %v", err)
+	}
+	if imagem.Bounds().Dx() != 300 || imagem.Bounds().Dy() != 300 {
+		t.Errorf("tamanho = %v, esperado 300x300", imagem.Bounds().Size())
+	}
+
+	// Canto superior esquerdo é margem (branco)
+	if r, _, _, _ := imagem.At(0, 0).RGBA(); r != 0xffff {
+		t.Errorf("margem deveria ser branca")
+	}
+
+	if _, err := GerarQRCodePNG("", 300); err == nil {
+		t.Error("esperado erro para conteúdo vazio")
+	}
+}
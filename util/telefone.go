@@ -1,14 +1,101 @@
 package util
 
 import (
+	"errors"
 	"regexp"
 	"strings"
 )
 
+var ErrTelefoneInvalido = errors.New("telefone inválido")
+
+// dddsValidos são os códigos de área em uso no Brasil (plano de numeração da Anatel)
+var dddsValidos = map[string]bool{
+	"11": true, "12": true, "13": true, "14": true, "15": true, "16": true, "17": true, "18": true, "19": true,
+	"21": true, "22": true, "24": true, "27": true, "28": true,
+	"31": true, "32": true, "33": true, "34": true, "35": true, "37": true, "38": true,
+	"41": true, "42": true, "43": true, "44": true, "45": true, "46": true, "47": true, "48": true, "49": true,
+	"51": true, "53": true, "54": true, "55": true,
+	"61": true, "62": true, "63": true, "64": true, "65": true, "66": true, "67": true, "68": true, "69": true,
+	"71": true, "73": true, "74": true, "75": true, "77": true, "79": true,
+	"81": true, "82": true, "83": true, "84": true, "85": true, "86": true, "87": true, "88": true, "89": true,
+	"91": true, "92": true, "93": true, "94": true, "95": true, "96": true, "97": true, "98": true, "99": true,
+}
+
+// NormalizarTelefone valida o telefone e o converte para E.164 (ex: +5577998616740)
+// Números com "+" ou "00" na frente são internacionais; sem eles, brasileiros, com ou sem
+// o 55, o 0 de longa distância e o código da operadora. Celulares brasileiros antigos
+// (8 dígitos começando com 6 a 9) ganham o nono dígito.
+func NormalizarTelefone(telefone string) (string, error) {
+	texto := strings.TrimSpace(telefone)
+	digitos := regexp.MustCompile(`[^0-9]`).ReplaceAllString(texto, "")
+	if digitos == "" {
+		return "", ErrTelefoneInvalido
+	}
+
+	internacional := strings.HasPrefix(texto, "+")
+	if !internacional && strings.HasPrefix(digitos, "00") {
+		internacional = true
+		digitos = digitos[2:]
+	}
+
+	if internacional {
+		if strings.HasPrefix(digitos, "55") {
+			return normalizarTelefoneNacional(digitos[2:])
+		}
+		// E.164: código do país (não começa com 0) + número, até 15 dígitos
+		if len(digitos) < 8 || len(digitos) > 15 || digitos[0] == '0' {
+			return "", ErrTelefoneInvalido
+		}
+		return "+" + digitos, nil
+	}
+
+	switch {
+	case strings.HasPrefix(digitos, "55") && (len(digitos) == 12 || len(digitos) == 13):
+		digitos = digitos[2:]
+	case strings.HasPrefix(digitos, "0") && (len(digitos) == 13 || len(digitos) == 14):
+		digitos = digitos[3:] // 0 + operadora + DDD + número
+	case strings.HasPrefix(digitos, "0") && (len(digitos) == 11 || len(digitos) == 12):
+		digitos = digitos[1:] // 0 + DDD + número
+	}
+
+	return normalizarTelefoneNacional(digitos)
+}
+
+// normalizarTelefoneNacional valida DDD + número brasileiro e retorna em E.164
+func normalizarTelefoneNacional(digitos string) (string, error) {
+	if len(digitos) != 10 && len(digitos) != 11 {
+		return "", ErrTelefoneInvalido
+	}
+
+	ddd, numero := digitos[:2], digitos[2:]
+	if !dddsValidos[ddd] {
+		return "", ErrTelefoneInvalido
+	}
+
+	switch {
+	case len(numero) == 9 && numero[0] == '9':
+		// Celular
+	case len(numero) == 8 && numero[0] >= '2' && numero[0] <= '5':
+		// Fixo
+	case len(numero) == 8 && numero[0] >= '6':
+		// Celular sem o nono dígito
+		numero = "9" + numero
+	default:
+		return "", ErrTelefoneInvalido
+	}
+
+	return "+55" + ddd + numero, nil
+}
+
 // FormatarTelefoneBrasileiro formata um número de telefone brasileiro para o formato internacional
 // Aceita formatos: (77) 99861-6740, 77998616740, 5577998616740
 // Retorna: 5577998616740
+// Números válidos para NormalizarTelefone (inclusive internacionais com +) saem em E.164 sem o +
 func FormatarTelefoneBrasileiro(telefone string) string {
+	if normalizado, err := NormalizarTelefone(telefone); err == nil {
+		return strings.TrimPrefix(normalizado, "+")
+	}
+
 	// Remover todos os caracteres não numéricos
 	re := regexp.MustCompile(`[^0-9]`)
 	apenasNumeros := re.ReplaceAllString(telefone, "")
@@ -18,8 +105,9 @@ func FormatarTelefoneBrasileiro(telefone string) string {
 		return ""
 	}
 
-	// Se já tem código do país (55) no início e tamanho correto (13 dígitos)
-	if strings.HasPrefix(apenasNumeros, "55") && len(apenasNumeros) == 13 {
+	// Se já tem código do país (55) no início e tamanho correto (13 dígitos,
+	// ou 12 para fixos e JIDs antigos sem o nono dígito)
+	if strings.HasPrefix(apenasNumeros, "55") && (len(apenasNumeros) == 13 || len(apenasNumeros) == 12) {
 		return apenasNumeros
 	}
 
@@ -47,3 +135,43 @@ func FormatarTelefoneBrasileiro(telefone string) string {
 	// Retornar com 55 na frente de qualquer forma
 	return "55" + apenasNumeros
 }
+
+// TelefoneDoJID extrai os dígitos do número de um JID do WhatsApp
+// Ex: 5577998616740@s.whatsapp.net -> 5577998616740
+func TelefoneDoJID(jid string) string {
+	numero, _, _ := strings.Cut(jid, "@")
+	numero, _, _ = strings.Cut(numero, ":") // sufixo de dispositivo
+	return regexp.MustCompile(`[^0-9]`).ReplaceAllString(numero, "")
+}
+
+// VariantesTelefoneBrasileiro retorna as formas (apenas dígitos) em que um mesmo
+// número pode estar cadastrado: com ou sem o código 55 e, para celulares, com ou
+// sem o nono dígito (o WhatsApp ainda entrega JIDs antigos sem ele)
+func VariantesTelefoneBrasileiro(telefone string) []string {
+	digitos := regexp.MustCompile(`[^0-9]`).ReplaceAllString(telefone, "")
+	if digitos == "" {
+		return nil
+	}
+
+	// Com 12 ou 13 dígitos e prefixo 55, o código do país já está presente
+	var local string
+	if strings.HasPrefix(digitos, "55") && (len(digitos) == 12 || len(digitos) == 13) {
+		local = digitos[2:]
+	} else {
+		local = strings.TrimPrefix(FormatarTelefoneBrasileiro(digitos), "55")
+	}
+	locais := []string{local}
+
+	switch {
+	case len(local) == 11 && local[2] == '9':
+		locais = append(locais, local[:2]+local[3:])
+	case len(local) == 10 && local[2] >= '6':
+		locais = append(locais, local[:2]+"9"+local[2:])
+	}
+
+	variantes := make([]string, 0, len(locais)*2)
+	for _, l := range locais {
+		variantes = append(variantes, "55"+l, l)
+	}
+	return variantes
+}
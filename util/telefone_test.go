@@ -0,0 +1,97 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTelefoneDoJID(t *testing.T) {
+	tests := map[string]string{
+		"5577998616740@s.whatsapp.net":    "5577998616740",
+		"5577998616740:12@s.whatsapp.net": "5577998616740",
+		"557798616740":                    "557798616740",
+	}
+
+	for jid, esperado := range tests {
+		if obtido := TelefoneDoJID(jid); obtido != esperado {
+			t.Errorf("TelefoneDoJID(%q) = %q, esperado %q", jid, obtido, esperado)
+		}
+	}
+}
+
+func TestVariantesTelefoneBrasileiro(t *testing.T) {
+	tests := []struct {
+		nome     string
+		telefone string
+		esperado []string
+	}{
+		{
+			nome:     "Celular com nono dígito",
+			telefone: "(77) 99861-6740",
+			esperado: []string{"5577998616740", "77998616740", "557798616740", "7798616740"},
+		},
+		{
+			nome:     "JID de celular sem nono dígito",
+			telefone: "557798616740",
+			esperado: []string{"557798616740", "7798616740", "5577998616740", "77998616740"},
+		},
+		{
+			nome:     "Telefone fixo",
+			telefone: "7734216740",
+			esperado: []string{"557734216740", "7734216740"},
+		},
+		{
+			nome:     "Vazio",
+			telefone: "",
+			esperado: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			obtido := VariantesTelefoneBrasileiro(tt.telefone)
+			if !reflect.DeepEqual(obtido, tt.esperado) {
+				t.Errorf("VariantesTelefoneBrasileiro(%q) = %v, esperado %v", tt.telefone, obtido, tt.esperado)
+			}
+		})
+	}
+}
+
+func TestNormalizarTelefone(t *testing.T) {
+	validos := map[string]string{
+		"(77) 99861-6740":     "+5577998616740",
+		"5577998616740":       "+5577998616740",
+		"+55 77 99861-6740":   "+5577998616740",
+		"557798616740":        "+5577998616740", // sem o nono dígito
+		"(77) 9861-6740":      "+5577998616740",
+		"(77) 3421-6740":      "+557734216740", // fixo
+		"0 77 99861-6740":     "+5577998616740",
+		"0 15 77 99861-6740":  "+5577998616740", // operadora
+		"0055 77 99861-6740":  "+5577998616740",
+		"+351 912 345 678":    "+351912345678",
+		"00 1 (415) 555-2671": "+14155552671",
+	}
+	for telefone, esperado := range validos {
+		obtido, err := NormalizarTelefone(telefone)
+		if err != nil || obtido != esperado {
+			t.Errorf("NormalizarTelefone(%q) = %q, %v; esperado %q", telefone, obtido, err, esperado)
+		}
+	}
+
+	invalidos := []string{
+		"",
+		"abc",
+		"(20) 99861-6740",   // DDD inexistente
+		"(77) 89861-6740",   // celular de 9 dígitos sem o 9
+		"(77) 1861-6740",    // número começando com 1
+		"998616740",         // sem DDD
+		"+0123456789",       // código de país inválido
+		"+1234567",          // curto demais
+		"+1234567890123456", // longo demais
+	}
+	for _, telefone := range invalidos {
+		if obtido, err := NormalizarTelefone(telefone); err == nil {
+			t.Errorf("NormalizarTelefone(%q) = %q, esperado erro", telefone, obtido)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package util
+
+import (
+	"strings"
+	"unicode"
+)
+
+// acentos mapeia letras acentuadas do português para a letra base
+var acentos = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a", "ª", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o", "º", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// RemoverAcentos troca letras acentuadas pela letra base ("Cobrança" -> "Cobranca")
+func RemoverAcentos(texto string) string {
+	return acentos.Replace(strings.ToLower(texto))
+}
+
+// NormalizarTexto prepara um texto para comparação: maiúsculas, sem acentos,
+// pontuação trocada por espaço e espaços repetidos removidos ("2ª via!" -> "2A VIA")
+func NormalizarTexto(texto string) string {
+	texto = RemoverAcentos(texto)
+
+	palavras := strings.FieldsFunc(texto, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.ToUpper(strings.Join(palavras, " "))
+}
+
+// IgualPalavraChave verifica se o texto inteiro é a palavra-chave, ignorando maiúsculas,
+// acentos, pontuação e espaços ("2 via" corresponde a "2VIA")
+func IgualPalavraChave(texto, palavraChave string) bool {
+	palavraChave = NormalizarTexto(palavraChave)
+	if palavraChave == "" {
+		return false
+	}
+
+	texto = NormalizarTexto(texto)
+	if texto == palavraChave {
+		return true
+	}
+
+	// Compara também sem espaços para aceitar "2 VIA" em regras "2VIA"
+	return strings.ReplaceAll(texto, " ", "") == strings.ReplaceAll(palavraChave, " ", "")
+}
+
+// ContemPalavraChave verifica se o texto é ou contém a palavra-chave como palavra inteira,
+// ignorando maiúsculas, acentos e pontuação
+func ContemPalavraChave(texto, palavraChave string) bool {
+	if IgualPalavraChave(texto, palavraChave) {
+		return true
+	}
+	palavraChave = NormalizarTexto(palavraChave)
+	return palavraChave != "" && strings.Contains(" "+NormalizarTexto(texto)+" ", " "+palavraChave+" ")
+}
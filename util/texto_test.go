@@ -0,0 +1,72 @@
+package util
+
+import "testing"
+
+func TestNormalizarTexto(t *testing.T) {
+	tests := []struct {
+		entrada  string
+		esperado string
+	}{
+		{"2via", "2VIA"},
+		{"  Quero a 2ª via, por favor!  ", "QUERO A 2A VIA POR FAVOR"},
+		{"Não quero mais", "NAO QUERO MAIS"},
+		{"PIX?", "PIX"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if resultado := NormalizarTexto(tt.entrada); resultado != tt.esperado {
+			t.Errorf("NormalizarTexto(%q) = %q, esperado %q", tt.entrada, resultado, tt.esperado)
+		}
+	}
+}
+
+func TestContemPalavraChave(t *testing.T) {
+	tests := []struct {
+		nome         string
+		texto        string
+		palavraChave string
+		esperado     bool
+	}{
+		{"Igual", "2VIA", "2VIA", true},
+		{"Minúsculas", "pix", "PIX", true},
+		{"Com espaço", "2 via", "2VIA", true},
+		{"Dentro da frase", "me manda o pix por favor", "PIX", true},
+		{"Acentos", "nao quero", "NÃO QUERO", true},
+		{"Parte de outra palavra", "pixel", "PIX", false},
+		{"Palavra-chave vazia", "oi", "", false},
+		{"Sem correspondência", "bom dia", "SAIR", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			if resultado := ContemPalavraChave(tt.texto, tt.palavraChave); resultado != tt.esperado {
+				t.Errorf("ContemPalavraChave(%q, %q) = %v, esperado %v", tt.texto, tt.palavraChave, resultado, tt.esperado)
+			}
+		})
+	}
+}
+
+func TestIgualPalavraChave(t *testing.T) {
+	tests := []struct {
+		nome         string
+		texto        string
+		palavraChave string
+		esperado     bool
+	}{
+		{"Igual", "sair", "SAIR", true},
+		{"Com pontuação", "Sair!", "SAIR", true},
+		{"Com espaço", "2 via", "2VIA", true},
+		{"Dentro da frase", "não vou poder sair hoje, pago amanhã", "SAIR", false},
+		{"Pix na frase", "já fiz o pix", "PIX", false},
+		{"Palavra-chave vazia", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nome, func(t *testing.T) {
+			if resultado := IgualPalavraChave(tt.texto, tt.palavraChave); resultado != tt.esperado {
+				t.Errorf("IgualPalavraChave(%q, %q) = %v, esperado %v", tt.texto, tt.palavraChave, resultado, tt.esperado)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var ErrEnderecoInterno = errors.New("endereço interno não permitido")
+
+// faixasInternas complementa os métodos de netip.Addr com faixas que não devem receber
+// chamadas feitas em nome de clientes
+var faixasInternas = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "esta rede"
+	netip.MustParsePrefix("100.64.0.0/10"), // CGNAT, usada por redes de clusters
+	netip.MustParsePrefix("192.0.0.0/24"),  // atribuições de protocolo (IETF)
+	netip.MustParsePrefix("198.18.0.0/15"), // testes de benchmark
+	netip.MustParsePrefix("64:ff9b::/96"),  // NAT64, pode apontar para IPv4 interno
+}
+
+// EnderecoInterno indica se o IP é de loopback, rede privada, link-local (inclui o
+// endereço de metadados das clouds, 169.254.169.254), multicast ou não especificado
+func EnderecoInterno(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsValid() || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() {
+		return true
+	}
+	for _, faixa := range faixasInternas {
+		if faixa.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidarURLExterna exige URL http(s) cujo host resolva apenas para endereços públicos.
+// A checagem no cadastro não basta (o DNS pode mudar depois); quem faz a chamada deve
+// usar um cliente de NovoClienteHTTPExterno.
+func ValidarURLExterna(ctx context.Context, endereco string) error {
+	u, err := url.Parse(endereco)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return errors.New("URL inválida, use http:// ou https://")
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrEnderecoInterno
+	}
+
+	if ip, err := netip.ParseAddr(host); err == nil {
+		if EnderecoInterno(ip) {
+			return ErrEnderecoInterno
+		}
+		return nil
+	}
+
+	ips, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("não foi possível resolver o host %s", host)
+	}
+	for _, ip := range ips {
+		if EnderecoInterno(ip) {
+			return ErrEnderecoInterno
+		}
+	}
+	return nil
+}
+
+// NovoClienteHTTPExterno cria um cliente que só conecta em endereços públicos. O IP é
+// verificado no momento da conexão (depois da resolução de DNS), o que impede que um
+// host troque para um endereço interno depois de validado. Redirecionamentos não são
+// seguidos: a resposta 3xx é devolvida como está.
+func NovoClienteHTTPExterno(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(_, endereco string, _ syscall.RawConn) error {
+			hostPorta, err := netip.ParseAddrPort(endereco)
+			if err != nil || EnderecoInterno(hostPorta.Addr()) {
+				return ErrEnderecoInterno
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:               nil, // Com proxy, o dialer veria o endereço do proxy e não o do destino
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConnsPerHost: 2,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,97 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestEnderecoInterno(t *testing.T) {
+	internos := []string{
+		"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.0.10", "169.254.169.254",
+		"0.0.0.0", "100.64.0.1", "224.0.0.1", "::1", "::", "fe80::1", "fd00::1",
+		"::ffff:127.0.0.1", "::ffff:169.254.169.254", "64:ff9b::a00:1",
+	}
+	for _, endereco := range internos {
+		if !EnderecoInterno(netip.MustParseAddr(endereco)) {
+			t.Errorf("%s deveria ser interno", endereco)
+		}
+	}
+
+	publicos := []string{"8.8.8.8", "200.147.67.142", "172.32.0.1", "2001:4860:4860::8888"}
+	for _, endereco := range publicos {
+		if EnderecoInterno(netip.MustParseAddr(endereco)) {
+			t.Errorf("%s deveria ser público", endereco)
+		}
+	}
+}
+
+func TestValidarURLExterna(t *testing.T) {
+	ctx := context.Background()
+
+	invalidas := []string{"ftp://exemplo.com.br", "exemplo.com.br/webhook", "https://", "://"}
+	for _, endereco := range invalidas {
+		if err := ValidarURLExterna(ctx, endereco); err == nil || errors.Is(err, ErrEnderecoInterno) {
+			t.Errorf("%q: err=%v, esperado URL inválida", endereco, err)
+		}
+	}
+
+	internas := []string{
+		"http://localhost:8080/webhook", "http://api.localhost/webhook", "http://127.0.0.1/webhook",
+		"http://169.254.169.254/latest/meta-data/", "https://10.0.0.5/webhook", "http://[::1]:9000/",
+		"http://[::ffff:192.168.0.1]/",
+	}
+	for _, endereco := range internas {
+		if err := ValidarURLExterna(ctx, endereco); !errors.Is(err, ErrEnderecoInterno) {
+			t.Errorf("%q: err=%v, esperado ErrEnderecoInterno", endereco, err)
+		}
+	}
+
+	if err := ValidarURLExterna(ctx, "https://8.8.8.8/webhook"); err != nil {
+		t.Errorf("IP público recusado: %v", err)
+	}
+}
+
+func TestClienteHTTPExternoRecusaEnderecoInterno(t *testing.T) {
+	chamado := false
+	servidor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chamado = true
+	}))
+	defer servidor.Close()
+
+	// O servidor de teste escuta em 127.0.0.1: a conexão deve ser recusada no dial
+	_, err := NovoClienteHTTPExterno(time.Second).Get(servidor.URL)
+	if !errors.Is(err, ErrEnderecoInterno) {
+		t.Fatalf("err=%v, esperado ErrEnderecoInterno", err)
+	}
+	if chamado {
+		t.Fatal("servidor interno recebeu a requisição")
+	}
+}
+
+func TestClienteHTTPExternoNaoSegueRedirecionamento(t *testing.T) {
+	cliente := NovoClienteHTTPExterno(time.Second)
+	// Sem rede externa no teste: usa o transporte padrão só para observar o CheckRedirect
+	cliente.Transport = http.DefaultTransport
+
+	destino := false
+	interno := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		destino = true
+	}))
+	defer interno.Close()
+	origem := httptest.NewServer(http.RedirectHandler(interno.URL, http.StatusFound))
+	defer origem.Close()
+
+	resp, err := cliente.Get(origem.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound || destino {
+		t.Fatalf("status %d, destino chamado=%v; esperado 302 sem seguir", resp.StatusCode, destino)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 )
 
@@ -11,6 +12,30 @@ var validate *validator.Validate
 
 func init() {
 	validate = validator.New()
+	RegistrarValidacoes(validate)
+
+	// Tags também disponíveis no binding do Gin (binding:"omitempty,cpf")
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		RegistrarValidacoes(v)
+	}
+}
+
+// RegistrarValidacoes adiciona as tags de documentos e endereços brasileiros: cpf e cnpj
+// (com ou sem máscara; cnpj aceita o formato alfanumérico), cep e uf
+func RegistrarValidacoes(v *validator.Validate) {
+	v.RegisterValidation("cpf", func(fl validator.FieldLevel) bool {
+		return ValidarCPF(fl.Field().String())
+	})
+	v.RegisterValidation("cnpj", func(fl validator.FieldLevel) bool {
+		return ValidarCNPJ(fl.Field().String())
+	})
+	v.RegisterValidation("cep", func(fl validator.FieldLevel) bool {
+		_, err := NormalizarCEP(fl.Field().String())
+		return err == nil
+	})
+	v.RegisterValidation("uf", func(fl validator.FieldLevel) bool {
+		return ValidarUF(fl.Field().String())
+	})
 }
 
 // ValidarStruct valida uma struct usando tags validate
@@ -51,6 +76,14 @@ func formatarErroValidacao(e validator.FieldError) string {
 		return fmt.Sprintf("%s deve ser menor que %s", campo, e.Param())
 	case "lte":
 		return fmt.Sprintf("%s deve ser menor ou igual a %s", campo, e.Param())
+	case "cpf":
+		return fmt.Sprintf("%s deve ser um CPF válido", campo)
+	case "cnpj":
+		return fmt.Sprintf("%s deve ser um CNPJ válido", campo)
+	case "cep":
+		return fmt.Sprintf("%s deve ser um CEP válido", campo)
+	case "uf":
+		return fmt.Sprintf("%s deve ser a sigla de um estado", campo)
 	default:
 		return fmt.Sprintf("%s não passou na validação %s", campo, tag)
 	}